@@ -679,6 +679,17 @@ def resolve_challenge(state: GameState, challenger_id: int) -> GameState:
     )
 
 
+def at_hand_boundary(state: GameState, genome: GameGenome) -> bool:
+    """Check if state sits between hands, where first_to_score is settled.
+
+    Trick games are between hands once every trick is played; any other game
+    is one running hand scored as it goes, so every turn is a boundary.
+    """
+    if any(isinstance(phase, TrickPhase) for phase in genome.turn_structure.phases):
+        return all(len(p.hand) == 0 for p in state.players)
+    return True
+
+
 def check_win_conditions(state: GameState, genome: GameGenome) -> Optional[int]:
     """Check if any player has won. Returns winner ID or None."""
     num_players = len(state.players)
@@ -695,10 +706,17 @@ def check_win_conditions(state: GameState, genome: GameGenome) -> Optional[int]:
                     return player_id
 
         elif wc.type == "first_to_score":
-            if wc.threshold is not None:
-                for player_id, player in enumerate(state.players):
-                    if player.score >= wc.threshold:
-                        return player_id
+            # Checked at hand boundaries, like the Go engine's HandBoundary.
+            # A positive threshold is a target to reach, a negative one a
+            # floor to stay above; once triggered the highest score wins
+            # (ties to the lower player index).
+            if wc.threshold and at_hand_boundary(state, genome):
+                if wc.threshold > 0:
+                    triggered = any(p.score >= wc.threshold for p in state.players)
+                else:
+                    triggered = any(p.score < wc.threshold for p in state.players)
+                if triggered:
+                    return max(range(num_players), key=lambda i: state.players[i].score)
 
         elif wc.type == "high_score":
             # Highest score wins when anyone reaches threshold
@@ -5,14 +5,23 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/rand"
 	"os"
+	"runtime"
+	"sort"
+	"sync/atomic"
+	"time"
 
 	"github.com/signalnine/darwindeck/gosim/engine"
+	"github.com/signalnine/darwindeck/gosim/mcts"
 	"github.com/signalnine/darwindeck/gosim/simulation"
 )
 
@@ -24,6 +33,27 @@ type Command struct {
 	MoveIndex int             `json:"move_index,omitempty"`
 	AIType    string          `json:"ai_type,omitempty"`
 	Seed      int64           `json:"seed,omitempty"`
+	// MCTS options for ai_type "mcts" (zero values use the defaults)
+	MCTSIterations int     `json:"mcts_iterations,omitempty"`
+	ExplorationC   float64 `json:"exploration_c,omitempty"`
+	// Temperature samples MCTS moves by visits^(1/T) (see mcts.SampleByVisits):
+	// 0 takes the most visited. Omitted, get_ai_move uses 0 and selfplay 1.
+	Temperature *float64 `json:"temperature,omitempty"`
+	// ViewerID requests a redacted view of the state for that player (see serializeStateFor)
+	ViewerID *int `json:"viewer_id,omitempty"`
+	// Move to look up for check_move
+	PhaseIndex int `json:"phase_index,omitempty"`
+	CardIndex  int `json:"card_index,omitempty"`
+	// Trace returns the engine events apply_move caused (debugging aid)
+	Trace bool `json:"trace,omitempty"`
+	// Framing switches the session's framing on ping ("lines" or "length_prefixed")
+	Framing string `json:"framing,omitempty"`
+	// HintCount is how many moves hint returns (0 means defaultHintCount)
+	HintCount int `json:"hint_count,omitempty"`
+	// StartingPlayer overrides the genome's first player for start_game
+	StartingPlayer *int `json:"starting_player,omitempty"`
+	// Games is how many games evaluate_fitness plays (0 means defaultFitnessGames)
+	Games int `json:"games,omitempty"`
 }
 
 // Response represents the JSON response sent to Python.
@@ -31,9 +61,131 @@ type Response struct {
 	Success bool            `json:"success"`
 	Error   string          `json:"error,omitempty"`
 	State   json.RawMessage `json:"state,omitempty"`
-	Moves   []MoveInfo      `json:"moves,omitempty"`
-	Winner  int             `json:"winner,omitempty"`
-	AIMove  *MoveInfo       `json:"ai_move,omitempty"`
+	// View is the state as seen by Command.ViewerID, safe to send to that player's client.
+	// State stays complete so it can be passed back to the worker.
+	View   json.RawMessage `json:"view,omitempty"`
+	Moves  []MoveInfo      `json:"moves,omitempty"`
+	Winner int             `json:"winner,omitempty"`
+	// EndReason says why the game ended (e.g. "empty_hand", "max_turns"); empty while in progress
+	EndReason string    `json:"end_reason,omitempty"`
+	AIMove    *MoveInfo `json:"ai_move,omitempty"`
+	// check_move results: Move is the matching legal move and Preview the
+	// state after applying it; both omitted when the move isn't legal
+	Legal   bool            `json:"legal,omitempty"`
+	Move    *MoveInfo       `json:"move,omitempty"`
+	Preview json.RawMessage `json:"preview,omitempty"`
+	// Summary is the describe_genome result
+	Summary *GenomeSummary `json:"summary,omitempty"`
+	// Trace lists engine events in order when Command.Trace is set
+	Trace []TraceEntry `json:"trace,omitempty"`
+	// Stats is the stats result
+	Stats *WorkerStats `json:"stats,omitempty"`
+	// Framing echoes the framing a ping switched to
+	Framing string `json:"framing,omitempty"`
+	// Hints are the hint result, best first
+	Hints []MoveHint `json:"hints,omitempty"`
+	// Observation is the encode result (see engine.EncodeObservation), and
+	// ActionMask marks the legal moves of the player to move in the genome's
+	// action space (see engine.ActionMask)
+	Observation []float32 `json:"observation,omitempty"`
+	ActionMask  []float32 `json:"action_mask,omitempty"`
+	// Advanced is how many forced moves advance_to_decision applied
+	Advanced int `json:"advanced,omitempty"`
+	// SelfPlay is the selfplay transcript, one step per ply
+	SelfPlay []SelfPlayStep `json:"selfplay,omitempty"`
+	// Fitness is the evaluate_fitness result
+	Fitness *FitnessReport `json:"fitness,omitempty"`
+	// ErrorKind categorizes genome failures for callers to branch on: one
+	// of the genomeError constants, empty for other errors. ErrorSection is
+	// the bytecode section a parse failure was in (see engine.ParseError).
+	ErrorKind    string `json:"error_kind,omitempty"`
+	ErrorSection string `json:"error_section,omitempty"`
+}
+
+// SelfPlayStep is one ply of a selfplay game: the mover's observation and
+// legal action mask, the MCTS visit share of each action (the policy
+// target), the action played, and how the game ended for the mover (the
+// value target: 1 win, -1 loss, 0 no winner).
+type SelfPlayStep struct {
+	Player      int       `json:"player"`
+	Observation []float32 `json:"observation"`
+	ActionMask  []float32 `json:"action_mask"`
+	Policy      []float32 `json:"policy"`
+	Action      int       `json:"action"`
+	Value       float32   `json:"value"`
+}
+
+// FitnessReport is the evaluate_fitness result: a batch of self-play games
+// summed up for the evolutionary loop. Rates and averages are over the games
+// that finished without an error.
+type FitnessReport struct {
+	Games  int  `json:"games"`
+	Errors int  `json:"errors"` // Games that crashed
+	Valid  bool `json:"valid"`  // No game crashed
+
+	// Balance: how fair the seats are
+	SeatWinRates         []float64 `json:"seat_win_rates"` // Share of games each seat won
+	DrawRate             float64   `json:"draw_rate"`
+	FirstPlayerAdvantage float64   `json:"first_player_advantage"` // First player's win rate minus the next player's
+	Balance              float64   `json:"balance"`                // 1 minus the spread of seat win rates
+
+	// Length
+	AvgTurns    float64 `json:"avg_turns"`
+	MedianTurns int     `json:"median_turns"`
+
+	// Tension
+	AvgLeadChanges  float64 `json:"avg_lead_changes"`
+	DecisiveTurnPct float64 `json:"decisive_turn_pct"`
+	ClosestMargin   float64 `json:"closest_margin"`
+	ComebackRate    float64 `json:"comeback_rate"` // Wins by a player trailing at the midpoint
+
+	// Decisions
+	AvgBranching    float64 `json:"avg_branching"` // Legal moves per decision
+	ForcedRate      float64 `json:"forced_rate"`   // Decisions with one legal move
+	InteractionRate float64 `json:"interaction_rate"`
+}
+
+// MoveHint is a legal move ranked by the AI. Score is the MCTS win rate
+// (0-1) for ai_type "mcts", otherwise the greedy heuristic score.
+type MoveHint struct {
+	MoveInfo
+	Score  float64 `json:"score"`
+	Visits int     `json:"visits,omitempty"` // MCTS visits behind the win rate
+}
+
+// WorkerStats reports worker internals for monitoring a pool of workers.
+type WorkerStats struct {
+	UptimeSeconds float64 `json:"uptime_seconds"`
+	Commands      uint64  `json:"commands"`    // Commands handled, including this one
+	PoolHits      uint64  `json:"pool_hits"`   // GameStates reused from the pool
+	PoolMisses    uint64  `json:"pool_misses"` // GameStates the pool had to allocate
+	HeapAlloc     uint64  `json:"heap_alloc"`  // Bytes of live heap objects
+	HeapSys       uint64  `json:"heap_sys"`    // Bytes of heap obtained from the OS
+	NumGC         uint32  `json:"num_gc"`
+}
+
+// TraceEntry is an engine.TraceEvent in JSON form.
+type TraceEntry struct {
+	Kind   string `json:"kind"` // "move", "effect", "trick", "bet" or "win"
+	Turn   int    `json:"turn"`
+	Player int    `json:"player"`
+	Detail string `json:"detail"`           // Move label, effect name, winning card, bet or end reason
+	Amount int64  `json:"amount,omitempty"` // Chips bet
+}
+
+// GenomeSummary is engine.GenomeSummary in JSON form.
+type GenomeSummary struct {
+	PlayerCount   int      `json:"player_count"`
+	MaxTurns      int      `json:"max_turns"`
+	Phases        []string `json:"phases"`
+	WinConditions []string `json:"win_conditions"`
+	TableauMode   string   `json:"tableau_mode"`
+	Teams         int      `json:"teams,omitempty"`
+	UsesBetting   bool     `json:"uses_betting"`
+	UsesTricks    bool     `json:"uses_tricks"`
+	UsesBidding   bool     `json:"uses_bidding"`
+	EffectRanks   []int    `json:"effect_ranks"`
+	Text          string   `json:"text"` // One-line form for logs
 }
 
 // MoveInfo describes a legal move for the human player.
@@ -49,24 +201,51 @@ type MoveInfo struct {
 type SerializedState struct {
 	Players       []SerializedPlayer `json:"players"`
 	Deck          []SerializedCard   `json:"deck"`
+	DeckCount     int                `json:"deck_count"`
 	Discard       []SerializedCard   `json:"discard"`
 	Tableau       [][]SerializedCard `json:"tableau"`
+	Community     []SerializedCard   `json:"community,omitempty"`
+	Kitty         []SerializedCard   `json:"kitty,omitempty"`
+	KittyFaceUp   bool               `json:"kitty_face_up,omitempty"`
 	CurrentPlayer int                `json:"current_player"`
+	CurrentPhase  int                `json:"current_phase,omitempty"`
 	TurnNumber    int                `json:"turn_number"`
 	WinnerID      int                `json:"winner_id"`
 	NumPlayers    int                `json:"num_players"`
 	// Betting state
 	Pot             int64 `json:"pot"`
+	CarryoverPot    int64 `json:"carryover_pot,omitempty"`
 	CurrentBet      int64 `json:"current_bet"`
 	BettingComplete bool  `json:"betting_complete"`
+	DealRound       int   `json:"deal_round,omitempty"`
+	BettingRound    int   `json:"betting_round,omitempty"`
 	// Trick-taking state
 	CurrentTrick []SerializedTrickCard `json:"current_trick,omitempty"`
 	TrickLeader  int                   `json:"trick_leader"`
 	TricksWon    []int                 `json:"tricks_won,omitempty"`
 	HeartsBroken bool                  `json:"hearts_broken"`
+	// Simultaneous phase state: this round's face-down plays, and the
+	// cards of tied rounds waiting for the next winner
+	Committed []SerializedTrickCard `json:"committed,omitempty"`
+	CommitPot []SerializedCard      `json:"commit_pot,omitempty"`
+	// SeenCards lists the cards shown to the table this hand (see engine.GameState.SeenCards)
+	SeenCards []SerializedCard `json:"seen_cards,omitempty"`
 	// Tableau mode
-	TableauMode       int `json:"tableau_mode"`
-	SequenceDirection int `json:"sequence_direction"`
+	TableauMode        int  `json:"tableau_mode"`
+	SequenceDirection  int  `json:"sequence_direction"`
+	AceLow             bool `json:"ace_low,omitempty"`
+	WarToBottom        bool `json:"war_to_bottom,omitempty"`
+	PerfectInformation bool `json:"perfect_information,omitempty"`
+	// Suit precedence per suit breaking rank ties (omitted when ranks alone decide)
+	SuitOrder []int `json:"suit_order,omitempty"`
+	// Turn order: 1 = clockwise, -1 = counter-clockwise (0 from older clients means 1)
+	PlayDirection int `json:"play_direction"`
+	// Eliminated players, in the order they went out
+	EliminationOrder []int `json:"elimination_order,omitempty"`
+	// StartingPlayer is the player who started the current hand
+	StartingPlayer int `json:"starting_player,omitempty"`
+	// Knocker is the player who knocked to end a Gin Rummy hand
+	Knocker *int `json:"knocker,omitempty"`
 }
 
 // SerializedPlayer holds player state in JSON format.
@@ -76,16 +255,30 @@ type SerializedPlayer struct {
 	Active     bool             `json:"active"`
 	Chips      int64            `json:"chips"`
 	CurrentBet int64            `json:"current_bet"`
+	TotalBet   int64            `json:"total_bet,omitempty"`
 	HasFolded  bool             `json:"has_folded"`
 	IsAllIn    bool             `json:"is_all_in"`
+	Captured   []SerializedCard `json:"captured,omitempty"`
+	Sweeps     int              `json:"sweeps,omitempty"`
+	// TrickPoints is the point-card value taken in tricks this hand
+	TrickPoints int32 `json:"trick_points,omitempty"`
+	// FaceUp parallels Hand: true where the card is visible to opponents.
+	// Omitted when the whole hand is face-down.
+	FaceUp []bool `json:"face_up,omitempty"`
 }
 
 // SerializedCard holds a card in JSON format.
 type SerializedCard struct {
-	Rank int `json:"rank"` // 0-12 (2-A)
-	Suit int `json:"suit"` // 0-3 (H,D,C,S)
+	Rank int `json:"rank"` // 0-12 (2-A), or hiddenCardValue
+	Suit int `json:"suit"` // 0-3 (H,D,C,S), or hiddenCardValue
 }
 
+// hiddenCardValue is the rank and suit of a redacted card placeholder
+const hiddenCardValue = -1
+
+// hiddenCard stands in for a card the viewer is not allowed to see
+var hiddenCard = SerializedCard{Rank: hiddenCardValue, Suit: hiddenCardValue}
+
 // SerializedTrickCard holds a card played to the current trick.
 type SerializedTrickCard struct {
 	PlayerID int            `json:"player_id"`
@@ -93,51 +286,201 @@ type SerializedTrickCard struct {
 }
 
 // Global state for the current game session
+// Only touched from the main command loop, which handles one request at a time
 var (
 	currentGenome *engine.Genome
 	currentState  *engine.GameState
+	// currentRand drives random AI moves; seeded from start_game's seed
+	currentRand *rand.Rand
 )
 
+// Worker counters reported by stats
+var (
+	startTime       = time.Now()
+	commandsHandled atomic.Uint64
+)
+
+// maxCommandBytes caps a single command line. Longer lines are skipped with
+// an error response instead of being buffered.
+const maxCommandBytes = 64 * 1024 * 1024 // 64MB
+
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	// Increase buffer size for large states/genomes
-	buf := make([]byte, 1024*1024) // 1MB
-	scanner.Buffer(buf, len(buf))
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
+	if err := serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "worker session ended: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Framing modes, chosen with ping's framing field. Lines is the default;
+// length_prefixed frames each command and response as a 4-byte big-endian
+// length followed by the JSON payload, for high-volume batch streaming.
+const (
+	framingLines          = "lines"
+	framingLengthPrefixed = "length_prefixed"
+)
+
+// serve reads commands from in and writes one response to out per command
+// until in is exhausted. Each response is flushed as soon as it is complete;
+// a failed write ends the session with the write error.
+func serve(in io.Reader, out io.Writer) error {
+	c := &conn{
+		reader: bufio.NewReaderSize(in, 64*1024),
+		writer: bufio.NewWriterSize(out, 64*1024),
+	}
+
+	for {
+		payload, err := c.readCommand()
+		if errors.Is(err, errCommandTooLong) {
+			if writeErr := c.write(&Response{Success: false, Error: fmt.Sprintf("command exceeds %d bytes", maxCommandBytes)}); writeErr != nil {
+				return writeErr
+			}
 			continue
 		}
+		if len(payload) > 0 {
+			var resp *Response
+			var cmd Command
+			if jsonErr := json.Unmarshal(payload, &cmd); jsonErr != nil {
+				resp = &Response{Success: false, Error: fmt.Sprintf("invalid JSON: %v", jsonErr)}
+			} else {
+				resp = handleCommand(&cmd)
+			}
+			if writeErr := c.write(resp); writeErr != nil {
+				return writeErr
+			}
+			// The handshake reply goes out in the old framing; the switch
+			// applies from the next command on
+			if cmd.Action == "ping" && resp.Success && cmd.Framing != "" {
+				c.framed = cmd.Framing == framingLengthPrefixed
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
 
-		var cmd Command
-		if err := json.Unmarshal([]byte(line), &cmd); err != nil {
-			writeError(fmt.Sprintf("invalid JSON: %v", err))
-			continue
+// conn is the worker's end of a session in its current framing
+type conn struct {
+	reader *bufio.Reader
+	writer *bufio.Writer
+	framed bool // Length-prefixed rather than newline-delimited
+}
+
+// readCommand reads the next command payload in the current framing
+func (c *conn) readCommand() ([]byte, error) {
+	if !c.framed {
+		return readCommandLine(c.reader, maxCommandBytes)
+	}
+
+	var header [4]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("truncated frame header: %w", err)
+		}
+		return nil, err
+	}
+	size := int64(binary.BigEndian.Uint32(header[:]))
+	if size > maxCommandBytes {
+		if _, err := io.CopyN(io.Discard, c.reader, size); err != nil {
+			return nil, fmt.Errorf("truncated frame: %w", err)
 		}
+		return nil, errCommandTooLong
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return nil, fmt.Errorf("truncated frame: %w", err)
+	}
+	return payload, nil
+}
 
-		resp := handleCommand(&cmd)
-		writeResponse(resp)
+// write sends resp in the current framing and flushes it
+func (c *conn) write(resp *Response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(&Response{Success: false, Error: fmt.Sprintf("failed to marshal response: %v", err)})
 	}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintf(os.Stderr, "error reading stdin: %v\n", err)
-		os.Exit(1)
+	if c.framed {
+		var header [4]byte
+		binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+		c.writer.Write(header[:])
+		c.writer.Write(data)
+	} else {
+		c.writer.Write(data)
+		c.writer.WriteByte('\n')
+	}
+	// bufio.Writer keeps the first error, so Flush reports any of them
+	return c.writer.Flush()
+}
+
+// errCommandTooLong reports a command line longer than the limit
+var errCommandTooLong = errors.New("command too long")
+
+// readCommandLine reads one line without its trailing newline, growing the
+// buffer as needed. A line over limit bytes is discarded up to its newline
+// and reported as errCommandTooLong. At end of input it returns the final
+// unterminated line, if any, along with io.EOF.
+func readCommandLine(reader *bufio.Reader, limit int) ([]byte, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if !tooLong {
+			if len(line)+len(bytes.TrimSuffix(chunk, []byte("\n"))) > limit {
+				tooLong, line = true, nil
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if tooLong {
+			if err == nil || err == io.EOF {
+				return nil, errCommandTooLong
+			}
+			return nil, err
+		}
+		return bytes.TrimRight(line, "\r\n"), err
 	}
 }
 
 func handleCommand(cmd *Command) *Response {
+	commandsHandled.Add(1)
 	switch cmd.Action {
 	case "ping":
-		return handlePing()
+		return handlePing(cmd)
+	case "stats":
+		return handleStats()
+	case "reset":
+		return handleReset()
 	case "start_game":
 		return handleStartGame(cmd)
 	case "apply_move":
 		return handleApplyMove(cmd)
+	case "advance_to_decision":
+		return handleAdvanceToDecision(cmd)
 	case "validate_genome":
 		return handleValidateGenome(cmd)
 	case "get_ai_move":
 		return handleGetAIMove(cmd)
+	case "check_move":
+		return handleCheckMove(cmd)
+	case "hint":
+		return handleHint(cmd)
+	case "legal_moves":
+		return handleLegalMoves(cmd)
+	case "encode":
+		return handleEncode(cmd)
+	case "selfplay":
+		return handleSelfPlay(cmd)
+	case "describe_genome":
+		return handleDescribeGenome(cmd)
+	case "evaluate_fitness":
+		return handleEvaluateFitness(cmd)
 	default:
 		return &Response{
 			Success: false,
@@ -146,110 +489,141 @@ func handleCommand(cmd *Command) *Response {
 	}
 }
 
-// handlePing is a health check that returns success.
-func handlePing() *Response {
+// handlePing is a health check that returns success. It doubles as the
+// framing handshake: a framing field switches the session's framing for
+// every later command and response (see serve).
+func handlePing(cmd *Command) *Response {
+	switch cmd.Framing {
+	case "", framingLines, framingLengthPrefixed:
+		return &Response{Success: true, Framing: cmd.Framing}
+	default:
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("unknown framing: %s", cmd.Framing),
+		}
+	}
+}
+
+// handleReset ends the game in progress, if any, so later commands that need
+// one fail until the next start_game.
+func handleReset() *Response {
+	if currentState != nil {
+		engine.PutState(currentState)
+	}
+	currentGenome = nil
+	currentState = nil
+	currentRand = nil
 	return &Response{Success: true}
 }
 
-// handleStartGame initializes a new game from genome bytecode.
-func handleStartGame(cmd *Command) *Response {
-	// Decode genome from base64
+// handleStats reports uptime, command count, GameState pool usage and memory.
+func handleStats() *Response {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	hits, misses := engine.PoolStats()
+	return &Response{
+		Success: true,
+		Stats: &WorkerStats{
+			UptimeSeconds: time.Since(startTime).Seconds(),
+			Commands:      commandsHandled.Load(),
+			PoolHits:      hits,
+			PoolMisses:    misses,
+			HeapAlloc:     mem.HeapAlloc,
+			HeapSys:       mem.HeapSys,
+			NumGC:         mem.NumGC,
+		},
+	}
+}
+
+// decodeGenome parses the base64 genome bytecode in cmd, returning an error
+// response if it is missing or invalid
+func decodeGenome(cmd *Command) (*engine.Genome, *Response) {
 	var genomeB64 string
 	if err := json.Unmarshal(cmd.Genome, &genomeB64); err != nil {
-		return &Response{
-			Success: false,
-			Error:   fmt.Sprintf("invalid genome field: %v", err),
+		return nil, &Response{
+			Success:   false,
+			Error:     fmt.Sprintf("invalid genome field: %v", err),
+			ErrorKind: genomeErrorEncoding,
 		}
 	}
 
 	bytecode, err := base64.StdEncoding.DecodeString(genomeB64)
 	if err != nil {
-		return &Response{
-			Success: false,
-			Error:   fmt.Sprintf("invalid base64 genome: %v", err),
+		return nil, &Response{
+			Success:   false,
+			Error:     fmt.Sprintf("invalid base64 genome: %v", err),
+			ErrorKind: genomeErrorEncoding,
 		}
 	}
 
-	// Parse genome from bytecode
 	genome, err := engine.ParseGenome(bytecode)
 	if err != nil {
-		return &Response{
-			Success: false,
-			Error:   fmt.Sprintf("failed to parse genome: %v", err),
+		resp := &Response{
+			Success:   false,
+			Error:     fmt.Sprintf("failed to parse genome: %v", err),
+			ErrorKind: genomeErrorKind(err),
 		}
+		var parseErr *engine.ParseError
+		if errors.As(err, &parseErr) {
+			resp.ErrorSection = parseErr.Section
+		}
+		return nil, resp
 	}
-	currentGenome = genome
-
-	// Initialize game state
-	state := engine.GetState()
-
-	// Setup deck
-	setupDeck(state, uint64(cmd.Seed))
-
-	// Read setup from genome
-	cardsPerPlayer := 26 // Default for War
-	initialDiscardCount := 0
-	startingChips := 0
-
-	if genome.Header.SetupOffset > 0 && genome.Header.SetupOffset+12 <= int32(len(genome.Bytecode)) {
-		setupOffset := genome.Header.SetupOffset
-		cardsPerPlayer = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset : setupOffset+4])))
-		initialDiscardCount = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset+4 : setupOffset+8])))
-		startingChips = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset+8 : setupOffset+12])))
-	}
-
-	// Number of players
-	numPlayers := int(genome.Header.PlayerCount)
-	if numPlayers == 0 || numPlayers > 4 {
-		numPlayers = 2
-	}
+	return genome, nil
+}
 
-	state.NumPlayers = uint8(numPlayers)
-	state.CardsPerPlayer = cardsPerPlayer
-	state.TableauMode = genome.Header.TableauMode
-	state.SequenceDirection = genome.Header.SequenceDirection
+// Response.ErrorKind values
+const (
+	genomeErrorEncoding          = "encoding"            // Not a base64 string
+	genomeErrorTruncated         = "truncated"           // Bytecode ends early
+	genomeErrorInvalidOffset     = "invalid_offset"      // A section offset is out of range
+	genomeErrorUnknownPhaseType  = "unknown_phase_type"  // Unsupported phase type
+	genomeErrorUnknownEvalMethod = "unknown_eval_method" // Unsupported hand evaluation
+	genomeErrorInvalid           = "invalid"             // Any other parse failure
+	genomeErrorCrash             = "crash"               // Parsed, but crashed in play
+)
 
-	// Initialize teams if configured
-	if genome.Header.TeamMode && genome.Header.TeamCount > 0 && genome.Header.TeamDataOffset > 0 {
-		teamDataOffset := genome.Header.TeamDataOffset
-		if teamDataOffset < len(genome.Bytecode) {
-			teams := engine.ParseTeams(genome.Bytecode[teamDataOffset:])
-			state.InitializeTeams(teams)
-		}
+// genomeErrorKind returns the ErrorKind of a ParseGenome error
+func genomeErrorKind(err error) string {
+	switch {
+	case errors.Is(err, engine.ErrTruncatedHeader), errors.Is(err, engine.ErrTruncated):
+		return genomeErrorTruncated
+	case errors.Is(err, engine.ErrInvalidOffset):
+		return genomeErrorInvalidOffset
+	case errors.Is(err, engine.ErrUnknownPhaseType):
+		return genomeErrorUnknownPhaseType
+	case errors.Is(err, engine.ErrUnknownEvalMethod):
+		return genomeErrorUnknownEvalMethod
 	}
+	return genomeErrorInvalid
+}
 
-	// Deal cards to each player
-	for i := 0; i < cardsPerPlayer; i++ {
-		for p := 0; p < numPlayers; p++ {
-			state.DrawCard(uint8(p), engine.LocationDeck)
-		}
+// handleStartGame initializes a new game from genome bytecode.
+func handleStartGame(cmd *Command) *Response {
+	genome, errResp := decodeGenome(cmd)
+	if errResp != nil {
+		return errResp
 	}
-
-	// Deal initial cards to discard/tableau
-	if initialDiscardCount > 0 && len(state.Deck) >= initialDiscardCount {
-		if state.TableauMode != 0 && len(state.Tableau) == 0 {
-			state.Tableau = make([][]engine.Card, 1)
-			state.Tableau[0] = make([]engine.Card, 0, initialDiscardCount)
-		}
-		for i := 0; i < initialDiscardCount; i++ {
-			if len(state.Deck) > 0 {
-				card := state.Deck[len(state.Deck)-1]
-				state.Deck = state.Deck[:len(state.Deck)-1]
-				if state.TableauMode != 0 {
-					state.Tableau[0] = append(state.Tableau[0], card)
-				} else {
-					state.Discard = append(state.Discard, card)
-				}
+	if cmd.StartingPlayer != nil {
+		if *cmd.StartingPlayer < 0 || *cmd.StartingPlayer >= engine.ReadSetupParams(genome).NumPlayers {
+			return &Response{
+				Success: false,
+				Error:   fmt.Sprintf("starting player %d out of range", *cmd.StartingPlayer),
 			}
 		}
+		genome.StartingPlayer = *cmd.StartingPlayer
 	}
+	currentGenome = genome
 
-	// Initialize chips
-	if startingChips > 0 {
-		state.InitializeChips(startingChips)
-	}
+	// Build the deck, deal, and seed chips/teams from the genome setup
+	state := engine.SetupGame(genome, uint64(cmd.Seed))
 
+	// The worker owns currentState; release the previous game's state
+	if currentState != nil {
+		engine.PutState(currentState)
+	}
 	currentState = state
+	currentRand = rand.New(rand.NewSource(cmd.Seed))
 
 	// Generate initial legal moves
 	moves := engine.GenerateLegalMoves(state, genome)
@@ -263,15 +637,24 @@ func handleStartGame(cmd *Command) *Response {
 			Error:   fmt.Sprintf("failed to serialize state: %v", err),
 		}
 	}
+	viewJSON, err := marshalView(cmd, state)
+	if err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to serialize view: %v", err),
+		}
+	}
 
 	// Check for immediate winner
-	winner := engine.CheckWinConditions(state, genome)
+	result := engine.CheckGameEnd(state, genome)
 
 	return &Response{
-		Success: true,
-		State:   stateJSON,
-		Moves:   moveInfos,
-		Winner:  int(winner),
+		Success:   true,
+		State:     stateJSON,
+		View:      viewJSON,
+		Moves:     moveInfos,
+		Winner:    int(result.Winner),
+		EndReason: endReasonLabel(result),
 	}
 }
 
@@ -285,15 +668,8 @@ func handleApplyMove(cmd *Command) *Response {
 	}
 
 	// Optionally load state from command (for stateless operation)
-	if cmd.State != nil && len(cmd.State) > 0 {
-		var serialized SerializedState
-		if err := json.Unmarshal(cmd.State, &serialized); err != nil {
-			return &Response{
-				Success: false,
-				Error:   fmt.Sprintf("invalid state: %v", err),
-			}
-		}
-		deserializeState(&serialized, currentState)
+	if errResp := loadCommandState(cmd); errResp != nil {
+		return errResp
 	}
 
 	// Generate legal moves and find the requested one
@@ -306,11 +682,16 @@ func handleApplyMove(cmd *Command) *Response {
 	}
 
 	// Apply the move
+	var trace []TraceEntry
+	if cmd.Trace {
+		currentState.Trace = traceCollector(&trace, currentState, currentGenome)
+	}
 	move := &moves[cmd.MoveIndex]
 	engine.ApplyMove(currentState, move, currentGenome)
 
 	// Check for winner
-	winner := engine.CheckWinConditions(currentState, currentGenome)
+	result := engine.CheckGameEnd(currentState, currentGenome)
+	currentState.Trace = nil
 
 	// Generate new legal moves
 	newMoves := engine.GenerateLegalMoves(currentState, currentGenome)
@@ -324,13 +705,433 @@ func handleApplyMove(cmd *Command) *Response {
 			Error:   fmt.Sprintf("failed to serialize state: %v", err),
 		}
 	}
+	viewJSON, err := marshalView(cmd, currentState)
+	if err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to serialize view: %v", err),
+		}
+	}
+
+	return &Response{
+		Success:   true,
+		State:     stateJSON,
+		View:      viewJSON,
+		Moves:     moveInfos,
+		Winner:    int(result.Winner),
+		EndReason: endReasonLabel(result),
+		Trace:     trace,
+	}
+}
+
+// maxAdvanceMoves bounds how many forced moves advance_to_decision applies
+// in one command, for games that never end on their own
+const maxAdvanceMoves = 10000
+
+// handleAdvanceToDecision applies moves for as long as the player to move
+// has exactly one legal move, stopping at a real choice, the end of the
+// game or maxAdvanceMoves. Response.Advanced counts the moves applied and
+// Moves are the legal moves where it stopped.
+func handleAdvanceToDecision(cmd *Command) *Response {
+	if currentGenome == nil || currentState == nil {
+		return &Response{
+			Success: false,
+			Error:   "no game in progress - call start_game first",
+		}
+	}
+
+	if errResp := loadCommandState(cmd); errResp != nil {
+		return errResp
+	}
+
+	var trace []TraceEntry
+	if cmd.Trace {
+		currentState.Trace = traceCollector(&trace, currentState, currentGenome)
+	}
+	advanced := 0
+	result := engine.CheckGameEnd(currentState, currentGenome)
+	moves := engine.GenerateLegalMoves(currentState, currentGenome)
+	for !result.Over() && len(moves) == 1 && advanced < maxAdvanceMoves {
+		engine.ApplyMove(currentState, &moves[0], currentGenome)
+		advanced++
+		result = engine.CheckGameEnd(currentState, currentGenome)
+		moves = engine.GenerateLegalMoves(currentState, currentGenome)
+	}
+	currentState.Trace = nil
+
+	stateJSON, err := json.Marshal(serializeState(currentState))
+	if err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to serialize state: %v", err),
+		}
+	}
+	viewJSON, err := marshalView(cmd, currentState)
+	if err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("failed to serialize view: %v", err),
+		}
+	}
+
+	return &Response{
+		Success:   true,
+		State:     stateJSON,
+		View:      viewJSON,
+		Moves:     convertMoves(moves, currentState, currentGenome),
+		Winner:    int(result.Winner),
+		EndReason: endReasonLabel(result),
+		Trace:     trace,
+		Advanced:  advanced,
+	}
+}
+
+// effectNames labels special effect types in traces
+var effectNames = [...]string{
+	engine.EFFECT_SKIP_NEXT:     "skip_next",
+	engine.EFFECT_REVERSE:       "reverse",
+	engine.EFFECT_DRAW_CARDS:    "draw_cards",
+	engine.EFFECT_EXTRA_TURN:    "extra_turn",
+	engine.EFFECT_FORCE_DISCARD: "force_discard",
+}
+
+// bettingActionLabels labels betting actions the way describeMoveLabel does
+var bettingActionLabels = [...]string{
+	engine.BettingCheck: "Check",
+	engine.BettingBet:   "Bet",
+	engine.BettingCall:  "Call",
+	engine.BettingRaise: "Raise",
+	engine.BettingAllIn: "All In",
+	engine.BettingFold:  "Fold",
+}
+
+// traceCollector returns an engine.TraceFunc that appends each event to
+// entries. Move labels are read from state as the move is applied.
+func traceCollector(entries *[]TraceEntry, state *engine.GameState, genome *engine.Genome) engine.TraceFunc {
+	return func(e engine.TraceEvent) {
+		entry := TraceEntry{Kind: e.Kind.String(), Turn: int(e.Turn), Player: e.Player}
+		switch e.Kind {
+		case engine.TraceMove:
+			entry.Detail = describeMoveLabel(e.Move, state, genome)
+		case engine.TraceEffect:
+			entry.Detail = fmt.Sprintf("effect %d", e.Effect.EffectType)
+			if int(e.Effect.EffectType) < len(effectNames) {
+				entry.Detail = effectNames[e.Effect.EffectType]
+			}
+		case engine.TraceTrick:
+			entry.Detail = e.Card.Label()
+		case engine.TraceBet:
+			entry.Detail = fmt.Sprintf("action %d", e.Action)
+			if int(e.Action) < len(bettingActionLabels) {
+				entry.Detail = bettingActionLabels[e.Action]
+			}
+			entry.Amount = e.Amount
+		case engine.TraceWin:
+			entry.Detail = e.Reason.String()
+		}
+		*entries = append(*entries, entry)
+	}
+}
+
+// loadCommandState replaces currentState with the state sent in cmd, if
+// any. An invalid state is rejected and currentState is left untouched.
+func loadCommandState(cmd *Command) *Response {
+	if len(cmd.State) == 0 {
+		return nil
+	}
+	var serialized SerializedState
+	if err := json.Unmarshal(cmd.State, &serialized); err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid state: %v", err),
+		}
+	}
+
+	loaded := engine.GetState()
+	if err := deserializeState(&serialized, loaded); err != nil {
+		engine.PutState(loaded)
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid state: %v", err),
+		}
+	}
+	engine.PutState(currentState)
+	currentState = loaded
+	return nil
+}
+
+// loadPreviewState overwrites preview with the state sent in cmd, if any,
+// returning an error response if it is invalid
+func loadPreviewState(cmd *Command, preview *engine.GameState) *Response {
+	if len(cmd.State) == 0 {
+		return nil
+	}
+	var serialized SerializedState
+	if err := json.Unmarshal(cmd.State, &serialized); err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid state: %v", err),
+		}
+	}
+	if err := deserializeState(&serialized, preview); err != nil {
+		return &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid state: %v", err),
+		}
+	}
+	return nil
+}
+
+// handleLegalMoves lists the legal moves in the current game, or in the
+// state sent with the command, without changing the game.
+func handleLegalMoves(cmd *Command) *Response {
+	if currentGenome == nil || currentState == nil {
+		return &Response{
+			Success: false,
+			Error:   "no game in progress - call start_game first",
+		}
+	}
+
+	state := currentState.Clone()
+	defer engine.PutState(state)
+	if errResp := loadPreviewState(cmd, state); errResp != nil {
+		return errResp
+	}
+
+	result := engine.CheckGameEnd(state, currentGenome)
+	moves := engine.GenerateLegalMoves(state, currentGenome)
+	return &Response{
+		Success:   true,
+		Moves:     convertMoves(moves, state, currentGenome),
+		Winner:    int(result.Winner),
+		EndReason: endReasonLabel(result),
+	}
+}
+
+// handleEncode returns the feature vector of the current game, or of the
+// state sent with the command, as seen by Command.ViewerID (the player to
+// move when omitted), with the legal action mask. A viewer outside the game
+// sees no hand.
+func handleEncode(cmd *Command) *Response {
+	if currentGenome == nil || currentState == nil {
+		return &Response{
+			Success: false,
+			Error:   "no game in progress - call start_game first",
+		}
+	}
+
+	state := currentState.Clone()
+	defer engine.PutState(state)
+	if errResp := loadPreviewState(cmd, state); errResp != nil {
+		return errResp
+	}
+
+	viewerID := int(state.CurrentPlayer)
+	if cmd.ViewerID != nil {
+		viewerID = *cmd.ViewerID
+	}
+	return &Response{
+		Success:     true,
+		Observation: engine.EncodeObservation(state, currentGenome, viewerID),
+		ActionMask:  engine.ActionMask(state, currentGenome),
+	}
+}
+
+// maxSelfPlayPlies stops a selfplay game that never ends; its steps get value 0
+const maxSelfPlayPlies = 2000
+
+// handleSelfPlay plays a game of cmd.Genome from cmd.Seed with MCTS at every
+// seat and returns the transcript as training data. Each move is sampled in
+// proportion to the search's root visit counts, sharpened or flattened by
+// cmd.Temperature (1 when omitted). The game in progress, if
+// any, is left alone.
+func handleSelfPlay(cmd *Command) *Response {
+	genome, errResp := decodeGenome(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	iterations := cmd.MCTSIterations
+	if iterations <= 0 {
+		iterations = defaultMCTSIterations
+	}
+
+	temperature := 1.0
+	if cmd.Temperature != nil {
+		temperature = *cmd.Temperature
+	}
+
+	state := engine.SetupGame(genome, uint64(cmd.Seed))
+	defer engine.PutState(state)
+	rng := rand.New(rand.NewSource(cmd.Seed))
+
+	var steps []SelfPlayStep
+	result := engine.CheckGameEnd(state, genome)
+	for !result.Over() && len(steps) < maxSelfPlayPlies {
+		moves := engine.GenerateLegalMoves(state, genome)
+		move := moves[0]
+		visits := []mcts.MoveStat{{Move: move, Visits: 1}}
+		if len(moves) > 1 {
+			// Each search is seeded from rng (never 0, which means the global
+			// source), so cmd.Seed replays the game
+			params := mcts.SearchParams{
+				Iterations:       iterations,
+				ExplorationParam: cmd.ExplorationC,
+				Seed:             rng.Int63() | 1,
+				Determinize:      !state.PerfectInformation,
+			}
+			if _, stats := mcts.SearchStatsWithParams(state, genome, params); len(stats) > 0 {
+				visits = stats
+			}
+		}
+		counts := make([]int, len(visits))
+		for i, stat := range visits {
+			counts[i] = stat.Visits
+		}
+		policy := visitPolicy(visits, genome)
+		move = visits[mcts.SampleByVisits(counts, temperature, rng)].Move
+
+		player := int(state.CurrentPlayer)
+		steps = append(steps, SelfPlayStep{
+			Player:      player,
+			Observation: engine.EncodeObservation(state, genome, player),
+			ActionMask:  engine.ActionMask(state, genome),
+			Policy:      policy,
+			Action:      engine.MoveToActionIndex(move, genome),
+		})
+		engine.ApplyMove(state, &move, genome)
+		result = engine.CheckGameEnd(state, genome)
+	}
 
+	for i := range steps {
+		steps[i].Value = selfPlayValue(state, result, steps[i].Player)
+	}
+	return &Response{
+		Success:   true,
+		SelfPlay:  steps,
+		Winner:    int(result.Winner),
+		EndReason: endReasonLabel(result),
+	}
+}
+
+// visitPolicy spreads visits over genome's action space in proportion to
+// their counts. Moves without an action index are left out and the rest
+// renormalized, so the policy still sums to 1 when any move has one.
+func visitPolicy(visits []mcts.MoveStat, genome *engine.Genome) []float32 {
+	policy := make([]float32, engine.ActionSpaceSize(genome))
+	total := 0
+	for _, stat := range visits {
+		if engine.MoveToActionIndex(stat.Move, genome) >= 0 {
+			total += stat.Visits
+		}
+	}
+	if total == 0 {
+		return policy
+	}
+	for _, stat := range visits {
+		if i := engine.MoveToActionIndex(stat.Move, genome); i >= 0 {
+			policy[i] = float32(stat.Visits) / float32(total)
+		}
+	}
+	return policy
+}
+
+// selfPlayValue scores how a finished game went for player: 1 if they or
+// their team won, -1 if someone else did, 0 if nobody did
+func selfPlayValue(state *engine.GameState, result engine.GameResult, player int) float32 {
+	switch {
+	case result.Winner < 0:
+		return 0
+	case state.WinningTeam >= 0 && player < len(state.PlayerToTeam):
+		if state.PlayerToTeam[player] == state.WinningTeam {
+			return 1
+		}
+	case int(result.Winner) == player:
+		return 1
+	}
+	return -1
+}
+
+// defaultHintCount is how many moves hint returns when the command omits it.
+const defaultHintCount = 3
+
+// handleHint ranks the legal moves with the AI named by cmd.AIType and
+// returns the best cmd.HintCount of them, without changing the game. MCTS
+// ranks by win rate and only returns moves its search visited; any other
+// AI type ranks by the greedy heuristic.
+func handleHint(cmd *Command) *Response {
+	if currentGenome == nil || currentState == nil {
+		return &Response{
+			Success: false,
+			Error:   "no game in progress - call start_game first",
+		}
+	}
+
+	state := currentState.Clone()
+	defer engine.PutState(state)
+	if errResp := loadPreviewState(cmd, state); errResp != nil {
+		return errResp
+	}
+
+	moves := engine.GenerateLegalMoves(state, currentGenome)
+	if len(moves) == 0 {
+		return &Response{
+			Success: false,
+			Error:   "no legal moves available",
+		}
+	}
+	infos := convertMoves(moves, state, currentGenome)
+
+	hints := make([]MoveHint, 0, len(moves))
+	if cmd.AIType == "mcts" {
+		iterations := cmd.MCTSIterations
+		if iterations <= 0 {
+			iterations = defaultMCTSIterations
+		}
+		_, stats := mcts.SearchWithStats(state, currentGenome, iterations, cmd.ExplorationC)
+		for _, stat := range stats {
+			for i := range moves {
+				if moves[i] == stat.Move {
+					hints = append(hints, MoveHint{MoveInfo: infos[i], Score: stat.WinRate, Visits: stat.Visits})
+					break
+				}
+			}
+		}
+	} else {
+		for i := range moves {
+			hints = append(hints, MoveHint{MoveInfo: infos[i], Score: scoreMove(state, &moves[i])})
+		}
+	}
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].Score > hints[j].Score
+	})
+
+	count := cmd.HintCount
+	if count <= 0 {
+		count = defaultHintCount
+	}
+	if count < len(hints) {
+		hints = hints[:count]
+	}
 	return &Response{
 		Success: true,
-		State:   stateJSON,
-		Moves:   moveInfos,
-		Winner:  int(winner),
+		Hints:   hints,
+	}
+}
+
+// marshalView returns the redacted state for cmd.ViewerID, or nil if no viewer was requested
+func marshalView(cmd *Command, state *engine.GameState) (json.RawMessage, error) {
+	if cmd.ViewerID == nil {
+		return nil, nil
+	}
+	return json.Marshal(serializeStateFor(state, *cmd.ViewerID))
+}
+
+// endReasonLabel returns the reason a finished game ended, or "" while it is in progress
+func endReasonLabel(result engine.GameResult) string {
+	if !result.Over() {
+		return ""
 	}
+	return result.Reason.String()
 }
 
 // handleGetAIMove selects a move using the specified AI type.
@@ -343,15 +1144,8 @@ func handleGetAIMove(cmd *Command) *Response {
 	}
 
 	// Optionally load state from command
-	if cmd.State != nil && len(cmd.State) > 0 {
-		var serialized SerializedState
-		if err := json.Unmarshal(cmd.State, &serialized); err != nil {
-			return &Response{
-				Success: false,
-				Error:   fmt.Sprintf("invalid state: %v", err),
-			}
-		}
-		deserializeState(&serialized, currentState)
+	if errResp := loadCommandState(cmd); errResp != nil {
+		return errResp
 	}
 
 	// Generate legal moves
@@ -368,10 +1162,16 @@ func handleGetAIMove(cmd *Command) *Response {
 	switch cmd.AIType {
 	case "greedy":
 		moveIdx = selectGreedyMoveIndex(currentState, currentGenome, moves)
+	case "mcts":
+		temperature := 0.0
+		if cmd.Temperature != nil {
+			temperature = *cmd.Temperature
+		}
+		moveIdx = selectMCTSMoveIndex(currentState, currentGenome, moves, cmd.MCTSIterations, cmd.ExplorationC, temperature, aiRand(cmd))
 	case "random":
 		fallthrough
 	default:
-		moveIdx = rand.Intn(len(moves))
+		moveIdx = aiRand(cmd).Intn(len(moves))
 	}
 
 	// Get move info
@@ -385,34 +1185,112 @@ func handleGetAIMove(cmd *Command) *Response {
 	}
 }
 
-// handleValidateGenome runs 5 random games to check for crashes.
-func handleValidateGenome(cmd *Command) *Response {
-	// Decode genome from base64
-	var genomeB64 string
-	if err := json.Unmarshal(cmd.Genome, &genomeB64); err != nil {
+// handleCheckMove reports whether the (phase, card) move in cmd is currently
+// legal and previews its result without touching currentState. If several
+// legal moves share the phase and card index, the first is used.
+func handleCheckMove(cmd *Command) *Response {
+	if currentGenome == nil || currentState == nil {
 		return &Response{
 			Success: false,
-			Error:   fmt.Sprintf("invalid genome field: %v", err),
+			Error:   "no game in progress - call start_game first",
 		}
 	}
 
-	bytecode, err := base64.StdEncoding.DecodeString(genomeB64)
+	// Work on a copy so the check never changes the game
+	preview := currentState.Clone()
+	defer engine.PutState(preview)
+
+	// Optionally check against the state from the command
+	if errResp := loadPreviewState(cmd, preview); errResp != nil {
+		return errResp
+	}
+
+	moves := engine.GenerateLegalMoves(preview, currentGenome)
+	moveIdx := -1
+	for i, move := range moves {
+		if move.PhaseIndex == cmd.PhaseIndex && move.CardIndex == cmd.CardIndex {
+			moveIdx = i
+			break
+		}
+	}
+	if moveIdx < 0 {
+		return &Response{Success: true, Legal: false}
+	}
+
+	moveInfo := convertMoves(moves[moveIdx:moveIdx+1], preview, currentGenome)[0]
+	moveInfo.Index = moveIdx
+	engine.ApplyMove(preview, &moves[moveIdx], currentGenome)
+
+	previewJSON, err := json.Marshal(serializeState(preview))
 	if err != nil {
 		return &Response{
 			Success: false,
-			Error:   fmt.Sprintf("invalid base64 genome: %v", err),
+			Error:   fmt.Sprintf("failed to serialize state: %v", err),
 		}
 	}
-
-	// Parse genome
-	genome, err := engine.ParseGenome(bytecode)
+	viewJSON, err := marshalView(cmd, preview)
 	if err != nil {
 		return &Response{
 			Success: false,
-			Error:   fmt.Sprintf("failed to parse genome: %v", err),
+			Error:   fmt.Sprintf("failed to serialize view: %v", err),
 		}
 	}
 
+	return &Response{
+		Success: true,
+		Legal:   true,
+		Move:    &moveInfo,
+		Preview: previewJSON,
+		View:    viewJSON,
+	}
+}
+
+// aiRand returns the random source for an AI move: a fresh source when the
+// command carries its own seed (stateless callers), else the game's source
+func aiRand(cmd *Command) *rand.Rand {
+	if cmd.Seed != 0 || currentRand == nil {
+		return rand.New(rand.NewSource(cmd.Seed))
+	}
+	return currentRand
+}
+
+// handleDescribeGenome summarizes a genome without running it.
+func handleDescribeGenome(cmd *Command) *Response {
+	genome, errResp := decodeGenome(cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	summary := genome.Summary()
+	effectRanks := make([]int, len(summary.EffectRanks))
+	for i, rank := range summary.EffectRanks {
+		effectRanks[i] = int(rank)
+	}
+	return &Response{
+		Success: true,
+		Summary: &GenomeSummary{
+			PlayerCount:   summary.PlayerCount,
+			MaxTurns:      summary.MaxTurns,
+			Phases:        summary.Phases,
+			WinConditions: summary.WinConditions,
+			TableauMode:   summary.TableauMode,
+			Teams:         summary.Teams,
+			UsesBetting:   summary.UsesBetting,
+			UsesTricks:    summary.UsesTricks,
+			UsesBidding:   summary.UsesBidding,
+			EffectRanks:   effectRanks,
+			Text:          summary.String(),
+		},
+	}
+}
+
+// handleValidateGenome runs 5 random games to check for crashes.
+func handleValidateGenome(cmd *Command) *Response {
+	genome, errResp := decodeGenome(cmd)
+	if errResp != nil {
+		return errResp
+	}
+
 	// Run 5 games with random AI
 	seed := uint64(cmd.Seed)
 	if seed == 0 {
@@ -423,23 +1301,114 @@ func handleValidateGenome(cmd *Command) *Response {
 
 	// Check for errors
 	if stats.Errors > 0 {
+		first := stats.ErrorDetails[0]
 		return &Response{
 			Success: false,
-			Error:   fmt.Sprintf("genome crashed in %d of 5 games", stats.Errors),
+			Error: fmt.Sprintf("genome crashed in %d of 5 games (first: game %d, seed %d: %s)",
+				stats.Errors, first.Game, first.Seed, first.Message),
+			ErrorKind: genomeErrorCrash,
 		}
 	}
 
 	return &Response{Success: true}
 }
 
-// setupDeck creates and shuffles a standard 52-card deck.
-func setupDeck(state *engine.GameState, seed uint64) {
-	for suit := uint8(0); suit < 4; suit++ {
-		for rank := uint8(0); rank < 13; rank++ {
-			state.Deck = append(state.Deck, engine.Card{Rank: rank, Suit: suit})
+// defaultFitnessGames is how many games evaluate_fitness plays when the
+// command omits it.
+const defaultFitnessGames = 100
+
+// handleEvaluateFitness plays cmd.Games games of cmd.Genome from cmd.Seed with
+// the AI named by cmd.AIType at every seat (random when omitted) and reports
+// balance, length, tension, decision richness and crashes in one response.
+// MCTS uses the smallest simulation preset with at least cmd.MCTSIterations
+// iterations.
+func handleEvaluateFitness(cmd *Command) *Response {
+	genome, errResp := decodeGenome(cmd)
+	if errResp != nil {
+		return errResp
+	}
+	games := cmd.Games
+	if games <= 0 {
+		games = defaultFitnessGames
+	}
+	seed := uint64(cmd.Seed)
+	if seed == 0 {
+		seed = 12345
+	}
+
+	stats := simulation.RunBatch(genome, games, fitnessAIType(cmd), 0, seed)
+	return &Response{
+		Success: true,
+		Fitness: fitnessReport(stats, genome),
+	}
+}
+
+// fitnessAIType maps cmd.AIType to a simulation AI
+func fitnessAIType(cmd *Command) simulation.AIPlayerType {
+	switch cmd.AIType {
+	case "greedy":
+		return simulation.GreedyAI
+	case "mcts":
+		iterations := cmd.MCTSIterations
+		if iterations <= 0 {
+			iterations = defaultMCTSIterations
+		}
+		switch {
+		case iterations <= 100:
+			return simulation.MCTS100AI
+		case iterations <= 500:
+			return simulation.MCTS500AI
+		case iterations <= 1000:
+			return simulation.MCTS1000AI
 		}
+		return simulation.MCTS2000AI
+	}
+	return simulation.RandomAI
+}
+
+// fitnessReport sums up a batch of games of genome
+func fitnessReport(stats simulation.AggregatedStats, genome *engine.Genome) *FitnessReport {
+	report := &FitnessReport{
+		Games:       int(stats.TotalGames),
+		Errors:      int(stats.Errors),
+		Valid:       stats.Errors == 0,
+		AvgTurns:    float64(stats.AvgTurns),
+		MedianTurns: int(stats.MedianTurns),
+	}
+	players := engine.ReadSetupParams(genome).NumPlayers
+	if players > len(stats.Wins) {
+		players = len(stats.Wins)
+	}
+	report.SeatWinRates = make([]float64, players)
+
+	finished := float64(stats.TotalGames - stats.Errors)
+	if finished == 0 {
+		return report
+	}
+	lowest, highest := 1.0, 0.0
+	for seat := range report.SeatWinRates {
+		rate := float64(stats.Wins[seat]) / finished
+		report.SeatWinRates[seat] = rate
+		lowest = math.Min(lowest, rate)
+		highest = math.Max(highest, rate)
+	}
+	report.DrawRate = float64(stats.Draws) / finished
+	report.FirstPlayerAdvantage = simulation.MeasureSeatAdvantage(genome, stats).Delta
+	report.Balance = 1 - (highest - lowest)
+
+	report.AvgLeadChanges = float64(stats.LeadChanges) / finished
+	report.DecisiveTurnPct = float64(stats.DecisiveTurnPct)
+	report.ClosestMargin = float64(stats.ClosestMargin)
+	report.ComebackRate = float64(stats.TrailingWinners) / finished
+
+	if stats.TotalDecisions > 0 {
+		report.AvgBranching = float64(stats.TotalValidMoves) / float64(stats.TotalDecisions)
+		report.ForcedRate = float64(stats.ForcedDecisions) / float64(stats.TotalDecisions)
 	}
-	state.ShuffleDeck(seed)
+	if stats.TotalActions > 0 {
+		report.InteractionRate = float64(stats.TotalInteractions) / float64(stats.TotalActions)
+	}
+	return report
 }
 
 // convertMoves converts engine.LegalMove to MoveInfo for JSON.
@@ -465,6 +1434,11 @@ func describeMoveLabel(move engine.LegalMove, state *engine.GameState, genome *e
 	phase := genome.TurnPhases[move.PhaseIndex]
 	currentPlayer := state.CurrentPlayer
 
+	if index := engine.MoveForcedDiscardOffset - move.CardIndex; index >= 0 && index < len(state.Players[currentPlayer].Hand) {
+		card := state.Players[currentPlayer].Hand[index]
+		return fmt.Sprintf("Discard %s (hand limit)", card.Label())
+	}
+
 	switch phase.PhaseType {
 	case engine.PhaseTypeDraw:
 		if move.CardIndex == engine.MoveDraw {
@@ -472,6 +1446,9 @@ func describeMoveLabel(move engine.LegalMove, state *engine.GameState, genome *e
 		} else if move.CardIndex == engine.MoveDrawPass {
 			return "Stand"
 		}
+		if index := engine.MoveDrawAtOffset - move.CardIndex; index >= 0 && index < len(state.Discard) {
+			return fmt.Sprintf("Take %s", state.Discard[index].Label())
+		}
 		return "Draw"
 
 	case engine.PhaseTypePlay:
@@ -480,25 +1457,29 @@ func describeMoveLabel(move engine.LegalMove, state *engine.GameState, genome *e
 		}
 		if move.CardIndex >= 0 && move.CardIndex < len(state.Players[currentPlayer].Hand) {
 			card := state.Players[currentPlayer].Hand[move.CardIndex]
-			return fmt.Sprintf("Play %s", cardName(card))
+			return fmt.Sprintf("Play %s", card.Label())
 		}
 		if move.CardIndex <= -100 {
 			rank := uint8(-(move.CardIndex + 100))
-			return fmt.Sprintf("Play set of %s", rankName(rank))
+			return fmt.Sprintf("Play set of %s", engine.GameRank(rank))
 		}
 		return "Play"
 
 	case engine.PhaseTypeDiscard:
 		if move.CardIndex >= 0 && move.CardIndex < len(state.Players[currentPlayer].Hand) {
 			card := state.Players[currentPlayer].Hand[move.CardIndex]
-			return fmt.Sprintf("Discard %s", cardName(card))
+			return fmt.Sprintf("Discard %s", card.Label())
+		}
+		if index := engine.MoveKnockOffset - move.CardIndex; index >= 0 && index < len(state.Players[currentPlayer].Hand) {
+			card := state.Players[currentPlayer].Hand[index]
+			return fmt.Sprintf("Discard %s and knock", card.Label())
 		}
 		return "Discard"
 
 	case engine.PhaseTypeTrick:
 		if move.CardIndex >= 0 && move.CardIndex < len(state.Players[currentPlayer].Hand) {
 			card := state.Players[currentPlayer].Hand[move.CardIndex]
-			return fmt.Sprintf("Play %s", cardName(card))
+			return fmt.Sprintf("Play %s", card.Label())
 		}
 		return "Play to trick"
 
@@ -527,7 +1508,7 @@ func describeMoveLabel(move engine.LegalMove, state *engine.GameState, genome *e
 		}
 		if move.CardIndex >= 0 && move.CardIndex < len(state.Players[currentPlayer].Hand) {
 			claimedRank := uint8(state.TurnNumber % 13)
-			return fmt.Sprintf("Claim %s", rankName(claimedRank))
+			return fmt.Sprintf("Claim %s", engine.GameRank(claimedRank))
 		}
 		return "Claim"
 
@@ -540,6 +1521,19 @@ func describeMoveLabel(move engine.LegalMove, state *engine.GameState, genome *e
 			return fmt.Sprintf("Bid %d", bidValue)
 		}
 		return "Bid"
+
+	case engine.PhaseTypeReveal:
+		return "Reveal"
+
+	case engine.PhaseTypeAction:
+		return "Continue"
+
+	case engine.PhaseTypeSimultaneous:
+		if move.CardIndex >= 0 && move.CardIndex < len(state.Players[currentPlayer].Hand) {
+			card := state.Players[currentPlayer].Hand[move.CardIndex]
+			return fmt.Sprintf("Commit %s", card.Label())
+		}
+		return "Commit"
 	}
 
 	return "Unknown"
@@ -550,6 +1544,9 @@ func describeMoveType(move engine.LegalMove, genome *engine.Genome) string {
 	if move.PhaseIndex >= len(genome.TurnPhases) {
 		return "unknown"
 	}
+	if move.CardIndex <= engine.MoveForcedDiscardOffset {
+		return "discard"
+	}
 
 	phase := genome.TurnPhases[move.PhaseIndex]
 	switch phase.PhaseType {
@@ -567,47 +1564,44 @@ func describeMoveType(move engine.LegalMove, genome *engine.Genome) string {
 		return "claim"
 	case engine.PhaseTypeBidding:
 		return "bidding"
+	case engine.PhaseTypeReveal:
+		return "reveal"
+	case engine.PhaseTypeAction:
+		return "action"
+	case engine.PhaseTypeSimultaneous:
+		return "simultaneous"
 	}
 	return "unknown"
 }
 
-// cardName returns a human-readable card name.
-func cardName(card engine.Card) string {
-	return fmt.Sprintf("%s%s", rankName(card.Rank), suitName(card.Suit))
-}
-
-// rankName returns the rank as a string.
-func rankName(rank uint8) string {
-	ranks := []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K", "A"}
-	if int(rank) < len(ranks) {
-		return ranks[rank]
-	}
-	return "?"
-}
-
-// suitName returns the suit as a symbol.
-func suitName(suit uint8) string {
-	suits := []string{"♥", "♦", "♣", "♠"}
-	if int(suit) < len(suits) {
-		return suits[suit]
-	}
-	return "?"
-}
-
 // serializeState converts GameState to SerializedState for JSON.
 func serializeState(state *engine.GameState) *SerializedState {
 	s := &SerializedState{
-		CurrentPlayer:     int(state.CurrentPlayer),
-		TurnNumber:        int(state.TurnNumber),
-		WinnerID:          int(state.WinnerID),
-		NumPlayers:        int(state.NumPlayers),
-		Pot:               state.Pot,
-		CurrentBet:        state.CurrentBet,
-		BettingComplete:   state.BettingComplete,
-		TrickLeader:       int(state.TrickLeader),
-		HeartsBroken:      state.HeartsBroken,
-		TableauMode:       int(state.TableauMode),
-		SequenceDirection: int(state.SequenceDirection),
+		CurrentPlayer:      int(state.CurrentPlayer),
+		CurrentPhase:       state.CurrentPhase,
+		StartingPlayer:     int(state.StartingPlayer),
+		TurnNumber:         int(state.TurnNumber),
+		WinnerID:           int(state.WinnerID),
+		NumPlayers:         int(state.NumPlayers),
+		Pot:                state.Pot,
+		CarryoverPot:       state.CarryoverPot,
+		CurrentBet:         state.CurrentBet,
+		BettingComplete:    state.BettingComplete,
+		DealRound:          state.DealRound,
+		BettingRound:       state.BettingRound,
+		TrickLeader:        int(state.TrickLeader),
+		HeartsBroken:       state.HeartsBroken,
+		TableauMode:        int(state.TableauMode),
+		SequenceDirection:  int(state.SequenceDirection),
+		PlayDirection:      int(state.PlayDirection),
+		AceLow:             state.AceLow,
+		WarToBottom:        state.WarToBottom,
+		PerfectInformation: state.PerfectInformation,
+	}
+	if state.SuitOrder != ([4]uint8{}) {
+		for _, precedence := range state.SuitOrder {
+			s.SuitOrder = append(s.SuitOrder, int(precedence))
+		}
 	}
 
 	// Players
@@ -619,21 +1613,34 @@ func serializeState(state *engine.GameState) *SerializedState {
 	for i := 0; i < numPlayers; i++ {
 		p := &state.Players[i]
 		sp := SerializedPlayer{
-			Hand:       make([]SerializedCard, len(p.Hand)),
-			Score:      int(p.Score),
-			Active:     p.Active,
-			Chips:      p.Chips,
-			CurrentBet: p.CurrentBet,
-			HasFolded:  p.HasFolded,
-			IsAllIn:    p.IsAllIn,
+			Hand:        make([]SerializedCard, len(p.Hand)),
+			Score:       int(p.Score),
+			Active:      p.Active,
+			Chips:       p.Chips,
+			CurrentBet:  p.CurrentBet,
+			TotalBet:    p.TotalBet,
+			HasFolded:   p.HasFolded,
+			IsAllIn:     p.IsAllIn,
+			Sweeps:      p.Sweeps,
+			TrickPoints: p.TrickPoints,
 		}
 		for j, card := range p.Hand {
 			sp.Hand[j] = SerializedCard{Rank: int(card.Rank), Suit: int(card.Suit)}
 		}
+		if p.HiddenCount() < len(p.Hand) {
+			sp.FaceUp = make([]bool, len(p.Hand))
+			for j, card := range p.Hand {
+				sp.FaceUp[j] = p.IsFaceUp(card)
+			}
+		}
+		for _, card := range p.Captured {
+			sp.Captured = append(sp.Captured, SerializedCard{Rank: int(card.Rank), Suit: int(card.Suit)})
+		}
 		s.Players[i] = sp
 	}
 
 	// Deck
+	s.DeckCount = len(state.Deck)
 	s.Deck = make([]SerializedCard, len(state.Deck))
 	for i, card := range state.Deck {
 		s.Deck[i] = SerializedCard{Rank: int(card.Rank), Suit: int(card.Suit)}
@@ -654,6 +1661,25 @@ func serializeState(state *engine.GameState) *SerializedState {
 		}
 	}
 
+	// Community cards
+	for _, card := range state.Community {
+		s.Community = append(s.Community, SerializedCard{Rank: int(card.Rank), Suit: int(card.Suit)})
+	}
+
+	// Kitty
+	for _, card := range state.Kitty {
+		s.Kitty = append(s.Kitty, SerializedCard{Rank: int(card.Rank), Suit: int(card.Suit)})
+	}
+	s.KittyFaceUp = state.KittyFaceUp
+
+	for _, playerID := range state.EliminationOrder {
+		s.EliminationOrder = append(s.EliminationOrder, int(playerID))
+	}
+	if state.Knocker >= 0 {
+		knocker := int(state.Knocker)
+		s.Knocker = &knocker
+	}
+
 	// Current trick
 	if len(state.CurrentTrick) > 0 {
 		s.CurrentTrick = make([]SerializedTrickCard, len(state.CurrentTrick))
@@ -665,6 +1691,26 @@ func serializeState(state *engine.GameState) *SerializedState {
 		}
 	}
 
+	// Simultaneous plays
+	for _, tc := range state.Committed {
+		s.Committed = append(s.Committed, SerializedTrickCard{
+			PlayerID: int(tc.PlayerID),
+			Card:     SerializedCard{Rank: int(tc.Card.Rank), Suit: int(tc.Card.Suit)},
+		})
+	}
+	for _, card := range state.CommitPot {
+		s.CommitPot = append(s.CommitPot, SerializedCard{Rank: int(card.Rank), Suit: int(card.Suit)})
+	}
+	if state.SeenCards != 0 {
+		for suit := uint8(0); suit <= engine.JokerSuit; suit++ {
+			for rank := uint8(0); rank < 13; rank++ {
+				if card := (engine.Card{Rank: rank, Suit: suit}); state.IsSeen(card) {
+					s.SeenCards = append(s.SeenCards, SerializedCard{Rank: int(rank), Suit: int(suit)})
+				}
+			}
+		}
+	}
+
 	// Tricks won
 	if len(state.TricksWon) > 0 {
 		s.TricksWon = make([]int, len(state.TricksWon))
@@ -676,21 +1722,73 @@ func serializeState(state *engine.GameState) *SerializedState {
 	return s
 }
 
-// deserializeState loads SerializedState back into GameState.
-func deserializeState(s *SerializedState, state *engine.GameState) {
+// serializeStateFor converts GameState to the SerializedState a single player
+// may see: their own hand, every face-up card, and public piles (discard,
+// tableau, community cards, current trick, captures). Opponents' face-down cards,
+// including the ones they have committed in a simultaneous phase, become
+// hiddenCard placeholders and the deck is reduced to DeckCount.
+// The result is for display only and cannot be deserialized back.
+// With GameState.PerfectInformation nothing is hidden.
+func serializeStateFor(state *engine.GameState, viewerID int) *SerializedState {
+	s := serializeState(state)
+	if state.PerfectInformation {
+		return s
+	}
+	s.Deck = []SerializedCard{}
+	if !s.KittyFaceUp {
+		for i := range s.Kitty {
+			s.Kitty[i] = hiddenCard
+		}
+	}
+
+	for i := range s.Players {
+		if i == viewerID {
+			continue
+		}
+		sp := &s.Players[i]
+		for j := range sp.Hand {
+			if sp.FaceUp == nil || !sp.FaceUp[j] {
+				sp.Hand[j] = hiddenCard
+			}
+		}
+	}
+	for i := range s.Committed {
+		if s.Committed[i].PlayerID != viewerID {
+			s.Committed[i].Card = hiddenCard
+		}
+	}
+	return s
+}
+
+// deserializeState loads SerializedState back into GameState and returns an
+// error if the result has out-of-range or duplicated cards (see engine.ValidateState).
+func deserializeState(s *SerializedState, state *engine.GameState) error {
 	state.Reset()
 
 	state.CurrentPlayer = uint8(s.CurrentPlayer)
+	state.CurrentPhase = s.CurrentPhase
 	state.TurnNumber = uint32(s.TurnNumber)
 	state.WinnerID = int8(s.WinnerID)
 	state.NumPlayers = uint8(s.NumPlayers)
 	state.Pot = s.Pot
+	state.CarryoverPot = s.CarryoverPot
 	state.CurrentBet = s.CurrentBet
 	state.BettingComplete = s.BettingComplete
+	state.DealRound = s.DealRound
+	state.BettingRound = s.BettingRound
 	state.TrickLeader = uint8(s.TrickLeader)
 	state.HeartsBroken = s.HeartsBroken
 	state.TableauMode = uint8(s.TableauMode)
 	state.SequenceDirection = uint8(s.SequenceDirection)
+	if s.PlayDirection != 0 {
+		state.PlayDirection = int8(s.PlayDirection)
+	}
+	state.AceLow = s.AceLow
+	state.WarToBottom = s.WarToBottom
+	state.PerfectInformation = s.PerfectInformation
+	for suit := 0; suit < len(s.SuitOrder) && suit < 4; suit++ {
+		state.SuitOrder[suit] = uint8(s.SuitOrder[suit])
+	}
 
 	// Players
 	for i, sp := range s.Players {
@@ -700,26 +1798,37 @@ func deserializeState(s *SerializedState, state *engine.GameState) {
 		p := &state.Players[i]
 		p.Hand = make([]engine.Card, len(sp.Hand))
 		for j, sc := range sp.Hand {
-			p.Hand[j] = engine.Card{Rank: uint8(sc.Rank), Suit: uint8(sc.Suit)}
+			p.Hand[j] = toEngineCard(sc)
+		}
+		for j, up := range sp.FaceUp {
+			if up && j < len(p.Hand) {
+				p.FaceUp = append(p.FaceUp, p.Hand[j])
+			}
 		}
 		p.Score = int32(sp.Score)
 		p.Active = sp.Active
 		p.Chips = sp.Chips
 		p.CurrentBet = sp.CurrentBet
+		p.TotalBet = sp.TotalBet
 		p.HasFolded = sp.HasFolded
 		p.IsAllIn = sp.IsAllIn
+		p.Sweeps = sp.Sweeps
+		p.TrickPoints = sp.TrickPoints
+		for _, sc := range sp.Captured {
+			p.Captured = append(p.Captured, toEngineCard(sc))
+		}
 	}
 
 	// Deck
 	state.Deck = make([]engine.Card, len(s.Deck))
 	for i, sc := range s.Deck {
-		state.Deck[i] = engine.Card{Rank: uint8(sc.Rank), Suit: uint8(sc.Suit)}
+		state.Deck[i] = toEngineCard(sc)
 	}
 
 	// Discard
 	state.Discard = make([]engine.Card, len(s.Discard))
 	for i, sc := range s.Discard {
-		state.Discard[i] = engine.Card{Rank: uint8(sc.Rank), Suit: uint8(sc.Suit)}
+		state.Discard[i] = toEngineCard(sc)
 	}
 
 	// Tableau
@@ -727,8 +1836,41 @@ func deserializeState(s *SerializedState, state *engine.GameState) {
 	for i, pile := range s.Tableau {
 		state.Tableau[i] = make([]engine.Card, len(pile))
 		for j, sc := range pile {
-			state.Tableau[i][j] = engine.Card{Rank: uint8(sc.Rank), Suit: uint8(sc.Suit)}
+			state.Tableau[i][j] = toEngineCard(sc)
+		}
+	}
+
+	// Community cards
+	state.Community = make([]engine.Card, len(s.Community))
+	for i, sc := range s.Community {
+		state.Community[i] = toEngineCard(sc)
+	}
+
+	// Kitty
+	state.Kitty = make([]engine.Card, len(s.Kitty))
+	for i, sc := range s.Kitty {
+		state.Kitty[i] = toEngineCard(sc)
+	}
+	state.KittyFaceUp = s.KittyFaceUp
+
+	// Elimination
+	for _, playerID := range s.EliminationOrder {
+		if playerID < 0 || playerID >= len(state.Players) {
+			return fmt.Errorf("eliminated player %d out of range", playerID)
+		}
+		state.Players[playerID].Eliminated = true
+		state.Players[playerID].Active = false
+		state.EliminationOrder = append(state.EliminationOrder, uint8(playerID))
+	}
+	if s.StartingPlayer < 0 || s.StartingPlayer >= len(state.Players) {
+		return fmt.Errorf("starting player %d out of range", s.StartingPlayer)
+	}
+	state.StartingPlayer = uint8(s.StartingPlayer)
+	if s.Knocker != nil {
+		if *s.Knocker < 0 || *s.Knocker >= len(state.Players) {
+			return fmt.Errorf("knocker %d out of range", *s.Knocker)
 		}
+		state.Knocker = int8(*s.Knocker)
 	}
 
 	// Current trick
@@ -736,15 +1878,70 @@ func deserializeState(s *SerializedState, state *engine.GameState) {
 	for i, tc := range s.CurrentTrick {
 		state.CurrentTrick[i] = engine.TrickCard{
 			PlayerID: uint8(tc.PlayerID),
-			Card:     engine.Card{Rank: uint8(tc.Card.Rank), Suit: uint8(tc.Card.Suit)},
+			Card:     toEngineCard(tc.Card),
 		}
 	}
 
+	// Simultaneous plays
+	for _, tc := range s.Committed {
+		state.Committed = append(state.Committed, engine.TrickCard{
+			PlayerID: uint8(tc.PlayerID),
+			Card:     toEngineCard(tc.Card),
+		})
+	}
+	for _, sc := range s.CommitPot {
+		state.CommitPot = append(state.CommitPot, toEngineCard(sc))
+	}
+	for _, sc := range s.SeenCards {
+		state.MarkSeen(toEngineCard(sc))
+	}
+
 	// Tricks won
 	state.TricksWon = make([]uint8, len(s.TricksWon))
 	for i, tw := range s.TricksWon {
 		state.TricksWon[i] = uint8(tw)
 	}
+
+	return engine.ValidateState(state)
+}
+
+// toEngineCard converts a serialized card. Values that don't fit a byte
+// become an invalid card rather than wrapping into a valid one.
+func toEngineCard(sc SerializedCard) engine.Card {
+	if sc.Rank < 0 || sc.Rank > 255 || sc.Suit < 0 || sc.Suit > 255 {
+		return engine.Card{Rank: 255, Suit: 255}
+	}
+	return engine.Card{Rank: uint8(sc.Rank), Suit: uint8(sc.Suit)}
+}
+
+// defaultMCTSIterations is the search budget when a get_ai_move command omits it.
+const defaultMCTSIterations = 500
+
+// selectMCTSMoveIndex picks a move with MCTS using the given exploration constant.
+// A zero explorationC uses mcts.DefaultExplorationParam. A temperature above 0
+// samples the move from the root visit counts with rng instead of taking the
+// most visited.
+func selectMCTSMoveIndex(state *engine.GameState, genome *engine.Genome, moves []engine.LegalMove, iterations int, explorationC float64, temperature float64, rng *rand.Rand) int {
+	if iterations <= 0 {
+		iterations = defaultMCTSIterations
+	}
+	move, stats := mcts.SearchWithStats(state, genome, iterations, explorationC)
+	if temperature > 0 && len(stats) > 0 {
+		counts := make([]int, len(stats))
+		for i, stat := range stats {
+			counts[i] = stat.Visits
+		}
+		move = &stats[mcts.SampleByVisits(counts, temperature, rng)].Move
+	}
+	if move == nil {
+		return 0
+	}
+	for i := range moves {
+		if moves[i] == *move {
+			return i
+		}
+	}
+	return 0
 }
 
 // selectGreedyMoveIndex picks the best move using greedy heuristics.
@@ -780,23 +1977,3 @@ func scoreMove(state *engine.GameState, move *engine.LegalMove) float64 {
 
 	return score
 }
-
-// writeResponse writes a JSON response to stdout.
-func writeResponse(resp *Response) {
-	data, err := json.Marshal(resp)
-	if err != nil {
-		writeError(fmt.Sprintf("failed to marshal response: %v", err))
-		return
-	}
-	fmt.Println(string(data))
-}
-
-// writeError writes an error response to stdout.
-func writeError(msg string) {
-	resp := &Response{
-		Success: false,
-		Error:   msg,
-	}
-	data, _ := json.Marshal(resp)
-	fmt.Println(string(data))
-}
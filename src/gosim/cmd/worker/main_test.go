@@ -0,0 +1,1026 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+	"github.com/signalnine/darwindeck/gosim/mcts"
+	"github.com/signalnine/darwindeck/gosim/simulation"
+)
+
+func TestSerializeStateFaceUp(t *testing.T) {
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []engine.Card{{Rank: 12, Suit: 0}, {Rank: 3, Suit: 1}}
+	state.Players[1].Hand = []engine.Card{{Rank: 5, Suit: 2}, {Rank: 8, Suit: 3}}
+	state.Players[1].FaceUp = []engine.Card{{Rank: 8, Suit: 3}}
+
+	s := serializeState(state)
+	if s.Players[0].FaceUp != nil {
+		t.Errorf("Expected face-down hand to omit face_up, got %v", s.Players[0].FaceUp)
+	}
+	if got := s.Players[1].FaceUp; len(got) != 2 || got[0] || !got[1] {
+		t.Errorf("Expected face_up [false true], got %v", got)
+	}
+
+	engine.ApplyReveal(state, 0)
+	s = serializeState(state)
+	if got := s.Players[0].FaceUp; len(got) != 2 || !got[0] || !got[1] {
+		t.Errorf("Expected revealed hand face_up [true true], got %v", got)
+	}
+
+	// Visibility survives a round trip through the worker protocol
+	restored := engine.NewGameState(2)
+	defer engine.PutState(restored)
+	if err := deserializeState(s, restored); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if restored.Players[0].HiddenCount() != 0 || restored.Players[1].HiddenCount() != 1 {
+		t.Errorf("Expected hidden counts 0 and 1 after round trip, got %d and %d",
+			restored.Players[0].HiddenCount(), restored.Players[1].HiddenCount())
+	}
+}
+
+func TestSerializeStateForRedactsOpponents(t *testing.T) {
+	state := engine.NewGameState(3)
+	defer engine.PutState(state)
+	state.NumPlayers = 3
+	state.Players[0].Hand = []engine.Card{{Rank: 12, Suit: 0}, {Rank: 3, Suit: 1}}
+	state.Players[1].Hand = []engine.Card{{Rank: 5, Suit: 2}, {Rank: 8, Suit: 3}}
+	state.Players[1].FaceUp = []engine.Card{{Rank: 8, Suit: 3}}
+	state.Players[2].Hand = []engine.Card{{Rank: 1, Suit: 0}}
+	state.Players[2].Captured = []engine.Card{{Rank: 9, Suit: 1}}
+	state.Deck = []engine.Card{{Rank: 0, Suit: 0}, {Rank: 7, Suit: 2}, {Rank: 10, Suit: 3}}
+	state.Discard = []engine.Card{{Rank: 4, Suit: 1}}
+	state.Tableau = [][]engine.Card{{{Rank: 6, Suit: 0}}}
+
+	s := serializeStateFor(state, 0)
+
+	// Viewer sees their own hand
+	want := []SerializedCard{{Rank: 12, Suit: 0}, {Rank: 3, Suit: 1}}
+	for j, card := range want {
+		if s.Players[0].Hand[j] != card {
+			t.Errorf("Viewer card %d: expected %v, got %v", j, card, s.Players[0].Hand[j])
+		}
+	}
+
+	// Opponents keep their hand sizes but only face-up cards are shown
+	if got := s.Players[1].Hand; len(got) != 2 || got[0] != hiddenCard || got[1] != (SerializedCard{Rank: 8, Suit: 3}) {
+		t.Errorf("Expected player 1 hand [hidden, 8/3], got %v", got)
+	}
+	if got := s.Players[2].Hand; len(got) != 1 || got[0] != hiddenCard {
+		t.Errorf("Expected player 2 hand [hidden], got %v", got)
+	}
+
+	// Deck is count-only; public piles stay visible
+	if len(s.Deck) != 0 || s.DeckCount != 3 {
+		t.Errorf("Expected empty deck with count 3, got %d cards and count %d", len(s.Deck), s.DeckCount)
+	}
+	if len(s.Discard) != 1 || len(s.Tableau[0]) != 1 || len(s.Players[2].Captured) != 1 {
+		t.Error("Expected discard, tableau and captured piles to stay visible")
+	}
+
+	// The full serialization is unaffected
+	full := serializeState(state)
+	if len(full.Deck) != 3 || full.Players[2].Hand[0] != (SerializedCard{Rank: 1, Suit: 0}) {
+		t.Error("Expected serializeState to keep the complete state")
+	}
+}
+
+func TestSerializeStateForPerfectInformation(t *testing.T) {
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []engine.Card{{Rank: 12, Suit: 0}}
+	state.Players[1].Hand = []engine.Card{{Rank: 5, Suit: 2}, {Rank: 8, Suit: 3}}
+	state.Deck = []engine.Card{{Rank: 0, Suit: 0}}
+	state.PerfectInformation = true
+
+	// Open hands: the view is the whole state
+	if view, full := serializeStateFor(state, 0), serializeState(state); !reflect.DeepEqual(view, full) {
+		t.Errorf("Expected nothing redacted with perfect information, got %+v", view)
+	}
+
+	// The switch survives a round trip and turns redaction back on when off
+	loaded := engine.NewGameState(2)
+	defer engine.PutState(loaded)
+	if err := deserializeState(serializeState(state), loaded); err != nil || !loaded.PerfectInformation {
+		t.Fatalf("Expected perfect information kept through deserialization, got %v", err)
+	}
+	loaded.PerfectInformation = false
+	if got := serializeStateFor(loaded, 0).Players[1].Hand; got[0] != hiddenCard || got[1] != hiddenCard {
+		t.Errorf("Expected player 1's hand hidden without perfect information, got %v", got)
+	}
+}
+
+func TestSerializeStateCommittedPlays(t *testing.T) {
+	state := engine.NewGameState(3)
+	defer engine.PutState(state)
+	state.NumPlayers = 3
+	state.Committed = []engine.TrickCard{
+		{PlayerID: 0, Card: engine.Card{Rank: 9, Suit: 0}},
+		{PlayerID: 1, Card: engine.Card{Rank: 11, Suit: 1}},
+	}
+	state.CommitPot = []engine.Card{{Rank: 4, Suit: 2}}
+
+	view := serializeStateFor(state, 1)
+	if view.Committed[0].Card != hiddenCard || view.Committed[1].Card != (SerializedCard{Rank: 11, Suit: 1}) {
+		t.Errorf("Expected only the viewer's committed card shown, got %v", view.Committed)
+	}
+
+	restored := engine.NewGameState(3)
+	defer engine.PutState(restored)
+	if err := deserializeState(serializeState(state), restored); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if !reflect.DeepEqual(restored.Committed, state.Committed) || !reflect.DeepEqual(restored.CommitPot, state.CommitPot) {
+		t.Errorf("Expected committed plays to round-trip, got %v and pot %v", restored.Committed, restored.CommitPot)
+	}
+}
+
+// playRandomMoves starts a War game with seed and returns the first n
+// random AI move indices
+func playRandomMoves(t *testing.T, seed int64, n int) []int {
+	t.Helper()
+	startWarGame(t, seed)
+
+	var indices []int
+	for i := 0; i < n; i++ {
+		resp := handleGetAIMove(&Command{AIType: "random"})
+		if !resp.Success {
+			break // Game over
+		}
+		indices = append(indices, resp.AIMove.Index)
+		if resp := handleApplyMove(&Command{MoveIndex: resp.AIMove.Index}); !resp.Success {
+			t.Fatalf("apply_move failed: %s", resp.Error)
+		}
+	}
+	return indices
+}
+
+func TestRandomAIReproducibleWithSeed(t *testing.T) {
+	first := playRandomMoves(t, 99, 40)
+	second := playRandomMoves(t, 99, 40)
+
+	if len(first) == 0 {
+		t.Fatal("Expected some AI moves")
+	}
+	if len(first) != len(second) {
+		t.Fatalf("Expected equal move counts, got %d and %d", len(first), len(second))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Move %d differs between runs: %d vs %d", i, first[i], second[i])
+		}
+	}
+}
+
+// warGenomeJSON returns the golden War genome as a command's genome field
+func warGenomeJSON(t *testing.T) json.RawMessage {
+	t.Helper()
+	return goldenGenomeJSON(t, "war_genome.bin")
+}
+
+// goldenGenomeJSON returns a golden genome file as a command's genome field
+func goldenGenomeJSON(t *testing.T, name string) json.RawMessage {
+	t.Helper()
+	bytecode, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "tests", "golden", name))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	genomeJSON, _ := json.Marshal(base64.StdEncoding.EncodeToString(bytecode))
+	return genomeJSON
+}
+
+// startWarGame starts a War game in the worker with seed
+func startWarGame(t *testing.T, seed int64) *Response {
+	t.Helper()
+	resp := handleStartGame(&Command{Genome: warGenomeJSON(t), Seed: seed})
+	if !resp.Success {
+		t.Fatalf("start_game failed: %s", resp.Error)
+	}
+	return resp
+}
+
+func TestApplyMoveTrace(t *testing.T) {
+	start := startWarGame(t, 3)
+
+	resp := handleApplyMove(&Command{MoveIndex: 0, Trace: true})
+	if !resp.Success {
+		t.Fatalf("apply_move failed: %s", resp.Error)
+	}
+	if len(resp.Trace) == 0 {
+		t.Fatal("Expected trace entries")
+	}
+	first := resp.Trace[0]
+	if first.Kind != "move" || first.Player != 0 || first.Detail != start.Moves[0].Label {
+		t.Errorf("Expected first entry to be player 0's move %q, got %+v", start.Moves[0].Label, first)
+	}
+	if currentState.Trace != nil {
+		t.Error("Expected the trace hook removed after the command")
+	}
+
+	// Off by default
+	if resp := handleApplyMove(&Command{MoveIndex: 0}); len(resp.Trace) != 0 {
+		t.Errorf("Expected no trace without the flag, got %v", resp.Trace)
+	}
+}
+
+func TestLegalMovesMatchesApplyMove(t *testing.T) {
+	startWarGame(t, 8)
+	applied := handleCommand(&Command{Action: "apply_move", MoveIndex: 0})
+	if !applied.Success {
+		t.Fatalf("apply_move failed: %s", applied.Error)
+	}
+
+	resp := handleCommand(&Command{Action: "legal_moves"})
+	if !resp.Success || !reflect.DeepEqual(resp.Moves, applied.Moves) {
+		t.Errorf("Expected the moves from apply_move %+v, got %+v", applied.Moves, resp)
+	}
+	current, _ := json.Marshal(serializeState(currentState))
+	if string(current) != string(applied.State) {
+		t.Error("Expected legal_moves to leave currentState unchanged")
+	}
+
+	// Moves for another position come from a clone
+	start := startWarGame(t, 8)
+	handleCommand(&Command{Action: "apply_move", MoveIndex: 0})
+	resp = handleCommand(&Command{Action: "legal_moves", State: start.State})
+	if !resp.Success || !reflect.DeepEqual(resp.Moves, start.Moves) {
+		t.Errorf("Expected the starting moves %+v, got %+v", start.Moves, resp)
+	}
+	if current, _ := json.Marshal(serializeState(currentState)); string(current) == string(start.State) {
+		t.Error("Expected legal_moves not to load the sent state into the game")
+	}
+}
+
+func TestHintRanksLegalMoves(t *testing.T) {
+	start := handleStartGame(&Command{Genome: goldenGenomeJSON(t, "hearts_genome.bin"), Seed: 4})
+	if !start.Success || len(start.Moves) <= 3 {
+		t.Fatalf("Expected a Hearts game with more than 3 opening moves, got %+v", start)
+	}
+	before, _ := json.Marshal(serializeState(currentState))
+
+	for _, cmd := range []*Command{
+		{Action: "hint"},
+		{Action: "hint", AIType: "mcts", MCTSIterations: 200, HintCount: 2},
+	} {
+		want := cmd.HintCount
+		if want == 0 {
+			want = defaultHintCount
+		}
+		resp := handleCommand(cmd)
+		if !resp.Success || len(resp.Hints) != want {
+			t.Fatalf("Expected %d hints from %q, got %+v", want, cmd.AIType, resp)
+		}
+		for i, hint := range resp.Hints {
+			if i > 0 && hint.Score > resp.Hints[i-1].Score {
+				t.Errorf("Expected %q hints best first, got %+v", cmd.AIType, resp.Hints)
+			}
+			if hint.Index >= len(start.Moves) || hint.MoveInfo != start.Moves[hint.Index] {
+				t.Errorf("Expected hint %+v to be a legal move", hint)
+			}
+		}
+	}
+	if current, _ := json.Marshal(serializeState(currentState)); string(current) != string(before) {
+		t.Error("Expected hint to leave currentState unchanged")
+	}
+}
+
+func TestCheckMoveLegal(t *testing.T) {
+	start := startWarGame(t, 5)
+	first := start.Moves[0]
+
+	resp := handleCheckMove(&Command{PhaseIndex: 0, CardIndex: first.CardIndex})
+	if !resp.Success || !resp.Legal {
+		t.Fatalf("Expected legal move, got %+v", resp)
+	}
+	if resp.Move == nil || resp.Move.Index != first.Index {
+		t.Errorf("Expected move index %d, got %+v", first.Index, resp.Move)
+	}
+
+	var preview SerializedState
+	if err := json.Unmarshal(resp.Preview, &preview); err != nil {
+		t.Fatalf("Invalid preview: %v", err)
+	}
+	if preview.CurrentPlayer != 1 {
+		t.Errorf("Expected preview to pass the turn to player 1, got %d", preview.CurrentPlayer)
+	}
+
+	// The game itself is untouched
+	current, _ := json.Marshal(serializeState(currentState))
+	if string(current) != string(start.State) {
+		t.Error("Expected check_move to leave currentState unchanged")
+	}
+}
+
+func TestCheckMoveIllegal(t *testing.T) {
+	start := startWarGame(t, 5)
+
+	resp := handleCheckMove(&Command{PhaseIndex: 3, CardIndex: 0})
+	if !resp.Success {
+		t.Fatalf("Expected check to succeed, got error %q", resp.Error)
+	}
+	if resp.Legal || resp.Move != nil || resp.Preview != nil {
+		t.Errorf("Expected an illegal move without preview, got %+v", resp)
+	}
+
+	current, _ := json.Marshal(serializeState(currentState))
+	if string(current) != string(start.State) {
+		t.Error("Expected check_move to leave currentState unchanged")
+	}
+}
+
+func TestSerializeStatePlayDirection(t *testing.T) {
+	state := engine.NewGameState(4)
+	defer engine.PutState(state)
+	state.NumPlayers = 4
+	state.PlayDirection = -1
+
+	s := serializeState(state)
+	if s.PlayDirection != -1 {
+		t.Errorf("Expected play_direction -1, got %d", s.PlayDirection)
+	}
+
+	restored := engine.NewGameState(4)
+	defer engine.PutState(restored)
+	if err := deserializeState(s, restored); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if restored.PlayDirection != -1 {
+		t.Errorf("Expected direction -1 after round trip, got %d", restored.PlayDirection)
+	}
+
+	// States from clients without the field keep the default direction
+	s.PlayDirection = 0
+	if err := deserializeState(s, restored); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if restored.PlayDirection != 1 {
+		t.Errorf("Expected default direction 1, got %d", restored.PlayDirection)
+	}
+}
+
+func TestSerializeStateCommunity(t *testing.T) {
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+	state.NumPlayers = 2
+	state.Tableau = [][]engine.Card{{{Rank: 2, Suit: 0}}}
+	state.Community = []engine.Card{{Rank: 12, Suit: 1}, {Rank: 11, Suit: 1}, {Rank: 10, Suit: 1}}
+
+	s := serializeState(state)
+	if len(s.Community) != 3 || len(s.Tableau) != 1 || len(s.Tableau[0]) != 1 {
+		t.Fatalf("Expected 3 community cards and 1 tableau card, got %v and %v", s.Community, s.Tableau)
+	}
+
+	restored := engine.NewGameState(2)
+	defer engine.PutState(restored)
+	if err := deserializeState(s, restored); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if !reflect.DeepEqual(restored.Community, state.Community) {
+		t.Errorf("Expected community %v after round trip, got %v", state.Community, restored.Community)
+	}
+	if len(restored.Tableau) != 1 || len(restored.Tableau[0]) != 1 {
+		t.Errorf("Expected tableau untouched by community cards, got %v", restored.Tableau)
+	}
+
+	// The board is public
+	if view := serializeStateFor(state, 1); len(view.Community) != 3 {
+		t.Errorf("Expected community cards in player view, got %v", view.Community)
+	}
+}
+
+func TestSerializeStateKitty(t *testing.T) {
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+	state.NumPlayers = 2
+	state.Kitty = []engine.Card{{Rank: 8, Suit: 2}, {Rank: 12, Suit: 3}}
+
+	s := serializeState(state)
+	restored := engine.NewGameState(2)
+	defer engine.PutState(restored)
+	if err := deserializeState(s, restored); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if !reflect.DeepEqual(restored.Kitty, state.Kitty) {
+		t.Errorf("Expected kitty %v after round trip, got %v", state.Kitty, restored.Kitty)
+	}
+
+	// A face-down kitty is hidden from every player until it is turned up
+	if view := serializeStateFor(state, 0); len(view.Kitty) != 2 || view.Kitty[0] != hiddenCard {
+		t.Errorf("Expected a hidden kitty in player view, got %v", view.Kitty)
+	}
+	state.KittyFaceUp = true
+	if view := serializeStateFor(state, 0); view.Kitty[1] != (SerializedCard{Rank: 12, Suit: 3}) {
+		t.Errorf("Expected a face-up kitty in player view, got %v", view.Kitty)
+	}
+}
+
+func TestSerializeStateElimination(t *testing.T) {
+	state := engine.NewGameState(4)
+	defer engine.PutState(state)
+	engine.EliminatePlayer(state, 2)
+	engine.EliminatePlayer(state, 0)
+
+	s := serializeState(state)
+	if !reflect.DeepEqual(s.EliminationOrder, []int{2, 0}) {
+		t.Fatalf("Expected elimination order [2 0], got %v", s.EliminationOrder)
+	}
+	restored := engine.NewGameState(4)
+	defer engine.PutState(restored)
+	if err := deserializeState(s, restored); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if !restored.Players[2].Eliminated || !restored.Players[0].Eliminated || restored.Players[1].Eliminated {
+		t.Errorf("Expected players 0 and 2 eliminated after round trip, got %+v", restored.Players)
+	}
+	if got := engine.FinalRanking(restored); !reflect.DeepEqual(got, engine.FinalRanking(state)) {
+		t.Errorf("Expected ranking %v after round trip, got %v", engine.FinalRanking(state), got)
+	}
+
+	s.EliminationOrder = []int{7}
+	if err := deserializeState(s, restored); err == nil {
+		t.Error("Expected an out-of-range eliminated player to be rejected")
+	}
+}
+
+func TestSerializeStateJokers(t *testing.T) {
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+	state.Players[0].Hand = []engine.Card{engine.Joker(1), {Rank: 4, Suit: 2}}
+	state.Deck = []engine.Card{engine.Joker(0)}
+
+	s := serializeState(state)
+	if got := s.Players[0].Hand[0]; got != (SerializedCard{Rank: 1, Suit: int(engine.JokerSuit)}) {
+		t.Errorf("Expected joker 2 as rank 1 of suit 4, got %+v", got)
+	}
+	restored := engine.NewGameState(2)
+	defer engine.PutState(restored)
+	if err := deserializeState(s, restored); err != nil {
+		t.Fatalf("Failed to deserialize jokers: %v", err)
+	}
+	if !reflect.DeepEqual(restored.Players[0].Hand, state.Players[0].Hand) || !restored.Deck[0].IsJoker() {
+		t.Errorf("Expected jokers after round trip, got hand %v and deck %v", restored.Players[0].Hand, restored.Deck)
+	}
+}
+
+func TestSerializeStateSeenCards(t *testing.T) {
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+	state.MarkSeen(engine.Card{Rank: 12, Suit: 3}, engine.Card{Rank: 0, Suit: 0}, engine.Joker(1))
+
+	s := serializeState(state)
+	want := []SerializedCard{{Rank: 0, Suit: 0}, {Rank: 12, Suit: 3}, {Rank: 1, Suit: int(engine.JokerSuit)}}
+	if !reflect.DeepEqual(s.SeenCards, want) {
+		t.Errorf("Expected seen cards %v, got %v", want, s.SeenCards)
+	}
+	restored := engine.NewGameState(2)
+	defer engine.PutState(restored)
+	if err := deserializeState(s, restored); err != nil || restored.SeenCards != state.SeenCards {
+		t.Errorf("Expected seen cards after round trip, got %#x (%v)", restored.SeenCards, err)
+	}
+}
+
+func TestSerializeStateKnocker(t *testing.T) {
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+	if s := serializeState(state); s.Knocker != nil {
+		t.Fatalf("Expected no knocker before a knock, got %d", *s.Knocker)
+	}
+
+	state.Knocker = 1
+	s := serializeState(state)
+	restored := engine.NewGameState(2)
+	defer engine.PutState(restored)
+	if err := deserializeState(s, restored); err != nil || restored.Knocker != 1 {
+		t.Fatalf("Expected knocker 1 after round trip, got %d (%v)", restored.Knocker, err)
+	}
+
+	bad := 5
+	s.Knocker = &bad
+	if err := deserializeState(s, restored); err == nil {
+		t.Error("Expected an out-of-range knocker to be rejected")
+	}
+}
+
+func TestSerializeStateLargeChips(t *testing.T) {
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+	state.InitializeChips(5_000_000_000)
+	state.Pot = 1 << 40
+
+	data, err := json.Marshal(serializeState(state))
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var s SerializedState
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	restored := engine.NewGameState(2)
+	defer engine.PutState(restored)
+	if err := deserializeState(&s, restored); err != nil {
+		t.Fatalf("Failed to deserialize: %v", err)
+	}
+	if restored.Players[1].Chips != 5_000_000_000 || restored.Pot != 1<<40 {
+		t.Errorf("Expected chips and pot to survive the round trip, got chips=%d pot=%d", restored.Players[1].Chips, restored.Pot)
+	}
+
+	s.Players[0].Chips = -1
+	if err := deserializeState(&s, restored); err == nil {
+		t.Error("Expected negative chips to be rejected")
+	}
+}
+
+func TestDescribeGenome(t *testing.T) {
+	resp := handleCommand(&Command{Action: "describe_genome", Genome: warGenomeJSON(t)})
+	if !resp.Success {
+		t.Fatalf("describe_genome failed: %s", resp.Error)
+	}
+
+	out, err := json.Marshal(resp.Summary)
+	if err != nil {
+		t.Fatalf("Failed to marshal summary: %v", err)
+	}
+	var summary map[string]interface{}
+	json.Unmarshal(out, &summary)
+	if summary["tableau_mode"] != "war" || summary["player_count"] != float64(2) {
+		t.Errorf("Unexpected summary %s", out)
+	}
+	if ranks, ok := summary["effect_ranks"].([]interface{}); !ok || len(ranks) != 0 {
+		t.Errorf("Expected an empty effect_ranks list, got %v", summary["effect_ranks"])
+	}
+	if resp.Summary.Text != "2p, phases [play], win [capture_all], tableau war, effects none" {
+		t.Errorf("Unexpected summary text %q", resp.Summary.Text)
+	}
+
+	if resp := handleDescribeGenome(&Command{}); resp.Success {
+		t.Error("Expected describe_genome without a genome to fail")
+	}
+}
+
+func TestValidateGenomeErrorKinds(t *testing.T) {
+	bytecode, err := os.ReadFile(filepath.Join("..", "..", "..", "..", "tests", "golden", "war_genome.bin"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	header, err := engine.ParseHeader(bytecode)
+	if err != nil {
+		t.Fatalf("Failed to parse header: %v", err)
+	}
+	unknownPhase := append([]byte(nil), bytecode...)
+	unknownPhase[header.TurnStructureOffset+4] = 99
+
+	for _, tc := range []struct {
+		bytecode []byte
+		kind     string
+		section  string
+	}{
+		{bytecode[:10], genomeErrorTruncated, engine.SectionHeader},
+		{unknownPhase, genomeErrorUnknownPhaseType, engine.SectionTurnStructure},
+	} {
+		genomeJSON, _ := json.Marshal(base64.StdEncoding.EncodeToString(tc.bytecode))
+		resp := handleCommand(&Command{Action: "validate_genome", Genome: genomeJSON})
+		if resp.Success || resp.ErrorKind != tc.kind || resp.ErrorSection != tc.section {
+			t.Errorf("Expected a %s error in %s, got kind %q section %q (%s)",
+				tc.kind, tc.section, resp.ErrorKind, resp.ErrorSection, resp.Error)
+		}
+	}
+
+	resp := handleCommand(&Command{Action: "validate_genome", Genome: json.RawMessage(`"not base64!"`)})
+	if resp.Success || resp.ErrorKind != genomeErrorEncoding || resp.ErrorSection != "" {
+		t.Errorf("Expected an encoding error, got kind %q section %q", resp.ErrorKind, resp.ErrorSection)
+	}
+}
+
+func TestDeserializeStateRejectsBadCards(t *testing.T) {
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+
+	dup := &SerializedState{
+		NumPlayers: 2,
+		Players: []SerializedPlayer{
+			{Hand: []SerializedCard{{Rank: 12, Suit: 0}}},
+			{Hand: []SerializedCard{{Rank: 12, Suit: 0}}}, // Second ace of hearts
+		},
+	}
+	if err := deserializeState(dup, state); err == nil {
+		t.Error("Expected a duplicated ace to be rejected")
+	}
+
+	// 268 would wrap to a valid rank if converted unchecked
+	for _, rank := range []int{13, 268, -1} {
+		bad := &SerializedState{NumPlayers: 2, Deck: []SerializedCard{{Rank: rank, Suit: 1}}}
+		if err := deserializeState(bad, state); err == nil {
+			t.Errorf("Expected rank %d to be rejected", rank)
+		}
+	}
+}
+
+func TestApplyMoveRejectsInvalidState(t *testing.T) {
+	start := startWarGame(t, 3)
+
+	var bad SerializedState
+	json.Unmarshal(start.State, &bad)
+	bad.Players[0].Hand = append(bad.Players[0].Hand, bad.Players[1].Hand[0])
+	badJSON, _ := json.Marshal(bad)
+
+	resp := handleApplyMove(&Command{State: badJSON})
+	if resp.Success || resp.Error == "" {
+		t.Fatalf("Expected apply_move to reject a duplicated card, got %+v", resp)
+	}
+
+	// The game in progress is untouched
+	current, _ := json.Marshal(serializeState(currentState))
+	if string(current) != string(start.State) {
+		t.Error("Expected currentState unchanged after rejecting the state")
+	}
+}
+
+func TestStatsCountsCommands(t *testing.T) {
+	first := handleCommand(&Command{Action: "stats"})
+	if !first.Success || first.Stats == nil {
+		t.Fatalf("Expected stats, got %+v", first)
+	}
+
+	handleCommand(&Command{Action: "ping"})
+	handleCommand(&Command{Action: "no_such_action"})
+	if resp := handleCommand(&Command{Action: "start_game", Genome: warGenomeJSON(t), Seed: 5}); !resp.Success {
+		t.Fatalf("start_game failed: %s", resp.Error)
+	}
+
+	second := handleCommand(&Command{Action: "stats"})
+	if got, want := second.Stats.Commands, first.Stats.Commands+4; got != want {
+		t.Errorf("Expected %d commands handled, got %d", want, got)
+	}
+	before := first.Stats.PoolHits + first.Stats.PoolMisses
+	if after := second.Stats.PoolHits + second.Stats.PoolMisses; after <= before {
+		t.Errorf("Expected start_game to take states from the pool, got %d gets before and %d after", before, after)
+	}
+	if second.Stats.HeapAlloc == 0 || second.Stats.UptimeSeconds < first.Stats.UptimeSeconds {
+		t.Errorf("Expected memory usage and a growing uptime, got %+v", second.Stats)
+	}
+}
+
+func TestServeLargeCommand(t *testing.T) {
+	// Well past the old 1MB scanner limit
+	padding := strings.Repeat("x", 3*1024*1024)
+	in := strings.NewReader(`{"action":"ping","padding":"` + padding + `"}` + "\n" + `{"action":"stats"}`)
+	var out bytes.Buffer
+	if err := serve(in, &out); err != nil {
+		t.Fatalf("serve failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 responses, got %d: %q", len(lines), out.String())
+	}
+	for i, line := range lines {
+		var resp Response
+		if err := json.Unmarshal([]byte(line), &resp); err != nil || !resp.Success {
+			t.Errorf("Response %d: expected success, got %s", i, line)
+		}
+	}
+}
+
+func TestReadCommandLineTooLong(t *testing.T) {
+	reader := bufio.NewReaderSize(strings.NewReader(strings.Repeat("x", 100)+"\n"+`{"action":"ping"}`+"\r\n"), 16)
+
+	if line, err := readCommandLine(reader, 32); !errors.Is(err, errCommandTooLong) || line != nil {
+		t.Fatalf("Expected errCommandTooLong, got %q, %v", line, err)
+	}
+
+	// The oversized line is skipped and the next command reads cleanly
+	line, err := readCommandLine(reader, 32)
+	if err != nil || string(line) != `{"action":"ping"}` {
+		t.Errorf("Expected the following command, got %q, %v", line, err)
+	}
+	if _, err := readCommandLine(reader, 32); err != io.EOF {
+		t.Errorf("Expected io.EOF at end of input, got %v", err)
+	}
+}
+
+func TestServeManyCommands(t *testing.T) {
+	const n = 500
+	var in strings.Builder
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			in.WriteString(`{"action":"stats"}` + "\n")
+		} else {
+			in.WriteString(`{"action":"ping"}` + "\n")
+		}
+	}
+	var out bytes.Buffer
+	if err := serve(strings.NewReader(in.String()), &out); err != nil {
+		t.Fatalf("serve failed: %v", err)
+	}
+
+	decoder := json.NewDecoder(&out)
+	count := 0
+	for decoder.More() {
+		var resp Response
+		if err := decoder.Decode(&resp); err != nil {
+			t.Fatalf("Response %d: %v", count, err)
+		}
+		if !resp.Success || (count%2 == 0) != (resp.Stats != nil) {
+			t.Errorf("Response %d out of order or failed: %+v", count, resp)
+		}
+		count++
+	}
+	if count != n {
+		t.Errorf("Expected %d responses, got %d", n, count)
+	}
+}
+
+// frame length-prefixes a payload
+func frame(payload string) []byte {
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(payload)))
+	return append(header[:], payload...)
+}
+
+func TestServeLengthPrefixedFraming(t *testing.T) {
+	var in bytes.Buffer
+	in.WriteString(`{"action":"ping","framing":"length_prefixed"}` + "\n")
+	in.Write(frame(`{"action":"ping"}`))
+	in.Write(frame(`{"action":"bogus"}`))
+	var out bytes.Buffer
+	if err := serve(&in, &out); err != nil {
+		t.Fatalf("serve failed: %v", err)
+	}
+
+	// The handshake reply is still a line
+	handshake, err := out.ReadBytes('\n')
+	if err != nil || !strings.Contains(string(handshake), `"framing":"length_prefixed"`) {
+		t.Fatalf("Expected handshake line, got %q, %v", handshake, err)
+	}
+	for i, wantSuccess := range []bool{true, false} {
+		var header [4]byte
+		if _, err := io.ReadFull(&out, header[:]); err != nil {
+			t.Fatalf("Frame %d: %v", i, err)
+		}
+		var resp Response
+		if err := json.Unmarshal(out.Next(int(binary.BigEndian.Uint32(header[:]))), &resp); err != nil {
+			t.Fatalf("Frame %d: %v", i, err)
+		}
+		if resp.Success != wantSuccess {
+			t.Errorf("Frame %d: expected success %v, got %+v", i, wantSuccess, resp)
+		}
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no trailing output, got %q", out.String())
+	}
+}
+
+func TestPingRejectsUnknownFraming(t *testing.T) {
+	if resp := handleCommand(&Command{Action: "ping", Framing: "smoke_signals"}); resp.Success {
+		t.Errorf("Expected unknown framing to be rejected, got %+v", resp)
+	}
+}
+
+// failingWriter fails every write, like a closed stdout pipe
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, io.ErrClosedPipe }
+
+func TestServeStopsOnWriteError(t *testing.T) {
+	in := strings.NewReader(`{"action":"ping"}` + "\n" + `{"action":"ping"}` + "\n")
+	if err := serve(in, failingWriter{}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Expected serve to stop with the write error, got %v", err)
+	}
+}
+
+func TestResetEndsGame(t *testing.T) {
+	startWarGame(t, 9)
+
+	if resp := handleCommand(&Command{Action: "reset"}); !resp.Success {
+		t.Fatalf("reset failed: %s", resp.Error)
+	}
+	if currentState != nil || currentGenome != nil {
+		t.Error("Expected reset to clear the current game")
+	}
+	resp := handleCommand(&Command{Action: "apply_move"})
+	if resp.Success || !strings.Contains(resp.Error, "no game in progress") {
+		t.Errorf("Expected apply_move to fail after reset, got %+v", resp)
+	}
+
+	// Resetting with no game is harmless
+	if resp := handleCommand(&Command{Action: "reset"}); !resp.Success {
+		t.Errorf("Expected a second reset to succeed, got %+v", resp)
+	}
+}
+
+func TestStartGameStartingPlayer(t *testing.T) {
+	starter := 2
+	resp := handleStartGame(&Command{Genome: goldenGenomeJSON(t, "hearts_genome.bin"), Seed: 4, StartingPlayer: &starter})
+	if !resp.Success {
+		t.Fatalf("start_game failed: %s", resp.Error)
+	}
+	var s SerializedState
+	if err := json.Unmarshal(resp.State, &s); err != nil {
+		t.Fatalf("Failed to unmarshal state: %v", err)
+	}
+	if s.CurrentPlayer != 2 || s.StartingPlayer != 2 {
+		t.Errorf("Expected player 2 to start, got current %d, starting %d", s.CurrentPlayer, s.StartingPlayer)
+	}
+
+	starter = 4
+	resp = handleStartGame(&Command{Genome: goldenGenomeJSON(t, "hearts_genome.bin"), StartingPlayer: &starter})
+	if resp.Success {
+		t.Error("Expected an out-of-range starting player to be rejected")
+	}
+}
+
+func TestEncodeObservation(t *testing.T) {
+	startWarGame(t, 5)
+	resp := handleCommand(&Command{Action: "encode"})
+	if !resp.Success || len(resp.Observation) != engine.ObservationSize(currentGenome) {
+		t.Fatalf("Expected an observation of %d features, got %+v", engine.ObservationSize(currentGenome), resp)
+	}
+	card := currentState.Players[0].Hand[0]
+	if resp.Observation[int(card.Suit)*13+int(card.Rank)] != 1 {
+		t.Errorf("Expected player 0's %s marked in the hand block", card)
+	}
+	if len(resp.ActionMask) != engine.ActionSpaceSize(currentGenome) {
+		t.Errorf("Expected an action mask of %d actions, got %d", engine.ActionSpaceSize(currentGenome), len(resp.ActionMask))
+	}
+}
+
+func TestSelfPlayTranscript(t *testing.T) {
+	genomeJSON := goldenGenomeJSON(t, "hearts_genome.bin")
+	resp := handleCommand(&Command{Action: "selfplay", Genome: genomeJSON, Seed: 9, MCTSIterations: 20})
+	if !resp.Success || resp.EndReason == "" || len(resp.SelfPlay) == 0 {
+		t.Fatalf("Expected a finished selfplay game, got %+v", resp.Error)
+	}
+
+	// Replaying the chosen actions reaches the same end in as many plies
+	genome, _ := decodeGenome(&Command{Genome: genomeJSON})
+	state := engine.SetupGame(genome, 9)
+	defer engine.PutState(state)
+	for ply, step := range resp.SelfPlay {
+		var sum float32
+		for i, p := range step.Policy {
+			if p > 0 && step.ActionMask[i] != 1 {
+				t.Fatalf("Ply %d: policy weight on illegal action %d", ply, i)
+			}
+			sum += p
+		}
+		if sum < 0.999 || sum > 1.001 {
+			t.Errorf("Ply %d: expected the policy to sum to 1, got %v", ply, sum)
+		}
+		if step.Player != int(state.CurrentPlayer) || len(step.Observation) != engine.ObservationSize(genome) {
+			t.Fatalf("Ply %d: step for player %d doesn't match the game", ply, step.Player)
+		}
+		if engine.CheckGameEnd(state, genome).Over() {
+			t.Fatalf("Ply %d: game already over", ply)
+		}
+		move, ok := engine.ActionIndexToMove(step.Action, genome)
+		if !ok || step.ActionMask[step.Action] != 1 {
+			t.Fatalf("Ply %d: action %d is not a legal move", ply, step.Action)
+		}
+		engine.ApplyMove(state, &move, genome)
+	}
+	result := engine.CheckGameEnd(state, genome)
+	if !result.Over() || int(result.Winner) != resp.Winner {
+		t.Errorf("Expected the transcript to end the game with winner %d, got %+v", resp.Winner, result)
+	}
+	for _, step := range resp.SelfPlay {
+		if want := selfPlayValue(state, result, step.Player); step.Value != want {
+			t.Fatalf("Expected value %v for player %d, got %v", want, step.Player, step.Value)
+		}
+	}
+}
+
+func TestSelfPlayReproducibleFromSeed(t *testing.T) {
+	genomeJSON := goldenGenomeJSON(t, "hearts_genome.bin")
+	first := handleCommand(&Command{Action: "selfplay", Genome: genomeJSON, Seed: 4, MCTSIterations: 20})
+	again := handleCommand(&Command{Action: "selfplay", Genome: genomeJSON, Seed: 4, MCTSIterations: 20})
+	if !first.Success || !again.Success {
+		t.Fatalf("Expected selfplay to succeed, got %v and %v", first.Error, again.Error)
+	}
+	if !reflect.DeepEqual(first.SelfPlay, again.SelfPlay) || first.Winner != again.Winner {
+		t.Errorf("Expected the same seed to replay the same game, got %d and %d plies", len(first.SelfPlay), len(again.SelfPlay))
+	}
+}
+
+func TestVisitPolicyRenormalizesUnmappedMoves(t *testing.T) {
+	genome, _ := decodeGenome(&Command{Genome: goldenGenomeJSON(t, "hearts_genome.bin")})
+	mapped := engine.LegalMove{PhaseIndex: 0, CardIndex: 2, TargetLoc: engine.LocationTableau}
+	visits := []mcts.MoveStat{
+		{Move: mapped, Visits: 6},
+		{Move: engine.LegalMove{PhaseIndex: len(genome.TurnPhases), CardIndex: 0}, Visits: 4}, // No action index
+	}
+	policy := visitPolicy(visits, genome)
+	var sum float32
+	for _, p := range policy {
+		sum += p
+	}
+	if sum < 0.999 || sum > 1.001 {
+		t.Errorf("Expected the policy to sum to 1, got %v", sum)
+	}
+	if p := policy[engine.MoveToActionIndex(mapped, genome)]; p != 1 {
+		t.Errorf("Expected all the weight on the mapped move, got %v", p)
+	}
+}
+
+func TestAdvanceToDecision(t *testing.T) {
+	// Playing War from piles, every ply is forced, so the game plays out
+	startWarGame(t, 6)
+	currentState.WarToBottom = true
+	resp := handleCommand(&Command{Action: "advance_to_decision"})
+	if !resp.Success || resp.EndReason == "" {
+		t.Fatalf("Expected War fast-forwarded to its end, got %+v", resp)
+	}
+	var s SerializedState
+	if err := json.Unmarshal(resp.State, &s); err != nil {
+		t.Fatalf("Failed to unmarshal state: %v", err)
+	}
+	if resp.Advanced == 0 || resp.Advanced != int(s.TurnNumber) {
+		t.Errorf("Expected a move applied for each of the %d turns, got %d", s.TurnNumber, resp.Advanced)
+	}
+
+	// Choosing which card to play is left to the player
+	start := startWarGame(t, 6)
+	resp = handleCommand(&Command{Action: "advance_to_decision"})
+	if !resp.Success || resp.Advanced != 0 || !reflect.DeepEqual(resp.Moves, start.Moves) {
+		t.Errorf("Expected no moves applied at a choice of cards, got %+v", resp)
+	}
+	if string(resp.State) != string(start.State) {
+		t.Error("Expected the state unchanged at a decision")
+	}
+}
+
+func TestEvaluateFitness(t *testing.T) {
+	genomeJSON := goldenGenomeJSON(t, "hearts_genome.bin")
+	resp := handleCommand(&Command{Action: "evaluate_fitness", Genome: genomeJSON, Seed: 4, Games: 20})
+	if !resp.Success || resp.Fitness == nil {
+		t.Fatalf("Expected a fitness report, got %+v", resp.Error)
+	}
+	report := resp.Fitness
+
+	// Every field comes from the same batch run directly
+	genome, _ := decodeGenome(&Command{Genome: genomeJSON})
+	stats := simulation.RunBatch(genome, 20, simulation.RandomAI, 0, 4)
+	finished := float64(stats.TotalGames - stats.Errors)
+	if report.Games != 20 || report.Errors != int(stats.Errors) || report.Valid != (stats.Errors == 0) {
+		t.Errorf("Expected 20 games with %d errors, got %+v", stats.Errors, report)
+	}
+	if len(report.SeatWinRates) != 4 {
+		t.Fatalf("Expected a win rate for each of 4 seats, got %v", report.SeatWinRates)
+	}
+	total := report.DrawRate
+	for seat, rate := range report.SeatWinRates {
+		if want := float64(stats.Wins[seat]) / finished; rate != want {
+			t.Errorf("Seat %d: expected win rate %v, got %v", seat, want, rate)
+		}
+		total += rate
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Expected seat win rates and draws to cover every game, got %v", total)
+	}
+	if report.AvgTurns != float64(stats.AvgTurns) || report.MedianTurns != int(stats.MedianTurns) {
+		t.Errorf("Expected %v turns on average, got %v", stats.AvgTurns, report.AvgTurns)
+	}
+	if want := float64(stats.LeadChanges) / finished; report.AvgLeadChanges != want {
+		t.Errorf("Expected %v lead changes a game, got %v", want, report.AvgLeadChanges)
+	}
+	if report.DecisiveTurnPct != float64(stats.DecisiveTurnPct) || report.ClosestMargin != float64(stats.ClosestMargin) {
+		t.Errorf("Expected the batch's tension averages, got %+v", report)
+	}
+	if want := float64(stats.TotalValidMoves) / float64(stats.TotalDecisions); report.AvgBranching != want || want <= 1 {
+		t.Errorf("Expected %v moves per decision, got %v", want, report.AvgBranching)
+	}
+	if report.Balance < 0 || report.Balance > 1 {
+		t.Errorf("Expected balance within [0, 1], got %v", report.Balance)
+	}
+	if want := simulation.MeasureSeatAdvantage(genome, stats).Delta; report.FirstPlayerAdvantage != want {
+		t.Errorf("Expected the simulation's first player advantage %v, got %v", want, report.FirstPlayerAdvantage)
+	}
+
+	// The first player is the genome's starting player, compared with the next seat
+	genome.StartingPlayer = 1
+	shifted := fitnessReport(simulation.AggregatedStats{TotalGames: 10, Wins: []uint32{1, 5, 2, 2}}, genome)
+	if math.Abs(shifted.FirstPlayerAdvantage-0.3) > 1e-9 {
+		t.Errorf("Expected seat 1 to lead seat 2 by 0.3, got %v", shifted.FirstPlayerAdvantage)
+	}
+
+	// Unparseable genomes fail like validate_genome
+	bad := handleCommand(&Command{Action: "evaluate_fitness", Genome: json.RawMessage(`"AAEC"`)})
+	if bad.Success || bad.ErrorKind == "" {
+		t.Errorf("Expected a genome error, got %+v", bad)
+	}
+}
@@ -0,0 +1,77 @@
+package engine
+
+// Genome accessors
+//
+// Read-only views of a parsed genome's structure for tools that inspect
+// genomes without running them, so they don't have to know the bytecode
+// layout of each phase type.
+
+// PlayerCount returns the number of players g is set up for, with the
+// same default the engine applies to a missing or out-of-range count
+func (g *Genome) PlayerCount() int {
+	if g.Header == nil {
+		return 2 // Default, as in ReadSetupParams
+	}
+	return ReadSetupParams(g).NumPlayers
+}
+
+// Phases returns g's turn phases in turn order. The descriptors are shared
+// with g and must not be modified.
+func (g *Genome) Phases() []PhaseDescriptor {
+	return g.TurnPhases
+}
+
+// phase returns the phase at phaseIdx if it has phaseType
+func (g *Genome) phase(phaseIdx int, phaseType uint8) (PhaseDescriptor, bool) {
+	if phaseIdx < 0 || phaseIdx >= len(g.TurnPhases) || g.TurnPhases[phaseIdx].PhaseType != phaseType {
+		return PhaseDescriptor{}, false
+	}
+	return g.TurnPhases[phaseIdx], true
+}
+
+// BettingParams returns the parameters of the betting phase at phaseIdx,
+// or false if that phase isn't a (well-formed) betting phase
+func (g *Genome) BettingParams(phaseIdx int) (*BettingPhaseData, bool) {
+	phase, ok := g.phase(phaseIdx, PhaseTypeBetting)
+	if !ok {
+		return nil, false
+	}
+	data, err := ParseBettingPhaseData(phase.Data)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// BiddingParams returns the bidding rules and contract scoring of the
+// bidding phase at phaseIdx, or false if that phase isn't a (well-formed)
+// bidding phase
+func (g *Genome) BiddingParams(phaseIdx int) (BiddingPhase, ContractScoring, bool) {
+	phase, ok := g.phase(phaseIdx, PhaseTypeBidding)
+	if !ok {
+		return BiddingPhase{}, ContractScoring{}, false
+	}
+	bidding, scoring, consumed := ParseBiddingPhase(phase.Data)
+	if consumed == 0 {
+		return BiddingPhase{}, ContractScoring{}, false
+	}
+	return bidding, scoring, true
+}
+
+// PhaseTypeName returns the bytecode name of the phase at phaseIdx (e.g.
+// "draw"), or "" if there is none
+func (g *Genome) PhaseTypeName(phaseIdx int) string {
+	if phaseIdx < 0 || phaseIdx >= len(g.TurnPhases) {
+		return ""
+	}
+	return phaseTypeName(g.TurnPhases[phaseIdx].PhaseType)
+}
+
+// TableauModeName returns the name of g's tableau mode: "none", "war",
+// "match_rank" or "sequence"
+func (g *Genome) TableauModeName() string {
+	if g.Header == nil {
+		return "none"
+	}
+	return tableauModeName(g.Header.TableauMode)
+}
@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestGenomeAccessorsMatchBytecode(t *testing.T) {
+	genome := loadGoldenGenome(t, "simple_poker_genome.bin")
+	bc := genome.Bytecode
+	header := bc
+	if header[0] == 2 {
+		header = header[1:] // V2 headers lead with a version byte
+	}
+
+	if want := int(binary.BigEndian.Uint32(header[12:16])); genome.PlayerCount() != want {
+		t.Errorf("Expected %d players, got %d", want, genome.PlayerCount())
+	}
+
+	offset := binary.BigEndian.Uint32(header[24:28])
+	phaseCount := int(binary.BigEndian.Uint32(bc[offset : offset+4]))
+	if len(genome.Phases()) != phaseCount {
+		t.Fatalf("Expected %d phases, got %d", phaseCount, len(genome.Phases()))
+	}
+	if bc[offset+4] != PhaseTypeBetting || genome.PhaseTypeName(0) != "betting" {
+		t.Fatalf("Expected a betting phase first, got %q", genome.PhaseTypeName(0))
+	}
+	data := bc[offset+5 : offset+13] // min_bet:4 + max_raises:4
+	params, ok := genome.BettingParams(0)
+	if !ok {
+		t.Fatal("Expected betting parameters for phase 0")
+	}
+	if params.MinBet != int(binary.BigEndian.Uint32(data[0:4])) || params.MaxRaises != int(binary.BigEndian.Uint32(data[4:8])) {
+		t.Errorf("Betting parameters %+v don't match bytes %v", params, data)
+	}
+
+	if _, ok := genome.BettingParams(phaseCount); ok {
+		t.Error("Expected no betting parameters past the last phase")
+	}
+	if _, _, ok := genome.BiddingParams(0); ok {
+		t.Error("Expected no bidding parameters for a betting phase")
+	}
+	if got := genome.TableauModeName(); got != tableauModeName(genome.Header.TableauMode) {
+		t.Errorf("Unexpected tableau mode %q", got)
+	}
+	if got := loadGoldenGenome(t, "war_genome.bin").TableauModeName(); got != "war" {
+		t.Errorf("Expected war tableau mode, got %q", got)
+	}
+}
+
+func TestGenomeBiddingParams(t *testing.T) {
+	data := make([]byte, 16)
+	data[1], data[2], data[3] = 1, 13, 0x01 // Bids 1-13, Nil allowed
+	data[4] = 10                            // Points per trick bid
+	genome := &Genome{
+		TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypeBidding, Data: data}},
+	}
+
+	bidding, scoring, ok := genome.BiddingParams(0)
+	if !ok || bidding.MinBid != 1 || bidding.MaxBid != 13 || !bidding.AllowNil || scoring.PointsPerTrickBid != 10 {
+		t.Errorf("Unexpected bidding parameters %+v %+v (ok=%v)", bidding, scoring, ok)
+	}
+	if genome.PlayerCount() != 2 || genome.TableauModeName() != "none" {
+		t.Errorf("Expected defaults without a header, got %d players, tableau %q",
+			genome.PlayerCount(), genome.TableauModeName())
+	}
+}
@@ -0,0 +1,103 @@
+package engine
+
+// Action opcodes
+//
+// Actions are the OpDrawCards..OpReveal opcodes run directly on a GameState.
+// An action program is a sequence of 2-byte instructions, opcode:1 + arg:1,
+// where arg is a card or player count (ignored by opcodes that take none).
+// Action phases (PhaseTypeAction) run a program for the current player,
+// and the skip and reverse card effects run through the same interpreter.
+// Play, betting and claim opcodes need a player's choice and are handled by
+// their phases, so ExecuteAction rejects them.
+
+// actionLen is the size of one action instruction
+const actionLen = 2
+
+// ExecuteAction runs a single action opcode for playerID and returns false
+// if the opcode isn't a supported action
+func ExecuteAction(state *GameState, playerID uint8, op OpCode, arg uint8) bool {
+	switch op {
+	case OpDrawCards:
+		for i := uint8(0); i < arg; i++ {
+			state.DrawCard(playerID, LocationDeck)
+		}
+
+	case OpDiscardCard:
+		// Discards from the end of the hand, like EFFECT_FORCE_DISCARD
+		if int(playerID) < len(state.Players) {
+			hand := &state.Players[playerID].Hand
+			for i := uint8(0); i < arg && len(*hand) > 0; i++ {
+				state.PlayCard(playerID, len(*hand)-1, LocationDiscard)
+			}
+		}
+
+	case OpSkipTurn:
+		state.SkipCount += arg
+		// Cap at NumPlayers-1 to prevent degenerate infinite turns
+		if state.NumPlayers > 0 && state.SkipCount > state.NumPlayers-1 {
+			state.SkipCount = state.NumPlayers - 1
+		}
+
+	case OpReverseOrder:
+		state.PlayDirection *= -1
+
+	case OpDrawFromOpponent:
+		for i := uint8(0); i < arg; i++ {
+			state.DrawCard(playerID, LocationOpponentHand)
+		}
+
+	case OpDiscardPairs:
+		DiscardPairs(state, playerID)
+
+	case OpReveal:
+		ApplyReveal(state, int(playerID))
+
+	default:
+		return false
+	}
+	return true
+}
+
+// ExecuteActions runs an action program for playerID and returns the number
+// of instructions executed. Unsupported opcodes are skipped and a trailing
+// partial instruction is ignored.
+func ExecuteActions(state *GameState, playerID uint8, program []byte) int {
+	executed := 0
+	for i := 0; i+actionLen <= len(program); i += actionLen {
+		if ExecuteAction(state, playerID, OpCode(program[i]), program[i+1]) {
+			executed++
+		}
+	}
+	return executed
+}
+
+// DiscardPairs moves every pair of equal-rank cards in the player's hand to
+// the discard pile, in hand order, and returns the number of pairs shed.
+// With three of a rank the last one stays in hand (Old Maid).
+func DiscardPairs(state *GameState, playerID uint8) int {
+	if int(playerID) >= len(state.Players) {
+		return 0
+	}
+	hand := state.Players[playerID].Hand
+
+	counts := CountRanks(hand)
+
+	// Each rank sheds its first count/2*2 cards
+	var shed RankCounts
+	kept := hand[:0]
+	pairs := 0
+	for _, card := range hand {
+		if card.Rank < 13 && shed[card.Rank] < counts[card.Rank]/2*2 {
+			shed[card.Rank]++
+			state.Discard = append(state.Discard, card)
+			state.MarkSeen(card)
+			if shed[card.Rank]%2 == 0 {
+				pairs++
+			}
+			continue
+		}
+		kept = append(kept, card)
+	}
+	state.Players[playerID].Hand = kept
+	return pairs
+}
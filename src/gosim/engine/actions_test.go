@@ -0,0 +1,95 @@
+package engine
+
+import "testing"
+
+func TestDiscardPairs(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.Players[0].Hand = []Card{
+		{Rank: 3, Suit: 0}, {Rank: 9, Suit: 1}, {Rank: 3, Suit: 2}, {Rank: 11, Suit: 0},
+		{Rank: 9, Suit: 3}, {Rank: 5, Suit: 1}, {Rank: 9, Suit: 0}, {Rank: 3, Suit: 1}, {Rank: 3, Suit: 3},
+	}
+
+	if got := DiscardPairs(state, 0); got != 3 {
+		t.Errorf("Expected 3 pairs shed, got %d", got)
+	}
+
+	// Two pairs of 3s and one pair of 9s; the third 9 stays
+	want := []Card{{Rank: 11, Suit: 0}, {Rank: 5, Suit: 1}, {Rank: 9, Suit: 0}}
+	hand := state.Players[0].Hand
+	if len(hand) != len(want) {
+		t.Fatalf("Expected hand %v, got %v", want, hand)
+	}
+	for i := range want {
+		if hand[i] != want[i] {
+			t.Errorf("Hand card %d: expected %v, got %v", i, want[i], hand[i])
+		}
+	}
+	if len(state.Discard) != 6 {
+		t.Errorf("Expected 6 cards discarded, got %d", len(state.Discard))
+	}
+
+	// No pairs left
+	if got := DiscardPairs(state, 0); got != 0 {
+		t.Errorf("Expected no pairs on second call, got %d", got)
+	}
+}
+
+func TestActionPhaseDiscardsPairs(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 7, Suit: 0}, {Rank: 2, Suit: 1}, {Rank: 7, Suit: 2}, {Rank: 2, Suit: 3}, {Rank: 12, Suit: 0}}
+	state.Players[1].Hand = []Card{{Rank: 7, Suit: 1}, {Rank: 7, Suit: 3}}
+	genome := &Genome{TurnPhases: []PhaseDescriptor{
+		{PhaseType: PhaseTypeAction, Data: []byte{1, byte(OpDiscardPairs), 0}},
+	}}
+
+	moves := GenerateLegalMoves(state, genome)
+	if len(moves) != 1 || moves[0].CardIndex != MoveAction {
+		t.Fatalf("Expected a single action move, got %v", moves)
+	}
+	ApplyMove(state, &moves[0], genome)
+
+	if hand := state.Players[0].Hand; len(hand) != 1 || hand[0] != (Card{Rank: 12, Suit: 0}) {
+		t.Errorf("Expected only the unpaired card left, got %v", hand)
+	}
+	if len(state.Players[1].Hand) != 2 {
+		t.Errorf("Expected player 1's hand untouched, got %v", state.Players[1].Hand)
+	}
+	if state.CurrentPlayer != 1 {
+		t.Errorf("Expected turn to pass to player 1, got %d", state.CurrentPlayer)
+	}
+}
+
+func TestExecuteActions(t *testing.T) {
+	state := NewGameState(3)
+	defer PutState(state)
+	state.NumPlayers = 3
+	state.Deck = []Card{{Rank: 0, Suit: 0}, {Rank: 1, Suit: 0}, {Rank: 2, Suit: 0}}
+	state.Players[1].Hand = []Card{{Rank: 10, Suit: 2}}
+
+	program := []byte{
+		byte(OpDrawCards), 2,
+		byte(OpReverseOrder), 0,
+		byte(OpSkipTurn), 1,
+		byte(OpDrawFromOpponent), 1,
+		byte(OpBet), 5, // Needs a choice; skipped
+	}
+	if got := ExecuteActions(state, 0, program); got != 4 {
+		t.Errorf("Expected 4 actions executed, got %d", got)
+	}
+	if len(state.Players[0].Hand) != 3 || len(state.Deck) != 1 || len(state.Players[1].Hand) != 0 {
+		t.Errorf("Expected 3 cards in hand, 1 in deck and none for player 1, got %d, %d and %d",
+			len(state.Players[0].Hand), len(state.Deck), len(state.Players[1].Hand))
+	}
+	if state.PlayDirection != -1 || state.SkipCount != 1 {
+		t.Errorf("Expected direction -1 and 1 skip, got %d and %d", state.PlayDirection, state.SkipCount)
+	}
+
+	// Reversed with a skip: 0 -> 2 is skipped -> 1
+	AdvanceTurn(state)
+	if state.CurrentPlayer != 1 {
+		t.Errorf("Expected player 1 next, got %d", state.CurrentPlayer)
+	}
+}
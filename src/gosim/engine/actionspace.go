@@ -0,0 +1,165 @@
+package engine
+
+// Action space
+//
+// A policy network picks from a fixed set of outputs, so legal moves are
+// mapped onto a canonical action space that only depends on the genome:
+// one block of actionsPerPhase indices per turn phase, in phase order,
+// followed by one block of obsCards forced discards (Genome.MaxHandSize).
+// Within a phase block:
+//
+//	[0, 54)    the card at that hand index (play, discard, trick, claim)
+//	[54, 108)  the phase's indexed move: draw the discard at that offset
+//	           (draw), discard and knock with that hand index (discard),
+//	           bid that value (bidding), or play the set of that rank (play)
+//	108        bid Nil
+//	[109, 121) the special moves in actionSpecials order
+//
+// ActionMask marks the legal moves of a state in this space.
+
+const (
+	actionExtra     = obsCards
+	actionNilBid    = 2 * obsCards
+	actionSpecial   = actionNilBid + 1
+	actionsPerPhase = actionSpecial + len(actionSpecials)
+)
+
+// actionSpecials are the phase-independent CardIndex values (MoveChallenge
+// shares MoveDraw's -1)
+var actionSpecials = [...]int{
+	MoveDraw, MovePass, MoveDrawPass, MovePlayPass, MoveReveal, MoveAction,
+	MoveBettingCheck, MoveBettingBet, MoveBettingCall, MoveBettingRaise, MoveBettingAllIn, MoveBettingFold,
+}
+
+// ActionSpaceSize returns the number of actions in genome's action space
+func ActionSpaceSize(genome *Genome) int {
+	return len(genome.TurnPhases)*actionsPerPhase + obsCards
+}
+
+// MoveToActionIndex returns move's index in genome's action space, or -1
+// for a move that has none (a hand index or bid past 53, or a phase the
+// genome doesn't have)
+func MoveToActionIndex(move LegalMove, genome *Genome) int {
+	if move.CardIndex <= MoveForcedDiscardOffset {
+		return actionSlot(len(genome.TurnPhases)*actionsPerPhase, MoveForcedDiscardOffset-move.CardIndex)
+	}
+	if move.PhaseIndex < 0 || move.PhaseIndex >= len(genome.TurnPhases) {
+		return -1
+	}
+	block := move.PhaseIndex * actionsPerPhase
+	if move.CardIndex >= 0 {
+		return actionSlot(block, move.CardIndex)
+	}
+
+	switch genome.TurnPhases[move.PhaseIndex].PhaseType {
+	case PhaseTypeDraw:
+		if move.CardIndex <= MoveDrawAtOffset {
+			return actionSlot(block+actionExtra, MoveDrawAtOffset-move.CardIndex)
+		}
+	case PhaseTypeDiscard:
+		if move.CardIndex <= MoveKnockOffset {
+			return actionSlot(block+actionExtra, MoveKnockOffset-move.CardIndex)
+		}
+	case PhaseTypeBidding:
+		if move.CardIndex <= MoveBidOffset {
+			if move.TargetLoc == LocationDiscard { // Nil marker
+				return block + actionNilBid
+			}
+			return actionSlot(block+actionExtra, MoveBidOffset-move.CardIndex)
+		}
+	case PhaseTypePlay:
+		if move.CardIndex <= -100 {
+			return actionSlot(block+actionExtra, -(move.CardIndex + 100))
+		}
+	}
+	for i, special := range actionSpecials {
+		if move.CardIndex == special {
+			return block + actionSpecial + i
+		}
+	}
+	return -1
+}
+
+// actionSlot returns block+i for i within a card-sized block, otherwise -1
+func actionSlot(block, i int) int {
+	if i < 0 || i >= obsCards {
+		return -1
+	}
+	return block + i
+}
+
+// ActionIndexToMove returns the move at index in genome's action space,
+// with TargetLoc set as the move generator sets it. It returns false for an
+// index outside the space, or one its phase has no such move for (an indexed
+// move in a phase without any, or a Nil bid outside a bidding phase);
+// every other index round-trips through MoveToActionIndex.
+func ActionIndexToMove(index int, genome *Genome) (LegalMove, bool) {
+	phases := len(genome.TurnPhases)
+	if index < 0 || index >= ActionSpaceSize(genome) {
+		return LegalMove{}, false
+	}
+	if index >= phases*actionsPerPhase {
+		i := index - phases*actionsPerPhase
+		return LegalMove{CardIndex: MoveForcedDiscardOffset - i, TargetLoc: LocationDiscard}, true
+	}
+
+	phaseIdx, slot := index/actionsPerPhase, index%actionsPerPhase
+	phase := genome.TurnPhases[phaseIdx]
+	move := LegalMove{PhaseIndex: phaseIdx, TargetLoc: actionTarget(phase)}
+	switch {
+	case slot < actionExtra:
+		move.CardIndex = slot
+	case slot < actionNilBid:
+		i := slot - actionExtra
+		switch phase.PhaseType {
+		case PhaseTypeDraw:
+			move.CardIndex = MoveDrawAtOffset - i
+		case PhaseTypeDiscard:
+			move.CardIndex = MoveKnockOffset - i
+		case PhaseTypeBidding:
+			move.CardIndex = MoveBidOffset - i
+		case PhaseTypePlay:
+			move.CardIndex = -i - 100 // Set of rank i
+		default:
+			return LegalMove{}, false
+		}
+	case slot == actionNilBid:
+		if phase.PhaseType != PhaseTypeBidding {
+			return LegalMove{}, false
+		}
+		move.CardIndex = MoveBidOffset
+		move.TargetLoc = LocationDiscard
+	default:
+		move.CardIndex = actionSpecials[slot-actionSpecial]
+	}
+	return move, true
+}
+
+// actionTarget returns the TargetLoc the move generator gives phase's moves
+func actionTarget(phase PhaseDescriptor) Location {
+	switch phase.PhaseType {
+	case PhaseTypeDraw, PhaseTypePlay:
+		if len(phase.Data) > 0 {
+			return Location(phase.Data[0])
+		}
+	case PhaseTypeDiscard, PhaseTypeClaim:
+		return LocationDiscard
+	case PhaseTypeTrick, PhaseTypeSimultaneous:
+		return LocationTableau
+	case PhaseTypeReveal, PhaseTypeAction:
+		return LocationHand
+	}
+	return LocationDeck
+}
+
+// ActionMask returns a 0/1 vector over genome's action space marking the
+// current player's legal moves in state
+func ActionMask(state *GameState, genome *Genome) []float32 {
+	mask := make([]float32, ActionSpaceSize(genome))
+	for _, move := range GenerateLegalMoves(state, genome) {
+		if i := MoveToActionIndex(move, genome); i >= 0 {
+			mask[i] = 1
+		}
+	}
+	return mask
+}
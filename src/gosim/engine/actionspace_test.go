@@ -0,0 +1,57 @@
+package engine
+
+import "testing"
+
+func TestActionIndexRoundTrip(t *testing.T) {
+	genome := &Genome{}
+	for phaseType := PhaseTypeDraw; phaseType <= PhaseTypeAction; phaseType++ {
+		genome.TurnPhases = append(genome.TurnPhases, PhaseDescriptor{PhaseType: uint8(phaseType), Data: []byte{byte(LocationDiscard)}})
+	}
+
+	seen := make(map[LegalMove]int)
+	for index := 0; index < ActionSpaceSize(genome); index++ {
+		move, ok := ActionIndexToMove(index, genome)
+		if !ok {
+			continue
+		}
+		if got := MoveToActionIndex(move, genome); got != index {
+			t.Errorf("Index %d: %+v maps back to %d", index, move, got)
+		}
+		if prev, dup := seen[move]; dup {
+			t.Errorf("Indices %d and %d both decode to %+v", prev, index, move)
+		}
+		seen[move] = index
+	}
+	if _, ok := ActionIndexToMove(ActionSpaceSize(genome), genome); ok {
+		t.Error("Expected no move past the end of the action space")
+	}
+}
+
+func TestActionMaskMarksLegalMoves(t *testing.T) {
+	genome := loadGoldenGenome(t, "hearts_genome.bin")
+	state := SetupGame(genome, 3)
+	defer PutState(state)
+
+	for turn := 0; turn < 8; turn++ {
+		moves := GenerateLegalMoves(state, genome)
+		mask := ActionMask(state, genome)
+		if len(mask) != ActionSpaceSize(genome) {
+			t.Fatalf("Expected a mask of %d actions, got %d", ActionSpaceSize(genome), len(mask))
+		}
+
+		legal := make(map[int]bool)
+		for _, move := range moves {
+			index := MoveToActionIndex(move, genome)
+			if back, ok := ActionIndexToMove(index, genome); !ok || back != move {
+				t.Fatalf("Expected %+v to round-trip through action %d, got %+v", move, index, back)
+			}
+			legal[index] = true
+		}
+		for index, v := range mask {
+			if (v == 1) != legal[index] {
+				t.Errorf("Turn %d: action %d has mask %v, legal %v", turn, index, v, legal[index])
+			}
+		}
+		ApplyMove(state, &moves[0], genome)
+	}
+}
@@ -1,6 +1,9 @@
 package engine
 
-import "sort"
+import (
+	"math"
+	"sort"
+)
 
 // BettingAction represents a betting action type
 type BettingAction int
@@ -56,33 +59,40 @@ func GenerateBettingMoves(gs *GameState, phase *BettingPhaseData, playerID int)
 // ApplyBettingAction executes a betting action, mutating the game state
 func ApplyBettingAction(gs *GameState, phase *BettingPhaseData, playerID int, action BettingAction) {
 	player := &gs.Players[playerID]
+	chipsBefore := player.Chips
+
+	// GenerateBettingMoves never offers a bet the player can't cover, but
+	// callers may apply actions directly; go all in rather than overdraw
+	if cost := bettingCost(gs, phase, player, action); cost > 0 && cost > player.Chips {
+		action = BettingAllIn
+	}
 
 	switch action {
 	case BettingCheck:
 		// No change
 	case BettingBet:
 		player.Chips -= int64(phase.MinBet)
-		player.CurrentBet += int64(phase.MinBet)
-		gs.Pot += int64(phase.MinBet)
+		player.CurrentBet = addChips(player.CurrentBet, int64(phase.MinBet))
+		gs.Pot = addChips(gs.Pot, int64(phase.MinBet))
 		gs.CurrentBet = int64(phase.MinBet)
 	case BettingCall:
 		toCall := gs.CurrentBet - player.CurrentBet
 		player.Chips -= toCall
 		player.CurrentBet = gs.CurrentBet
-		gs.Pot += toCall
+		gs.Pot = addChips(gs.Pot, toCall)
 	case BettingRaise:
 		toCall := gs.CurrentBet - player.CurrentBet
-		raiseAmount := toCall + int64(phase.MinBet)
+		raiseAmount := addChips(toCall, int64(phase.MinBet))
 		player.Chips -= raiseAmount
-		player.CurrentBet = gs.CurrentBet + int64(phase.MinBet)
-		gs.Pot += raiseAmount
+		player.CurrentBet = addChips(gs.CurrentBet, int64(phase.MinBet))
+		gs.Pot = addChips(gs.Pot, raiseAmount)
 		gs.CurrentBet = player.CurrentBet
 		gs.RaiseCount++
 	case BettingAllIn:
 		amount := player.Chips
 		player.Chips = 0
-		player.CurrentBet += amount
-		gs.Pot += amount
+		player.CurrentBet = addChips(player.CurrentBet, amount)
+		gs.Pot = addChips(gs.Pot, amount)
 		player.IsAllIn = true
 		if player.CurrentBet > gs.CurrentBet {
 			gs.CurrentBet = player.CurrentBet
@@ -90,6 +100,36 @@ func ApplyBettingAction(gs *GameState, phase *BettingPhaseData, playerID int, ac
 	case BettingFold:
 		player.HasFolded = true
 	}
+
+	player.TotalBet = addChips(player.TotalBet, chipsBefore-player.Chips)
+
+	if gs.Trace != nil {
+		gs.Trace(TraceEvent{Kind: TraceBet, Turn: gs.TurnNumber, Player: playerID, Action: action, Amount: chipsBefore - player.Chips})
+	}
+}
+
+// bettingCost returns the chips player would put in with action
+func bettingCost(gs *GameState, phase *BettingPhaseData, player *PlayerState, action BettingAction) int64 {
+	switch action {
+	case BettingBet:
+		return int64(phase.MinBet)
+	case BettingCall:
+		return gs.CurrentBet - player.CurrentBet
+	case BettingRaise:
+		return addChips(gs.CurrentBet-player.CurrentBet, int64(phase.MinBet))
+	}
+	return 0
+}
+
+// addChips adds two non-negative chip amounts, saturating at math.MaxInt64
+// instead of wrapping negative. Chip totals only get that large from
+// hand-built or deserialized states, but a wrapped pot would hand the
+// winner a negative stack.
+func addChips(a, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
 }
 
 // CountActivePlayers returns the number of players who haven't folded
@@ -115,6 +155,28 @@ func CountActingPlayers(gs *GameState) int {
 	return count
 }
 
+// OnlyOneActive returns the sole player (among the first NumPlayers seats)
+// who hasn't folded. When it reports true the hand is over: that player wins
+// the pot without a showdown.
+func OnlyOneActive(gs *GameState) (int8, bool) {
+	numPlayers := int(gs.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+
+	remaining := int8(-1)
+	for i := 0; i < numPlayers && i < len(gs.Players); i++ {
+		if gs.Players[i].HasFolded {
+			continue
+		}
+		if remaining >= 0 {
+			return -1, false
+		}
+		remaining = int8(i)
+	}
+	return remaining, remaining >= 0
+}
+
 // AllBetsMatched returns true if all active players have matched the current bet
 // or are all-in/folded
 func AllBetsMatched(gs *GameState) bool {
@@ -141,24 +203,118 @@ func ResolveShowdown(gs *GameState) []int {
 	return activePlayers
 }
 
-// AwardPot distributes the pot to the winner(s)
+// AwardPot distributes the pot to the winner(s), less phase's rake, which
+// leaves play. phase may be nil for no rake.
 // If multiple winners, pot is split evenly with remainder going to first winner
-func AwardPot(gs *GameState, winnerIDs []int) {
+func AwardPot(gs *GameState, phase *BettingPhaseData, winnerIDs []int) {
 	if len(winnerIDs) == 0 {
 		return
 	}
+	splitChips(gs, gs.Pot-Rake(phase, gs.Pot), winnerIDs)
+	gs.Pot = 0
+}
+
+// Rake returns the house's cut of a pot under phase: RakePercent of the
+// pot, rounded down, and no more than RakeCap when one is set
+func Rake(phase *BettingPhaseData, pot int64) int64 {
+	if phase == nil || phase.RakePercent <= 0 || pot <= 0 {
+		return 0
+	}
+	rake := percentOf(pot, phase.RakePercent)
+	if phase.RakeCap > 0 && rake > int64(phase.RakeCap) {
+		rake = int64(phase.RakeCap)
+	}
+	if rake > pot {
+		rake = pot
+	}
+	return rake
+}
 
-	// Split pot evenly among winners
-	share := gs.Pot / int64(len(winnerIDs))
-	remainder := gs.Pot % int64(len(winnerIDs))
+// percentOf returns percent of amount, rounded down
+func percentOf(amount int64, percent int) int64 {
+	// Split so a huge amount can't overflow
+	p := int64(percent)
+	return amount/100*p + amount%100*p/100
+}
+
+// PostAntes opens a hand's pot with the chips carried over from passed
+// hands (see CarryOverPot) and phase's ante from every player still in. A
+// player who can't cover the ante puts in what they have and is all in.
+func PostAntes(gs *GameState, phase *BettingPhaseData) {
+	gs.Pot = addChips(gs.Pot, gs.CarryoverPot)
+	gs.CarryoverPot = 0
+	if phase == nil || phase.Ante <= 0 {
+		return
+	}
+	numPlayers := int(gs.NumPlayers)
+	if numPlayers == 0 || numPlayers > len(gs.Players) {
+		numPlayers = len(gs.Players)
+	}
+	for i := 0; i < numPlayers; i++ {
+		player := &gs.Players[i]
+		if player.HasFolded || player.Chips <= 0 {
+			continue
+		}
+		ante := min(int64(phase.Ante), player.Chips)
+		player.Chips -= ante
+		gs.Pot = addChips(gs.Pot, ante)
+		if player.Chips == 0 {
+			player.IsAllIn = true
+		}
+	}
+}
+
+// HandPassed reports whether nobody has bet this hand, in any betting
+// round, so the pot holds only antes and carryover
+func HandPassed(gs *GameState) bool {
+	for i := range gs.Players {
+		if gs.Players[i].TotalBet > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// NextBettingRound closes a betting round of a hand dealt in streets (see
+// DealPattern): the bets of the round stay in the pot, and the next round
+// starts with nothing to call and no raises yet. Each player's TotalBet
+// keeps the hand's running total.
+func NextBettingRound(gs *GameState) {
+	for i := range gs.Players {
+		gs.Players[i].CurrentBet = 0
+	}
+	gs.CurrentBet = 0
+	gs.RaiseCount = 0
+	gs.BettingComplete = false
+	gs.BettingRound++
+}
+
+// CarryOverPot moves phase's CarryoverPercent of a passed hand's pot into
+// CarryoverPot, where PostAntes adds it to the next hand's pot, and returns
+// the chips moved. The rest of the pot is awarded as usual. A hand someone
+// bet in carries nothing over.
+func CarryOverPot(gs *GameState, phase *BettingPhaseData) int64 {
+	if phase == nil || phase.CarryoverPercent <= 0 || !HandPassed(gs) {
+		return 0
+	}
+	carried := min(percentOf(gs.Pot, phase.CarryoverPercent), gs.Pot)
+	gs.Pot -= carried
+	gs.CarryoverPot = addChips(gs.CarryoverPot, carried)
+	return carried
+}
+
+// splitChips shares amount evenly among winnerIDs, the remainder going to
+// the first winner
+func splitChips(gs *GameState, amount int64, winnerIDs []int) {
+	share := amount / int64(len(winnerIDs))
+	remainder := amount % int64(len(winnerIDs))
 
 	for i, winnerID := range winnerIDs {
-		gs.Players[winnerID].Chips += share
+		gs.Players[winnerID].Chips = addChips(gs.Players[winnerID].Chips, share)
 		if i == 0 {
-			gs.Players[winnerID].Chips += remainder
+			gs.Players[winnerID].Chips = addChips(gs.Players[winnerID].Chips, remainder)
 		}
 	}
-	gs.Pot = 0
 }
 
 // ============================================================================
@@ -226,19 +382,16 @@ func EvaluateHandStrength(hand []Card) float64 {
 	}
 
 	// Count pairs, trips, etc.
-	rankCounts := make(map[uint8]int)
-	for _, card := range hand {
-		rankCounts[card.Rank]++
-	}
+	rankCounts := CountRanks(hand)
+	maxCount := rankCounts.Max()
 
-	maxCount := 0
 	highRank := uint8(0)
 	for rank, count := range rankCounts {
-		if count > maxCount {
-			maxCount = count
+		if count == 0 {
+			continue
 		}
 		// Convert rank for comparison: Ace (0) becomes highest (13)
-		effectiveRank := rank
+		effectiveRank := uint8(rank)
 		if rank == 0 {
 			effectiveRank = 13 // Ace high
 		}
@@ -294,34 +447,22 @@ func matchesPattern(hand []Card, p HandPattern) bool {
 
 	// Check same suit count (for flush)
 	if p.SameSuitCount > 0 {
-		suitCounts := make(map[uint8]int)
-		for _, c := range hand {
-			suitCounts[c.Suit]++
-		}
-		maxSuit := 0
-		for _, count := range suitCounts {
-			if count > maxSuit {
-				maxSuit = count
-			}
-		}
-		if maxSuit < int(p.SameSuitCount) {
+		suitCounts := CountSuits(hand)
+		if suitCounts.Max() < int(p.SameSuitCount) {
 			return false
 		}
 	}
 
 	// Check same rank groups (for pairs, trips, full house)
 	if len(p.SameRankGroups) > 0 {
-		rankCounts := make(map[uint8]int)
-		for _, c := range hand {
-			rankCounts[c.Rank]++
-		}
+		rankCounts := CountRanks(hand)
 
-		// Sort counts descending
-		counts := make([]int, 0, len(rankCounts))
-		for _, count := range rankCounts {
-			counts = append(counts, count)
-		}
+		// Sort counts descending, dropping absent ranks
+		counts := rankCounts[:]
 		sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+		for len(counts) > 0 && counts[len(counts)-1] == 0 {
+			counts = counts[:len(counts)-1]
+		}
 
 		// Check if groups match
 		for i, required := range p.SameRankGroups {
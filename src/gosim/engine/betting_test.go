@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"math"
 	"testing"
 )
 
@@ -528,6 +529,67 @@ func TestCountActingPlayers(t *testing.T) {
 	}
 }
 
+func TestOnlyOneActive_EveryoneFoldsButOne(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 4
+
+	gs.Players[0].HasFolded = true
+	gs.Players[1].HasFolded = true
+	gs.Players[3].HasFolded = true
+
+	winner, ok := OnlyOneActive(gs)
+	if !ok || winner != 2 {
+		t.Errorf("Expected player 2 as sole active player, got %d (ok=%v)", winner, ok)
+	}
+}
+
+func TestOnlyOneActive_MultipleActive(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 3
+
+	gs.Players[1].HasFolded = true
+
+	if winner, ok := OnlyOneActive(gs); ok {
+		t.Errorf("Expected no sole winner with two active players, got %d", winner)
+	}
+}
+
+func TestOnlyOneActive_IgnoresUnusedSeats(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 2 // Seats 2-3 exist in the pooled state but aren't playing
+
+	gs.Players[0].HasFolded = true
+
+	winner, ok := OnlyOneActive(gs)
+	if !ok || winner != 1 {
+		t.Errorf("Expected player 1 to win when opponent folds, got %d (ok=%v)", winner, ok)
+	}
+}
+
+func TestOnlyOneActive_FoldWinAwardsPot(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 3
+	gs.Pot = 60
+
+	// Fold-out with hands too short for poker evaluation
+	gs.Players[0].HasFolded = true
+	gs.Players[2].HasFolded = true
+	gs.Players[1].Hand = []Card{{Rank: 3, Suit: 0}}
+
+	winner, ok := OnlyOneActive(gs)
+	if !ok {
+		t.Fatal("Expected a sole active player")
+	}
+	AwardPot(gs, nil, []int{int(winner)})
+	if gs.Players[1].Chips != 60 || gs.Pot != 0 {
+		t.Errorf("Expected player 1 to take the 60 pot, got chips=%d pot=%d", gs.Players[1].Chips, gs.Pot)
+	}
+}
+
 func TestAllBetsMatched(t *testing.T) {
 	gs := GetState()
 	defer PutState(gs)
@@ -666,7 +728,7 @@ func TestAwardPot_SingleWinner(t *testing.T) {
 	gs.Players[1].Chips = 50
 	gs.Pot = 100
 
-	AwardPot(gs, []int{0})
+	AwardPot(gs, nil, []int{0})
 
 	if gs.Players[0].Chips != 150 {
 		t.Errorf("Expected winner to have 150 chips, got %d", gs.Players[0].Chips)
@@ -687,7 +749,7 @@ func TestAwardPot_SplitEven(t *testing.T) {
 	gs.Players[1].Chips = 50
 	gs.Pot = 100
 
-	AwardPot(gs, []int{0, 1})
+	AwardPot(gs, nil, []int{0, 1})
 
 	// Each should get 50
 	if gs.Players[0].Chips != 100 {
@@ -709,7 +771,7 @@ func TestAwardPot_OddRemainder(t *testing.T) {
 	gs.Players[1].Chips = 50
 	gs.Pot = 101 // Odd pot
 
-	AwardPot(gs, []int{0, 1})
+	AwardPot(gs, nil, []int{0, 1})
 
 	// 101 / 2 = 50 each, remainder 1 goes to first winner
 	if gs.Players[0].Chips != 101 { // 50 + 50 + 1
@@ -730,7 +792,7 @@ func TestAwardPot_EmptyWinners(t *testing.T) {
 	gs.Pot = 100
 	initialPot := gs.Pot
 
-	AwardPot(gs, []int{})
+	AwardPot(gs, nil, []int{})
 
 	// Pot should remain unchanged
 	if gs.Pot != initialPot {
@@ -747,7 +809,7 @@ func TestAwardPot_ThreeWayOddSplit(t *testing.T) {
 	gs.Players[2].Chips = 0
 	gs.Pot = 100 // 100 / 3 = 33 each, remainder 1
 
-	AwardPot(gs, []int{0, 1, 2})
+	AwardPot(gs, nil, []int{0, 1, 2})
 
 	// 100 / 3 = 33 each, remainder 1 goes to first winner
 	if gs.Players[0].Chips != 34 {
@@ -764,6 +826,129 @@ func TestAwardPot_ThreeWayOddSplit(t *testing.T) {
 	}
 }
 
+func TestAwardPot_Rake(t *testing.T) {
+	tests := []struct {
+		name    string
+		phase   BettingPhaseData
+		pot     int64
+		awarded int64
+	}{
+		{"uncapped", BettingPhaseData{RakePercent: 5}, 130, 124}, // 6.5 rounds down to 6
+		{"capped", BettingPhaseData{RakePercent: 10, RakeCap: 4}, 100, 96},
+		{"under cap", BettingPhaseData{RakePercent: 10, RakeCap: 20}, 100, 90},
+		{"no rake", BettingPhaseData{RakeCap: 4}, 100, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gs := GetState()
+			defer PutState(gs)
+			gs.Players[0].Chips = 50
+			gs.Players[1].Chips = 50
+			gs.Pot = tt.pot
+			before := gs.Players[0].Chips + gs.Players[1].Chips + gs.Pot
+
+			AwardPot(gs, &tt.phase, []int{0, 1})
+
+			if got := gs.Players[0].Chips + gs.Players[1].Chips - 100; got != tt.awarded {
+				t.Errorf("Expected %d chips awarded, got %d", tt.awarded, got)
+			}
+			after := gs.Players[0].Chips + gs.Players[1].Chips + gs.Pot
+			if rake := Rake(&tt.phase, tt.pot); before-after != rake || rake != tt.pot-tt.awarded {
+				t.Errorf("Expected chips in play to drop by the rake %d, dropped by %d", rake, before-after)
+			}
+		})
+	}
+}
+
+func TestCarryOverPotIntoNextHand(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 3
+	gs.InitializeChips(100)
+	gs.Players[2].Chips = 3 // Short of the ante
+	phase := &BettingPhaseData{MinBet: 10, MaxRaises: 3, Ante: 5, CarryoverPercent: 100}
+
+	PostAntes(gs, phase)
+	if gs.Pot != 13 || gs.Players[0].Chips != 95 || !gs.Players[2].IsAllIn {
+		t.Fatalf("Expected antes of 5, 5 and an all-in 3, got pot %d and players %+v", gs.Pot, gs.Players[:3])
+	}
+	if gs.Players[3].Chips != 100 {
+		t.Error("Expected no ante from an empty seat")
+	}
+
+	// Everyone checks: the pot goes uncalled and rolls over
+	if !HandPassed(gs) || CarryOverPot(gs, phase) != 13 || gs.Pot != 0 {
+		t.Fatalf("Expected the passed hand's 13 chips carried over, pot left %d", gs.Pot)
+	}
+	gs.ResetHand()
+	PostAntes(gs, phase)
+	if gs.Pot != 13+5+5 || gs.CarryoverPot != 0 {
+		t.Fatalf("Expected the next pot to open with 13 carried plus 10 in antes, got %d", gs.Pot)
+	}
+
+	// A hand with a bet is awarded in full
+	ApplyBettingAction(gs, phase, 0, BettingBet)
+	if HandPassed(gs) || CarryOverPot(gs, phase) != 0 || gs.Pot != 33 {
+		t.Errorf("Expected nothing carried from a hand with a bet, pot %d", gs.Pot)
+	}
+}
+
+func TestCarryOverPotPortion(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.InitializeChips(100)
+	phase := &BettingPhaseData{MinBet: 10, MaxRaises: 3, Ante: 15, CarryoverPercent: 50}
+
+	PostAntes(gs, phase)
+	CarryOverPot(gs, phase)
+	AwardPot(gs, phase, []int{1})
+	if gs.CarryoverPot != 15 || gs.Players[1].Chips != 100 {
+		t.Errorf("Expected half the 30-chip pot carried and half won, got carryover %d and chips %d",
+			gs.CarryoverPot, gs.Players[1].Chips)
+	}
+}
+
+func TestNextBettingRoundKeepsPot(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.InitializeChips(100)
+	phase := &BettingPhaseData{MinBet: 10, MaxRaises: 3}
+
+	// Preflop, flop, turn and river: bet, raise, call each time
+	for round := 0; round < 4; round++ {
+		if moves := GenerateBettingMoves(gs, phase, 0); !containsBettingAction(moves, BettingBet) {
+			t.Fatalf("Round %d: expected a fresh round with nothing to call, got %v", round, moves)
+		}
+		ApplyBettingAction(gs, phase, 0, BettingBet)
+		ApplyBettingAction(gs, phase, 1, BettingRaise)
+		ApplyBettingAction(gs, phase, 0, BettingCall)
+		if gs.CurrentBet != 20 || gs.RaiseCount != 1 || gs.Players[0].CurrentBet != 20 || gs.Players[1].CurrentBet != 20 {
+			t.Fatalf("Round %d: expected bets of 20 after one raise, got %d (raises %d, players %d/%d)",
+				round, gs.CurrentBet, gs.RaiseCount, gs.Players[0].CurrentBet, gs.Players[1].CurrentBet)
+		}
+		if want := int64(40 * (round + 1)); gs.Pot != want {
+			t.Fatalf("Round %d: expected the pot to grow to %d, got %d", round, want, gs.Pot)
+		}
+		NextBettingRound(gs)
+		if gs.CurrentBet != 0 || gs.RaiseCount != 0 || gs.Players[0].CurrentBet != 0 || gs.BettingRound != round+1 {
+			t.Fatalf("Round %d: expected the next round to start clear, got bet %d raises %d", round, gs.CurrentBet, gs.RaiseCount)
+		}
+	}
+	if gs.Players[0].TotalBet != 80 || gs.Players[1].TotalBet != 80 || gs.Players[0].Chips != 20 {
+		t.Errorf("Expected 80 bet by each player over the hand, got %d and %d", gs.Players[0].TotalBet, gs.Players[1].TotalBet)
+	}
+	if HandPassed(gs) {
+		t.Error("Expected a hand with bets in earlier rounds not to count as passed")
+	}
+
+	gs.ResetHand()
+	if gs.BettingRound != 0 || gs.Players[0].TotalBet != 0 {
+		t.Error("Expected a new hand to start from the first betting round")
+	}
+}
+
 // ============================================================================
 // AI Betting Selection Tests
 // ============================================================================
@@ -1581,3 +1766,80 @@ func TestCalculateDefaultHandValueEmpty(t *testing.T) {
 		t.Errorf("Empty hand default value should be 0, got %d", value)
 	}
 }
+
+func TestBettingLargeStacks(t *testing.T) {
+	gs := NewGameState(2)
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.InitializeChips(3_000_000_000)
+	phase := &BettingPhaseData{MinBet: 10, MaxRaises: 3}
+
+	ApplyBettingAction(gs, phase, 0, BettingAllIn)
+	ApplyBettingAction(gs, phase, 1, BettingCall)
+	if gs.Pot != 6_000_000_000 || gs.Players[1].Chips != 0 {
+		t.Fatalf("Expected a 6e9 pot with player 1 all in, got pot=%d chips=%d", gs.Pot, gs.Players[1].Chips)
+	}
+
+	AwardPot(gs, nil, []int{1})
+	if gs.Players[1].Chips != 6_000_000_000 || gs.Pot != 0 {
+		t.Errorf("Expected player 1 to take all 6e9 chips, got chips=%d pot=%d", gs.Players[1].Chips, gs.Pot)
+	}
+}
+
+func TestBettingSaturatesInsteadOfOverflowing(t *testing.T) {
+	gs := NewGameState(2)
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.Players[0].Chips = math.MaxInt64
+	gs.Players[1].Chips = math.MaxInt64
+	phase := &BettingPhaseData{MinBet: 10, MaxRaises: 3}
+
+	ApplyBettingAction(gs, phase, 0, BettingAllIn)
+	gs.Players[1].CurrentBet = 1
+	gs.Players[1].Chips = math.MaxInt64
+	ApplyBettingAction(gs, phase, 1, BettingAllIn)
+	if gs.Pot != math.MaxInt64 || gs.Players[1].CurrentBet != math.MaxInt64 {
+		t.Errorf("Expected pot and bet to saturate at MaxInt64, got pot=%d bet=%d", gs.Pot, gs.Players[1].CurrentBet)
+	}
+
+	gs.Players[0].Chips = math.MaxInt64 - 5
+	gs.Pot = 10
+	AwardPot(gs, nil, []int{0})
+	if gs.Players[0].Chips != math.MaxInt64 {
+		t.Errorf("Expected winnings to saturate at MaxInt64, got %d", gs.Players[0].Chips)
+	}
+}
+
+func TestApplyBettingAction_OverdrawGoesAllIn(t *testing.T) {
+	gs := NewGameState(2)
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.InitializeChips(100)
+	gs.Players[1].Chips = 25
+	phase := &BettingPhaseData{MinBet: 10, MaxRaises: 3}
+	events := recordTrace(gs)
+
+	ApplyBettingAction(gs, phase, 0, BettingBet)
+	ApplyBettingAction(gs, phase, 0, BettingRaise) // raise to 20
+	ApplyBettingAction(gs, phase, 1, BettingRaise) // needs 30, has 25
+
+	p := gs.Players[1]
+	if p.Chips != 0 || !p.IsAllIn || p.CurrentBet != 25 {
+		t.Errorf("Expected player 1 all in for 25, got chips=%d bet=%d allIn=%v", p.Chips, p.CurrentBet, p.IsAllIn)
+	}
+	if gs.Pot != 45 || gs.CurrentBet != 25 {
+		t.Errorf("Expected pot 45 at a 25 bet, got pot=%d bet=%d", gs.Pot, gs.CurrentBet)
+	}
+	if e := (*events)[2]; e.Action != BettingAllIn || e.Amount != 25 {
+		t.Errorf("Expected the overdrawn raise to be traced as a 25-chip all-in, got %+v", e)
+	}
+
+	gs.Players[0].Chips = 3
+	ApplyBettingAction(gs, phase, 0, BettingCall) // needs 5, has 3
+	if p := gs.Players[0]; p.Chips != 0 || !p.IsAllIn || p.CurrentBet != 23 {
+		t.Errorf("Expected player 0 all in for 3 more, got chips=%d bet=%d allIn=%v", p.Chips, p.CurrentBet, p.IsAllIn)
+	}
+	if gs.CurrentBet != 25 {
+		t.Errorf("Expected a short all-in not to lower the bet, got %d", gs.CurrentBet)
+	}
+}
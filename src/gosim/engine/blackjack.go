@@ -88,6 +88,21 @@ func IsBlackjackGame(genome *Genome) bool {
 	return false
 }
 
+// IsBust reports whether hand is over genome's bust limit. A blackjack win
+// condition busts over its target (see BlackjackHandValue); a POINT_TOTAL
+// hand evaluation with a BustThreshold busts at that value (see
+// CalculateHandValue); otherwise a Blackjack genome (IsBlackjackGame) busts
+// over 21. Other genomes never bust.
+func IsBust(hand []Card, genome *Genome) bool {
+	if target, values := blackjackRules(genome); target > 0 {
+		return BlackjackHandValue(hand, values, target) > target
+	}
+	if eval := genome.HandEval; eval != nil && eval.Method == EvalMethodPointTotal && eval.BustThreshold > 0 {
+		return CalculateHandValue(hand, eval) >= int(eval.BustThreshold)
+	}
+	return IsBlackjackGame(genome) && CalculateBlackjackValue(hand) > 21
+}
+
 // SelectBlackjackMove implements basic blackjack strategy
 // Hit on < 17, stand on >= 17 (or if already busted)
 // Returns the index into the moves slice
@@ -128,3 +143,87 @@ func SelectBlackjackMove(state *GameState, moves []LegalMove) int {
 func IsBlackjackDrawMove(move *LegalMove) bool {
 	return move.CardIndex == MoveDraw || move.CardIndex == MoveDrawPass
 }
+
+// BlackjackRankValues values cards for the blackjack win condition: pip
+// cards face value, courts 10, Aces 1 (or 11, see BlackjackHandValue)
+var BlackjackRankValues = [13]int32{2, 3, 4, 5, 6, 7, 8, 9, 10, 10, 10, 10, 1}
+
+// DefaultBlackjackTarget is the blackjack win condition's target when its
+// threshold is unset
+const DefaultBlackjackTarget int32 = 21
+
+// blackjackRules returns the target and rank values of genome's blackjack
+// win condition (the genome's RankValues, else BlackjackRankValues), or a
+// zero target if it has none
+func blackjackRules(genome *Genome) (int32, [13]int32) {
+	for _, wc := range genome.WinConditions {
+		if wc.WinType != WinTypeBlackjack {
+			continue
+		}
+		target := wc.Threshold
+		if target <= 0 {
+			target = DefaultBlackjackTarget
+		}
+		values := genome.RankValues
+		if values == ([13]int32{}) {
+			values = BlackjackRankValues
+		}
+		return target, values
+	}
+	return 0, [13]int32{}
+}
+
+// BlackjackHandValue totals hand with values, counting an Ace ten more than
+// its value when that doesn't take the total over target (see SoftHandValue)
+func BlackjackHandValue(hand []Card, values [13]int32, target int32) int32 {
+	return SoftHandValue(hand, values, SoftValue{Rank: AceRank, Alt: values[AceRank] + 10, Target: target})
+}
+
+// ScoreBlackjack compares the hands of the players still in (not folded or
+// eliminated, holding cards) by BlackjackHandValue: the highest total not
+// over target wins, and a natural (target in two cards) beats any other
+// hand on target. Busted hands lose. Returns the winner, or -1 for a push
+// (a tie for the best hand) or when everyone busts.
+func ScoreBlackjack(state *GameState, target int32, values [13]int32) int8 {
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+
+	winner, best, bestNatural, push := int8(-1), int32(-1), false, false
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		player := &state.Players[playerID]
+		if player.HasFolded || player.Eliminated || len(player.Hand) == 0 {
+			continue
+		}
+		value := BlackjackHandValue(player.Hand, values, target)
+		if value > target {
+			continue // Bust
+		}
+		natural := value == target && len(player.Hand) == 2
+		switch {
+		case value > best || (value == best && natural && !bestNatural):
+			winner, best, bestNatural, push = int8(playerID), value, natural, false
+		case value == best && natural == bestNatural:
+			push = true
+		}
+	}
+	if push {
+		return -1
+	}
+	return winner
+}
+
+// allStood reports whether every player still in has stood (or busted)
+func allStood(state *GameState, numPlayers int) bool {
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		player := &state.Players[playerID]
+		if player.HasFolded || player.Eliminated {
+			continue
+		}
+		if playerID >= len(state.HasStood) || !state.HasStood[playerID] {
+			return false
+		}
+	}
+	return true
+}
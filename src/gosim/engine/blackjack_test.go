@@ -327,3 +327,121 @@ func TestSelectBlackjackMove_SoftAce(t *testing.T) {
 		t.Errorf("Expected to stand (idx 1) on soft 17, got idx %d", idx)
 	}
 }
+
+// blackjackGenome is a single optional draw from the deck, high score to 21
+func blackjackGenome() *Genome {
+	return &Genome{
+		TurnPhases: []PhaseDescriptor{
+			{PhaseType: PhaseTypeDraw, Data: []byte{byte(LocationDeck), 0, 0, 0, 1, 0, 0}},
+		},
+		WinConditions: []WinCondition{{WinType: 1, Threshold: 21}},
+	}
+}
+
+func TestBlackjackHitOrStand(t *testing.T) {
+	gs := NewGameState(2)
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.Players[0].Hand = []Card{{Rank: 9, Suit: 0}, {Rank: 4, Suit: 1}} // 15
+	gs.Deck = []Card{{Rank: 12, Suit: 2}, {Rank: 2, Suit: 3}}           // A 3 on top of a K
+	genome := blackjackGenome()
+
+	moves := GenerateLegalMoves(gs, genome)
+	if len(moves) != 2 || moves[0].CardIndex != MoveDraw || moves[1].CardIndex != MoveDrawPass {
+		t.Fatalf("Expected hit and stand, got %v", moves)
+	}
+
+	// Hitting to 18 keeps the player in
+	ApplyMove(gs, &moves[0], genome)
+	if len(gs.Players[0].Hand) != 3 || gs.HasStood[0] {
+		t.Fatalf("Expected a third card and more to come, got hand %v, stood %v", gs.Players[0].Hand, gs.HasStood[0])
+	}
+
+	// Standing ends the player's drawing
+	gs.CurrentPlayer = 0
+	ApplyMove(gs, &LegalMove{PhaseIndex: 0, CardIndex: MoveDrawPass, TargetLoc: LocationDeck}, genome)
+	if !gs.HasStood[0] || len(gs.Players[0].Hand) != 3 {
+		t.Errorf("Expected the stand to keep the hand and mark player 0 stood, got %v", gs.Players[0].Hand)
+	}
+	gs.CurrentPlayer = 0
+	if moves := GenerateLegalMoves(gs, genome); len(moves) != 0 {
+		t.Errorf("Expected no moves after standing, got %v", moves)
+	}
+}
+
+func TestBlackjackBustEndsDrawing(t *testing.T) {
+	gs := NewGameState(2)
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.Players[0].Hand = []Card{{Rank: 9, Suit: 0}, {Rank: 4, Suit: 1}} // 15
+	gs.Deck = []Card{{Rank: 2, Suit: 3}, {Rank: 12, Suit: 2}}           // K on top
+	genome := blackjackGenome()
+
+	ApplyMove(gs, &LegalMove{PhaseIndex: 0, CardIndex: MoveDraw, TargetLoc: LocationDeck}, genome)
+	if !IsBust(gs.Players[0].Hand, genome) || !gs.HasStood[0] {
+		t.Fatalf("Expected 25 to bust and end player 0's drawing, got hand %v", gs.Players[0].Hand)
+	}
+	gs.CurrentPlayer = 0
+	if moves := GenerateLegalMoves(gs, genome); len(moves) != 0 {
+		t.Errorf("Expected a busted player to have no moves, got %v", moves)
+	}
+
+	// An explicit POINT_TOTAL limit replaces 21
+	genome.HandEval = &HandEvaluation{Method: EvalMethodPointTotal, TargetValue: 31, BustThreshold: 32}
+	if IsBust([]Card{{Rank: 9, Suit: 0}, {Rank: 9, Suit: 1}, {Rank: 5, Suit: 2}}, genome) {
+		t.Error("Expected 11 + 11 + 7 to stay under a bust threshold of 32")
+	}
+}
+
+func TestScoreBlackjackNatural(t *testing.T) {
+	gs := NewGameState(2)
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.Players[0].Hand = []Card{{Rank: 5, Suit: 0}, {Rank: 2, Suit: 1}, {Rank: 9, Suit: 2}} // 7 4 J = 21
+	gs.Players[1].Hand = []Card{{Rank: 12, Suit: 3}, {Rank: 11, Suit: 3}}                   // A K
+
+	if got := BlackjackHandValue(gs.Players[1].Hand, BlackjackRankValues, 21); got != 21 {
+		t.Fatalf("Expected A K to count 21, got %d", got)
+	}
+	if winner := ScoreBlackjack(gs, 21, BlackjackRankValues); winner != 1 {
+		t.Errorf("Expected the natural to beat a three-card 21, got %d", winner)
+	}
+}
+
+func TestScoreBlackjackBust(t *testing.T) {
+	gs := NewGameState(2)
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.Players[0].Hand = []Card{{Rank: 8, Suit: 0}, {Rank: 10, Suit: 1}, {Rank: 3, Suit: 2}}  // 10 Q 5 = 25
+	gs.Players[1].Hand = []Card{{Rank: 12, Suit: 3}, {Rank: 0, Suit: 3}, {Rank: 10, Suit: 2}} // A 2 Q = 13, the Ace counts 1
+	genome := &Genome{WinConditions: []WinCondition{{WinType: WinTypeBlackjack}}}
+
+	if !IsBust(gs.Players[0].Hand, genome) || IsBust(gs.Players[1].Hand, genome) {
+		t.Fatal("Expected only 25 to bust")
+	}
+	if winner := ScoreBlackjack(gs, 21, BlackjackRankValues); winner != 1 {
+		t.Errorf("Expected 13 to beat a bust, got %d", winner)
+	}
+
+	// The hand is only decided once everyone has stood
+	gs.HasStood[0] = true
+	if result := CheckGameOutcome(gs, genome); result.Over() {
+		t.Fatalf("Expected the hand to go on while player 1 can draw, got %+v", result)
+	}
+	gs.HasStood[1] = true
+	if result := CheckGameOutcome(gs, genome); result.Winner != 1 || result.Reason != EndReasonShowdown {
+		t.Errorf("Expected player 1 to win the showdown, got %+v", result)
+	}
+}
+
+func TestScoreBlackjackPush(t *testing.T) {
+	gs := NewGameState(2)
+	defer PutState(gs)
+	gs.NumPlayers = 2
+	gs.Players[0].Hand = []Card{{Rank: 8, Suit: 0}, {Rank: 6, Suit: 1}}                     // 10 8
+	gs.Players[1].Hand = []Card{{Rank: 4, Suit: 3}, {Rank: 5, Suit: 2}, {Rank: 3, Suit: 1}} // 6 7 5
+
+	if winner := ScoreBlackjack(gs, 21, BlackjackRankValues); winner != -1 {
+		t.Errorf("Expected 18 against 18 to push, got %d", winner)
+	}
+}
@@ -1,10 +1,6 @@
 package engine
 
-import (
-	"encoding/binary"
-	"errors"
-	"fmt"
-)
+import "encoding/binary"
 
 // OpCode matches Python bytecode.py
 type OpCode uint8
@@ -18,6 +14,11 @@ const (
 	PhaseTypeBetting = 5
 	PhaseTypeClaim   = 6
 	PhaseTypeBidding = 7
+	PhaseTypeReveal  = 8
+	PhaseTypeAction  = 9
+	// PhaseTypeSimultaneous collects a face-down card from every player
+	// before resolving the round (see simultaneous.go)
+	PhaseTypeSimultaneous = 10
 )
 
 const (
@@ -100,7 +101,7 @@ type BytecodeHeader struct {
 // Supports both V1 (36 bytes, no version prefix) and V2 (47 bytes, version at byte 0)
 func ParseHeader(bytecode []byte) (*BytecodeHeader, error) {
 	if len(bytecode) < 36 {
-		return nil, errors.New("bytecode too short for header")
+		return nil, parseError(SectionHeader, ErrTruncatedHeader, "need 36 bytes, got %d", len(bytecode))
 	}
 
 	// Check if this is V2 format (version byte at offset 0)
@@ -152,7 +153,7 @@ func parseV1Header(bytecode []byte) (*BytecodeHeader, error) {
 // - Bytes 43-46: hand_evaluation_offset (int32) [optional, for backwards compat]
 func parseV2Header(bytecode []byte) (*BytecodeHeader, error) {
 	if len(bytecode) < 39 {
-		return nil, fmt.Errorf("v2 bytecode too short: %d < 39", len(bytecode))
+		return nil, parseError(SectionHeader, ErrTruncatedHeader, "v2 header needs 39 bytes, got %d", len(bytecode))
 	}
 
 	h := &BytecodeHeader{}
@@ -278,11 +279,44 @@ type Genome struct {
 	WinConditions []WinCondition
 	Effects       map[uint8]SpecialEffect // rank -> effect lookup
 	CardScoring   []CardScoringRule       // explicit card scoring rules
+	RankValues    [13]int32               // Points per rank (2..A) left in hand at hand end; all zero = 1 per card (SetupOptRankValues)
 	HandEval      *HandEvaluation         // hand evaluation method
+	DealPattern   *DealPattern            // staged dealing (nil = deal CardsPerPlayer up front)
+	AceLow        bool                    // Ace ranks below 2 (copied to GameState.AceLow; SetupOptAceLow)
+	SuitOrder     [4]uint8                // Suit precedence (copied to GameState.SuitOrder; SetupOptSuitOrder)
+	KittySize     int                     // Cards set aside at the deal (see DealKitty; SetupOptKitty)
+	KittyFaceUp   bool                    // Deal the kitty face up (widow; SetupOptKitty)
+	// StartingPlayer starts the first hand (out-of-range values mean player 0)
+	StartingPlayer int
+	// WarToBottom plays WAR tableau games from piles (copied to
+	// GameState.WarToBottom; setup option SetupOptWarToBottom)
+	WarToBottom bool
+	// PerfectInformation plays with open hands (copied to
+	// GameState.PerfectInformation; setup option SetupOptPerfectInformation)
+	PerfectInformation bool
+	// MatchPlay plays hands to a cumulative target (see MatchTarget; setup
+	// option SetupOptMatchPlay)
+	MatchPlay bool
+	// MaxHandSize caps hands at the end of a turn: a player left holding
+	// more discards down to it before the turn passes (0 = no cap; setup
+	// option SetupOptMaxHandSize)
+	MaxHandSize int
+	// SequentialPhases plays a turn's phases in order, one move each,
+	// instead of offering every phase's moves at once (see CurrentPhase;
+	// setup option SetupOptSequentialPhases). Typed genomes have no such
+	// setting, so the typed runner always offers every phase.
+	SequentialPhases bool
+	// Knocking offers discard-and-knock moves in discard phases once the
+	// rest of the hand has at most KnockThreshold deadwood (see meld.go)
+	Knocking       bool
+	KnockThreshold int32
+	// Betting, when set, replaces the bytecode betting phase's parameters,
+	// for the settings bytecode can't encode (rake, ante, carryover)
+	Betting *BettingPhaseData
 }
 
 type PhaseDescriptor struct {
-	PhaseType uint8  // 1=Draw, 2=Play, 3=Discard, 4=Trick, 5=Betting, 6=Claim
+	PhaseType uint8  // 1=Draw, 2=Play, 3=Discard, 4=Trick, 5=Betting, 6=Claim, 7=Bidding, 8=Reveal, 9=Action, 10=Simultaneous
 	Data      []byte // Raw bytes for this phase
 }
 
@@ -290,6 +324,14 @@ type PhaseDescriptor struct {
 type BettingPhaseData struct {
 	MinBet    int // Minimum bet/raise amount
 	MaxRaises int // Maximum raises per round (prevents infinite loops)
+	// House rake taken from each pot before it is awarded (see Rake).
+	// Not in the bytecode, which always encodes no rake.
+	RakePercent int // Percent of the pot, 0 = no rake
+	RakeCap     int // Most chips raked from one pot, 0 = uncapped
+	// Forced bets and carryover in multi-hand play (see PostAntes and
+	// CarryOverPot). Not in the bytecode either.
+	Ante             int // Chips each player puts in before a hand's betting
+	CarryoverPercent int // Percent of a passed hand's pot that rolls into the next hand
 }
 
 type WinCondition struct {
@@ -301,7 +343,7 @@ type WinCondition struct {
 // Expected format: min_bet:4 + max_raises:4 = 8 bytes
 func ParseBettingPhaseData(data []byte) (*BettingPhaseData, error) {
 	if len(data) < 8 {
-		return nil, errors.New("betting phase data too short: need at least 8 bytes")
+		return nil, parseError(SectionBettingPhase, ErrTruncated, "need 8 bytes, got %d", len(data))
 	}
 
 	return &BettingPhaseData{
@@ -322,6 +364,21 @@ func ParseGenome(bytecode []byte) (*Genome, error) {
 		Bytecode: bytecode,
 	}
 
+	// Parse the optional deal pattern and setup options between the 12-byte
+	// setup and the turn structure (see options.go)
+	setupEnd := header.SetupOffset + 12
+	if header.SetupOffset > 0 && setupEnd < header.TurnStructureOffset && int(header.TurnStructureOffset) <= len(bytecode) {
+		extra := bytecode[setupEnd:header.TurnStructureOffset]
+		pattern, err := ParseDealPattern(extra)
+		if err != nil {
+			return nil, err
+		}
+		genome.DealPattern = pattern
+		if err := parseSetupOptions(extra[1+3*int(extra[0]):], genome); err != nil {
+			return nil, err
+		}
+	}
+
 	// Parse turn structure
 	if err := genome.parseTurnStructure(); err != nil {
 		return nil, err
@@ -336,7 +393,7 @@ func ParseGenome(bytecode []byte) (*Genome, error) {
 	// Parse effects section (at end of bytecode)
 	effects, _, err := parseEffects(bytecode, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse effects: %w", err)
+		return nil, err
 	}
 	genome.Effects = effects
 
@@ -345,7 +402,7 @@ func ParseGenome(bytecode []byte) (*Genome, error) {
 	if header.CardScoringOffset >= 47 && int(header.CardScoringOffset) < len(bytecode) {
 		scoring, err := ParseCardScoringRules(bytecode[header.CardScoringOffset:])
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse card_scoring: %w", err)
+			return nil, err
 		}
 		genome.CardScoring = scoring
 	}
@@ -354,7 +411,7 @@ func ParseGenome(bytecode []byte) (*Genome, error) {
 	if header.HandEvaluationOffset >= 47 && int(header.HandEvaluationOffset) < len(bytecode) {
 		eval, err := ParseHandEvaluation(bytecode[header.HandEvaluationOffset:])
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse hand_evaluation: %w", err)
+			return nil, err
 		}
 		genome.HandEval = eval
 	}
@@ -365,7 +422,10 @@ func ParseGenome(bytecode []byte) (*Genome, error) {
 func (g *Genome) parseTurnStructure() error {
 	offset := g.Header.TurnStructureOffset
 	if offset < 0 || offset >= int32(len(g.Bytecode)) {
-		return errors.New("invalid turn structure offset")
+		return parseError(SectionTurnStructure, ErrInvalidOffset, "offset %d in %d bytes", offset, len(g.Bytecode))
+	}
+	if offset+4 > int32(len(g.Bytecode)) {
+		return parseError(SectionTurnStructure, ErrTruncated, "missing phase count")
 	}
 
 	phaseCount := int(binary.BigEndian.Uint32(g.Bytecode[offset : offset+4]))
@@ -375,7 +435,7 @@ func (g *Genome) parseTurnStructure() error {
 
 	for i := 0; i < phaseCount; i++ {
 		if offset >= int32(len(g.Bytecode)) {
-			return errors.New("unexpected end of bytecode in turn structure")
+			return parseError(SectionTurnStructure, ErrTruncated, "missing phase %d of %d", i, phaseCount)
 		}
 		phaseType := g.Bytecode[offset]
 		offset++
@@ -384,19 +444,19 @@ func (g *Genome) parseTurnStructure() error {
 		// Python bytecode format (phase_type already read):
 		var phaseLen int
 		switch phaseType {
-		case PhaseTypeDraw: // DrawPhase: source:1 + count:4 + mandatory:1 + has_condition:1 = 7 bytes
+		case PhaseTypeDraw: // DrawPhase: source:1 + count:4 + mandatory:1 + flags:1 = 7 bytes
 			baseLen := 7
 			if offset+int32(baseLen) > int32(len(g.Bytecode)) {
-				return errors.New("invalid draw phase data")
+				return parseError(SectionTurnStructure, ErrTruncated, "draw phase %d", i)
 			}
-			hasCondition := g.Bytecode[offset+6]
+			flags := g.Bytecode[offset+6]
 			phaseLen = baseLen
-			if hasCondition == 1 {
+			if flags&DrawFlagCondition != 0 {
 				phaseLen += 7 // Add condition bytes
 			}
 		case PhaseTypePlay: // PlayPhase: target:1 + min:1 + max:1 + mandatory:1 + pass_if_unable:1 + conditionLen:4 + condition
 			if offset+9 > int32(len(g.Bytecode)) {
-				return errors.New("invalid play phase header")
+				return parseError(SectionTurnStructure, ErrTruncated, "play phase %d header", i)
 			}
 			conditionLen := int(binary.BigEndian.Uint32(g.Bytecode[offset+5 : offset+9]))
 			phaseLen = 9 + conditionLen
@@ -410,13 +470,22 @@ func (g *Genome) parseTurnStructure() error {
 			phaseLen = 10
 		case PhaseTypeBidding: // BiddingPhase: opcode:1 + min_bid:1 + max_bid:1 + flags:1 + scoring:12 = 16 bytes
 			phaseLen = 16
+		case PhaseTypeReveal: // RevealPhase: no data, players reveal in turn order
+			phaseLen = 0
+		case PhaseTypeAction: // ActionPhase: count:1 + count * (opcode:1 + arg:1)
+			if offset+1 > int32(len(g.Bytecode)) {
+				return parseError(SectionTurnStructure, ErrTruncated, "action phase %d header", i)
+			}
+			phaseLen = 1 + int(g.Bytecode[offset])*actionLen
+		case PhaseTypeSimultaneous: // SimultaneousPhase: outcome:1
+			phaseLen = 1
 		default:
-			return fmt.Errorf("unknown phase type: %d", phaseType)
+			return parseError(SectionTurnStructure, ErrUnknownPhaseType, "phase %d has type %d", i, phaseType)
 		}
 
 		phaseEnd := offset + int32(phaseLen)
 		if phaseEnd > int32(len(g.Bytecode)) {
-			return errors.New("phase data exceeds bytecode length")
+			return parseError(SectionTurnStructure, ErrTruncated, "phase %d data", i)
 		}
 
 		phaseData := make([]byte, phaseLen)
@@ -513,7 +582,7 @@ func parseEffects(data []byte, offset int) (map[uint8]SpecialEffect, int, error)
 
 	// Bounds check: need count byte
 	if offset >= len(data) {
-		return nil, offset, fmt.Errorf("truncated effects section: missing count")
+		return nil, offset, parseError(SectionEffects, ErrTruncated, "missing count")
 	}
 
 	count := int(data[offset])
@@ -522,7 +591,7 @@ func parseEffects(data []byte, offset int) (map[uint8]SpecialEffect, int, error)
 	// Bounds check: need 4 bytes per effect
 	requiredBytes := count * 4
 	if offset+requiredBytes > len(data) {
-		return nil, offset, fmt.Errorf("truncated effects section: expected %d bytes, have %d",
+		return nil, offset, parseError(SectionEffects, ErrTruncated, "expected %d bytes, have %d",
 			requiredBytes, len(data)-offset)
 	}
 
@@ -544,7 +613,10 @@ func parseEffects(data []byte, offset int) (map[uint8]SpecialEffect, int, error)
 func (g *Genome) parseWinConditions() (int, error) {
 	offset := g.Header.WinConditionsOffset
 	if offset < 0 || offset >= int32(len(g.Bytecode)) {
-		return 0, errors.New("invalid win conditions offset")
+		return 0, parseError(SectionWinConditions, ErrInvalidOffset, "offset %d in %d bytes", offset, len(g.Bytecode))
+	}
+	if offset+4 > int32(len(g.Bytecode)) {
+		return 0, parseError(SectionWinConditions, ErrTruncated, "missing count")
 	}
 
 	count := int(binary.BigEndian.Uint32(g.Bytecode[offset : offset+4]))
@@ -554,7 +626,7 @@ func (g *Genome) parseWinConditions() (int, error) {
 
 	for i := 0; i < count; i++ {
 		if offset+5 > int32(len(g.Bytecode)) {
-			return 0, errors.New("win condition data exceeds bytecode length")
+			return 0, parseError(SectionWinConditions, ErrTruncated, "win condition %d of %d", i, count)
 		}
 
 		winType := g.Bytecode[offset]
@@ -589,7 +661,7 @@ func ParseCardScoringRules(data []byte) ([]CardScoringRule, error) {
 
 	for i := uint16(0); i < count; i++ {
 		if offset+5 > len(data) {
-			return nil, fmt.Errorf("incomplete scoring rule at index %d", i)
+			return nil, parseError(SectionCardScoring, ErrTruncated, "rule %d of %d", i, count)
 		}
 		rules[i] = CardScoringRule{
 			Suit:    data[offset],
@@ -624,7 +696,7 @@ func ParseHandEvaluation(data []byte) (*HandEvaluation, error) {
 		return nil, nil // NONE method - valid
 	}
 	if len(data) < 4 {
-		return nil, fmt.Errorf("hand evaluation data too short: need at least 4 bytes, got %d", len(data))
+		return nil, parseError(SectionHandEvaluation, ErrTruncated, "need at least 4 bytes, got %d", len(data))
 	}
 
 	eval := &HandEvaluation{
@@ -635,7 +707,7 @@ func ParseHandEvaluation(data []byte) (*HandEvaluation, error) {
 
 	// Validate method is known
 	if eval.Method > EvalMethodCardCount {
-		return nil, fmt.Errorf("unknown evaluation method: %d", eval.Method)
+		return nil, parseError(SectionHandEvaluation, ErrUnknownEvalMethod, "method %d", eval.Method)
 	}
 
 	offset := 3
@@ -645,7 +717,7 @@ func ParseHandEvaluation(data []byte) (*HandEvaluation, error) {
 	offset++
 
 	if offset+valueCount*cardValueSize > len(data) {
-		return nil, fmt.Errorf("truncated card values: expected %d bytes, have %d", valueCount*cardValueSize, len(data)-offset)
+		return nil, parseError(SectionHandEvaluation, ErrTruncated, "card values: expected %d bytes, have %d", valueCount*cardValueSize, len(data)-offset)
 	}
 
 	eval.CardValues = make([]CardValue, valueCount)
@@ -669,7 +741,7 @@ func ParseHandEvaluation(data []byte) (*HandEvaluation, error) {
 	eval.Patterns = make([]HandPattern, 0, patternCount)
 	for i := 0; i < patternCount; i++ {
 		if offset+patternHeaderSize > len(data) {
-			return nil, fmt.Errorf("truncated pattern header at index %d", i)
+			return nil, parseError(SectionHandEvaluation, ErrTruncated, "pattern %d header", i)
 		}
 
 		p := HandPattern{
@@ -683,13 +755,13 @@ func ParseHandEvaluation(data []byte) (*HandEvaluation, error) {
 
 		// Parse same rank groups
 		if offset >= len(data) {
-			return nil, fmt.Errorf("truncated pattern: missing group count at index %d", i)
+			return nil, parseError(SectionHandEvaluation, ErrTruncated, "pattern %d group count", i)
 		}
 		groupCount := int(data[offset])
 		offset++
 
 		if offset+groupCount > len(data) {
-			return nil, fmt.Errorf("truncated same rank groups at pattern %d", i)
+			return nil, parseError(SectionHandEvaluation, ErrTruncated, "pattern %d same rank groups", i)
 		}
 		p.SameRankGroups = make([]uint8, groupCount)
 		copy(p.SameRankGroups, data[offset:offset+groupCount])
@@ -697,13 +769,13 @@ func ParseHandEvaluation(data []byte) (*HandEvaluation, error) {
 
 		// Parse required ranks
 		if offset >= len(data) {
-			return nil, fmt.Errorf("truncated pattern: missing rank count at index %d", i)
+			return nil, parseError(SectionHandEvaluation, ErrTruncated, "pattern %d rank count", i)
 		}
 		rankCount := int(data[offset])
 		offset++
 
 		if offset+rankCount > len(data) {
-			return nil, fmt.Errorf("truncated required ranks at pattern %d", i)
+			return nil, parseError(SectionHandEvaluation, ErrTruncated, "pattern %d required ranks", i)
 		}
 		p.RequiredRanks = make([]uint8, rankCount)
 		copy(p.RequiredRanks, data[offset:offset+rankCount])
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -287,6 +288,11 @@ func TestParseGoldenSimplePokerGenome(t *testing.T) {
 	if !foundBettingPhase {
 		t.Error("simple_poker genome should have a BettingPhase")
 	}
+
+	// Setup is exactly 12 bytes, so no staged deal pattern
+	if genome.DealPattern != nil {
+		t.Errorf("Expected no deal pattern, got %+v", genome.DealPattern)
+	}
 }
 
 func TestPhaseTypeConstants(t *testing.T) {
@@ -864,3 +870,38 @@ func TestParseBiddingPhaseTooShort(t *testing.T) {
 		t.Errorf("Expected zero value for PointsPerTrickBid, got %d", scoring.PointsPerTrickBid)
 	}
 }
+
+func TestParseGenomeErrors(t *testing.T) {
+	bytecode := loadGoldenGenome(t, "war_genome.bin").Bytecode
+	header, err := ParseHeader(bytecode)
+	if err != nil {
+		t.Fatalf("Failed to parse header: %v", err)
+	}
+
+	if _, err := ParseGenome(bytecode[:20]); !errors.Is(err, ErrTruncatedHeader) {
+		t.Errorf("Expected ErrTruncatedHeader for a short header, got %v", err)
+	}
+
+	// The first phase type byte follows the 4-byte phase count
+	unknownPhase := append([]byte(nil), bytecode...)
+	unknownPhase[header.TurnStructureOffset+4] = 99
+	_, err = ParseGenome(unknownPhase)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Section != SectionTurnStructure || !errors.Is(err, ErrUnknownPhaseType) {
+		t.Errorf("Expected an unknown phase type in the turn structure, got %v", err)
+	}
+	if errors.Is(err, ErrTruncated) {
+		t.Errorf("Expected an unknown phase type not to match ErrTruncated, got %v", err)
+	}
+
+	if _, err := ParseGenome(bytecode[:header.TurnStructureOffset+6]); !errors.Is(err, ErrTruncated) {
+		t.Errorf("Expected ErrTruncated for a cut-off phase, got %v", err)
+	}
+
+	if _, err := ParseHandEvaluation([]byte{9, 0, 0, 0}); !errors.Is(err, ErrUnknownEvalMethod) {
+		t.Errorf("Expected ErrUnknownEvalMethod, got %v", err)
+	}
+	if _, err := ParseBettingPhaseData([]byte{0, 0}); !errors.As(err, &parseErr) || parseErr.Section != SectionBettingPhase {
+		t.Errorf("Expected a betting phase parse error, got %v", err)
+	}
+}
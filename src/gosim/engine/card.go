@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/signalnine/darwindeck/gosim/game"
+)
+
+// Jokers
+//
+// A joker is encoded as Suit JokerSuit, one past the four real suits, with
+// Rank numbering the jokers from 0 (a deck's two jokers are Rank 0 and 1).
+// Card stays two bytes, and code that only knows suits 0-3 sees a joker as
+// an out-of-range card rather than mistaking it for a real one. Card
+// comparison ranks jokers above every other card, and poker evaluation plays
+// them wild; elsewhere a joker has no rank or suit to match.
+
+// JokerSuit is the Card.Suit of a joker
+const JokerSuit uint8 = 4
+
+// MaxJokers is the number of distinct jokers (Rank 0 to MaxJokers-1)
+const MaxJokers = 2
+
+// Joker returns joker n (0 or 1)
+func Joker(n uint8) Card {
+	return Card{Rank: n, Suit: JokerSuit}
+}
+
+// IsJoker returns true if c is one of the MaxJokers jokers
+func (c Card) IsJoker() bool {
+	return c.Suit == JokerSuit && c.Rank < MaxJokers
+}
+
+// Conversions between the compact engine encoding (Rank 0=2..11=K, 12=A;
+// Suit 0-3 = H,D,C,S) and the 1-based game package types (Ace=1, Hearts=1).
+// The game package can't import engine, so the reverse direction lives here
+// as FromGameCard rather than as a method on game.Card.
+
+// GameRank converts an engine rank to a game.Rank
+func GameRank(rank uint8) game.Rank {
+	if rank == AceRank {
+		return game.Ace
+	}
+	return game.Rank(rank) + game.Two
+}
+
+// GameSuit converts an engine suit to a game.Suit
+func GameSuit(suit uint8) game.Suit {
+	return game.Suit(suit) + game.Hearts
+}
+
+// ToGameCard converts c to the game package representation
+func (c Card) ToGameCard() game.Card {
+	return game.Card{Rank: GameRank(c.Rank), Suit: GameSuit(c.Suit)}
+}
+
+// FromGameCard converts a game package card to the engine representation
+func FromGameCard(c game.Card) Card {
+	rank := AceRank
+	if c.Rank != game.Ace {
+		rank = uint8(c.Rank - game.Two)
+	}
+	return Card{Rank: rank, Suit: uint8(c.Suit - game.Hearts)}
+}
+
+// String returns the card as a string (e.g., "AH"), using game.Card
+// formatting; jokers are "JK1" and "JK2"
+func (c Card) String() string {
+	if c.IsJoker() {
+		return fmt.Sprintf("JK%d", c.Rank+1)
+	}
+	return c.ToGameCard().String()
+}
+
+// Label returns the card for display with a suit glyph (e.g., "A♥"); jokers
+// show as a joker glyph and number
+func (c Card) Label() string {
+	if c.IsJoker() {
+		return fmt.Sprintf("🃏%d", c.Rank+1)
+	}
+	return c.ToGameCard().Label()
+}
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/signalnine/darwindeck/gosim/game"
+)
+
+func TestCard_GameCardRoundTrip(t *testing.T) {
+	seen := make(map[game.Card]bool)
+	for suit := uint8(0); suit < 4; suit++ {
+		for rank := uint8(0); rank < 13; rank++ {
+			card := Card{Rank: rank, Suit: suit}
+			gc := card.ToGameCard()
+			if seen[gc] {
+				t.Errorf("Duplicate game card %s for %+v", gc, card)
+			}
+			seen[gc] = true
+			if back := FromGameCard(gc); back != card {
+				t.Errorf("Round trip %+v -> %s -> %+v", card, gc, back)
+			}
+		}
+	}
+
+	// Every card of game.NewDeck converts back the same way
+	for _, gc := range game.NewDeck() {
+		if FromGameCard(gc).ToGameCard() != gc {
+			t.Errorf("Round trip failed for %s", gc)
+		}
+	}
+}
+
+func TestCard_String(t *testing.T) {
+	tests := []struct {
+		card  Card
+		want  string
+		label string
+	}{
+		{Card{Rank: AceRank, Suit: 0}, "AH", "A♥"},
+		{Card{Rank: 0, Suit: 1}, "2D", "2♦"},
+		{Card{Rank: 8, Suit: 2}, "10C", "10♣"},
+		{Card{Rank: 11, Suit: 3}, "KS", "K♠"},
+		{Card{Rank: 13, Suit: 4}, "??", "??"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.card.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.card, got, tt.want)
+		}
+		if got := tt.card.Label(); got != tt.label {
+			t.Errorf("%+v.Label() = %q, want %q", tt.card, got, tt.label)
+		}
+	}
+}
+
+func TestCard_Joker(t *testing.T) {
+	for n := uint8(0); n < MaxJokers; n++ {
+		if joker := Joker(n); !joker.IsJoker() || joker.Suit != JokerSuit {
+			t.Errorf("Expected Joker(%d) to be a joker, got %+v", n, joker)
+		}
+	}
+	if (Card{Rank: 13, Suit: JokerSuit}).IsJoker() || (Card{Rank: 0, Suit: 3}).IsJoker() {
+		t.Error("Expected only ranks below MaxJokers in the joker suit to be jokers")
+	}
+	if got := Joker(1).String(); got != "JK2" {
+		t.Errorf("Joker(1).String() = %q, want JK2", got)
+	}
+	if got := Joker(0).Label(); got != "🃏1" {
+		t.Errorf("Joker(0).Label() = %q, want 🃏1", got)
+	}
+}
@@ -72,16 +72,9 @@ func EvaluateCondition(state *GameState, playerID uint8, conditionBytes []byte)
 	// Optional extensions: pattern matching
 	case OpCheckHasSetOfN:
 		// Detect N cards of same rank in player's hand
-		requiredCount := int(value)
-		rankCounts := make(map[uint8]int)
-
-		for _, card := range state.Players[playerID].Hand {
-			rankCounts[card.Rank]++
-			if rankCounts[card.Rank] >= requiredCount {
-				return true
-			}
-		}
-		return false
+		hand := state.Players[playerID].Hand
+		rankCounts := CountRanks(hand)
+		return len(hand) > 0 && rankCounts.Max() >= int(value)
 
 	case OpCheckHasRunOfN:
 		// Detect N cards in sequence (any suit, sequential ranks)
@@ -96,18 +89,19 @@ func EvaluateCondition(state *GameState, playerID uint8, conditionBytes []byte)
 		sorted := make([]Card, len(hand))
 		copy(sorted, hand)
 		sort.Slice(sorted, func(i, j int) bool {
-			return sorted[i].Rank < sorted[j].Rank
+			return state.RankValue(sorted[i].Rank) < state.RankValue(sorted[j].Rank)
 		})
 
 		// Find sequential run
 		runLength := 1
 		for i := 1; i < len(sorted); i++ {
-			if sorted[i].Rank == sorted[i-1].Rank+1 {
+			value, prevValue := state.RankValue(sorted[i].Rank), state.RankValue(sorted[i-1].Rank)
+			if value == prevValue+1 {
 				runLength++
 				if runLength >= requiredLength {
 					return true
 				}
-			} else if sorted[i].Rank != sorted[i-1].Rank {
+			} else if value != prevValue {
 				// Different rank, not sequential - reset counter
 				runLength = 1
 			}
@@ -177,16 +171,22 @@ func compareInt64(actual int64, operator uint8, value int64) bool {
 }
 
 func getReferencedCard(state *GameState, reference uint8) *Card {
+	pile := getReferencedPile(state, reference)
+	if len(pile) == 0 {
+		return nil
+	}
+	return &pile[len(pile)-1]
+}
+
+// getReferencedPile returns the pile a card reference points into
+func getReferencedPile(state *GameState, reference uint8) []Card {
 	switch reference {
 	case 1: // top_discard
-		if len(state.Discard) > 0 {
-			return &state.Discard[len(state.Discard)-1]
-		}
+		return state.Discard
 	case 2, 3: // last_played / tableau_top (top of tableau pile)
 		// Reference 2 = "last_played", Reference 3 = "tableau" (both mean top of tableau)
-		if len(state.Tableau) > 0 && len(state.Tableau[0]) > 0 {
-			pile := state.Tableau[0]
-			return &pile[len(pile)-1]
+		if len(state.Tableau) > 0 {
+			return state.Tableau[0]
 		}
 	}
 	return nil
@@ -195,6 +195,12 @@ func getReferencedCard(state *GameState, reference uint8) *Card {
 // EvaluateCardCondition checks if a candidate card satisfies a condition.
 // Used for valid_play_condition evaluation in PlayPhase.
 func EvaluateCardCondition(state *GameState, playerID uint8, candidateCard Card, conditionBytes []byte) bool {
+	return evaluateCardCondition(state, playerID, candidateCard, 1, conditionBytes)
+}
+
+// evaluateCardCondition checks a condition for a play of setSize cards of
+// candidateCard's rank (1 for a single-card play)
+func evaluateCardCondition(state *GameState, playerID uint8, candidateCard Card, setSize int, conditionBytes []byte) bool {
 	if len(conditionBytes) < 7 {
 		return false
 	}
@@ -230,21 +236,22 @@ func EvaluateCardCondition(state *GameState, playerID uint8, candidateCard Card,
 		return candidateCard.Suit == refCard.Suit
 
 	case OpCheckCardBeatsTop:
-		// CARD_BEATS_TOP: Check if candidate beats reference card (President/Daifugo)
-		// Higher rank wins, same rank is allowed (multiple cards of same rank can be played)
-		refCard := getReferencedCard(state, reference)
-		if refCard == nil {
+		// CARD_BEATS_TOP: Check if the play beats the last set played (President/Daifugo):
+		// the same number of cards and a strictly higher rank, or with a suit order the
+		// same rank led by a higher suit (Big Two). candidateCard is the play's highest card.
+		refSet := topSet(getReferencedPile(state, reference))
+		if len(refSet) == 0 {
 			return true // No reference card = any card valid
 		}
-		return candidateCard.Rank >= refCard.Rank
+		return setSize == len(refSet) && state.CompareCards(candidateCard, state.highestCard(refSet)) > 0
 
 	case OpAnd:
 		// Compound AND: all nested conditions must be true
-		return evaluateCompoundCardCondition(state, playerID, candidateCard, conditionBytes, true)
+		return evaluateCompoundCardCondition(state, playerID, candidateCard, setSize, conditionBytes, true)
 
 	case OpOr:
 		// Compound OR: at least one nested condition must be true
-		return evaluateCompoundCardCondition(state, playerID, candidateCard, conditionBytes, false)
+		return evaluateCompoundCardCondition(state, playerID, candidateCard, setSize, conditionBytes, false)
 
 	default:
 		// For non-card conditions, delegate to EvaluateCondition
@@ -253,7 +260,7 @@ func EvaluateCardCondition(state *GameState, playerID uint8, candidateCard Card,
 }
 
 // evaluateCompoundCardCondition evaluates compound AND/OR conditions for a card
-func evaluateCompoundCardCondition(state *GameState, playerID uint8, candidateCard Card, conditionBytes []byte, isAnd bool) bool {
+func evaluateCompoundCardCondition(state *GameState, playerID uint8, candidateCard Card, setSize int, conditionBytes []byte, isAnd bool) bool {
 	if len(conditionBytes) < 5 {
 		return false
 	}
@@ -283,7 +290,7 @@ func evaluateCompoundCardCondition(state *GameState, playerID uint8, candidateCa
 			return false
 		}
 
-		result := EvaluateCardCondition(state, playerID, candidateCard, conditionBytes[offset:offset+nestedLen])
+		result := evaluateCardCondition(state, playerID, candidateCard, setSize, conditionBytes[offset:offset+nestedLen])
 
 		if isAnd && !result {
 			return false // AND: any false = false
@@ -0,0 +1,62 @@
+package engine
+
+// RankCounts holds the number of cards of each rank, indexed by Card.Rank.
+// Fixed-size arrays keep counting allocation-free in move generation and
+// hand evaluation hot loops.
+type RankCounts [13]int
+
+// SuitCounts holds the number of cards of each suit, indexed by Card.Suit
+type SuitCounts [4]int
+
+// CountRanks counts cards by rank, ignoring jokers and out-of-range ranks
+func CountRanks(cards []Card) RankCounts {
+	var counts RankCounts
+	for _, card := range cards {
+		if int(card.Rank) < len(counts) && card.Suit != JokerSuit {
+			counts[card.Rank]++
+		}
+	}
+	return counts
+}
+
+// CountSuits counts cards by suit, ignoring out-of-range suits
+func CountSuits(cards []Card) SuitCounts {
+	var counts SuitCounts
+	for _, card := range cards {
+		if int(card.Suit) < len(counts) {
+			counts[card.Suit]++
+		}
+	}
+	return counts
+}
+
+// Distinct returns the number of ranks present
+func (c RankCounts) Distinct() int {
+	n := 0
+	for _, count := range c {
+		if count > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Max returns the size of the largest group of one rank
+func (c RankCounts) Max() int {
+	return maxCount(c[:])
+}
+
+// Max returns the size of the largest group of one suit
+func (c SuitCounts) Max() int {
+	return maxCount(c[:])
+}
+
+func maxCount(counts []int) int {
+	m := 0
+	for _, count := range counts {
+		if count > m {
+			m = count
+		}
+	}
+	return m
+}
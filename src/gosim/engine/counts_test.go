@@ -0,0 +1,77 @@
+package engine
+
+import "testing"
+
+var countsHand = []Card{
+	{Rank: 12, Suit: 0}, {Rank: 3, Suit: 1}, {Rank: 12, Suit: 2}, {Rank: 7, Suit: 0},
+	{Rank: 3, Suit: 3}, {Rank: 12, Suit: 1}, {Rank: 0, Suit: 0},
+}
+
+func TestCountRanks(t *testing.T) {
+	counts := CountRanks(countsHand)
+
+	want := map[uint8]int{12: 3, 3: 2, 7: 1, 0: 1}
+	for rank, count := range counts {
+		if count != want[uint8(rank)] {
+			t.Errorf("Rank %d: expected %d, got %d", rank, want[uint8(rank)], count)
+		}
+	}
+	if counts.Distinct() != 4 {
+		t.Errorf("Expected 4 distinct ranks, got %d", counts.Distinct())
+	}
+	if counts.Max() != 3 {
+		t.Errorf("Expected largest group of 3, got %d", counts.Max())
+	}
+
+	// Out-of-range ranks are ignored rather than panicking
+	empty := CountRanks([]Card{{Rank: 200, Suit: 0}})
+	if empty.Distinct() != 0 || empty.Max() != 0 {
+		t.Errorf("Expected out-of-range rank to be ignored, got %v", empty)
+	}
+}
+
+func TestCountSuits(t *testing.T) {
+	counts := CountSuits(countsHand)
+
+	if counts != (SuitCounts{3, 2, 1, 1}) {
+		t.Errorf("Expected suit counts [3 2 1 1], got %v", counts)
+	}
+	if counts.Max() != 3 {
+		t.Errorf("Expected largest suit of 3, got %d", counts.Max())
+	}
+	if CountSuits(nil).Max() != 0 {
+		t.Error("Expected no suits for an empty hand")
+	}
+}
+
+// countRanksMap is the map-based counting CountRanks replaced
+func countRanksMap(cards []Card) map[uint8]int {
+	counts := make(map[uint8]int)
+	for _, card := range cards {
+		counts[card.Rank]++
+	}
+	return counts
+}
+
+var benchMaxCount int
+
+func BenchmarkCountRanks(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		counts := CountRanks(countsHand)
+		benchMaxCount = counts.Max()
+	}
+}
+
+func BenchmarkCountRanksMap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m := 0
+		for _, count := range countRanksMap(countsHand) {
+			if count > m {
+				m = count
+			}
+		}
+		benchMaxCount = m
+	}
+}
@@ -0,0 +1,229 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Bytecode crossover
+//
+// CrossoverGenomes recombines two bytecode genomes section by section, in
+// the order the compiler lays them out after the header: setup (with any
+// deal pattern), turn structure, win conditions, effects, scoring, card
+// scoring, hand evaluation and teams. The child takes each trait from one
+// parent or the other:
+//
+//	setup        setup section
+//	turns        turn structure, max turns, tableau mode, sequence direction
+//	wins         win conditions
+//	effects      special effects
+//	scoring      scoring and card scoring
+//	evaluation   hand evaluation
+//	players      player count and teams
+//
+// and is written with a fresh 53-byte header whose offsets point at where
+// the sections landed. A section the chosen parent doesn't have (older
+// bytecode without hand evaluation, say) is copied from the other parent.
+// The child has to parse and pass playableGenome, or the traits are drawn
+// again, up to maxCrossoverAttempts times.
+
+// Crossover traits, indexes into a draw of parents
+const (
+	traitSetup = iota
+	traitTurns
+	traitWins
+	traitEffects
+	traitScoring
+	traitEvaluation
+	traitPlayers
+	numTraits
+)
+
+// maxCrossoverAttempts is how many trait draws CrossoverGenomes tries before
+// returning a copy of the first parent
+const maxCrossoverAttempts = 10
+
+// crossoverHeaderSize is the header CrossoverGenomes writes, with team fields
+const crossoverHeaderSize = 53
+
+// genomeSections is bytecode split at its section offsets
+type genomeSections struct {
+	header                      *BytecodeHeader
+	setup, turns, wins, effects []byte
+	scoring, cardScoring, eval  []byte
+	teams                       []byte
+}
+
+// splitSections splits bytecode into its sections, returning false if the
+// offsets aren't laid out as the compiler writes them
+func splitSections(bytecode []byte) (*genomeSections, bool) {
+	genome, err := ParseGenome(bytecode)
+	if err != nil || genome.Header.BytecodeVersion < 2 {
+		return nil, false
+	}
+	h := genome.Header
+	winsEnd := int(h.WinConditionsOffset) + 4 + 5*len(genome.WinConditions)
+	if h.SetupOffset < 39 || h.SetupOffset >= h.TurnStructureOffset || h.TurnStructureOffset >= h.WinConditionsOffset ||
+		winsEnd > int(h.ScoringOffset) || int(h.ScoringOffset) > len(bytecode) {
+		return nil, false
+	}
+
+	// Optional trailing sections end where the next present one starts
+	end := len(bytecode)
+	teamsAt := 0
+	if h.SetupOffset >= crossoverHeaderSize && h.TeamDataOffset > int(h.ScoringOffset) && h.TeamDataOffset < end {
+		teamsAt = h.TeamDataOffset
+	}
+	bounds := []int{int(h.ScoringOffset)}
+	last := bounds[0]
+	for _, at := range []int{int(h.CardScoringOffset), int(h.HandEvaluationOffset), teamsAt} {
+		// As in ParseGenome, offsets inside a 47-byte header mean absent
+		if at < 47 || at < last || at >= end {
+			bounds = append(bounds, -1)
+			continue
+		}
+		bounds = append(bounds, at)
+		last = at
+	}
+	section := func(i int) []byte {
+		if bounds[i] < 0 {
+			return nil
+		}
+		stop := end
+		for _, next := range bounds[i+1:] {
+			if next >= 0 {
+				stop = next
+				break
+			}
+		}
+		return bytecode[bounds[i]:stop]
+	}
+
+	return &genomeSections{
+		header:      h,
+		setup:       bytecode[h.SetupOffset:h.TurnStructureOffset],
+		turns:       bytecode[h.TurnStructureOffset:h.WinConditionsOffset],
+		wins:        bytecode[h.WinConditionsOffset:winsEnd],
+		effects:     bytecode[winsEnd:h.ScoringOffset],
+		scoring:     section(0),
+		cardScoring: section(1),
+		eval:        section(2),
+		teams:       section(3),
+	}, true
+}
+
+// CrossoverGenomes returns a child of bytecode genomes a and b (see above),
+// drawing the parent of each trait from seed so the same inputs always give
+// the same child. The child takes at least one trait from each parent
+// where they have two they disagree on. If
+// either parent can't be split into sections, it returns a copy of the
+// other (or of a, if neither can).
+func CrossoverGenomes(a, b []byte, seed uint64) []byte {
+	sa, okA := splitSections(a)
+	sb, okB := splitSections(b)
+	switch {
+	case !okA && okB:
+		return append([]byte(nil), b...)
+	case !okA || !okB:
+		return append([]byte(nil), a...)
+	}
+
+	// Only traits the parents disagree on make the child a mix
+	differs := make([]int, 0, numTraits)
+	for trait, same := range [numTraits]bool{
+		traitSetup:      bytes.Equal(sa.setup, sb.setup),
+		traitTurns:      bytes.Equal(sa.turns, sb.turns) && sa.header.MaxTurns == sb.header.MaxTurns,
+		traitWins:       bytes.Equal(sa.wins, sb.wins),
+		traitEffects:    bytes.Equal(sa.effects, sb.effects),
+		traitScoring:    bytes.Equal(sa.scoring, sb.scoring) && bytes.Equal(sa.cardScoring, sb.cardScoring),
+		traitEvaluation: bytes.Equal(sa.eval, sb.eval),
+		traitPlayers:    sa.header.PlayerCount == sb.header.PlayerCount && bytes.Equal(sa.teams, sb.teams),
+	} {
+		if !same {
+			differs = append(differs, trait)
+		}
+	}
+
+	m := &mutator{rng: seed}
+	parents := [2]*genomeSections{sa, sb}
+	for attempt := 0; attempt < maxCrossoverAttempts; attempt++ {
+		var from [numTraits]int
+		for i := range from {
+			from[i] = m.intn(2)
+		}
+		fromB := 0
+		for _, trait := range differs {
+			fromB += from[trait]
+		}
+		if len(differs) > 1 && (fromB == 0 || fromB == len(differs)) {
+			// All from one parent: give the other a trait
+			from[differs[m.intn(len(differs))]] ^= 1
+		}
+
+		child := assembleChild(parents, from)
+		if genome, err := ParseGenome(child); err == nil && playableGenome(genome) {
+			return child
+		}
+	}
+	return append([]byte(nil), a...)
+}
+
+// assembleChild writes a genome whose trait i comes from parents[from[i]]
+func assembleChild(parents [2]*genomeSections, from [numTraits]int) []byte {
+	// pick returns the chosen parent's section, or the other's if it has none
+	pick := func(trait int, section func(*genomeSections) []byte) []byte {
+		if s := section(parents[from[trait]]); len(s) > 0 {
+			return s
+		}
+		return section(parents[1-from[trait]])
+	}
+	setup := pick(traitSetup, func(s *genomeSections) []byte { return s.setup })
+	turns := pick(traitTurns, func(s *genomeSections) []byte { return s.turns })
+	wins := pick(traitWins, func(s *genomeSections) []byte { return s.wins })
+	effects := parents[from[traitEffects]].effects
+	scoring := pick(traitScoring, func(s *genomeSections) []byte { return s.scoring })
+	cardScoring := pick(traitScoring, func(s *genomeSections) []byte { return s.cardScoring })
+	eval := pick(traitEvaluation, func(s *genomeSections) []byte { return s.eval })
+	players := parents[from[traitPlayers]]
+	teams := players.teams
+	if len(teams) == 0 {
+		teams = []byte{0} // No teams
+	}
+
+	child := make([]byte, crossoverHeaderSize, crossoverHeaderSize+len(setup)+len(turns)+len(wins)+
+		len(effects)+len(scoring)+len(cardScoring)+len(eval)+len(teams))
+	offsets := make([]int, 0, 8)
+	for _, s := range [][]byte{setup, turns, wins, effects, scoring, cardScoring, eval, teams} {
+		offsets = append(offsets, len(child))
+		child = append(child, s...)
+	}
+	// An absent section gets offset 0, which the parser skips
+	sectionAt := func(i int, s []byte) uint32 {
+		if len(s) == 0 {
+			return 0
+		}
+		return uint32(offsets[i])
+	}
+
+	turnsFrom := parents[from[traitTurns]].header
+	child[0] = 2
+	binary.BigEndian.PutUint32(child[1:5], 1)
+	hashA, hashB := parents[0].header.GenomeIDHash, parents[1].header.GenomeIDHash
+	binary.BigEndian.PutUint64(child[5:13], hashA^(hashB<<1|hashB>>63))
+	binary.BigEndian.PutUint32(child[13:17], players.header.PlayerCount)
+	binary.BigEndian.PutUint32(child[17:21], turnsFrom.MaxTurns)
+	binary.BigEndian.PutUint32(child[21:25], uint32(offsets[0]))
+	binary.BigEndian.PutUint32(child[25:29], uint32(offsets[1]))
+	binary.BigEndian.PutUint32(child[29:33], uint32(offsets[2]))
+	binary.BigEndian.PutUint32(child[33:37], uint32(offsets[4]))
+	child[37] = turnsFrom.TableauMode
+	child[38] = turnsFrom.SequenceDirection
+	binary.BigEndian.PutUint32(child[39:43], sectionAt(5, cardScoring))
+	binary.BigEndian.PutUint32(child[43:47], sectionAt(6, eval))
+	if len(players.teams) > 0 && players.header.TeamMode {
+		child[47] = 1
+		child[48] = uint8(players.header.TeamCount)
+	}
+	binary.BigEndian.PutUint32(child[49:53], uint32(offsets[7]))
+	return child
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCrossoverGenomesCombinesParents(t *testing.T) {
+	hearts := loadGoldenGenome(t, "hearts_genome.bin")
+	poker := loadGoldenGenome(t, "simple_poker_genome.bin")
+
+	mixed := false
+	for seed := uint64(1); seed <= 40; seed++ {
+		child, err := ParseGenome(CrossoverGenomes(hearts.Bytecode, poker.Bytecode, seed))
+		if err != nil || !playableGenome(child) {
+			t.Fatalf("Seed %d: offspring doesn't make a valid genome: %v", seed, err)
+		}
+
+		// Every section is one parent's, and both parents contribute where they differ
+		sc, _ := splitSections(child.Bytecode)
+		sh, _ := splitSections(hearts.Bytecode)
+		sp, _ := splitSections(poker.Bytecode)
+		fromHearts, fromPoker := 0, 0
+		for _, section := range []func(*genomeSections) []byte{
+			func(s *genomeSections) []byte { return s.setup },
+			func(s *genomeSections) []byte { return s.turns },
+			func(s *genomeSections) []byte { return s.wins },
+			func(s *genomeSections) []byte { return s.effects },
+			func(s *genomeSections) []byte { return append(append([]byte(nil), s.scoring...), s.cardScoring...) },
+			func(s *genomeSections) []byte { return s.eval },
+		} {
+			switch {
+			case bytes.Equal(section(sh), section(sp)):
+			case bytes.Equal(section(sc), section(sh)):
+				fromHearts++
+			case bytes.Equal(section(sc), section(sp)):
+				fromPoker++
+			default:
+				t.Fatalf("Seed %d: section % x is neither parent's", seed, section(sc))
+			}
+		}
+		switch child.Header.PlayerCount {
+		case hearts.Header.PlayerCount:
+			if hearts.Header.PlayerCount != poker.Header.PlayerCount {
+				fromHearts++
+			}
+		case poker.Header.PlayerCount:
+			fromPoker++
+		default:
+			t.Fatalf("Seed %d: expected a parent's player count, got %d", seed, child.Header.PlayerCount)
+		}
+		if fromHearts == 0 || fromPoker == 0 {
+			t.Errorf("Seed %d: expected sections of both parents, got %d from hearts and %d from poker", seed, fromHearts, fromPoker)
+		}
+		childParams := ReadSetupParams(child)
+
+		// Betting only survives with chips to bet
+		if child.TurnPhases[0].PhaseType == PhaseTypeBetting && childParams.StartingChips == 0 {
+			t.Errorf("Seed %d: expected betting offspring to get poker's chips", seed)
+		}
+		if child.TurnPhases[0].PhaseType == PhaseTypeTrick && reflect.DeepEqual(child.WinConditions, poker.WinConditions) {
+			mixed = true
+		}
+
+		state := SetupGame(child, seed)
+		GenerateLegalMoves(state, child)
+		PutState(state)
+	}
+	if !mixed {
+		t.Error("Expected some offspring to play hearts' tricks to poker's win conditions")
+	}
+}
+
+func TestCrossoverGenomesDeterministic(t *testing.T) {
+	war := loadGoldenGenome(t, "war_genome.bin").Bytecode
+	hearts := loadGoldenGenome(t, "hearts_genome.bin").Bytecode
+	first := CrossoverGenomes(war, hearts, 7)
+	if second := CrossoverGenomes(war, hearts, 7); !bytes.Equal(first, second) {
+		t.Error("Expected the same seed to give the same child")
+	}
+
+	// A parent that can't be split leaves the other to copy
+	if got := CrossoverGenomes([]byte{1, 2, 3}, hearts, 7); !bytes.Equal(got, hearts) {
+		t.Error("Expected a copy of the valid parent")
+	}
+}
@@ -0,0 +1,142 @@
+package engine
+
+import "fmt"
+
+// DealStage describes the cards dealt in one street of a staged deal
+type DealStage struct {
+	FaceDown  uint8 // Cards dealt to each player's hand, hidden from opponents
+	FaceUp    uint8 // Cards dealt to each player's hand, visible to opponents
+	Community uint8 // Shared cards dealt to GameState.Community
+}
+
+// DealPattern is an ordered list of deal stages. Stage 0 is dealt at game
+// start; later stages are dealt between betting rounds (flop, turn, river).
+type DealPattern struct {
+	Stages []DealStage
+}
+
+// HoldemDealPattern returns the Texas Hold'em pattern:
+// 2 hole cards, then a 3-card flop, 1-card turn and 1-card river.
+func HoldemDealPattern() *DealPattern {
+	return &DealPattern{Stages: []DealStage{
+		{FaceDown: 2},
+		{Community: 3},
+		{Community: 1},
+		{Community: 1},
+	}}
+}
+
+// ParseDealPattern extracts a deal pattern from the optional bytes that
+// follow the 12-byte setup section.
+// Format: stage_count:1 + [face_down:1 + face_up:1 + community:1]...
+// Returns nil (no pattern) for empty data or a zero stage count.
+func ParseDealPattern(data []byte) (*DealPattern, error) {
+	if len(data) == 0 || data[0] == 0 {
+		return nil, nil
+	}
+
+	stageCount := int(data[0])
+	if len(data) < 1+stageCount*3 {
+		return nil, parseError(SectionDealPattern, ErrTruncated, "need %d bytes, got %d", 1+stageCount*3, len(data))
+	}
+
+	pattern := &DealPattern{Stages: make([]DealStage, stageCount)}
+	for i := 0; i < stageCount; i++ {
+		offset := 1 + i*3
+		pattern.Stages[i] = DealStage{
+			FaceDown:  data[offset],
+			FaceUp:    data[offset+1],
+			Community: data[offset+2],
+		}
+	}
+	return pattern, nil
+}
+
+// Deal deals the given stage of pattern and advances state.DealRound.
+// Player cards are dealt round-robin from the deck one card at a time,
+// face-down cards before face-up ones; community cards go to state.Community.
+// Face-up cards are held in the hand and marked visible (see IsFaceUp).
+// Returns false if the round is out of range or the deck ran out.
+func Deal(state *GameState, pattern *DealPattern, round int) bool {
+	if pattern == nil || round < 0 || round >= len(pattern.Stages) {
+		return false
+	}
+	stage := pattern.Stages[round]
+
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 || numPlayers > len(state.Players) {
+		numPlayers = len(state.Players)
+	}
+
+	ok := true
+	perPlayer := int(stage.FaceDown) + int(stage.FaceUp)
+	for i := 0; i < perPlayer; i++ {
+		faceUp := i >= int(stage.FaceDown)
+		for p := 0; p < numPlayers; p++ {
+			if !state.DrawCard(uint8(p), LocationDeck) {
+				ok = false
+				continue
+			}
+			if faceUp {
+				player := &state.Players[p]
+				player.setFaceUp(player.Hand[len(player.Hand)-1])
+				state.MarkSeen(player.Hand[len(player.Hand)-1])
+			}
+		}
+	}
+
+	for i := 0; i < int(stage.Community); i++ {
+		if len(state.Deck) == 0 {
+			ok = false
+			break
+		}
+		card := state.Deck[len(state.Deck)-1]
+		state.Deck = state.Deck[:len(state.Deck)-1]
+		state.Community = append(state.Community, card)
+		state.MarkSeen(card)
+	}
+
+	state.DealRound = round + 1
+	return ok
+}
+
+// HasMoreStreets reports whether pattern has stages left to deal
+func HasMoreStreets(state *GameState, pattern *DealPattern) bool {
+	return pattern != nil && state.DealRound < len(pattern.Stages)
+}
+
+// DealFixed installs an exact deal: hands[i] becomes player i's hand and deck
+// the remaining deck, top card last. There must be one hand per player, and
+// the cards must be valid and distinct from each other and from the cards in
+// the other zones (see ValidateState). On error state is left unchanged.
+// Tests and scripted scenarios use it in place of a shuffled deal.
+func DealFixed(state *GameState, hands [][]Card, deck []Card) error {
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+	if len(hands) != numPlayers || numPlayers > len(state.Players) {
+		return fmt.Errorf("deal has %d hands for %d players", len(hands), numPlayers)
+	}
+
+	// Check the result on a copy before touching state
+	check := state.Clone()
+	defer PutState(check)
+	installFixedDeal(check, hands, deck)
+	if err := ValidateState(check); err != nil {
+		return fmt.Errorf("invalid deal: %w", err)
+	}
+
+	installFixedDeal(state, hands, deck)
+	return nil
+}
+
+// installFixedDeal copies hands and deck into state, face down
+func installFixedDeal(state *GameState, hands [][]Card, deck []Card) {
+	for i, hand := range hands {
+		player := &state.Players[i]
+		player.Hand = append(player.Hand[:0], hand...)
+		player.FaceUp = player.FaceUp[:0]
+	}
+	state.Deck = append(state.Deck[:0], deck...)
+}
@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newDealState returns a state with a full ordered 52-card deck
+func newDealState(numPlayers int) *GameState {
+	state := GetStateN(numPlayers)
+	state.NumPlayers = uint8(numPlayers)
+	for suit := uint8(0); suit < 4; suit++ {
+		for rank := uint8(0); rank < 13; rank++ {
+			state.Deck = append(state.Deck, Card{Rank: rank, Suit: suit})
+		}
+	}
+	return state
+}
+
+func TestDeal_Holdem(t *testing.T) {
+	state := newDealState(3)
+	defer PutState(state)
+	pattern := HoldemDealPattern()
+
+	if !Deal(state, pattern, 0) {
+		t.Fatal("Expected preflop deal to succeed")
+	}
+	for p := 0; p < 3; p++ {
+		if len(state.Players[p].Hand) != 2 {
+			t.Errorf("Player %d: expected 2 hole cards, got %d", p, len(state.Players[p].Hand))
+		}
+	}
+	if len(state.Community) != 0 {
+		t.Errorf("Expected no community cards preflop, got %d", len(state.Community))
+	}
+
+	// Flop, turn and river go to the board without touching hands
+	wantBoard := []int{3, 4, 5}
+	for round := 1; round <= 3; round++ {
+		if !HasMoreStreets(state, pattern) {
+			t.Fatalf("Expected street %d to remain", round)
+		}
+		if !Deal(state, pattern, state.DealRound) {
+			t.Fatalf("Expected street %d to deal", round)
+		}
+		if got := len(state.Community); got != wantBoard[round-1] {
+			t.Errorf("Street %d: expected %d community cards, got %d", round, wantBoard[round-1], got)
+		}
+		for p := 0; p < 3; p++ {
+			if len(state.Players[p].Hand) != 2 {
+				t.Errorf("Street %d: player %d hand changed to %d cards", round, p, len(state.Players[p].Hand))
+			}
+		}
+	}
+
+	if HasMoreStreets(state, pattern) {
+		t.Error("Expected no streets after the river")
+	}
+	if len(state.Tableau) != 0 {
+		t.Errorf("Expected the board to stay off the tableau, got %d piles", len(state.Tableau))
+	}
+	if len(state.Deck) != 52-6-5 {
+		t.Errorf("Expected %d cards left in deck, got %d", 52-6-5, len(state.Deck))
+	}
+	if Deal(state, pattern, 4) {
+		t.Error("Expected out-of-range round to fail")
+	}
+}
+
+func TestDeal_RoundRobinOrder(t *testing.T) {
+	state := newDealState(2)
+	defer PutState(state)
+
+	// Cards come off the top (end) of the deck alternating between players
+	top := len(state.Deck) - 1
+	first, second := state.Deck[top], state.Deck[top-1]
+	Deal(state, &DealPattern{Stages: []DealStage{{FaceDown: 1, FaceUp: 1}}}, 0)
+
+	if state.Players[0].Hand[0] != first || state.Players[1].Hand[0] != second {
+		t.Errorf("Expected first card to P0 and second to P1, got %v / %v",
+			state.Players[0].Hand[0], state.Players[1].Hand[0])
+	}
+	if len(state.Players[0].Hand) != 2 || len(state.Players[1].Hand) != 2 {
+		t.Error("Expected face-down and face-up cards in each hand")
+	}
+}
+
+func TestDeal_DeckExhausted(t *testing.T) {
+	state := newDealState(2)
+	defer PutState(state)
+	state.Deck = state.Deck[:3]
+
+	if Deal(state, &DealPattern{Stages: []DealStage{{FaceDown: 2}}}, 0) {
+		t.Error("Expected deal to report a short deck")
+	}
+	if state.DealRound != 1 {
+		t.Errorf("Expected DealRound to advance to 1, got %d", state.DealRound)
+	}
+}
+
+func TestParseDealPattern(t *testing.T) {
+	pattern, err := ParseDealPattern([]byte{4, 2, 0, 0, 0, 0, 3, 0, 0, 1, 0, 0, 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := HoldemDealPattern()
+	if len(pattern.Stages) != len(want.Stages) {
+		t.Fatalf("Expected %d stages, got %d", len(want.Stages), len(pattern.Stages))
+	}
+	for i := range want.Stages {
+		if pattern.Stages[i] != want.Stages[i] {
+			t.Errorf("Stage %d: expected %+v, got %+v", i, want.Stages[i], pattern.Stages[i])
+		}
+	}
+
+	if p, err := ParseDealPattern(nil); p != nil || err != nil {
+		t.Errorf("Expected no pattern for empty data, got %v, %v", p, err)
+	}
+	if _, err := ParseDealPattern([]byte{2, 1, 0, 0}); err == nil {
+		t.Error("Expected error for truncated pattern")
+	}
+}
+
+func TestClone_DealRound(t *testing.T) {
+	state := newDealState(2)
+	defer PutState(state)
+	Deal(state, HoldemDealPattern(), 0)
+
+	clone := state.Clone()
+	defer PutState(clone)
+	if clone.DealRound != 1 {
+		t.Errorf("Expected cloned DealRound 1, got %d", clone.DealRound)
+	}
+}
+
+func TestDealFixed(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.Discard = []Card{{Rank: 7, Suit: 3}}
+	hands := [][]Card{
+		{{Rank: 12, Suit: 0}, {Rank: 0, Suit: 1}},
+		{{Rank: 5, Suit: 2}},
+	}
+	deck := []Card{{Rank: 3, Suit: 3}, {Rank: 9, Suit: 0}}
+
+	if err := DealFixed(state, hands, deck); err != nil {
+		t.Fatalf("Expected a valid deal, got %v", err)
+	}
+	if !reflect.DeepEqual(state.Players[0].Hand, hands[0]) || !reflect.DeepEqual(state.Players[1].Hand, hands[1]) || !reflect.DeepEqual(state.Deck, deck) {
+		t.Errorf("Expected the exact deal installed, got hands %v/%v and deck %v", state.Players[0].Hand, state.Players[1].Hand, state.Deck)
+	}
+
+	// The deal is copied, not aliased
+	hands[0][0] = Card{Rank: 1, Suit: 1}
+	if state.Players[0].Hand[0] != (Card{Rank: 12, Suit: 0}) {
+		t.Error("Expected DealFixed to copy the hands")
+	}
+}
+
+func TestDealFixedRejects(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.Discard = []Card{{Rank: 7, Suit: 3}}
+	state.Players[0].Hand = []Card{{Rank: 2, Suit: 2}}
+
+	tests := []struct {
+		name  string
+		hands [][]Card
+		deck  []Card
+	}{
+		{"duplicate across hands", [][]Card{{{Rank: 4, Suit: 0}}, {{Rank: 4, Suit: 0}}}, nil},
+		{"duplicate of the discard", [][]Card{{}, {}}, []Card{{Rank: 7, Suit: 3}}},
+		{"out-of-range card", [][]Card{{{Rank: 13, Suit: 0}}, {}}, nil},
+		{"wrong hand count", [][]Card{{{Rank: 4, Suit: 0}}}, nil},
+	}
+	for _, tt := range tests {
+		if err := DealFixed(state, tt.hands, tt.deck); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+		if len(state.Players[0].Hand) != 1 || len(state.Deck) != 0 {
+			t.Errorf("%s: expected state unchanged, got hand %v and deck %v", tt.name, state.Players[0].Hand, state.Deck)
+		}
+	}
+}
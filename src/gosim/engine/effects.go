@@ -35,17 +35,15 @@ type RNG interface {
 
 // ApplyEffect executes a special effect on the game state
 func ApplyEffect(state *GameState, effect *SpecialEffect, rng RNG) {
+	if state.Trace != nil {
+		state.Trace(TraceEvent{Kind: TraceEffect, Turn: state.TurnNumber, Player: int(state.CurrentPlayer), Effect: *effect})
+	}
 	switch effect.EffectType {
 	case EFFECT_SKIP_NEXT:
-		state.SkipCount += effect.Value
-		// Cap at NumPlayers-1 to prevent degenerate infinite turns
-		maxSkip := state.NumPlayers - 1
-		if state.SkipCount > maxSkip {
-			state.SkipCount = maxSkip
-		}
+		ExecuteAction(state, state.CurrentPlayer, OpSkipTurn, effect.Value)
 
 	case EFFECT_REVERSE:
-		state.PlayDirection *= -1
+		ExecuteAction(state, state.CurrentPlayer, OpReverseOrder, 0)
 
 	case EFFECT_DRAW_CARDS:
 		applyToTargets(state, effect.Target, rng, func(targetID int) {
@@ -71,6 +69,7 @@ func ApplyEffect(state *GameState, effect *SpecialEffect, rng RNG) {
 				card := (*hand)[len(*hand)-1]
 				*hand = (*hand)[:len(*hand)-1]
 				state.Discard = append(state.Discard, card)
+				state.MarkSeen(card)
 			}
 		})
 
@@ -87,9 +86,9 @@ func resolveTarget(state *GameState, target uint8) int {
 
 	switch target {
 	case TARGET_NEXT_PLAYER:
-		return (current + direction + numPlayers) % numPlayers
+		return nextInTurnOrder(state, current, direction)
 	case TARGET_PREV_PLAYER:
-		return (current - direction + numPlayers) % numPlayers
+		return nextInTurnOrder(state, current, -direction)
 	case TARGET_ALL_OPPONENTS:
 		// Returns -1 to signal caller must loop over all opponents
 		return -1
@@ -104,7 +103,7 @@ func applyToTargets(state *GameState, target uint8, rng RNG, action func(int)) {
 	if targetID == -1 {
 		// ALL_OPPONENTS: apply to everyone except current player
 		for i := 0; i < int(state.NumPlayers); i++ {
-			if i != int(state.CurrentPlayer) {
+			if i != int(state.CurrentPlayer) && !state.Players[i].Eliminated {
 				action(i)
 			}
 		}
@@ -113,15 +112,15 @@ func applyToTargets(state *GameState, target uint8, rng RNG, action func(int)) {
 	}
 }
 
-// AdvanceTurn moves to the next player, respecting direction and skips
+// AdvanceTurn moves to the next player, respecting direction and skips and
+// passing over eliminated players
 func AdvanceTurn(state *GameState) {
 	step := int(state.PlayDirection)
 	next := int(state.CurrentPlayer)
-	numPlayers := int(state.NumPlayers)
 
 	// Always advance at least once, plus any skips
 	for i := 0; i <= int(state.SkipCount); i++ {
-		next = (next + step + numPlayers) % numPlayers
+		next = nextInTurnOrder(state, next, step)
 	}
 
 	state.CurrentPlayer = uint8(next)
@@ -191,3 +191,39 @@ func TestAdvanceTurnWraparound(t *testing.T) {
 		t.Errorf("Should wrap to 0, got %d", state.CurrentPlayer)
 	}
 }
+
+func TestReverseEffectChangesTurnOrder(t *testing.T) {
+	state := NewGameState(4)
+	defer PutState(state)
+	state.NumPlayers = 4
+	for p := range state.Players {
+		state.Players[p].Hand = []Card{{Rank: 3, Suit: uint8(p)}, {Rank: 9, Suit: uint8(p)}, {Rank: 4, Suit: uint8(p)}}
+	}
+	genome := &Genome{
+		TurnPhases: []PhaseDescriptor{
+			{PhaseType: PhaseTypePlay, Data: []byte{byte(LocationDiscard), 1, 1, 1, 0, 0, 0, 0, 0}},
+		},
+		Effects: map[uint8]SpecialEffect{9: {TriggerRank: 9, EffectType: EFFECT_REVERSE}},
+	}
+	play := func(cardIndex int) {
+		ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: cardIndex, TargetLoc: LocationDiscard}, genome)
+	}
+
+	play(0) // Player 0 plays a plain card
+	if state.CurrentPlayer != 1 {
+		t.Fatalf("Expected player 1 next, got %d", state.CurrentPlayer)
+	}
+
+	play(1) // Player 1 plays the reverse card
+	if state.PlayDirection != -1 {
+		t.Fatalf("Expected direction -1 after reverse, got %d", state.PlayDirection)
+	}
+	if state.CurrentPlayer != 0 {
+		t.Fatalf("Expected turn to go back to player 0, got %d", state.CurrentPlayer)
+	}
+
+	play(1) // Player 0's hand is now [9, 4]
+	if state.CurrentPlayer != 3 {
+		t.Errorf("Expected counter-clockwise wrap to player 3, got %d", state.CurrentPlayer)
+	}
+}
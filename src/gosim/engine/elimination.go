@@ -0,0 +1,99 @@
+package engine
+
+import "sort"
+
+// Elimination
+//
+// In multi-player score and chip games a player can drop out for good while
+// the others play on. EliminatePlayer takes them out of the turn order, trick
+// completion and win checks; the game ends once a single player is left
+// standing, and FinalRanking orders everyone by how they finished.
+
+// EliminatePlayer knocks playerID out of the game: their hand goes to the
+// discard pile and they are skipped from then on. Eliminating a player who
+// is already out does nothing. If it was their turn, play passes on.
+func EliminatePlayer(state *GameState, playerID uint8) {
+	if int(playerID) >= len(state.Players) || state.Players[playerID].Eliminated {
+		return
+	}
+	player := &state.Players[playerID]
+	player.Eliminated = true
+	player.Active = false
+	state.Discard = append(state.Discard, player.Hand...)
+	state.MarkSeen(player.Hand...)
+	player.Hand = player.Hand[:0]
+	player.FaceUp = player.FaceUp[:0]
+	state.EliminationOrder = append(state.EliminationOrder, playerID)
+
+	if state.CurrentPlayer == playerID && RemainingPlayers(state) > 0 {
+		AdvanceTurn(state)
+	}
+}
+
+// RemainingPlayers returns the number of seats (of the first NumPlayers)
+// not yet eliminated
+func RemainingPlayers(state *GameState) int {
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+	remaining := 0
+	for i := 0; i < numPlayers && i < len(state.Players); i++ {
+		if !state.Players[i].Eliminated {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// LastStanding returns the only player not eliminated, once everyone else
+// has been. It reports false until then, and in games without eliminations.
+func LastStanding(state *GameState) (int8, bool) {
+	if len(state.EliminationOrder) == 0 || RemainingPlayers(state) != 1 {
+		return -1, false
+	}
+	for i := range state.Players[:state.NumPlayers] {
+		if !state.Players[i].Eliminated {
+			return int8(i), true
+		}
+	}
+	return -1, false
+}
+
+// FinalRanking returns every player ID from first place to last. Players
+// still in the game come first, by Score descending; eliminated players
+// follow, the last to go out ranking highest. Ties keep seat order.
+func FinalRanking(state *GameState) []int {
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+
+	ranking := make([]int, 0, numPlayers)
+	for i := 0; i < numPlayers && i < len(state.Players); i++ {
+		if !state.Players[i].Eliminated {
+			ranking = append(ranking, i)
+		}
+	}
+	sort.SliceStable(ranking, func(a, b int) bool {
+		return state.Players[ranking[a]].Score > state.Players[ranking[b]].Score
+	})
+	for i := len(state.EliminationOrder) - 1; i >= 0; i-- {
+		ranking = append(ranking, int(state.EliminationOrder[i]))
+	}
+	return ranking
+}
+
+// nextInTurnOrder returns the first seat not eliminated stepping from
+// player in direction step, or player itself if everyone else is out
+func nextInTurnOrder(state *GameState, player, step int) int {
+	numPlayers := int(state.NumPlayers)
+	next := player
+	for i := 0; i < numPlayers; i++ {
+		next = (next + step + numPlayers) % numPlayers
+		if next >= len(state.Players) || !state.Players[next].Eliminated {
+			return next
+		}
+	}
+	return player
+}
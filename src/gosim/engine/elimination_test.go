@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fourPlayerScoreState deals each of 4 players two cards and sets scores
+func fourPlayerScoreState() *GameState {
+	state := NewGameState(4)
+	for p := 0; p < 4; p++ {
+		state.Players[p].Hand = []Card{{Rank: uint8(p), Suit: 0}, {Rank: uint8(p), Suit: 1}}
+	}
+	state.Players[0].Score = 10
+	state.Players[1].Score = 5
+	state.Players[2].Score = 30
+	state.Players[3].Score = 20
+	return state
+}
+
+func TestEliminationFourPlayers(t *testing.T) {
+	state := fourPlayerScoreState()
+	defer PutState(state)
+	genome := &Genome{
+		TurnPhases:    []PhaseDescriptor{{PhaseType: PhaseTypePlay, Data: []byte{byte(LocationDiscard), 1, 1, 1, 0, 0, 0, 0, 0}}},
+		WinConditions: []WinCondition{{WinType: WinTypeEmptyHand}},
+	}
+
+	EliminatePlayer(state, 1)
+	if len(state.Players[1].Hand) != 0 || len(state.Discard) != 2 {
+		t.Errorf("Expected player 1's hand discarded, got hand %v and discard %v", state.Players[1].Hand, state.Discard)
+	}
+	// An eliminated player's empty hand doesn't win
+	if result := CheckGameEnd(state, genome); result.Over() {
+		t.Fatalf("Expected the game to continue with 3 players, got %+v", result)
+	}
+
+	// Player 0 plays; the turn skips player 1
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationDiscard}, genome)
+	if state.CurrentPlayer != 2 {
+		t.Errorf("Expected turn to pass from 0 to 2, got %d", state.CurrentPlayer)
+	}
+
+	// Eliminating the player to act passes the turn on
+	EliminatePlayer(state, 2)
+	if state.CurrentPlayer != 3 {
+		t.Errorf("Expected turn to pass to 3, got %d", state.CurrentPlayer)
+	}
+	EliminatePlayer(state, 2) // Already out
+	if !reflect.DeepEqual(state.EliminationOrder, []uint8{1, 2}) {
+		t.Errorf("Expected elimination order [1 2], got %v", state.EliminationOrder)
+	}
+	if ranking := FinalRanking(state); !reflect.DeepEqual(ranking, []int{3, 0, 2, 1}) {
+		t.Errorf("Expected ranking [3 0 2 1], got %v", ranking)
+	}
+
+	EliminatePlayer(state, 3)
+	result := CheckGameEnd(state, genome)
+	if result.Winner != 0 || result.Reason != EndReasonLastStanding {
+		t.Errorf("Expected player 0 to win as last standing, got %+v", result)
+	}
+	if ranking := FinalRanking(state); !reflect.DeepEqual(ranking, []int{0, 3, 2, 1}) {
+		t.Errorf("Expected ranking [0 3 2 1], got %v", ranking)
+	}
+}
+
+func TestEliminationTrickAndScoreWins(t *testing.T) {
+	state := fourPlayerScoreState()
+	defer PutState(state)
+	EliminatePlayer(state, 2)
+
+	// The top score belongs to an eliminated player
+	genome := &Genome{WinConditions: []WinCondition{{WinType: 1, Threshold: 15}}}
+	if winner := CheckWinConditions(state, genome); winner != 3 {
+		t.Errorf("Expected player 3 to win on score, got %d", winner)
+	}
+
+	// A trick completes once each remaining player has played
+	trick := &Genome{TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypeTrick}}}
+	for i := 0; i < 3; i++ {
+		ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationTableau}, trick)
+	}
+	if len(state.CurrentTrick) != 0 || len(state.TricksWon) != 4 || state.TricksWon[3] != 1 {
+		t.Errorf("Expected player 3 to take a 3-card trick, got trick %v and tricks won %v", state.CurrentTrick, state.TricksWon)
+	}
+}
@@ -0,0 +1,44 @@
+package engine
+
+// OutcomeUnresolved is the EnumerateOutcomes key for lines cut off by the
+// depth limit or the node cap before the game ended
+const OutcomeUnresolved int8 = -2
+
+// enumerateNodeCap bounds the positions EnumerateOutcomes visits, so a
+// position that isn't a tiny endgame can't run away
+const enumerateNodeCap = 1 << 20
+
+// EnumerateOutcomes explores every line of play from state up to maxDepth
+// moves and counts how many end with each winner (-1 for games that end
+// without one). Lines still open at maxDepth, or once the node cap is hit,
+// are counted under OutcomeUnresolved. Moves are applied to clones; state
+// is left untouched.
+func EnumerateOutcomes(state *GameState, genome *Genome, maxDepth int) map[int8]int {
+	outcomes := make(map[int8]int)
+	nodes := 0
+	root := state.Clone()
+	enumerateOutcomes(root, genome, maxDepth, outcomes, &nodes)
+	PutState(root)
+	return outcomes
+}
+
+// enumerateOutcomes tallies the lines below state into outcomes
+func enumerateOutcomes(state *GameState, genome *Genome, depth int, outcomes map[int8]int, nodes *int) {
+	*nodes++
+	if result := CheckGameEnd(state, genome); result.Over() {
+		outcomes[result.Winner]++
+		return
+	}
+	if depth <= 0 || *nodes >= enumerateNodeCap {
+		outcomes[OutcomeUnresolved]++
+		return
+	}
+
+	moves := GenerateLegalMoves(state, genome)
+	for i := range moves {
+		child := state.Clone()
+		ApplyMove(child, &moves[i], genome)
+		enumerateOutcomes(child, genome, depth-1, outcomes, nodes)
+		PutState(child)
+	}
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// emptyHandRaceState returns a 2-player discard race where player 0 holds
+// two cards and player 1 one: whatever player 0 plays, player 1 goes out first
+func emptyHandRaceState() (*GameState, *Genome) {
+	state := NewGameState(2)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}, {Rank: 7, Suit: 1}}
+	state.Players[1].Hand = []Card{{Rank: 5, Suit: 2}}
+	genome := &Genome{
+		TurnPhases: []PhaseDescriptor{
+			{PhaseType: PhaseTypePlay, Data: []byte{byte(LocationDiscard), 1, 1, 1, 0, 0, 0, 0, 0}},
+		},
+		WinConditions: []WinCondition{{WinType: WinTypeEmptyHand}},
+	}
+	return state, genome
+}
+
+func TestEnumerateOutcomesForcedWin(t *testing.T) {
+	state, genome := emptyHandRaceState()
+	defer PutState(state)
+	before := state.Clone()
+	defer PutState(before)
+
+	outcomes := EnumerateOutcomes(state, genome, 10)
+	if want := map[int8]int{1: 2}; !reflect.DeepEqual(outcomes, want) {
+		t.Errorf("Expected both lines to end in a player 1 win, got %v", outcomes)
+	}
+	if !statesEqual(state, before) {
+		t.Error("Expected EnumerateOutcomes to leave state untouched")
+	}
+}
+
+func TestEnumerateOutcomesDepthLimit(t *testing.T) {
+	state, genome := emptyHandRaceState()
+	defer PutState(state)
+
+	outcomes := EnumerateOutcomes(state, genome, 1)
+	if want := map[int8]int{OutcomeUnresolved: 2}; !reflect.DeepEqual(outcomes, want) {
+		t.Errorf("Expected 2 unresolved lines at depth 1, got %v", outcomes)
+	}
+
+	// A finished game is a single line
+	state.Players[1].Hand = state.Players[1].Hand[:0]
+	if outcomes := EnumerateOutcomes(state, genome, 0); !reflect.DeepEqual(outcomes, map[int8]int{1: 1}) {
+		t.Errorf("Expected the finished game as one player 1 win, got %v", outcomes)
+	}
+}
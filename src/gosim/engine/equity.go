@@ -0,0 +1,138 @@
+package engine
+
+// Hand equity
+//
+// HandEquity answers "how often does this hand beat that one": it completes
+// the board to five cards from the cards still unseen, every way it can,
+// and shows the two hands down on each run-out (see BestPokerHand, Aces
+// high). Preflop that is 1.7 million boards, so past equityEnumerationLimit
+// run-outs it shows down equitySamples random ones instead. The sampler is
+// seeded from the cards, so the same question always gets the same answer.
+
+const (
+	// equityEnumerationLimit is the most run-outs HandEquity enumerates
+	equityEnumerationLimit = 50000
+	// equitySamples is how many run-outs HandEquity samples above the limit
+	equitySamples = 10000
+)
+
+// HandEquity returns how often hand1 wins, ties and loses against hand2
+// once board is completed to five cards from deck. A nil deck means every
+// card of a standard deck not in either hand or on the board. The three
+// results sum to 1, or are all 0 when deck can't complete the board.
+func HandEquity(hand1, hand2 []Card, board []Card, deck []Card) (float64, float64, float64) {
+	if deck == nil {
+		deck = unseenCards(hand1, hand2, board)
+	}
+	need := 5 - len(board)
+	if need < 0 {
+		need = 0
+	}
+	if need > len(deck) {
+		return 0, 0, 0
+	}
+
+	cards1 := append(append(make([]Card, 0, len(hand1)+len(board)+need), hand1...), board...)
+	cards2 := append(append(make([]Card, 0, len(hand2)+len(board)+need), hand2...), board...)
+	var wins, ties, total int
+	showdown := func(runout []Card) {
+		cmp := ComparePokerHands(
+			BestPokerHand(append(cards1, runout...), false),
+			BestPokerHand(append(cards2, runout...), false))
+		if cmp > 0 {
+			wins++
+		} else if cmp == 0 {
+			ties++
+		}
+		total++
+	}
+
+	runout := make([]Card, need)
+	if binomial(len(deck), need) <= equityEnumerationLimit {
+		// Walk every need-card combination of deck by index
+		idx := make([]int, need)
+		for i := range idx {
+			idx[i] = i
+		}
+		for {
+			for i, j := range idx {
+				runout[i] = deck[j]
+			}
+			showdown(runout)
+			i := need - 1
+			for i >= 0 && idx[i] == len(deck)-need+i {
+				i--
+			}
+			if i < 0 {
+				break
+			}
+			idx[i]++
+			for j := i + 1; j < need; j++ {
+				idx[j] = idx[j-1] + 1
+			}
+		}
+	} else {
+		pool := append([]Card(nil), deck...)
+		rng := equitySeed(hand1, hand2, board)
+		for s := 0; s < equitySamples; s++ {
+			// Partial Fisher-Yates: the first need cards of pool are the sample
+			for i := 0; i < need; i++ {
+				rng = rng*6364136223846793005 + 1442695040888963407
+				j := i + int((rng>>33)%uint64(len(pool)-i))
+				pool[i], pool[j] = pool[j], pool[i]
+			}
+			showdown(pool[:need])
+		}
+	}
+
+	n := float64(total)
+	return float64(wins) / n, float64(ties) / n, float64(total-wins-ties) / n
+}
+
+// unseenCards returns the standard 52-card deck less the given piles
+func unseenCards(piles ...[]Card) []Card {
+	deck := make([]Card, 0, 52)
+	for suit := uint8(0); suit < 4; suit++ {
+		for rank := uint8(0); rank < 13; rank++ {
+			card := Card{Rank: rank, Suit: suit}
+			seen := false
+			for _, pile := range piles {
+				if containsCard(pile, card) {
+					seen = true
+					break
+				}
+			}
+			if !seen {
+				deck = append(deck, card)
+			}
+		}
+	}
+	return deck
+}
+
+// equitySeed derives a sampling seed from the known cards
+func equitySeed(piles ...[]Card) uint64 {
+	seed := uint64(len(piles))
+	for _, pile := range piles {
+		for _, card := range pile {
+			seed = seed*31 + uint64(card.Rank)<<2 + uint64(card.Suit)
+		}
+		seed = seed*31 + 0xff
+	}
+	return seed
+}
+
+// binomial returns n choose k, saturating well above equityEnumerationLimit
+func binomial(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	result := 1
+	for i := 0; i < k; i++ {
+		result = result * (n - i) / (i + 1)
+		if result > 1<<40 {
+			return result
+		}
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHandEquityAcesOverKingsPreflop(t *testing.T) {
+	aces := []Card{{Rank: 12, Suit: 0}, {Rank: 12, Suit: 3}}  // AH AS
+	kings := []Card{{Rank: 11, Suit: 1}, {Rank: 11, Suit: 2}} // KD KC
+
+	// The usual figure is 81.9% win, 0.5% tie, 17.6% lose
+	win, tie, lose := HandEquity(aces, kings, nil, nil)
+	if math.Abs(win-0.819) > 0.02 || tie > 0.02 || math.Abs(lose-0.176) > 0.02 {
+		t.Errorf("Expected about 82/0.5/18, got %.3f/%.3f/%.3f", win, tie, lose)
+	}
+	if math.Abs(win+tie+lose-1) > 1e-9 {
+		t.Errorf("Expected the outcomes to sum to 1, got %f", win+tie+lose)
+	}
+
+	again, _, _ := HandEquity(aces, kings, nil, nil)
+	if again != win {
+		t.Errorf("Expected sampling to be repeatable, got %f then %f", win, again)
+	}
+	if back, _, _ := HandEquity(kings, aces, nil, nil); math.Abs(back-lose) > 0.02 {
+		t.Errorf("Expected the matchup reversed to swap win and lose, got %.3f vs %.3f", back, lose)
+	}
+}
+
+func TestHandEquityEnumeratesRiver(t *testing.T) {
+	aces := []Card{{Rank: 12, Suit: 0}, {Rank: 12, Suit: 1}}                                        // AH AD
+	kings := []Card{{Rank: 11, Suit: 0}, {Rank: 11, Suit: 1}}                                       // KH KD
+	board := []Card{{Rank: 0, Suit: 2}, {Rank: 5, Suit: 3}, {Rank: 7, Suit: 1}, {Rank: 9, Suit: 2}} // 2C 7S 9D JC
+
+	// Only the last two Kings, 2 of the 44 unseen cards, save the Kings
+	win, tie, lose := HandEquity(aces, kings, board, nil)
+	if win != 42.0/44 || tie != 0 || lose != 2.0/44 {
+		t.Errorf("Expected 42/44 wins and 2/44 losses, got %f/%f/%f", win, tie, lose)
+	}
+
+	// A complete board is a single showdown
+	board = append(board, Card{Rank: 8, Suit: 3})              // TS
+	straight := []Card{{Rank: 6, Suit: 0}, {Rank: 1, Suit: 0}} // 8H 3H
+	if win, tie, lose := HandEquity(straight, kings, board, nil); win != 1 || tie != 0 || lose != 0 {
+		t.Errorf("Expected the straight to win outright, got %f/%f/%f", win, tie, lose)
+	}
+	if win, tie, lose := HandEquity(aces, kings, board, []Card{}); win != 1 || tie != 0 || lose != 0 {
+		t.Errorf("Expected a complete board to need no deck, got %f/%f/%f", win, tie, lose)
+	}
+	if win, tie, lose := HandEquity(aces, kings, board[:3], []Card{{Rank: 3, Suit: 0}}); win+tie+lose != 0 {
+		t.Errorf("Expected no result when the deck can't complete the board, got %f/%f/%f", win, tie, lose)
+	}
+}
@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Parse errors
+//
+// ParseGenome and the section parsers it calls fail with a *ParseError that
+// names the bytecode section and wraps one of the sentinel errors below, so
+// callers can tell damaged bytecode from a genome the engine doesn't support
+// with errors.Is, and find the failing section with errors.As.
+
+var (
+	// ErrTruncatedHeader means the bytecode is shorter than its header
+	ErrTruncatedHeader = errors.New("bytecode too short for header")
+	// ErrTruncated means a section runs past the end of the bytecode
+	ErrTruncated = errors.New("truncated section")
+	// ErrInvalidOffset means a header offset points outside the bytecode
+	ErrInvalidOffset = errors.New("invalid section offset")
+	// ErrUnknownPhaseType means the turn structure has a phase type the
+	// engine doesn't know
+	ErrUnknownPhaseType = errors.New("unknown phase type")
+	// ErrUnknownEvalMethod means the hand evaluation has a method the
+	// engine doesn't know
+	ErrUnknownEvalMethod = errors.New("unknown evaluation method")
+	// ErrUnknownSetupOption means the setup options have a tag the engine
+	// doesn't know
+	ErrUnknownSetupOption = errors.New("unknown setup option")
+)
+
+// Bytecode sections named by ParseError
+const (
+	SectionHeader         = "header"
+	SectionDealPattern    = "deal_pattern"
+	SectionSetupOptions   = "setup_options"
+	SectionTurnStructure  = "turn_structure"
+	SectionBettingPhase   = "betting_phase"
+	SectionWinConditions  = "win_conditions"
+	SectionEffects        = "effects"
+	SectionCardScoring    = "card_scoring"
+	SectionHandEvaluation = "hand_evaluation"
+)
+
+// ParseError is a failure to parse one section of genome bytecode
+type ParseError struct {
+	Section string // One of the Section constants
+	Err     error  // One of the sentinel errors above
+	Detail  string // What was wrong, e.g. "phase 2 has type 12"
+}
+
+func (e *ParseError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("%s: %v", e.Section, e.Err)
+	}
+	return fmt.Sprintf("%s: %v: %s", e.Section, e.Err, e.Detail)
+}
+
+// Unwrap returns the sentinel error, for errors.Is
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// parseError returns a *ParseError for section with a formatted detail
+func parseError(section string, err error, format string, args ...interface{}) *ParseError {
+	return &ParseError{Section: section, Err: err, Detail: fmt.Sprintf(format, args...)}
+}
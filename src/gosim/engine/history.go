@@ -0,0 +1,231 @@
+package engine
+
+import "encoding/binary"
+
+// Move history
+//
+// While GameState.RecordHistory is set, ApplyMove appends a MoveRecord to
+// GameState.History. Draws, single-card plays and discards without side
+// effects are recorded as small deltas; every other move (tricks, betting,
+// captures, card effects, ...) stores a snapshot of the prior state. Either
+// way UndoLastMove restores the state exactly as it was before the move.
+
+// undoKind says how a MoveRecord is reversed
+type undoKind uint8
+
+const (
+	undoSnapshot undoKind = iota // Restore a full copy of the prior state
+	undoDraw                     // Return drawn cards to their source pile
+	undoPlay                     // Return a played card to its hand position
+	undoPass                     // Only turn bookkeeping changed
+)
+
+// MoveRecord is one applied move and what is needed to reverse it
+type MoveRecord struct {
+	Move LegalMove
+	kind undoKind
+	// Turn bookkeeping restored by every delta record
+	player     uint8
+	phase      int
+	turnNumber uint32
+	passes     int
+	stood      bool
+	seen       uint64
+	// undoDraw: cards drawn from source; undoPlay: the card and its pile
+	source  Location
+	drawn   int
+	card    Card
+	tableau [][]Card // Tableau before the play, to drop a pile the play created
+	// undoSnapshot: owned copy of the prior state, released on undo or Reset
+	snapshot *GameState
+}
+
+// recordMove applies move to state and appends its MoveRecord to the history
+func recordMove(state *GameState, move *LegalMove, genome *Genome) {
+	rec := MoveRecord{
+		Move:       *move,
+		kind:       undoKindFor(state, move, genome),
+		player:     state.CurrentPlayer,
+		phase:      state.CurrentPhase,
+		turnNumber: state.TurnNumber,
+		passes:     state.ConsecutivePasses,
+		seen:       state.SeenCards,
+	}
+	if int(rec.player) < len(state.HasStood) {
+		rec.stood = state.HasStood[rec.player]
+	}
+
+	handLen := 0
+	if int(rec.player) < len(state.Players) {
+		handLen = len(state.Players[rec.player].Hand)
+	}
+	switch rec.kind {
+	case undoSnapshot:
+		rec.snapshot = state.Clone()
+	case undoDraw:
+		rec.source = move.TargetLoc
+	case undoPlay:
+		rec.source = move.TargetLoc
+		rec.card = state.Players[rec.player].Hand[move.CardIndex]
+		rec.tableau = state.Tableau
+	}
+
+	applyMove(state, move, genome)
+
+	if rec.kind == undoDraw {
+		rec.drawn = len(state.Players[rec.player].Hand) - handLen
+	}
+	state.History = append(state.History, rec)
+}
+
+// undoKindFor picks the cheapest exact way to reverse move
+func undoKindFor(state *GameState, move *LegalMove, genome *Genome) undoKind {
+	if move.PhaseIndex >= len(genome.TurnPhases) || int(state.CurrentPlayer) >= len(state.Players) {
+		return undoPass
+	}
+	hand := state.Players[state.CurrentPlayer].Hand
+	playable := move.CardIndex >= 0 && move.CardIndex < len(hand)
+
+	switch genome.TurnPhases[move.PhaseIndex].PhaseType {
+	case PhaseTypeDraw:
+		if move.CardIndex == MoveDrawPass {
+			return undoPass
+		}
+		if move.CardIndex == MoveDraw && (move.TargetLoc == LocationDeck || move.TargetLoc == LocationDiscard) &&
+			!drawMayRecycle(state, move, genome.TurnPhases[move.PhaseIndex].Data) {
+			return undoDraw
+		}
+
+	case PhaseTypePlay:
+		if move.CardIndex == MovePlayPass && state.ConsecutivePasses+1 < int(state.NumPlayers)-1 {
+			return undoPass // Pass that doesn't clear the tableau
+		}
+		if !playable {
+			break
+		}
+		if _, ok := genome.Effects[hand[move.CardIndex].Rank]; ok {
+			break
+		}
+		if move.TargetLoc == LocationDiscard {
+			return undoPlay
+		}
+		// WAR and MATCH_RANK resolve captures after the play
+		if move.TargetLoc == LocationTableau && (state.TableauMode == 0 || state.TableauMode == 3) {
+			return undoPlay
+		}
+
+	case PhaseTypeDiscard:
+		if playable {
+			return undoPlay
+		}
+	}
+	return undoSnapshot
+}
+
+// drawMayRecycle reports whether a draw could run the deck dry and recycle
+// the discard pile, which the draw delta can't reverse
+func drawMayRecycle(state *GameState, move *LegalMove, data []byte) bool {
+	if move.TargetLoc != LocationDeck || len(data) < 6 || data[5] != 1 {
+		return false
+	}
+	count := int(binary.BigEndian.Uint32(data[1:5]))
+	return count > len(state.Deck)
+}
+
+// UndoLastMove reverses the most recent recorded ApplyMove and returns
+// false if there is nothing to undo. The undone move can be reapplied with
+// RedoMove until the next ApplyMove.
+func UndoLastMove(state *GameState, genome *Genome) bool {
+	if len(state.History) == 0 {
+		return false
+	}
+	rec := state.History[len(state.History)-1]
+	state.History[len(state.History)-1] = MoveRecord{}
+	state.History = state.History[:len(state.History)-1]
+
+	switch rec.kind {
+	case undoSnapshot:
+		restoreSnapshot(state, rec.snapshot)
+		PutState(rec.snapshot)
+		state.Undone = append(state.Undone, rec.Move)
+		return true
+
+	case undoDraw:
+		player := &state.Players[rec.player]
+		src := &state.Deck
+		if rec.source == LocationDiscard {
+			src = &state.Discard
+		}
+		// Last card drawn goes back first so the pile order is restored
+		for i := 0; i < rec.drawn; i++ {
+			last := len(player.Hand) - 1
+			*src = append(*src, player.Hand[last])
+			player.Hand = player.Hand[:last]
+		}
+
+	case undoPlay:
+		if rec.source == LocationDiscard {
+			state.Discard = state.Discard[:len(state.Discard)-1]
+		} else if len(rec.tableau) == 0 {
+			state.Tableau = rec.tableau
+		} else {
+			state.Tableau[0] = state.Tableau[0][:len(state.Tableau[0])-1]
+		}
+		insertCard(&state.Players[rec.player].Hand, rec.Move.CardIndex, rec.card)
+	}
+
+	state.CurrentPlayer = rec.player
+	state.CurrentPhase = rec.phase
+	state.TurnNumber = rec.turnNumber
+	state.ConsecutivePasses = rec.passes
+	state.SeenCards = rec.seen
+	if int(rec.player) < len(state.HasStood) {
+		state.HasStood[rec.player] = rec.stood
+	}
+	state.Undone = append(state.Undone, rec.Move)
+	return true
+}
+
+// RedoMove reapplies the most recently undone move and returns false if
+// there is none
+func RedoMove(state *GameState, genome *Genome) bool {
+	if len(state.Undone) == 0 {
+		return false
+	}
+	move := state.Undone[len(state.Undone)-1]
+	state.Undone = state.Undone[:len(state.Undone)-1]
+	recordMove(state, &move, genome)
+	return true
+}
+
+// restoreSnapshot overwrites state with snapshot, keeping state's history
+// and trace hook
+func restoreSnapshot(state *GameState, snapshot *GameState) {
+	record, history, undone, trace := state.RecordHistory, state.History, state.Undone, state.Trace
+	state.History = nil // Keep Reset from releasing the remaining snapshots
+
+	state.resizePlayers(len(snapshot.Players))
+	state.Reset()
+	state.copyFrom(snapshot)
+
+	state.RecordHistory, state.History, state.Undone, state.Trace = record, history, undone, trace
+}
+
+// clearHistory drops the move history, releasing any snapshots it owns
+func (s *GameState) clearHistory() {
+	for i := range s.History {
+		if s.History[i].snapshot != nil {
+			PutState(s.History[i].snapshot)
+		}
+		s.History[i] = MoveRecord{}
+	}
+	s.History = s.History[:0]
+	s.Undone = s.Undone[:0]
+}
+
+// insertCard inserts card into hand at index, reusing the hand's capacity
+func insertCard(hand *[]Card, index int, card Card) {
+	*hand = append(*hand, Card{})
+	copy((*hand)[index+1:], (*hand)[index:])
+	(*hand)[index] = card
+}
@@ -0,0 +1,193 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newHistoryState returns a 2-player state with dealt hands, a deck and a
+// discard pile, recording move history
+func newHistoryState() *GameState {
+	state := newDealState(2)
+	for i := 0; i < 5; i++ {
+		state.DrawCard(0, LocationDeck)
+		state.DrawCard(1, LocationDeck)
+	}
+	state.DrawCard(0, LocationDeck)
+	state.PlayCard(0, 5, LocationDiscard)
+	state.RecordHistory = true
+	return state
+}
+
+// normalizeEmpty sets every empty slice reachable from v to nil, since
+// pooled states may hold either after a Reset
+func normalizeEmpty(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			normalizeEmpty(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				normalizeEmpty(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if v.Len() == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			normalizeEmpty(v.Index(i))
+		}
+	}
+}
+
+// statesEqual deep-compares two states, treating nil and empty slices alike
+func statesEqual(a, b *GameState) bool {
+	normalizeEmpty(reflect.ValueOf(a))
+	normalizeEmpty(reflect.ValueOf(b))
+	return reflect.DeepEqual(a, b)
+}
+
+// assertUndoRestores applies move, undoes it, and checks the state matches
+// the state before the move
+func assertUndoRestores(t *testing.T, state *GameState, move LegalMove, genome *Genome) {
+	t.Helper()
+	before := state.Clone()
+	defer PutState(before)
+
+	ApplyMove(state, &move, genome)
+	if len(state.History) != 1 {
+		t.Fatalf("Expected 1 history entry, got %d", len(state.History))
+	}
+	if !UndoLastMove(state, genome) {
+		t.Fatal("Expected undo to succeed")
+	}
+
+	after := state.Clone()
+	defer PutState(after)
+	if !statesEqual(before, after) {
+		t.Errorf("State after undo differs from state before move\nbefore: %+v\nafter:  %+v", before, after)
+	}
+	if len(state.History) != 0 {
+		t.Errorf("Expected empty history after undo, got %d entries", len(state.History))
+	}
+}
+
+func TestUndoDraw(t *testing.T) {
+	genome := &Genome{TurnPhases: []PhaseDescriptor{
+		{PhaseType: PhaseTypeDraw, Data: []byte{byte(LocationDeck), 0, 0, 0, 3, 1, 0}},
+	}}
+	state := newHistoryState()
+	defer PutState(state)
+
+	assertUndoRestores(t, state, LegalMove{PhaseIndex: 0, CardIndex: MoveDraw, TargetLoc: LocationDeck}, genome)
+}
+
+func TestUndoDrawFromDiscard(t *testing.T) {
+	genome := &Genome{TurnPhases: []PhaseDescriptor{
+		{PhaseType: PhaseTypeDraw, Data: []byte{byte(LocationDiscard), 0, 0, 0, 2, 1, 0}},
+	}}
+	state := newHistoryState()
+	defer PutState(state)
+
+	// Discard holds a single card, so only one of the two draws succeeds
+	assertUndoRestores(t, state, LegalMove{PhaseIndex: 0, CardIndex: MoveDraw, TargetLoc: LocationDiscard}, genome)
+}
+
+func TestUndoPlay(t *testing.T) {
+	state := newHistoryState()
+	defer PutState(state)
+
+	// Onto an empty tableau, which the play creates
+	assertUndoRestores(t, state, LegalMove{PhaseIndex: 0, CardIndex: 2, TargetLoc: LocationTableau}, minimalPlayPhaseGenome())
+	if len(state.Tableau) != 0 {
+		t.Errorf("Expected tableau removed after undo, got %d piles", len(state.Tableau))
+	}
+
+	// Onto an existing tableau pile
+	state.Tableau = append(state.Tableau, []Card{{Rank: 0, Suit: 3}})
+	assertUndoRestores(t, state, LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationTableau}, minimalPlayPhaseGenome())
+}
+
+func TestUndoDiscard(t *testing.T) {
+	genome := &Genome{TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypeDiscard}}}
+	state := newHistoryState()
+	defer PutState(state)
+
+	assertUndoRestores(t, state, LegalMove{PhaseIndex: 0, CardIndex: 4, TargetLoc: LocationDiscard}, genome)
+}
+
+func TestUndoSnapshot(t *testing.T) {
+	// War-mode play resolves a battle, which is undone from a snapshot
+	genome := minimalPlayPhaseGenome()
+	state := newHistoryState()
+	defer PutState(state)
+	state.TableauMode = 1
+	state.Tableau = append(state.Tableau, []Card{{Rank: 0, Suit: 3}})
+
+	assertUndoRestores(t, state, LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationTableau}, genome)
+}
+
+func TestRedoMove(t *testing.T) {
+	genome := &Genome{TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypeDiscard}}}
+	state := newHistoryState()
+	defer PutState(state)
+
+	move := LegalMove{PhaseIndex: 0, CardIndex: 1, TargetLoc: LocationDiscard}
+	ApplyMove(state, &move, genome)
+	applied := state.Clone()
+	defer PutState(applied)
+
+	UndoLastMove(state, genome)
+	if !RedoMove(state, genome) {
+		t.Fatal("Expected redo to succeed")
+	}
+	redone := state.Clone()
+	defer PutState(redone)
+	if !statesEqual(applied, redone) {
+		t.Error("Expected redo to reproduce the applied state")
+	}
+	if RedoMove(state, genome) {
+		t.Error("Expected nothing left to redo")
+	}
+
+	// A new move clears the redo stack
+	UndoLastMove(state, genome)
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationDiscard}, genome)
+	if RedoMove(state, genome) {
+		t.Error("Expected ApplyMove to clear the redo stack")
+	}
+}
+
+func TestHistoryOff(t *testing.T) {
+	genome := &Genome{TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypeDiscard}}}
+	state := newHistoryState()
+	defer PutState(state)
+	state.RecordHistory = false
+
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationDiscard}, genome)
+	if len(state.History) != 0 {
+		t.Errorf("Expected no history without RecordHistory, got %d entries", len(state.History))
+	}
+	if UndoLastMove(state, genome) {
+		t.Error("Expected undo to fail with empty history")
+	}
+}
+
+func TestUndoDrawThatRecycles(t *testing.T) {
+	// Drawing 3 from a 1-card deck recycles the discard, undone from a snapshot
+	genome := &Genome{TurnPhases: []PhaseDescriptor{
+		{PhaseType: PhaseTypeDraw, Data: []byte{byte(LocationDeck), 0, 0, 0, 3, 1, 0}},
+	}}
+	state := newHistoryState()
+	defer PutState(state)
+	for len(state.Deck) > 1 {
+		state.Discard = append(state.Discard, state.Deck[len(state.Deck)-1])
+		state.Deck = state.Deck[:len(state.Deck)-1]
+	}
+
+	assertUndoRestores(t, state, LegalMove{PhaseIndex: 0, CardIndex: MoveDraw, TargetLoc: LocationDeck}, genome)
+}
@@ -0,0 +1,130 @@
+package engine
+
+// Match play: a game made of several hands, decided by a cumulative target
+// rather than by whoever wins the first hand.
+//
+// A genome opts into match play with Genome.MatchPlay. Its empty_hand win
+// condition then only ends the current hand, and its first_to_score or
+// most_chips win condition's threshold is the cumulative match target.
+// Without MatchPlay the same win conditions play a single hand, where going
+// out wins the game.
+
+// MatchTarget returns the cumulative target for match play and whether it is
+// measured in Chips rather than Score. A zero target means single-hand play.
+func MatchTarget(genome *Genome) (target int64, useChips bool) {
+	if !genome.MatchPlay {
+		return 0, false
+	}
+	hasEmptyHand := false
+	for _, wc := range genome.WinConditions {
+		switch wc.WinType {
+		case WinTypeEmptyHand:
+			hasEmptyHand = true
+		case WinTypeFirstToScore:
+			if target == 0 && wc.Threshold > 0 {
+				target = int64(wc.Threshold)
+				useChips = false
+			}
+		case WinTypeMostChips:
+			if target == 0 && wc.Threshold > 0 {
+				target = int64(wc.Threshold)
+				useChips = true
+			}
+		}
+	}
+	if !hasEmptyHand {
+		return 0, false
+	}
+	return target, useChips
+}
+
+// IsMatchPlay returns true if the genome plays multiple hands to a cumulative target
+func IsMatchPlay(genome *Genome) bool {
+	target, _ := MatchTarget(genome)
+	return target > 0
+}
+
+// HandComplete returns true when the current hand of a match is over
+// (some player has emptied their hand). Always false for single-hand games.
+func HandComplete(state *GameState, genome *Genome) bool {
+	if !IsMatchPlay(genome) {
+		return false
+	}
+	return handWinner(state) >= 0
+}
+
+// handWinner returns the first player with an empty hand, or -1
+func handWinner(state *GameState) int8 {
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		if len(state.Players[playerID].Hand) == 0 {
+			return int8(playerID)
+		}
+	}
+	return -1
+}
+
+// ScoreHand awards the player who went out the HandValue of the cards left
+// in the opponents' hands under genome.RankValues, or one point per card if
+// the genome sets no rank values (Crazy Eights style), and returns that
+// player's ID. Returns -1 and changes nothing if no player has emptied
+// their hand.
+func ScoreHand(state *GameState, genome *Genome) int8 {
+	winner := handWinner(state)
+	if winner < 0 {
+		return -1
+	}
+
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2
+	}
+	points := int32(0)
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		if playerID == int(winner) {
+			continue
+		}
+		if genome.RankValues == ([13]int32{}) {
+			points += int32(len(state.Players[playerID].Hand))
+		} else {
+			points += HandValue(state.Players[playerID].Hand, genome.RankValues)
+		}
+	}
+
+	state.Players[winner].Score += points
+	UpdateTeamScore(state, int(winner), points)
+	return winner
+}
+
+// MatchComplete returns the match winner once any player's cumulative Score
+// (or Chips) reaches the match target, or -1 if the match continues.
+// The highest total wins; ties go to the lower player index.
+func MatchComplete(state *GameState, genome *Genome) int8 {
+	target, useChips := MatchTarget(genome)
+	if target <= 0 {
+		return -1
+	}
+
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2
+	}
+
+	winner := int8(-1)
+	best := int64(0)
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		total := int64(state.Players[playerID].Score)
+		if useChips {
+			total = state.Players[playerID].Chips
+		}
+		if total >= target && (winner < 0 || total > best) {
+			winner = int8(playerID)
+			best = total
+		}
+	}
+
+	return setWinnerWithTeam(state, winner)
+}
@@ -0,0 +1,151 @@
+package engine
+
+import "testing"
+
+// matchGenome builds a shedding genome played as a match to 10 points
+func matchGenome() *Genome {
+	return &Genome{
+		Header:    &BytecodeHeader{PlayerCount: 3},
+		MatchPlay: true,
+		WinConditions: []WinCondition{
+			{WinType: WinTypeEmptyHand, Threshold: 0},
+			{WinType: WinTypeFirstToScore, Threshold: 10},
+		},
+	}
+}
+
+// dealTestHands sets each player's hand to the given number of cards
+func dealTestHands(state *GameState, sizes ...int) {
+	for p, size := range sizes {
+		state.Players[p].Hand = state.Players[p].Hand[:0]
+		for i := 0; i < size; i++ {
+			state.Players[p].Hand = append(state.Players[p].Hand, Card{Rank: uint8(i % 13), Suit: uint8(p % 4)})
+		}
+	}
+}
+
+func TestMatchTarget(t *testing.T) {
+	target, useChips := MatchTarget(matchGenome())
+	if target != 10 || useChips {
+		t.Errorf("Expected score target 10, got %d (chips=%v)", target, useChips)
+	}
+
+	chipGenome := &Genome{MatchPlay: true, WinConditions: []WinCondition{
+		{WinType: WinTypeEmptyHand},
+		{WinType: WinTypeMostChips, Threshold: 500},
+	}}
+	target, useChips = MatchTarget(chipGenome)
+	if target != 500 || !useChips {
+		t.Errorf("Expected chip target 500, got %d (chips=%v)", target, useChips)
+	}
+
+	// first_to_score without empty_hand is a plain race, not match play
+	raceGenome := &Genome{WinConditions: []WinCondition{
+		{WinType: WinTypeFirstToScore, Threshold: 10},
+	}}
+	if IsMatchPlay(raceGenome) {
+		t.Error("Expected genome without empty_hand to be single-hand play")
+	}
+
+	// The same win conditions without MatchPlay play a single hand
+	single := matchGenome()
+	single.MatchPlay = false
+	if IsMatchPlay(single) {
+		t.Error("Expected genome without MatchPlay to be single-hand play")
+	}
+	state := NewGameState(3)
+	defer PutState(state)
+	dealTestHands(state, 3, 0, 2)
+	if HandComplete(state, single) {
+		t.Error("Expected HandComplete false without MatchPlay")
+	}
+	if winner := CheckWinConditions(state, single); winner != 1 {
+		t.Errorf("Expected going out to win without MatchPlay, got %d", winner)
+	}
+}
+
+func TestHandCompleteSingleHandGame(t *testing.T) {
+	state := NewGameState(2)
+	genome := &Genome{WinConditions: []WinCondition{{WinType: WinTypeEmptyHand}}}
+	dealTestHands(state, 0, 3)
+
+	if HandComplete(state, genome) {
+		t.Error("HandComplete should be false for single-hand games")
+	}
+	if winner := CheckWinConditions(state, genome); winner != 0 {
+		t.Errorf("Expected empty_hand to win immediately, got %d", winner)
+	}
+}
+
+// TestThreeHandMatch plays three hands with different hand winners and
+// verifies the cumulative target decides the match.
+func TestThreeHandMatch(t *testing.T) {
+	state := NewGameState(3)
+	genome := matchGenome()
+
+	// Hand 1: player 0 goes out, opponents hold 3 + 2 cards
+	dealTestHands(state, 0, 3, 2)
+	if !HandComplete(state, genome) {
+		t.Fatal("Hand 1 should be complete")
+	}
+	if winner := ScoreHand(state, genome); winner != 0 {
+		t.Fatalf("Expected player 0 to win hand 1, got %d", winner)
+	}
+	if winner := MatchComplete(state, genome); winner != -1 {
+		t.Fatalf("Match should continue after hand 1, got winner %d", winner)
+	}
+
+	// Hand 2: player 2 goes out, opponents hold 1 + 3 cards
+	dealTestHands(state, 1, 3, 0)
+	if winner := ScoreHand(state, genome); winner != 2 {
+		t.Fatalf("Expected player 2 to win hand 2, got %d", winner)
+	}
+	if winner := MatchComplete(state, genome); winner != -1 {
+		t.Fatalf("Match should continue after hand 2, got winner %d", winner)
+	}
+
+	// Hand 3: player 1 goes out, opponents hold 6 + 6 cards
+	dealTestHands(state, 6, 0, 6)
+	if winner := ScoreHand(state, genome); winner != 1 {
+		t.Fatalf("Expected player 1 to win hand 3, got %d", winner)
+	}
+
+	expected := []int32{5, 12, 4}
+	for p, score := range expected {
+		if state.Players[p].Score != score {
+			t.Errorf("Player %d: expected cumulative score %d, got %d", p, score, state.Players[p].Score)
+		}
+	}
+
+	if winner := MatchComplete(state, genome); winner != 1 {
+		t.Errorf("Expected player 1 to win the match, got %d", winner)
+	}
+}
+
+func TestScoreHandIncomplete(t *testing.T) {
+	state := NewGameState(2)
+	dealTestHands(state, 2, 3)
+
+	if winner := ScoreHand(state, matchGenome()); winner != -1 {
+		t.Errorf("Expected -1 for incomplete hand, got %d", winner)
+	}
+	if state.Players[0].Score != 0 || state.Players[1].Score != 0 {
+		t.Error("ScoreHand should not change scores for an incomplete hand")
+	}
+}
+
+func TestScoreHandRankValues(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[1].Hand = []Card{{Rank: AceRank, Suit: 0}, {Rank: 11, Suit: 1}, {Rank: 3, Suit: 2}}
+	genome := matchGenome()
+	genome.RankValues = rummyValues
+
+	if winner := ScoreHand(state, genome); winner != 0 {
+		t.Fatalf("Expected player 0 to win the hand, got %d", winner)
+	}
+	if state.Players[0].Score != 1+10+5 {
+		t.Errorf("Expected player 0 to score the 16 points of deadwood, got %d", state.Players[0].Score)
+	}
+}
@@ -0,0 +1,278 @@
+package engine
+
+import "sort"
+
+// Rummy melds
+//
+// A meld is a set (three or four cards of one rank) or a run (three or more
+// consecutive cards of one suit, Ace low). Cards in no meld are deadwood,
+// valued with a rank value table (RummyRankValues unless the genome sets
+// RankValues). A card can only be in one meld, so a 7 that fits both a run
+// and a set has to go to whichever leaves less deadwood; the decomposition
+// searches every split of the hand for the minimum. Jokers are not wild
+// here and always count as (worthless) deadwood.
+//
+// A genome with Knocking set lets a player end the hand from a discard
+// phase by discarding and knocking once their remaining deadwood is at most
+// KnockThreshold (0 allows only gin). The game then ends with the knock
+// scored by ScoreKnock and won by KnockWinner.
+
+// RummyRankValues scores deadwood: Aces 1, pip cards face value, courts 10
+var RummyRankValues = [13]int32{2, 3, 4, 5, 6, 7, 8, 9, 10, 10, 10, 10, 1}
+
+// Gin Rummy knock rules
+const (
+	KnockLimit    int32 = 10 // Standard KnockThreshold
+	GinBonus      int32 = 25 // Extra points for knocking with no deadwood
+	UndercutBonus int32 = 25 // Extra points to a defender who undercuts the knocker
+)
+
+// meldSearch memoizes the best deadwood for each subset of a hand
+type meldSearch struct {
+	hand   []Card
+	values [13]int32
+	order  []int // Hand indices by suit, then Ace-low rank
+	memo   map[uint64]meldResult
+}
+
+// meldResult is the best split of a subset: its deadwood value and the
+// first meld (bitmask of hand indices) to take, 0 to leave the lowest card
+// as deadwood
+type meldResult struct {
+	deadwood int32
+	cards    int // Deadwood card count, to prefer fewer cards on equal value
+	meld     uint64
+}
+
+// BestMeldDecomposition splits hand into melds and deadwood so that the
+// deadwood's RummyRankValues total is as small as possible, then with as
+// few deadwood cards as possible. Deadwood keeps hand order. Hands of more
+// than 64 cards are returned as all deadwood.
+func BestMeldDecomposition(hand []Card) (melds [][]Card, deadwood []Card) {
+	return MeldDecomposition(hand, RummyRankValues)
+}
+
+// MeldDecomposition is BestMeldDecomposition with deadwood valued by values
+func MeldDecomposition(hand []Card, values [13]int32) (melds [][]Card, deadwood []Card) {
+	if len(hand) > 64 {
+		return nil, append([]Card(nil), hand...)
+	}
+	s := &meldSearch{hand: hand, values: values, memo: make(map[uint64]meldResult)}
+	s.order = make([]int, len(hand))
+	for i := range s.order {
+		s.order[i] = i
+	}
+	sort.SliceStable(s.order, func(a, b int) bool {
+		ca, cb := hand[s.order[a]], hand[s.order[b]]
+		if ca.Suit != cb.Suit {
+			return ca.Suit < cb.Suit
+		}
+		return RankValue(ca.Rank, true) < RankValue(cb.Rank, true)
+	})
+
+	remaining := uint64(1)<<uint(len(hand)) - 1 // Shifting by 64 gives 0, so all ones
+	inMeld := uint64(0)
+	for remaining != 0 {
+		res := s.best(remaining)
+		if res.meld == 0 {
+			remaining &^= uint64(1) << uint(s.lowest(remaining))
+			continue
+		}
+		meld := make([]Card, 0, 4)
+		for _, i := range s.order {
+			if res.meld&(uint64(1)<<uint(i)) != 0 {
+				meld = append(meld, hand[i])
+			}
+		}
+		melds = append(melds, meld)
+		inMeld |= res.meld
+		remaining &^= res.meld
+	}
+	for i, card := range hand {
+		if inMeld&(uint64(1)<<uint(i)) == 0 {
+			deadwood = append(deadwood, card)
+		}
+	}
+	return melds, deadwood
+}
+
+// lowest returns the first hand index in search order still in mask
+func (s *meldSearch) lowest(mask uint64) int {
+	for _, i := range s.order {
+		if mask&(uint64(1)<<uint(i)) != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// best finds the minimum deadwood for the cards in mask. The lowest card
+// in search order is either deadwood, in a set, or the bottom of a run
+// (every lower card of its suit is already spoken for).
+func (s *meldSearch) best(mask uint64) meldResult {
+	if mask == 0 {
+		return meldResult{}
+	}
+	if res, ok := s.memo[mask]; ok {
+		return res
+	}
+
+	first := s.lowest(mask)
+	card := s.hand[first]
+	rest := mask &^ (uint64(1) << uint(first))
+
+	// Leave it as deadwood
+	res := s.best(rest)
+	res = meldResult{deadwood: res.deadwood + HandValue([]Card{card}, s.values), cards: res.cards + 1}
+
+	consider := func(meld uint64) {
+		sub := s.best(mask &^ meld)
+		if sub.deadwood < res.deadwood || (sub.deadwood == res.deadwood && sub.cards < res.cards) {
+			res = meldResult{deadwood: sub.deadwood, cards: sub.cards, meld: meld}
+		}
+	}
+
+	if !card.IsJoker() {
+		// Sets: the card with two or three more of its rank
+		var same []int
+		for _, i := range s.order {
+			if rest&(uint64(1)<<uint(i)) != 0 && !s.hand[i].IsJoker() && s.hand[i].Rank == card.Rank {
+				same = append(same, i)
+			}
+		}
+		for a := 0; a < len(same); a++ {
+			for b := a + 1; b < len(same); b++ {
+				set := uint64(1)<<uint(first) | uint64(1)<<uint(same[a]) | uint64(1)<<uint(same[b])
+				consider(set)
+				for c := b + 1; c < len(same); c++ {
+					consider(set | uint64(1)<<uint(same[c]))
+				}
+			}
+		}
+
+		// Runs: the card and the next ranks up in its suit
+		run := uint64(1) << uint(first)
+		for length, next := 1, RankValue(card.Rank, true)+1; next < 13; length, next = length+1, next+1 {
+			i := s.find(rest, card.Suit, next)
+			if i < 0 {
+				break
+			}
+			run |= uint64(1) << uint(i)
+			if length+1 >= 3 {
+				consider(run)
+			}
+		}
+	}
+
+	s.memo[mask] = res
+	return res
+}
+
+// find returns the hand index in mask of a card with suit and Ace-low rank
+// value, or -1
+func (s *meldSearch) find(mask uint64, suit uint8, value uint8) int {
+	for _, i := range s.order {
+		c := s.hand[i]
+		if mask&(uint64(1)<<uint(i)) != 0 && !c.IsJoker() && c.Suit == suit && RankValue(c.Rank, true) == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// Deadwood returns the values total of hand's best deadwood
+func Deadwood(hand []Card, values [13]int32) int32 {
+	_, deadwood := MeldDecomposition(hand, values)
+	return HandValue(deadwood, values)
+}
+
+// CanKnock reports whether hand's deadwood under values is at most threshold
+func CanKnock(hand []Card, values [13]int32, threshold int32) bool {
+	return Deadwood(hand, values) <= threshold
+}
+
+// CanGin reports whether every card in hand is melded
+func CanGin(hand []Card, values [13]int32) bool {
+	_, deadwood := MeldDecomposition(hand, values)
+	return len(deadwood) == 0
+}
+
+// knockValues returns the deadwood values genome scores knocks with
+func knockValues(genome *Genome) [13]int32 {
+	if genome == nil || genome.RankValues == ([13]int32{}) {
+		return RummyRankValues
+	}
+	return genome.RankValues
+}
+
+// ScoreKnock settles a Gin Rummy knock by knocker against each other
+// remaining player and returns the points each player was awarded, which
+// are also added to their scores. The knocker scores the difference in
+// deadwood, plus GinBonus with none; a defender with no more deadwood than
+// the knocker undercuts and scores the difference plus UndercutBonus
+// instead. Layoffs onto the knocker's melds are not played.
+func ScoreKnock(state *GameState, knocker int, values [13]int32) []int32 {
+	points := make([]int32, len(state.Players))
+	if knocker < 0 || knocker >= len(state.Players) {
+		return points
+	}
+	knockerDeadwood := Deadwood(state.Players[knocker].Hand, values)
+	gin := CanGin(state.Players[knocker].Hand, values)
+
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		if playerID == knocker || state.Players[playerID].Eliminated {
+			continue
+		}
+		defenderDeadwood := Deadwood(state.Players[playerID].Hand, values)
+		switch {
+		case gin:
+			points[knocker] += defenderDeadwood + GinBonus
+		case defenderDeadwood <= knockerDeadwood:
+			points[playerID] += knockerDeadwood - defenderDeadwood + UndercutBonus
+		default:
+			points[knocker] += defenderDeadwood - knockerDeadwood
+		}
+	}
+
+	for playerID, p := range points {
+		if p != 0 {
+			state.Players[playerID].Score += p
+			UpdateTeamScore(state, playerID, p)
+		}
+	}
+	return points
+}
+
+// KnockWinner returns the winner of state's knock, or -1 if nobody has
+// knocked: the knocker, unless they went down without gin and a defender
+// undercut them, in which case the defender with the least deadwood
+func KnockWinner(state *GameState, values [13]int32) int8 {
+	knocker := int(state.Knocker)
+	if knocker < 0 || knocker >= len(state.Players) {
+		return -1
+	}
+	if CanGin(state.Players[knocker].Hand, values) {
+		return int8(knocker)
+	}
+
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+	winner, best := int8(knocker), Deadwood(state.Players[knocker].Hand, values)
+	undercut := false
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		if playerID == knocker || state.Players[playerID].Eliminated {
+			continue
+		}
+		deadwood := Deadwood(state.Players[playerID].Hand, values)
+		if deadwood < best || (deadwood == best && !undercut) {
+			winner, best, undercut = int8(playerID), deadwood, true
+		}
+	}
+	return winner
+}
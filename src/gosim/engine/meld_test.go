@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBestMeldDecompositionContestedCard(t *testing.T) {
+	// 4-5-6-7 of hearts and the 7s of clubs and spades: the 7 of hearts
+	// goes to the set, not the run, leaving no deadwood
+	hand := []Card{
+		{Rank: 2, Suit: 0}, {Rank: 3, Suit: 0}, {Rank: 4, Suit: 0}, {Rank: 5, Suit: 0},
+		{Rank: 5, Suit: 2}, {Rank: 5, Suit: 3},
+	}
+	melds, deadwood := BestMeldDecomposition(hand)
+	if len(deadwood) != 0 {
+		t.Fatalf("Expected no deadwood, got %v (melds %v)", deadwood, melds)
+	}
+	want := [][]Card{
+		{{Rank: 2, Suit: 0}, {Rank: 3, Suit: 0}, {Rank: 4, Suit: 0}},
+		{{Rank: 5, Suit: 0}, {Rank: 5, Suit: 2}, {Rank: 5, Suit: 3}},
+	}
+	if !reflect.DeepEqual(melds, want) {
+		t.Errorf("Expected run 4-6 and a set of 7s, got %v", melds)
+	}
+
+	// A stray 8 of hearts pulls the 7 back into the run: 4-8 and three 7s
+	hand = append(hand, Card{Rank: 6, Suit: 0}, Card{Rank: 5, Suit: 1})
+	melds, deadwood = BestMeldDecomposition(hand)
+	if len(deadwood) != 0 || len(melds) != 2 || len(melds[0]) != 5 {
+		t.Errorf("Expected the 4-8 run and a set of three 7s, got %v with deadwood %v", melds, deadwood)
+	}
+}
+
+func TestBestMeldDecompositionAceLowAndDeadwood(t *testing.T) {
+	hand := []Card{
+		{Rank: AceRank, Suit: 1}, {Rank: 0, Suit: 1}, {Rank: 1, Suit: 1}, // A-2-3 of diamonds
+		{Rank: 11, Suit: 1}, // K of diamonds can't wrap to the Ace
+		{Rank: 9, Suit: 2},  // J
+	}
+	melds, deadwood := BestMeldDecomposition(hand)
+	if len(melds) != 1 || len(melds[0]) != 3 {
+		t.Errorf("Expected the A-2-3 run, got %v", melds)
+	}
+	if want := []Card{{Rank: 11, Suit: 1}, {Rank: 9, Suit: 2}}; !reflect.DeepEqual(deadwood, want) {
+		t.Errorf("Expected the king and jack as deadwood, got %v", deadwood)
+	}
+	if Deadwood(hand, RummyRankValues) != 20 || CanKnock(hand, RummyRankValues, KnockLimit) || CanGin(hand, RummyRankValues) {
+		t.Errorf("Expected 20 deadwood and no knock, got %d", Deadwood(hand, RummyRankValues))
+	}
+}
+
+func TestScoreKnock(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	// Knocker: run 2-3-4 of hearts, set of 9s, 2 of spades (2 deadwood)
+	state.Players[0].Hand = []Card{
+		{Rank: 0, Suit: 0}, {Rank: 1, Suit: 0}, {Rank: 2, Suit: 0},
+		{Rank: 7, Suit: 1}, {Rank: 7, Suit: 2}, {Rank: 7, Suit: 3},
+		{Rank: 0, Suit: 3},
+	}
+	// Defender: queen and king unmelded (20 deadwood)
+	state.Players[1].Hand = []Card{{Rank: 10, Suit: 0}, {Rank: 11, Suit: 2}}
+
+	if !CanKnock(state.Players[0].Hand, RummyRankValues, KnockLimit) {
+		t.Fatal("Expected 2 deadwood to allow a knock")
+	}
+	if got := ScoreKnock(state, 0, RummyRankValues); !reflect.DeepEqual(got, []int32{18, 0}) || state.Players[0].Score != 18 {
+		t.Errorf("Expected the knocker to score 18, got %v", got)
+	}
+
+	// The defender undercuts with less deadwood
+	state.Players[1].Hand = []Card{{Rank: 0, Suit: 2}}
+	if got := ScoreKnock(state, 0, RummyRankValues); !reflect.DeepEqual(got, []int32{0, UndercutBonus}) {
+		t.Errorf("Expected an undercut worth %d, got %v", UndercutBonus, got)
+	}
+
+	// Gin scores the defender's whole deadwood plus the bonus
+	state.Players[0].Hand = state.Players[0].Hand[:6]
+	state.Players[1].Hand = []Card{{Rank: 10, Suit: 0}}
+	if got := ScoreKnock(state, 0, RummyRankValues); !reflect.DeepEqual(got, []int32{10 + GinBonus, 0}) {
+		t.Errorf("Expected gin worth %d, got %v", 10+GinBonus, got)
+	}
+}
+
+func TestCanKnockThreshold(t *testing.T) {
+	// Run 2-3-4 of hearts, set of 9s, and a 10 left over
+	hand := []Card{
+		{Rank: 0, Suit: 0}, {Rank: 1, Suit: 0}, {Rank: 2, Suit: 0},
+		{Rank: 7, Suit: 1}, {Rank: 7, Suit: 2}, {Rank: 7, Suit: 3},
+		{Rank: 8, Suit: 3},
+	}
+	if !CanKnock(hand, RummyRankValues, 10) {
+		t.Error("Expected 10 deadwood to knock at a threshold of 10")
+	}
+	if CanKnock(hand, RummyRankValues, 9) || CanGin(hand, RummyRankValues) {
+		t.Error("Expected 10 deadwood not to knock at 9 or count as gin")
+	}
+
+	// An ace more puts the hand one over
+	hand = append(hand, Card{Rank: AceRank, Suit: 2})
+	if CanKnock(hand, RummyRankValues, 10) {
+		t.Errorf("Expected %d deadwood not to knock at 10", Deadwood(hand, RummyRankValues))
+	}
+	if !CanGin(hand[:6], RummyRankValues) {
+		t.Error("Expected the melds alone to be gin")
+	}
+}
+
+func TestKnockMove(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	// Discarding the king leaves 2-3-4 of hearts, 9s and a 3 of spades
+	state.Players[0].Hand = []Card{
+		{Rank: 0, Suit: 0}, {Rank: 1, Suit: 0}, {Rank: 2, Suit: 0},
+		{Rank: 7, Suit: 1}, {Rank: 7, Suit: 2}, {Rank: 7, Suit: 3},
+		{Rank: 1, Suit: 3}, {Rank: 11, Suit: 3},
+	}
+	state.Players[1].Hand = []Card{{Rank: 10, Suit: 0}, {Rank: 11, Suit: 2}}
+	genome := &Genome{
+		TurnPhases:     []PhaseDescriptor{{PhaseType: PhaseTypeDiscard}},
+		Knocking:       true,
+		KnockThreshold: KnockLimit,
+	}
+
+	var knocks []LegalMove
+	for _, move := range GenerateLegalMoves(state, genome) {
+		if move.CardIndex <= MoveKnockOffset {
+			knocks = append(knocks, move)
+		}
+	}
+	// Only dropping the king or the 3 of spades leaves 10 or less
+	if len(knocks) != 2 || knocks[0].CardIndex != MoveKnockOffset-6 || knocks[1].CardIndex != MoveKnockOffset-7 {
+		t.Fatalf("Expected knocks discarding cards 6 and 7, got %+v", knocks)
+	}
+
+	ApplyMove(state, &knocks[1], genome)
+	result := CheckGameEnd(state, genome)
+	if result.Winner != 0 || result.Reason != EndReasonKnock {
+		t.Errorf("Expected player 0 to win by knocking, got %+v", result)
+	}
+	if state.Players[0].Score != 20-3 {
+		t.Errorf("Expected the knock to score 17, got %d", state.Players[0].Score)
+	}
+}
@@ -34,11 +34,54 @@ const (
 	MoveDrawPass = -3 // Skip drawing (stand in blackjack)
 )
 
+// Buried discard draws are encoded as -(pile_index + 20), so -20 takes the
+// bottom card of the discard pile
+const (
+	MoveDrawAtOffset = -20 // CardIndex = -(pile_index + 20)
+)
+
+// DrawPhase flags, byte 6 of the phase data
+const (
+	DrawFlagCondition   = 0x01 // A 7-byte condition follows the flags
+	DrawFlagPickDiscard = 0x02 // Any discard may be drawn, not just the top card
+	DrawFlagTakeAbove   = 0x04 // Drawing a buried discard also takes every card above it
+)
+
+// Discard-and-knock moves in a DiscardPhase (Genome.Knocking) are encoded
+// as -(card_index + 200)
+const (
+	MoveKnockOffset = -200 // CardIndex = -(card_index + 200)
+)
+
+// Discards down to Genome.MaxHandSize are encoded as -(card_index + 300).
+// They belong to no phase and carry PhaseIndex 0.
+const (
+	MoveForcedDiscardOffset = -300 // CardIndex = -(card_index + 300)
+)
+
 // Special CardIndex values for PlayPhase
 const (
 	MovePlayPass = -4 // Pass/skip playing (used in President when can't beat top card)
 )
 
+// Play phase pass rules, the phase's pass byte (Data[4]): whether a player
+// may answer with MovePlayPass
+const (
+	PlayPassNever    = 0 // Never; a player without a play has no move
+	PlayPassIfUnable = 1 // Only without a legal play
+	PlayPassAllowed  = 2 // Any time, even holding a legal play (President)
+)
+
+// Special CardIndex values for RevealPhase
+const (
+	MoveReveal = -5 // Turn all of the player's face-down cards face-up
+)
+
+// Special CardIndex values for ActionPhase
+const (
+	MoveAction = -6 // Run the phase's action program
+)
+
 // Special CardIndex values for BettingPhase
 const (
 	MoveBettingCheck = -10
@@ -63,12 +106,37 @@ type LegalMove struct {
 	TargetLoc  Location
 }
 
-// GenerateLegalMoves returns all valid moves for current player
+// GenerateLegalMoves returns all valid moves for current player. With
+// genome.SequentialPhases only the first phase from state.CurrentPhase on
+// that has any move is offered; phases without one are skipped.
 func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
-	moves := make([]LegalMove, 0, 10)
+	return GenerateLegalMovesInto(state, genome, make([]LegalMove, 0, 10))
+}
+
+// GenerateLegalMovesInto appends all valid moves for current player to buf
+// and returns the extended slice. Hot loops pass buf[:0] from the previous
+// call to avoid allocating; the result aliases buf, so copy a move out
+// before the next call if it must survive.
+func GenerateLegalMovesInto(state *GameState, genome *Genome, buf []LegalMove) []LegalMove {
+	moves := buf
 	currentPlayer := state.CurrentPlayer
 
+	// A player over the hand size cap must discard before anything else
+	if overHandCap(state, genome, currentPlayer) {
+		for cardIdx := range state.Players[currentPlayer].Hand {
+			moves = append(moves, LegalMove{
+				PhaseIndex: 0,
+				CardIndex:  MoveForcedDiscardOffset - cardIdx,
+				TargetLoc:  LocationDiscard,
+			})
+		}
+		return moves
+	}
+
 	for phaseIdx, phase := range genome.TurnPhases {
+		if genome.SequentialPhases && (phaseIdx < state.CurrentPhase || len(moves) > len(buf)) {
+			continue
+		}
 		switch phase.PhaseType {
 		case 1: // DrawPhase
 			if len(phase.Data) < 6 {
@@ -83,15 +151,9 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 			source := Location(phase.Data[0])
 			mandatory := phase.Data[5] == 1
 
-			// Check phase condition if present
-			// Data layout: source:1, count:4, mandatory:1, has_condition:1, [condition:7]
-			hasCondition := len(phase.Data) > 6 && phase.Data[6] == 1
-			if hasCondition && len(phase.Data) >= 14 {
-				// Condition is at bytes 7-13: opcode:1, operator:1, value:4, ref:1
-				conditionMet := EvaluateCondition(state, currentPlayer, phase.Data[7:14])
-				if !conditionMet {
-					continue // Skip this phase if condition not met
-				}
+			// Skip this phase if its draw condition is not met (see drawOffered)
+			if !drawOffered(state, currentPlayer, phase.Data) {
+				continue
 			}
 
 			// Check if can draw, with automatic deck reshuffling
@@ -99,8 +161,8 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 			switch source {
 			case LocationDeck:
 				// If deck is empty but discard has cards, reshuffle discard into deck
-				if len(state.Deck) == 0 && len(state.Discard) > 1 {
-					reshuffleDeck(state)
+				if len(state.Deck) == 0 {
+					RecycleDiscard(state, uint64(state.TurnNumber))
 				}
 				canDraw = len(state.Deck) > 0
 			case LocationDiscard:
@@ -119,6 +181,17 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 				})
 			}
 
+			// Buried discards, when the phase lets players pick them
+			if source == LocationDiscard && drawFlags(phase.Data)&DrawFlagPickDiscard != 0 {
+				for i := len(state.Discard) - 2; i >= 0; i-- {
+					moves = append(moves, LegalMove{
+						PhaseIndex: phaseIdx,
+						CardIndex:  MoveDrawAtOffset - i,
+						TargetLoc:  source,
+					})
+				}
+			}
+
 			// Add pass/stand option when drawing is not mandatory
 			if !mandatory && canDraw {
 				moves = append(moves, LegalMove{
@@ -136,7 +209,7 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 			minCards := int(phase.Data[1])
 			maxCards := int(phase.Data[2])
 			// phase.Data[3] is mandatory flag
-			passIfUnable := phase.Data[4] == 1
+			passRule := phase.Data[4]
 			conditionLen := int(binary.BigEndian.Uint32(phase.Data[5:9]))
 
 			// Extract condition bytes if present
@@ -197,7 +270,7 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 						for _, pile := range state.Tableau {
 							if len(pile) > 0 {
 								topCard := pile[len(pile)-1]
-								if isValidSequencePlay(card, topCard, state.SequenceDirection) {
+								if isValidSequencePlay(card, topCard, state.SequenceDirection, state.AceLow) {
 									canPlayOnExisting = true
 									break
 								}
@@ -226,8 +299,8 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 					}
 				}
 
-				// If no valid plays but pass_if_unable is set, add pass move
-				if playMoveCount == 0 && passIfUnable {
+				// Pass when the phase allows it (see PlayPassIfUnable)
+				if passRule == PlayPassAllowed || (playMoveCount == 0 && passRule == PlayPassIfUnable) {
 					moves = append(moves, LegalMove{
 						PhaseIndex: phaseIdx,
 						CardIndex:  MovePlayPass,
@@ -239,8 +312,12 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 
 			// Single-card plays (standard)
 			if minCards <= 1 && maxCards >= 1 {
+				fromTop := PlaysFromTop(state, target)
 				// Check each card in hand
 				for cardIdx, card := range hand {
+					if fromTop && cardIdx != len(hand)-1 {
+						continue
+					}
 					// Evaluate valid_play_condition if present
 					if len(conditionBytes) > 0 {
 						if !EvaluateCardCondition(state, currentPlayer, card, conditionBytes) {
@@ -261,19 +338,20 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 			// CardIndex encodes the rank to play (all cards of that rank)
 			if minCards > 1 {
 				// Count cards by rank
-				rankCounts := make(map[uint8]int)
-				for _, card := range hand {
-					rankCounts[card.Rank]++
-				}
+				rankCounts := CountRanks(hand)
 
-				// Find ranks with enough cards
+				// Find ranks with enough cards, in rank order
 				for rank, count := range rankCounts {
 					if count >= minCards && count <= maxCards {
+						// The whole set must satisfy valid_play_condition
+						if len(conditionBytes) > 0 && !evaluateCardCondition(state, currentPlayer, highestOfRank(state, hand, uint8(rank)), count, conditionBytes) {
+							continue
+						}
 						// Use negative CardIndex to encode rank + 100
 						// CardIndex = -(rank + 100) to distinguish from single plays
 						moves = append(moves, LegalMove{
 							PhaseIndex: phaseIdx,
-							CardIndex:  -int(rank) - 100, // Negative rank encoding
+							CardIndex:  -rank - 100, // Negative rank encoding
 							TargetLoc:  target,
 						})
 						playMoveCount++
@@ -281,8 +359,8 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 				}
 			}
 
-			// If no valid plays but pass_if_unable is set, add pass move
-			if playMoveCount == 0 && passIfUnable {
+			// Pass when the phase allows it (see PlayPassIfUnable)
+			if passRule == PlayPassAllowed || (playMoveCount == 0 && passRule == PlayPassIfUnable) {
 				moves = append(moves, LegalMove{
 					PhaseIndex: phaseIdx,
 					CardIndex:  MovePlayPass,
@@ -301,6 +379,9 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 					})
 				}
 			}
+			if genome.Knocking {
+				moves = appendKnockMoves(moves, state, genome, phaseIdx)
+			}
 
 		case 4: // TrickPhase
 			if len(phase.Data) < 4 {
@@ -493,14 +574,156 @@ func GenerateLegalMoves(state *GameState, genome *Genome) []LegalMove {
 					TargetLoc:  targetLoc,
 				})
 			}
+
+		case 8: // RevealPhase - showdown, players reveal in turn order
+			player := &state.Players[currentPlayer]
+			if player.HasFolded || player.HiddenCount() == 0 {
+				continue
+			}
+			moves = append(moves, LegalMove{
+				PhaseIndex: phaseIdx,
+				CardIndex:  MoveReveal,
+				TargetLoc:  LocationHand,
+			})
+
+		case 9: // ActionPhase - runs automatically, so a single move
+			if len(phase.Data) < 1 {
+				continue
+			}
+			moves = append(moves, LegalMove{
+				PhaseIndex: phaseIdx,
+				CardIndex:  MoveAction,
+				TargetLoc:  LocationHand,
+			})
+
+		case 10: // SimultaneousPhase - commit one card face-down per round
+			if !canCommit(state, int(currentPlayer)) {
+				continue
+			}
+			for cardIdx := range state.Players[currentPlayer].Hand {
+				moves = append(moves, LegalMove{
+					PhaseIndex: phaseIdx,
+					CardIndex:  cardIdx,
+					TargetLoc:  LocationTableau,
+				})
+			}
 		}
 	}
 
 	return moves
 }
 
-// ApplyMove executes a legal move, mutating state
+// SingleForcedMove returns the only legal move when it can be found without
+// running the full generator: a single-phase genome whose phase is a
+// mandatory draw from a non-empty deck or discard, a single-card hand in
+// an unconditioned play or discard phase, or an action phase. Returns false whenever the answer
+// isn't trivially one move; callers then fall back to GenerateLegalMoves,
+// which agrees with this whenever it returns true.
+func SingleForcedMove(state *GameState, genome *Genome) (*LegalMove, bool) {
+	if len(genome.TurnPhases) != 1 || int(state.CurrentPlayer) >= len(state.Players) ||
+		overHandCap(state, genome, state.CurrentPlayer) {
+		return nil, false
+	}
+	phase := genome.TurnPhases[0]
+	currentPlayer := state.CurrentPlayer
+	hand := state.Players[currentPlayer].Hand
+
+	switch phase.PhaseType {
+	case 1: // DrawPhase
+		if len(phase.Data) < 6 || phase.Data[5] != 1 {
+			return nil, false
+		}
+		if int(currentPlayer) < len(state.HasStood) && state.HasStood[currentPlayer] {
+			return nil, false
+		}
+		source := Location(phase.Data[0])
+		// An empty deck may trigger a reshuffle in the full generator; being
+		// mandatory, the draw ignores any condition (see drawOffered)
+		canDraw := (source == LocationDeck && len(state.Deck) > 0) ||
+			(source == LocationDiscard && len(state.Discard) > 0)
+		if !canDraw {
+			return nil, false
+		}
+		if source == LocationDiscard && drawFlags(phase.Data)&DrawFlagPickDiscard != 0 && len(state.Discard) > 1 {
+			return nil, false // Buried discards can be picked too
+		}
+		return &LegalMove{PhaseIndex: 0, CardIndex: MoveDraw, TargetLoc: source}, true
+
+	case 2: // PlayPhase
+		if len(phase.Data) < 9 || len(hand) != 1 {
+			return nil, false
+		}
+		target := Location(phase.Data[0])
+		minCards := int(phase.Data[1])
+		maxCards := int(phase.Data[2])
+		conditionLen := binary.BigEndian.Uint32(phase.Data[5:9])
+		if conditionLen != 0 || minCards > 1 || maxCards < 1 {
+			return nil, false
+		}
+		if state.TableauMode == 3 && target == LocationTableau {
+			return nil, false // Sequence plays depend on the piles
+		}
+		return &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: target}, true
+
+	case 3: // DiscardPhase
+		if len(hand) != 1 || genome.Knocking {
+			return nil, false
+		}
+		return &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationDiscard}, true
+
+	case 9: // ActionPhase
+		if len(phase.Data) < 1 {
+			return nil, false
+		}
+		return &LegalMove{PhaseIndex: 0, CardIndex: MoveAction, TargetLoc: LocationHand}, true
+	}
+	return nil, false
+}
+
+// drawConditionMet reports whether a DrawPhase's optional condition passes.
+// Data layout: source:1, count:4, mandatory:1, flags:1, [condition:7]
+// Phases without a condition (or with truncated condition bytes) always pass.
+func drawConditionMet(state *GameState, playerID uint8, data []byte) bool {
+	hasCondition := drawFlags(data)&DrawFlagCondition != 0
+	if !hasCondition || len(data) < 14 {
+		return true
+	}
+	// Condition is at bytes 7-13: opcode:1, operator:1, value:4, ref:1
+	return EvaluateCondition(state, playerID, data[7:14])
+}
+
+// drawOffered reports whether a DrawPhase's draw may be taken. A mandatory
+// draw always may, since the turn can't go on without it, so its condition
+// doesn't apply; an optional draw needs its condition to pass.
+func drawOffered(state *GameState, playerID uint8, data []byte) bool {
+	mandatory := len(data) >= 6 && data[5] == 1
+	return mandatory || drawConditionMet(state, playerID, data)
+}
+
+// drawFlags returns a DrawPhase's DrawFlag bits, 0 if the data has none
+func drawFlags(data []byte) byte {
+	if len(data) < 7 {
+		return 0
+	}
+	return data[6]
+}
+
+// ApplyMove executes a legal move, mutating state.
+// With state.RecordHistory set, the move is logged for UndoLastMove.
 func ApplyMove(state *GameState, move *LegalMove, genome *Genome) {
+	if state.Trace != nil {
+		state.Trace(TraceEvent{Kind: TraceMove, Turn: state.TurnNumber, Player: int(state.CurrentPlayer), Move: *move})
+	}
+	if !state.RecordHistory {
+		applyMove(state, move, genome)
+		return
+	}
+	state.Undone = state.Undone[:0]
+	recordMove(state, move, genome)
+}
+
+// applyMove executes a legal move without touching the move history
+func applyMove(state *GameState, move *LegalMove, genome *Genome) {
 	if move.PhaseIndex >= len(genome.TurnPhases) {
 		return
 	}
@@ -508,15 +731,41 @@ func ApplyMove(state *GameState, move *LegalMove, genome *Genome) {
 	phase := genome.TurnPhases[move.PhaseIndex]
 	currentPlayer := state.CurrentPlayer
 
+	if move.CardIndex <= MoveForcedDiscardOffset {
+		if cardIdx := MoveForcedDiscardOffset - move.CardIndex; cardIdx < len(state.Players[currentPlayer].Hand) {
+			state.PlayCard(currentPlayer, cardIdx, LocationDiscard)
+		}
+		endTurn(state, genome, currentPlayer)
+		return
+	}
+
 	switch phase.PhaseType {
 	case 1: // DrawPhase
+		// Re-check the draw condition so stale moves can't bypass it: a
+		// refused draw takes nothing, and the turn moves on as after a pass
+		if move.CardIndex != MoveDrawPass && !drawOffered(state, currentPlayer, phase.Data) {
+			break
+		}
+
 		// MoveDrawPass (-3) = stand/pass, mark player as stood (for Blackjack-style games)
 		// MoveDraw (-1) = hit/draw
 		if move.CardIndex == MoveDraw && len(phase.Data) >= 5 {
 			count := int(binary.BigEndian.Uint32(phase.Data[1:5]))
+			mandatory := len(phase.Data) >= 6 && phase.Data[5] == 1
 			for i := 0; i < count; i++ {
+				// A mandatory draw must not stall on an empty deck
+				if mandatory && move.TargetLoc == LocationDeck && len(state.Deck) == 0 {
+					RecycleDiscard(state, uint64(state.TurnNumber))
+				}
 				state.DrawCard(currentPlayer, move.TargetLoc)
 			}
+			// A bust ends the player's drawing for the hand, as standing does
+			if IsBust(state.Players[currentPlayer].Hand, genome) && int(currentPlayer) < len(state.HasStood) {
+				state.HasStood[currentPlayer] = true
+			}
+		} else if move.CardIndex <= MoveDrawAtOffset {
+			takeAbove := drawFlags(phase.Data)&DrawFlagTakeAbove != 0
+			state.DrawCardAt(currentPlayer, move.TargetLoc, MoveDrawAtOffset-move.CardIndex, takeAbove)
 		} else if move.CardIndex == MoveDrawPass {
 			// Mark player as having stood - but only for non-shedding games
 			// In shedding games (empty_hand win condition), passing is just skipping a draw
@@ -598,6 +847,7 @@ func ApplyMove(state *GameState, move *LegalMove, genome *Genome) {
 			state.Players[currentPlayer].Hand = newHand
 
 			// Play cards to target location
+			state.MarkSeen(cardsToPlay...)
 			switch move.TargetLoc {
 			case LocationDiscard:
 				state.Discard = append(state.Discard, cardsToPlay...)
@@ -622,6 +872,10 @@ func ApplyMove(state *GameState, move *LegalMove, genome *Genome) {
 	case 3: // DiscardPhase
 		if move.CardIndex >= 0 {
 			state.PlayCard(currentPlayer, move.CardIndex, LocationDiscard)
+		} else if cardIdx := MoveKnockOffset - move.CardIndex; cardIdx >= 0 && cardIdx < len(state.Players[currentPlayer].Hand) {
+			state.PlayCard(currentPlayer, cardIdx, LocationDiscard)
+			state.Knocker = int8(currentPlayer)
+			ScoreKnock(state, int(currentPlayer), knockValues(genome))
 		}
 
 	case 4: // TrickPhase
@@ -639,6 +893,7 @@ func ApplyMove(state *GameState, move *LegalMove, genome *Genome) {
 				PlayerID: currentPlayer,
 				Card:     card,
 			})
+			state.MarkSeen(card)
 
 			// Check if this card breaks hearts (or other breaking suit)
 			if len(phase.Data) >= 4 {
@@ -648,14 +903,11 @@ func ApplyMove(state *GameState, move *LegalMove, genome *Genome) {
 				}
 			}
 
-			// Check if trick is complete
-			numPlayers := int(state.NumPlayers)
-			if numPlayers == 0 {
-				numPlayers = 2 // Default to 2 players
-			}
-			if len(state.CurrentTrick) >= numPlayers {
+			// Check if trick is complete (eliminated players sit out)
+			if len(state.CurrentTrick) >= RemainingPlayers(state) {
 				// Resolve trick
 				resolveTrick(state, genome, phase)
+				state.CurrentPhase = 0
 				return // Don't advance turn normally - resolveTrick sets next player
 			}
 		}
@@ -704,6 +956,7 @@ func ApplyMove(state *GameState, move *LegalMove, genome *Genome) {
 				resolveChallenge(state, currentPlayer)
 				// After challenge resolves, this player makes the next claim
 				// Don't advance turn - current player will claim
+				state.CurrentPhase = 0
 				state.TurnNumber++
 				return
 			}
@@ -712,6 +965,7 @@ func ApplyMove(state *GameState, move *LegalMove, genome *Genome) {
 			state.CurrentClaim = nil
 			// After pass, this player makes the next claim
 			// Don't advance turn - current player will claim
+			state.CurrentPhase = 0
 			state.TurnNumber++
 			return
 		}
@@ -728,19 +982,92 @@ func ApplyMove(state *GameState, move *LegalMove, genome *Genome) {
 			// Don't advance turn for bidding - round continues until all players bid
 			// The next player to bid is determined by clockwise order
 			state.CurrentPlayer = (state.CurrentPlayer + 1) % state.NumPlayers
+			state.CurrentPhase = 0
 			state.TurnNumber++
 			return
 		}
+
+	case 8: // RevealPhase
+		if move.CardIndex == MoveReveal {
+			ApplyReveal(state, int(currentPlayer))
+		}
+
+	case 9: // ActionPhase
+		if move.CardIndex == MoveAction && len(phase.Data) >= 1 {
+			ExecuteActions(state, currentPlayer, phase.Data[1:])
+		}
+
+	case 10: // SimultaneousPhase - resolve once the last card is in
+		if CommitCard(state, int(currentPlayer), move.CardIndex) && RoundCommitted(state) {
+			ResolveSimultaneous(state, simultaneousOutcome(phase.Data))
+		}
 	}
 
-	// Advance turn
-	state.CurrentPlayer = (state.CurrentPlayer + 1) % state.NumPlayers
+	// Sequential turns stay with the player while a later phase has a move
+	if genome.SequentialPhases && openPhase(state, genome, move.PhaseIndex+1) {
+		return
+	}
+	endTurn(state, genome, currentPlayer)
+}
+
+// endTurn passes the turn on from currentPlayer, unless they hold more than
+// the hand size cap and must discard down to it first
+func endTurn(state *GameState, genome *Genome, currentPlayer uint8) {
+	state.CurrentPhase = 0
+	if overHandCap(state, genome, currentPlayer) {
+		return
+	}
+
+	// Advance turn in play direction, applying any pending skips
 	if state.NumPlayers == 0 {
 		state.CurrentPlayer = 1 - currentPlayer // Fallback for 2 players
+	} else {
+		AdvanceTurn(state)
 	}
 	state.TurnNumber++
 }
 
+// overHandCap reports whether playerID holds more cards than genome.MaxHandSize
+func overHandCap(state *GameState, genome *Genome, playerID uint8) bool {
+	return genome.MaxHandSize > 0 && int(playerID) < len(state.Players) &&
+		len(state.Players[playerID].Hand) > genome.MaxHandSize
+}
+
+// appendKnockMoves adds a discard-and-knock move for each card whose
+// discard leaves the current player's deadwood within the knock threshold
+func appendKnockMoves(moves []LegalMove, state *GameState, genome *Genome, phaseIdx int) []LegalMove {
+	hand := state.Players[state.CurrentPlayer].Hand
+	values := knockValues(genome)
+	rest := make([]Card, 0, len(hand))
+	for cardIdx := range hand {
+		rest = append(rest[:0], hand[:cardIdx]...)
+		rest = append(rest, hand[cardIdx+1:]...)
+		if CanKnock(rest, values, genome.KnockThreshold) {
+			moves = append(moves, LegalMove{
+				PhaseIndex: phaseIdx,
+				CardIndex:  MoveKnockOffset - cardIdx,
+				TargetLoc:  LocationDiscard,
+			})
+		}
+	}
+	return moves
+}
+
+// openPhase moves state.CurrentPhase to the first phase from `from` on in
+// which the current player has a move, and reports false if there is none
+func openPhase(state *GameState, genome *Genome, from int) bool {
+	if from >= len(genome.TurnPhases) {
+		return false
+	}
+	state.CurrentPhase = from
+	moves := GenerateLegalMovesInto(state, genome, make([]LegalMove, 0, 10))
+	if len(moves) == 0 {
+		return false
+	}
+	state.CurrentPhase = moves[0].PhaseIndex
+	return true
+}
+
 // calculateTrickPoints calculates points for cards in current trick.
 // Uses explicit CardScoring rules from genome if available, otherwise
 // falls back to implicit Hearts scoring for backwards compatibility.
@@ -802,6 +1129,10 @@ func resolveTrick(state *GameState, genome *Genome, phase PhaseDescriptor) {
 	for i := 1; i < len(state.CurrentTrick); i++ {
 		tc := state.CurrentTrick[i]
 		card := tc.Card
+		// Compared cards share a suit here, so this is a rank comparison
+		// unless several decks put identical cards in one trick
+		cmp := state.CompareCards(card, winningCard)
+		higher, lower := cmp > 0, cmp < 0
 
 		// Determine if this card beats the current winner
 		beats := false
@@ -817,17 +1148,17 @@ func resolveTrick(state *GameState, genome *Genome, phase PhaseDescriptor) {
 			} else if cardIsTrump && winnerIsTrump {
 				// Both trump - compare ranks
 				if highCardWins {
-					beats = card.Rank > winningCard.Rank
+					beats = higher
 				} else {
-					beats = card.Rank < winningCard.Rank
+					beats = lower
 				}
 			} else if !cardIsTrump && !winnerIsTrump && card.Suit == leadSuit {
 				// Neither trump - must follow suit to win
 				if winningCard.Suit == leadSuit {
 					if highCardWins {
-						beats = card.Rank > winningCard.Rank
+						beats = higher
 					} else {
-						beats = card.Rank < winningCard.Rank
+						beats = lower
 					}
 				} else {
 					// Current winner didn't follow suit, this card does
@@ -840,9 +1171,9 @@ func resolveTrick(state *GameState, genome *Genome, phase PhaseDescriptor) {
 				if winningCard.Suit != leadSuit {
 					beats = true
 				} else if highCardWins {
-					beats = card.Rank > winningCard.Rank
+					beats = higher
 				} else {
-					beats = card.Rank < winningCard.Rank
+					beats = lower
 				}
 			}
 		}
@@ -854,12 +1185,22 @@ func resolveTrick(state *GameState, genome *Genome, phase PhaseDescriptor) {
 	}
 
 	winner := state.CurrentTrick[winnerIdx].PlayerID
+	if state.Trace != nil {
+		state.Trace(TraceEvent{Kind: TraceTrick, Turn: state.TurnNumber, Player: int(winner), Card: winningCard})
+	}
 
 	// Calculate and award points for trick
 	points := calculateTrickPoints(state, genome, breakingSuit)
 	state.Players[winner].Score += points
 	UpdateTeamScore(state, int(winner), points)
 
+	// Tally point cards taken (Pinochle counters, Skat eyes)
+	if genome.RankValues != ([13]int32{}) {
+		for _, tc := range state.CurrentTrick {
+			state.Players[winner].TrickPoints += HandValue([]Card{tc.Card}, genome.RankValues)
+		}
+	}
+
 	// Track tricks won
 	if len(state.TricksWon) <= int(winner) {
 		// Extend TricksWon slice if needed
@@ -878,6 +1219,13 @@ func resolveTrick(state *GameState, genome *Genome, phase PhaseDescriptor) {
 	state.TurnNumber++
 }
 
+// PlaysFromTop reports whether plays to target are limited to the top of
+// the player's hand: a WAR tableau with WarToBottom, where hands are piles
+// played from the end and won cards go to the front
+func PlaysFromTop(state *GameState, target Location) bool {
+	return state.WarToBottom && state.TableauMode == 1 && target == LocationTableau
+}
+
 // resolveWarBattle handles War game card comparison
 func resolveWarBattle(state *GameState) {
 	// Check if both players have played (tableau has 2 cards)
@@ -889,11 +1237,12 @@ func resolveWarBattle(state *GameState) {
 	card1 := tableau[len(tableau)-2] // Second-to-last card (player 0's card)
 	card2 := tableau[len(tableau)-1] // Last card (player 1's card)
 
-	// Compare ranks (Ace high: A=12, K=11, ..., 2=0)
+	// Compare ranks (Ace high: A=12, K=11, ..., 2=0, unless the game plays Ace low)
+	value1, value2 := state.RankValue(card1.Rank), state.RankValue(card2.Rank)
 	var winner uint8
-	if card1.Rank > card2.Rank {
+	if value1 > value2 {
 		winner = 0
-	} else if card2.Rank > card1.Rank {
+	} else if value2 > value1 {
 		winner = 1
 	} else {
 		// Tie - alternate who wins ties based on battle number
@@ -902,9 +1251,18 @@ func resolveWarBattle(state *GameState) {
 		winner = uint8(battleNum % 2)
 	}
 
-	// Winner takes all cards from tableau
-	for _, card := range tableau {
-		state.Players[winner].Hand = append(state.Players[winner].Hand, card)
+	// Winner takes all cards from tableau, under their pile with WarToBottom
+	if state.WarToBottom {
+		hand := state.Players[winner].Hand
+		won := len(tableau)
+		hand = append(hand, tableau...)
+		copy(hand[won:], hand[:len(hand)-won])
+		copy(hand[:won], tableau)
+		state.Players[winner].Hand = hand
+	} else {
+		for _, card := range tableau {
+			state.Players[winner].Hand = append(state.Players[winner].Hand, card)
+		}
 	}
 
 	// Clear tableau
@@ -934,8 +1292,9 @@ func resolveMatchRankCapture(state *GameState, playerID uint8, playedCard Card)
 	}
 
 	if matchIdx >= 0 {
-		// Capture both cards - add to player's score
+		// Capture both cards - add to player's capture pile and score
 		capturedCard := tableau[matchIdx]
+		state.Players[playerID].Captured = append(state.Players[playerID].Captured, capturedCard, playedCard)
 
 		// Remove the matched card from tableau
 		state.Tableau[0] = append(tableau[:matchIdx], tableau[matchIdx+1:]...)
@@ -949,9 +1308,10 @@ func resolveMatchRankCapture(state *GameState, playerID uint8, playedCard Card)
 		state.Players[playerID].Score += 2 // Both captured card and played card
 		UpdateTeamScore(state, int(playerID), 2)
 
-		// For a more complete Scopa implementation, we'd track captured cards
-		// in a separate pile, but for scoring purposes, just increment Score
-		_ = capturedCard // Used for capture
+		// Clearing the tableau is a sweep (scopa)
+		if len(state.Tableau[0]) == 0 {
+			state.Players[playerID].Sweeps++
+		}
 	}
 	// If no match, played card stays on tableau (already added by PlayCard)
 }
@@ -976,17 +1336,27 @@ func setWinnerWithTeam(state *GameState, winnerID int8) int8 {
 // Exported so mcts package can use it
 // When a winner is found and teams are configured, also sets state.WinningTeam
 func CheckWinConditions(state *GameState, genome *Genome) int8 {
+	return CheckGameOutcome(state, genome).Winner
+}
+
+// CheckGameOutcome evaluates win conditions like CheckWinConditions and also
+// reports which kind of condition ended the game
+func CheckGameOutcome(state *GameState, genome *Genome) GameResult {
 	numPlayers := int(state.NumPlayers)
 	if numPlayers == 0 {
 		numPlayers = 2 // Default fallback
 	}
 
+	if genome.Knocking && state.Knocker >= 0 {
+		return newGameResult(state, setWinnerWithTeam(state, KnockWinner(state, knockValues(genome))), EndReasonKnock)
+	}
+
 	for _, wc := range genome.WinConditions {
 		switch wc.WinType {
 		case 0: // empty_hand
 			for playerID := 0; playerID < numPlayers; playerID++ {
-				if len(state.Players[playerID].Hand) == 0 {
-					return setWinnerWithTeam(state, int8(playerID))
+				if len(state.Players[playerID].Hand) == 0 && !state.Players[playerID].Eliminated {
+					return newGameResult(state, setWinnerWithTeam(state, int8(playerID)), EndReasonEmptyHand)
 				}
 			}
 		case 1: // high_score (highest score wins, triggers when anyone reaches threshold)
@@ -995,6 +1365,9 @@ func CheckWinConditions(state *GameState, genome *Genome) int8 {
 			triggered := false
 			for playerID := 0; playerID < numPlayers; playerID++ {
 				player := state.Players[playerID]
+				if player.Eliminated {
+					continue
+				}
 				if player.Score >= wc.Threshold {
 					triggered = true
 				}
@@ -1004,18 +1377,18 @@ func CheckWinConditions(state *GameState, genome *Genome) int8 {
 				}
 			}
 			if triggered && winner >= 0 {
-				return setWinnerWithTeam(state, winner)
+				return newGameResult(state, setWinnerWithTeam(state, winner), EndReasonScoreTarget)
 			}
-		case 2: // first_to_score
-			for playerID := 0; playerID < numPlayers; playerID++ {
-				if state.Players[playerID].Score >= wc.Threshold {
-					return setWinnerWithTeam(state, int8(playerID))
+		case 2: // first_to_score (see FirstToScoreWinner for threshold semantics)
+			if HandBoundary(state, genome) {
+				if winner := FirstToScoreWinner(state, wc.Threshold); winner >= 0 {
+					return newGameResult(state, setWinnerWithTeam(state, winner), EndReasonScoreTarget)
 				}
 			}
 		case 3: // capture_all
 			for playerID := 0; playerID < numPlayers; playerID++ {
 				if len(state.Players[playerID].Hand) == 52 {
-					return setWinnerWithTeam(state, int8(playerID))
+					return newGameResult(state, setWinnerWithTeam(state, int8(playerID)), EndReasonCaptureAll)
 				}
 			}
 		case 4: // low_score (Hearts: lowest score wins when anyone reaches threshold)
@@ -1024,6 +1397,9 @@ func CheckWinConditions(state *GameState, genome *Genome) int8 {
 			triggered := false
 			for playerID := 0; playerID < numPlayers; playerID++ {
 				player := state.Players[playerID]
+				if player.Eliminated {
+					continue
+				}
 				if player.Score >= wc.Threshold {
 					triggered = true
 				}
@@ -1033,7 +1409,7 @@ func CheckWinConditions(state *GameState, genome *Genome) int8 {
 				}
 			}
 			if triggered && winner >= 0 {
-				return setWinnerWithTeam(state, winner)
+				return newGameResult(state, setWinnerWithTeam(state, winner), EndReasonScoreTarget)
 			}
 		case 5: // all_hands_empty (trick-taking: hand ends when all empty)
 			allEmpty := true
@@ -1048,12 +1424,12 @@ func CheckWinConditions(state *GameState, genome *Genome) int8 {
 				minScore := int32(999999)
 				winner := int8(-1)
 				for playerID := 0; playerID < numPlayers; playerID++ {
-					if state.Players[playerID].Score < minScore {
+					if state.Players[playerID].Score < minScore && !state.Players[playerID].Eliminated {
 						minScore = state.Players[playerID].Score
 						winner = int8(playerID)
 					}
 				}
-				return setWinnerWithTeam(state, winner)
+				return newGameResult(state, setWinnerWithTeam(state, winner), EndReasonHandsPlayed)
 			}
 
 		case 6: // best_hand (poker: compare hands at end of game)
@@ -1062,15 +1438,21 @@ func CheckWinConditions(state *GameState, genome *Genome) int8 {
 			// and the draw phase is complete
 			allHaveFive := true
 			for playerID := 0; playerID < numPlayers; playerID++ {
-				if len(state.Players[playerID].Hand) != 5 {
+				if len(state.Players[playerID].Hand) != 5 && !state.Players[playerID].Eliminated {
 					allHaveFive = false
 					break
 				}
 			}
 			// Only trigger after some turns have passed (draw phase complete)
 			if allHaveFive && state.TurnNumber >= uint32(numPlayers*2) {
-				winner := FindBestPokerWinner(state, numPlayers)
-				return setWinnerWithTeam(state, winner)
+				// A chopped showdown has no single game winner: it ends drawn
+				switch winners := FindBestPokerWinner(state, numPlayers); len(winners) {
+				case 0:
+				case 1:
+					return newGameResult(state, setWinnerWithTeam(state, winners[0]), EndReasonShowdown)
+				default:
+					return GameResult{Winner: -1, Reason: EndReasonShowdown, Turn: state.TurnNumber}
+				}
 			}
 
 		case 7: // most_captured (Scopa: player with most captured cards wins)
@@ -1088,16 +1470,133 @@ func CheckWinConditions(state *GameState, genome *Genome) int8 {
 				maxScore := int32(-1)
 				winner := int8(-1)
 				for playerID := 0; playerID < numPlayers; playerID++ {
-					if state.Players[playerID].Score > maxScore {
+					if state.Players[playerID].Score > maxScore && !state.Players[playerID].Eliminated {
 						maxScore = state.Players[playerID].Score
 						winner = int8(playerID)
 					}
 				}
-				return setWinnerWithTeam(state, winner)
+				return newGameResult(state, setWinnerWithTeam(state, winner), EndReasonHandsPlayed)
+			}
+
+		case 12: // blackjack (closest to the threshold without busting, once everyone stands)
+			if allStood(state, numPlayers) {
+				target, values := blackjackRules(genome)
+				if winner := ScoreBlackjack(state, target, values); winner >= 0 {
+					return newGameResult(state, setWinnerWithTeam(state, winner), EndReasonShowdown)
+				}
 			}
+
+		case 11: // most_cards (Casino/Scopa: largest capture pile wins)
+			// Decided once the deck is exhausted and all hands are played out
+			if len(state.Deck) > 0 {
+				continue
+			}
+			handsEmpty := true
+			for playerID := 0; playerID < numPlayers; playerID++ {
+				if len(state.Players[playerID].Hand) > 0 {
+					handsEmpty = false
+					break
+				}
+			}
+			if handsEmpty {
+				if winner := mostCapturedWinner(state, numPlayers, int(wc.Threshold)); winner >= 0 {
+					return newGameResult(state, setWinnerWithTeam(state, winner), EndReasonHandsPlayed)
+				}
+			}
+		}
+	}
+	return newGameResult(state, -1, EndReasonNone)
+}
+
+// HandBoundary reports whether state sits between hands, where scores that
+// swing within a hand are settled and first_to_score is checked:
+//   - in match play, once the hand is complete (see HandComplete)
+//   - in trick games, once every trick of the hand has been played
+//   - otherwise the game is one running hand scored as it goes, so every
+//     turn is a boundary
+func HandBoundary(state *GameState, genome *Genome) bool {
+	if IsMatchPlay(genome) {
+		return HandComplete(state, genome)
+	}
+	for _, phase := range genome.TurnPhases {
+		if phase.PhaseType == PhaseTypeTrick {
+			return AllHandsEmpty(state)
 		}
 	}
-	return -1
+	return true
+}
+
+// AllHandsEmpty returns true when every player has played out their hand
+func AllHandsEmpty(state *GameState) bool {
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		if len(state.Players[playerID].Hand) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// FirstToScoreWinner evaluates a first_to_score condition, which callers
+// should only check at a HandBoundary since scores can swing within a hand.
+// The sign of threshold selects the variant:
+//   - threshold > 0: reach the target. Once any player's Score is at least
+//     threshold, the highest score wins.
+//   - threshold < 0: avoid the floor. Once any player's Score falls below
+//     threshold (e.g. after failed contracts), the highest score wins.
+//   - threshold == 0: no target; never fires.
+//
+// Ties go to the lower player index. Returns -1 if the game continues.
+func FirstToScoreWinner(state *GameState, threshold int32) int8 {
+	if threshold == 0 {
+		return -1
+	}
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+
+	triggered := false
+	winner := int8(-1)
+	var best int32
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		if state.Players[playerID].Eliminated {
+			continue
+		}
+		score := state.Players[playerID].Score
+		if (threshold > 0 && score >= threshold) || (threshold < 0 && score < threshold) {
+			triggered = true
+		}
+		if winner < 0 || score > best {
+			winner = int8(playerID)
+			best = score
+		}
+	}
+	if !triggered {
+		return -1
+	}
+	return winner
+}
+
+// mostCapturedWinner returns the player with the largest capture pile, or -1
+// if nobody has at least minCards captured cards. Ties go to the lower index.
+func mostCapturedWinner(state *GameState, numPlayers int, minCards int) int8 {
+	winner := int8(-1)
+	most := 0
+	for playerID := 0; playerID < numPlayers && playerID < len(state.Players); playerID++ {
+		captured := len(state.Players[playerID].Captured)
+		if captured > most && !state.Players[playerID].Eliminated {
+			most = captured
+			winner = int8(playerID)
+		}
+	}
+	if winner >= 0 && most < minCards {
+		return -1
+	}
+	return winner
 }
 
 // resolveChallenge handles a challenge in ClaimPhase
@@ -1120,6 +1619,9 @@ func resolveChallenge(state *GameState, challengerID uint8) {
 		}
 	}
 
+	// Turning the cards over shows them to everyone
+	state.MarkSeen(claim.CardsPlayed...)
+
 	var loserID uint8
 	if truthful {
 		// Claim was true - challenger was wrong, takes the pile
@@ -1139,61 +1641,45 @@ func resolveChallenge(state *GameState, challengerID uint8) {
 	state.CurrentClaim = nil
 }
 
-// reshuffleDeck moves all discard cards except the top one into the deck and shuffles.
-// Used for shedding games like Uno when the deck runs out.
-func reshuffleDeck(state *GameState) {
-	if len(state.Discard) <= 1 {
-		return // Nothing to reshuffle
-	}
-
-	// Keep the top card, move the rest to deck
-	topCard := state.Discard[len(state.Discard)-1]
-	for i := 0; i < len(state.Discard)-1; i++ {
-		state.Deck = append(state.Deck, state.Discard[i])
+// highestOfRank returns the highest card of rank in hand under the game's
+// suit order; hand must hold one
+func highestOfRank(state *GameState, hand []Card, rank uint8) Card {
+	best := Card{Rank: rank}
+	found := false
+	for _, card := range hand {
+		if card.Rank == rank && (!found || state.CompareCards(card, best) > 0) {
+			best, found = card, true
+		}
 	}
-	state.Discard = state.Discard[:1]
-	state.Discard[0] = topCard
-
-	// Shuffle the deck using turn number as seed for determinism
-	state.ShuffleDeck(uint64(state.TurnNumber))
+	return best
 }
 
 // isValidSequencePlay checks if card can be played on top of topCard according to sequence rules.
 // Rules:
 // - Cards must match suit
-// - Direction determines valid ranks:
-//   - ASCENDING (0): card.Rank must be exactly topCard.Rank + 1
-//   - DESCENDING (1): card.Rank must be exactly topCard.Rank - 1
+// - Direction determines valid ranks (compared by RankValue):
+//   - ASCENDING (0): card must be exactly one rank above topCard
+//   - DESCENDING (1): card must be exactly one rank below topCard
 //   - BOTH (2): either direction is valid
 //
-// No wrapping: K (13) can't go to A (14) in ascending, 2 can't go to A in descending.
-// Rank encoding: 2-10 are face value, 11=J, 12=Q, 13=K, 14=A (Ace high)
-// Valid ascending range: 2 -> 3 -> ... -> 13 (K is the end, can't go to A which is 14)
-// Valid descending range: 13 -> 12 -> ... -> 2 (2 is the end)
-func isValidSequencePlay(card Card, topCard Card, direction uint8) bool {
+// No wrapping: nothing goes above the highest rank or below the lowest, so
+// ace-high runs 2 -> ... -> K -> A and ace-low runs A -> 2 -> ... -> K.
+func isValidSequencePlay(card Card, topCard Card, direction uint8, aceLow bool) bool {
 	// Must match suit
 	if card.Suit != topCard.Suit {
 		return false
 	}
 
+	value := int(RankValue(card.Rank, aceLow))
+	topValue := int(RankValue(topCard.Rank, aceLow))
+
 	switch direction {
 	case 0: // ASCENDING - card must be exactly 1 rank higher
-		// King (13) is the highest in ascending sequences - can't go to Ace (14)
-		if topCard.Rank == 13 {
-			return false // K is the end of ascending sequence
-		}
-		return card.Rank == topCard.Rank+1
+		return value == topValue+1
 	case 1: // DESCENDING - card must be exactly 1 rank lower
-		// 2 is the lowest in descending sequences - can't go lower
-		if topCard.Rank == 2 {
-			return false // 2 is the end of descending sequence
-		}
-		return card.Rank == topCard.Rank-1
+		return value == topValue-1
 	case 2: // BOTH - either direction is valid
-		// Apply both boundary checks
-		canAscend := topCard.Rank != 13 && card.Rank == topCard.Rank+1
-		canDescend := topCard.Rank != 2 && card.Rank == topCard.Rank-1
-		return canAscend || canDescend
+		return value == topValue+1 || value == topValue-1
 	}
 	return false
 }
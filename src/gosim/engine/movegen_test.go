@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -103,6 +104,10 @@ func TestApplyMoveTableauModeMatchRank(t *testing.T) {
 	if len(state.Tableau[0]) != 0 {
 		t.Errorf("Expected empty tableau after match capture, got %d cards", len(state.Tableau[0]))
 	}
+	// Both cards go to the player's capture pile
+	if len(state.Players[0].Captured) != 2 {
+		t.Errorf("Expected 2 captured cards, got %d", len(state.Players[0].Captured))
+	}
 }
 
 // TestApplyMoveTableauModeSequence verifies SEQUENCE mode where cards
@@ -172,6 +177,46 @@ func TestApplyMoveTableauModeWarTie(t *testing.T) {
 	}
 }
 
+// TestWarToBottomPlaysFromTop verifies that with WarToBottom only the top
+// of each pile can be played and won cards go underneath, so they aren't
+// the next ones played
+func TestWarToBottomPlaysFromTop(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.TableauMode = 1 // WAR
+	state.WarToBottom = true
+	state.NumPlayers = 2
+	state.Tableau = [][]Card{{}}
+	state.Players[0].Hand = []Card{{Rank: 0, Suit: 0}, {Rank: 7, Suit: 0}, {Rank: 11, Suit: 0}} // 2H 9H KH (top)
+	state.Players[1].Hand = []Card{{Rank: 1, Suit: 1}, {Rank: 2, Suit: 1}}                      // 3D 4D (top)
+	genome := minimalPlayPhaseGenome()
+
+	for player := 0; player < 2; player++ {
+		moves := GenerateLegalMoves(state, genome)
+		top := len(state.Players[player].Hand) - 1
+		if len(moves) != 1 || moves[0].CardIndex != top {
+			t.Fatalf("Expected player %d to only play the top card %d, got %v", player, top, moves)
+		}
+		ApplyMove(state, &moves[0], genome)
+	}
+
+	// KH beats 4D; both go under player 0's pile in play order
+	want := []Card{{Rank: 11, Suit: 0}, {Rank: 2, Suit: 1}, {Rank: 0, Suit: 0}, {Rank: 7, Suit: 0}}
+	if !reflect.DeepEqual(state.Players[0].Hand, want) {
+		t.Fatalf("Expected the won cards under the pile, got %v", state.Players[0].Hand)
+	}
+	moves := GenerateLegalMoves(state, genome)
+	if len(moves) != 1 || state.Players[0].Hand[moves[0].CardIndex] != (Card{Rank: 7, Suit: 0}) {
+		t.Errorf("Expected the 9H to be played next, not a won card, got %v", moves)
+	}
+
+	// Without the option every card can be played
+	state.WarToBottom = false
+	if moves := GenerateLegalMoves(state, genome); len(moves) != 4 {
+		t.Errorf("Expected all 4 cards playable without WarToBottom, got %d", len(moves))
+	}
+}
+
 // TestApplyMoveTableauModeMatchRankNoMatch verifies that when there's no
 // matching card, the played card stays on the tableau
 func TestApplyMoveTableauModeMatchRankNoMatch(t *testing.T) {
@@ -376,90 +421,71 @@ func TestSequenceModeBoth(t *testing.T) {
 	}
 }
 
-// TestSequenceModeBoundaryKing verifies that K can't go higher in ascending mode
+// TestSequenceModeBoundaryKing verifies that runs don't wrap from the top rank
+// back to the bottom: ace-high ends at A, ace-low ends at K
 func TestSequenceModeBoundaryKing(t *testing.T) {
-	state := NewGameState(2)
-	state.TableauMode = 3       // SEQUENCE
-	state.SequenceDirection = 0 // ASCENDING
-	state.NumPlayers = 2
-
-	// Tableau has King (rank 13) - can't go higher
-	state.Tableau = make([][]Card, 4)
-	state.Tableau[0] = []Card{{Rank: 13, Suit: 0}} // King of spades
-	state.Tableau[1] = []Card{}
-	state.Tableau[2] = []Card{}
-	state.Tableau[3] = []Card{}
+	kingCard := Card{Rank: 11, Suit: 0}
+	aceCard := Card{Rank: AceRank, Suit: 0}
+	twoCard := Card{Rank: 0, Suit: 0}
 
-	// Player has Ace (rank 14 in our system) - can't play on King in ascending
-	state.Players[0].Hand = []Card{
-		{Rank: 14, Suit: 0}, // Ace of spades
+	// Ace-high: K -> A continues the run, A -> 2 would wrap
+	if !isValidSequencePlay(aceCard, kingCard, 0, false) { // ASCENDING
+		t.Errorf("Ace should be playable on King in ASCENDING mode when Ace is high")
+	}
+	if isValidSequencePlay(twoCard, aceCard, 0, false) {
+		t.Errorf("2 should NOT be playable on Ace in ASCENDING mode (no wrapping)")
 	}
-	state.CurrentPlayer = 0
-
-	genome := sequencePhaseGenome()
-	moves := GenerateLegalMoves(state, genome)
 
-	// Count moves that play Ace on the spade pile specifically (should be 0)
-	// The Ace can start a new pile on empty slots, but shouldn't wrap around King
-	aceOnKingPile := false
-	for _, m := range moves {
-		if m.TargetLoc == LocationTableau {
-			card := state.Players[0].Hand[m.CardIndex]
-			// For sequence mode with pile-specific targeting, this would need to check
-			// if the move is specifically for pile 0. For now, check that we have
-			// at least some moves (Ace can start new piles on empty slots)
-			if card.Rank == 14 && card.Suit == 0 {
-				// This is tricky - the current design may not track which pile
-				// Let's just verify the helper function rejects King->Ace
-				_ = card
-			}
-		}
+	// Ace-low: K is the top, so Ace can't follow it
+	if isValidSequencePlay(aceCard, kingCard, 0, true) {
+		t.Errorf("Ace should NOT be playable on King in ASCENDING mode when Ace is low")
 	}
+}
 
-	// Verify the helper function directly
-	kingCard := Card{Rank: 13, Suit: 0}
-	aceCard := Card{Rank: 14, Suit: 0}
+// TestSequenceModeBoundaryAce verifies that nothing goes below the bottom rank
+// in descending mode: 2 when Ace is high, Ace when Ace is low
+func TestSequenceModeBoundaryAce(t *testing.T) {
+	twoCard := Card{Rank: 0, Suit: 0}
+	aceCard := Card{Rank: AceRank, Suit: 0}
 
-	if isValidSequencePlay(aceCard, kingCard, 0) { // ASCENDING
-		aceOnKingPile = true
+	// Ace-high: 2 is the bottom, the Ace doesn't sit below it
+	if isValidSequencePlay(aceCard, twoCard, 1, false) { // DESCENDING
+		t.Errorf("Ace should NOT be valid descending from 2 when Ace is high")
 	}
 
-	if aceOnKingPile {
-		t.Errorf("Ace should NOT be playable on King in ASCENDING mode (no wrapping)")
+	// Ace-low: 2 -> A ends the run
+	if !isValidSequencePlay(aceCard, twoCard, 1, true) {
+		t.Errorf("Ace should be valid descending from 2 when Ace is low")
 	}
 }
 
-// TestSequenceModeBoundaryAce verifies that A can't go lower in descending mode
-func TestSequenceModeBoundaryAce(t *testing.T) {
-	state := NewGameState(2)
-	state.TableauMode = 3       // SEQUENCE
-	state.SequenceDirection = 1 // DESCENDING
-	state.NumPlayers = 2
+// TestSequenceModeAceLowRun verifies A-2-3 builds in ascending mode only when Ace is low
+func TestSequenceModeAceLowRun(t *testing.T) {
+	ace := Card{Rank: AceRank, Suit: 2}
+	two := Card{Rank: 0, Suit: 2}
+	three := Card{Rank: 1, Suit: 2}
 
-	// For ranks where 2 is the lowest playable rank
-	// Ace (14) is high, so descending from 2 has no valid play
-
-	// Tableau has 2 (rank 2) - can't go lower
-	state.Tableau = make([][]Card, 4)
-	state.Tableau[0] = []Card{{Rank: 2, Suit: 0}} // 2 of spades
-	state.Tableau[1] = []Card{}
-	state.Tableau[2] = []Card{}
-	state.Tableau[3] = []Card{}
-
-	// Player has Ace - in typical card games, Ace is high (14)
-	// So descending from 2 would need rank 1, which doesn't exist
-	state.Players[0].Hand = []Card{
-		{Rank: 14, Suit: 0}, // Ace of spades (high)
+	if !isValidSequencePlay(two, ace, 0, true) || !isValidSequencePlay(three, two, 0, true) {
+		t.Errorf("Expected A-2-3 to be a valid ascending run when Ace is low")
 	}
-	state.CurrentPlayer = 0
+	if isValidSequencePlay(two, ace, 0, false) {
+		t.Errorf("Expected 2 on Ace to be invalid when Ace is high")
+	}
+}
 
-	// Verify the helper function directly - 2 descending has no valid lower card
-	twoCard := Card{Rank: 2, Suit: 0}
-	aceCard := Card{Rank: 14, Suit: 0}
+// TestSequenceModeAceHighRun verifies J-Q-K builds descending from K when Ace is high
+func TestSequenceModeAceHighRun(t *testing.T) {
+	king := Card{Rank: 11, Suit: 1}
+	queen := Card{Rank: 10, Suit: 1}
+	jack := Card{Rank: 9, Suit: 1}
 
-	// Ace (14) is not rank 1, so it shouldn't be valid descending from 2
-	if isValidSequencePlay(aceCard, twoCard, 1) { // DESCENDING
-		t.Errorf("Ace (rank 14) should NOT be valid descending from 2 (would need rank 1)")
+	for _, aceLow := range []bool{false, true} {
+		if !isValidSequencePlay(queen, king, 1, aceLow) || !isValidSequencePlay(jack, queen, 1, aceLow) {
+			t.Errorf("Expected K-Q-J to be a valid descending run (aceLow=%v)", aceLow)
+		}
+	}
+	if !isValidSequencePlay(Card{Rank: AceRank, Suit: 1}, king, 2, false) {
+		t.Errorf("Expected A next to K in BOTH mode when Ace is high")
 	}
 }
 
@@ -482,11 +508,11 @@ func TestSequenceModeSuitMatching(t *testing.T) {
 	eightHearts := Card{Rank: 8, Suit: 1}
 	eightSpades := Card{Rank: 8, Suit: 0}
 
-	if isValidSequencePlay(eightHearts, sevenSpades, 0) {
+	if isValidSequencePlay(eightHearts, sevenSpades, 0, false) {
 		t.Errorf("8 of hearts should NOT be valid on 7 of spades (wrong suit)")
 	}
 
-	if !isValidSequencePlay(eightSpades, sevenSpades, 0) {
+	if !isValidSequencePlay(eightSpades, sevenSpades, 0, false) {
 		t.Errorf("8 of spades SHOULD be valid on 7 of spades (same suit, ascending)")
 	}
 }
@@ -889,9 +915,9 @@ func TestCheckWinConditionsTeamWinFirstToScore(t *testing.T) {
 	state.Players[2].Score = 7
 	state.Players[3].Score = 10 // First to 10!
 
-	// Give everyone cards
+	// Hands are played out: first_to_score is checked at hand boundaries
 	for i := 0; i < 4; i++ {
-		state.Players[i].Hand = []Card{{Rank: uint8(i + 2), Suit: 0}}
+		state.Players[i].Hand = []Card{}
 	}
 
 	genome := &Genome{
@@ -1197,6 +1223,47 @@ func TestDualScoringIntegrationTrickWin(t *testing.T) {
 	}
 }
 
+// TestResolveTrickCreditsPointCards verifies the winner tallies the trick's
+// card values and ScoreTrickPoints pays them out
+func TestResolveTrickCreditsPointCards(t *testing.T) {
+	state := NewGameState(4)
+	state.NumPlayers = 4
+	state.TricksWon = make([]uint8, 4)
+	state.CurrentTrick = []TrickCard{
+		{PlayerID: 0, Card: Card{Rank: 8, Suit: 3}},  // 10 of Spades
+		{PlayerID: 1, Card: Card{Rank: 12, Suit: 3}}, // Ace of Spades - wins
+		{PlayerID: 2, Card: Card{Rank: 3, Suit: 3}},  // 5 of Spades
+		{PlayerID: 3, Card: Card{Rank: 11, Suit: 2}}, // King of Clubs, off suit
+	}
+
+	// Skat card points: A 11, 10 10, K 4, Q 3, J 2
+	genome := &Genome{
+		TurnPhases: []PhaseDescriptor{
+			{PhaseType: 4, Data: []byte{1, 255, 1, 255}},
+		},
+		RankValues: [13]int32{0, 0, 0, 0, 0, 0, 0, 0, 10, 2, 3, 4, 11},
+	}
+
+	resolveTrick(state, genome, genome.TurnPhases[0])
+
+	if got := state.Players[1].TrickPoints; got != 25 {
+		t.Errorf("Expected trick winner to tally 25 card points, got %d", got)
+	}
+	for _, p := range []int{0, 2, 3} {
+		if got := state.Players[p].TrickPoints; got != 0 {
+			t.Errorf("Expected player %d to tally nothing, got %d", p, got)
+		}
+	}
+
+	points := ScoreTrickPoints(state)
+	if points[1] != 25 || state.Players[1].Score != 25 {
+		t.Errorf("Expected 25 points awarded to player 1, got %v (score %d)", points, state.Players[1].Score)
+	}
+	if state.Players[1].TrickPoints != 0 {
+		t.Errorf("Expected tally cleared after scoring, got %d", state.Players[1].TrickPoints)
+	}
+}
+
 // TestDualScoringIntegrationMatchRankCapture verifies match rank capture updates team scores
 func TestDualScoringIntegrationMatchRankCapture(t *testing.T) {
 	state := NewGameState(4)
@@ -1441,3 +1508,596 @@ func TestApplyBidMoveNoTeams(t *testing.T) {
 		t.Errorf("TeamContracts should be empty for non-team game")
 	}
 }
+
+// ============================================================================
+// Conditional Draw Tests
+// ============================================================================
+
+// conditionalDrawGenome builds a genome with a single draw phase gated by
+// "hand size < 5".
+func conditionalDrawGenome(mandatory bool) *Genome {
+	mandatoryByte := byte(0)
+	if mandatory {
+		mandatoryByte = 1
+	}
+	return &Genome{
+		Header: &BytecodeHeader{
+			PlayerCount: 2,
+		},
+		TurnPhases: []PhaseDescriptor{
+			{
+				PhaseType: PhaseTypeDraw,
+				Data: []byte{
+					byte(LocationDeck), 0, 0, 0, 1, mandatoryByte, 1, // source=DECK, count=1, mandatory, has_condition=true
+					byte(OpCheckHandSize), byte(OpLT - 50), 0, 0, 0, 5, 0, // condition: hand_size < 5
+				},
+			},
+		},
+		WinConditions: []WinCondition{
+			{WinType: WinTypeEmptyHand, Threshold: 0},
+		},
+	}
+}
+
+// TestConditionalDrawSuppressedWhenHandFull verifies that a draw phase whose
+// condition fails offers no moves at all.
+func TestConditionalDrawSuppressedWhenHandFull(t *testing.T) {
+	state := NewGameState(2)
+	state.Deck = []Card{{Rank: 1, Suit: 0}, {Rank: 2, Suit: 0}}
+	state.Players[0].Hand = []Card{
+		{Rank: 0, Suit: 0}, {Rank: 1, Suit: 1}, {Rank: 2, Suit: 2},
+		{Rank: 3, Suit: 3}, {Rank: 4, Suit: 0},
+	}
+
+	moves := GenerateLegalMoves(state, conditionalDrawGenome(false))
+	if len(moves) != 0 {
+		t.Errorf("Expected no draw moves with a full hand, got %d", len(moves))
+	}
+}
+
+// TestConditionalDrawOfferedWhenConditionPasses verifies draw and pass moves
+// are offered when the hand is below the limit.
+func TestConditionalDrawOfferedWhenConditionPasses(t *testing.T) {
+	state := NewGameState(2)
+	state.Deck = []Card{{Rank: 1, Suit: 0}, {Rank: 2, Suit: 0}}
+	state.Players[0].Hand = []Card{{Rank: 0, Suit: 0}, {Rank: 1, Suit: 1}}
+
+	moves := GenerateLegalMoves(state, conditionalDrawGenome(false))
+	if len(moves) != 2 {
+		t.Fatalf("Expected draw and pass moves, got %d", len(moves))
+	}
+	if moves[0].CardIndex != MoveDraw {
+		t.Errorf("Expected first move to be draw, got CardIndex %d", moves[0].CardIndex)
+	}
+	if moves[1].CardIndex != MoveDrawPass {
+		t.Errorf("Expected second move to be pass, got CardIndex %d", moves[1].CardIndex)
+	}
+}
+
+// TestConditionalDrawApplyMoveRespectsCondition verifies a stale draw move
+// takes nothing once the condition no longer holds, and the turn moves on.
+func TestConditionalDrawApplyMoveRespectsCondition(t *testing.T) {
+	state := NewGameState(2)
+	state.Deck = []Card{{Rank: 1, Suit: 0}, {Rank: 2, Suit: 0}}
+	state.Players[0].Hand = []Card{
+		{Rank: 0, Suit: 0}, {Rank: 1, Suit: 1}, {Rank: 2, Suit: 2},
+		{Rank: 3, Suit: 3}, {Rank: 4, Suit: 0},
+	}
+
+	move := LegalMove{PhaseIndex: 0, CardIndex: MoveDraw, TargetLoc: LocationDeck}
+	ApplyMove(state, &move, conditionalDrawGenome(false))
+
+	if len(state.Players[0].Hand) != 5 {
+		t.Errorf("Expected hand to stay at 5 cards, got %d", len(state.Players[0].Hand))
+	}
+	if len(state.Deck) != 2 {
+		t.Errorf("Expected deck to stay at 2 cards, got %d", len(state.Deck))
+	}
+	if state.CurrentPlayer != 1 || state.TurnNumber != 1 {
+		t.Errorf("Expected the turn to pass to player 1, got player %d on turn %d", state.CurrentPlayer, state.TurnNumber)
+	}
+}
+
+// TestConditionalDrawMandatoryIgnoresCondition verifies a mandatory draw is
+// forced whether or not its condition holds, so the turn can't stall.
+func TestConditionalDrawMandatoryIgnoresCondition(t *testing.T) {
+	genome := conditionalDrawGenome(true)
+	for _, handSize := range []int{2, 5} {
+		state := NewGameState(2)
+		state.Deck = []Card{{Rank: 1, Suit: 0}, {Rank: 2, Suit: 0}}
+		for i := 0; i < handSize; i++ {
+			state.Players[0].Hand = append(state.Players[0].Hand, Card{Rank: uint8(i), Suit: 3})
+		}
+
+		moves := GenerateLegalMoves(state, genome)
+		if len(moves) != 1 || moves[0].CardIndex != MoveDraw {
+			t.Fatalf("Hand of %d: expected only the draw, got %+v", handSize, moves)
+		}
+		if forced, ok := SingleForcedMove(state, genome); !ok || *forced != moves[0] {
+			t.Errorf("Hand of %d: expected the draw forced, got %+v", handSize, forced)
+		}
+
+		ApplyMove(state, &moves[0], genome)
+		if len(state.Players[0].Hand) != handSize+1 || len(state.Deck) != 1 {
+			t.Errorf("Hand of %d: expected one card drawn, hand %d deck %d", handSize, len(state.Players[0].Hand), len(state.Deck))
+		}
+		if state.CurrentPlayer != 1 {
+			t.Errorf("Hand of %d: expected the turn to pass, got player %d", handSize, state.CurrentPlayer)
+		}
+	}
+}
+
+// mostCardsGenome returns a genome won by the largest capture pile
+func mostCardsGenome(threshold int32) *Genome {
+	return &Genome{
+		WinConditions: []WinCondition{
+			{WinType: WinTypeMostCards, Threshold: threshold},
+		},
+	}
+}
+
+// captureCards gives a player n captured cards
+func captureCards(state *GameState, playerID int, n int) {
+	for i := 0; i < n; i++ {
+		state.Players[playerID].Captured = append(state.Players[playerID].Captured, Card{Rank: uint8(i % 13), Suit: uint8(i % 4)})
+	}
+}
+
+// TestCheckWinConditionsMostCards verifies the largest capture pile wins once the deck is exhausted
+func TestCheckWinConditionsMostCards(t *testing.T) {
+	state := NewGameState(3)
+	captureCards(state, 0, 5)
+	captureCards(state, 1, 8)
+	captureCards(state, 2, 3)
+
+	if winner := CheckWinConditions(state, mostCardsGenome(0)); winner != 1 {
+		t.Errorf("Expected player 1 (8 captured) to win, got %d", winner)
+	}
+}
+
+// TestCheckWinConditionsMostCardsWaitsForDeck verifies no winner while cards remain
+func TestCheckWinConditionsMostCardsWaitsForDeck(t *testing.T) {
+	state := NewGameState(2)
+	captureCards(state, 0, 10)
+	captureCards(state, 1, 2)
+
+	state.Deck = []Card{{Rank: 3, Suit: 0}}
+	if winner := CheckWinConditions(state, mostCardsGenome(0)); winner != -1 {
+		t.Errorf("Expected no winner while deck has cards, got %d", winner)
+	}
+
+	state.Deck = state.Deck[:0]
+	state.Players[1].Hand = []Card{{Rank: 4, Suit: 1}}
+	if winner := CheckWinConditions(state, mostCardsGenome(0)); winner != -1 {
+		t.Errorf("Expected no winner while hands have cards, got %d", winner)
+	}
+}
+
+// TestCheckWinConditionsMostCardsThreshold verifies Threshold is a minimum pile size
+func TestCheckWinConditionsMostCardsThreshold(t *testing.T) {
+	state := NewGameState(2)
+	captureCards(state, 0, 12)
+	captureCards(state, 1, 20)
+
+	if winner := CheckWinConditions(state, mostCardsGenome(21)); winner != -1 {
+		t.Errorf("Expected no winner below threshold, got %d", winner)
+	}
+	if winner := CheckWinConditions(state, mostCardsGenome(20)); winner != 1 {
+		t.Errorf("Expected player 1 to win at threshold, got %d", winner)
+	}
+}
+
+// TestCloneCopiesCaptured verifies capture piles are deep copied
+func TestCloneCopiesCaptured(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	captureCards(state, 0, 3)
+
+	clone := state.Clone()
+	defer PutState(clone)
+	state.Players[0].Captured[0].Rank = 12
+
+	if len(clone.Players[0].Captured) != 3 || clone.Players[0].Captured[0].Rank != 0 {
+		t.Errorf("Expected clone to keep its own capture pile, got %v", clone.Players[0].Captured)
+	}
+}
+
+// scoreRaceGenome returns a trick-taking genome with a single first_to_score condition
+func scoreRaceGenome(threshold int32) *Genome {
+	return &Genome{
+		TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypeTrick, Data: []byte{1, 255, 1, 255}}},
+		WinConditions: []WinCondition{
+			{WinType: WinTypeFirstToScore, Threshold: threshold},
+		},
+	}
+}
+
+// TestFirstToScoreOnlyAtHandBoundary verifies a mid-hand lead past the target doesn't end a trick game
+func TestFirstToScoreOnlyAtHandBoundary(t *testing.T) {
+	state := NewGameState(2)
+	state.Players[0].Score = 120
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}}
+
+	if winner := CheckWinConditions(state, scoreRaceGenome(100)); winner != -1 {
+		t.Errorf("Expected no winner mid-hand, got %d", winner)
+	}
+
+	state.Players[0].Hand = state.Players[0].Hand[:0]
+	if winner := CheckWinConditions(state, scoreRaceGenome(100)); winner != 0 {
+		t.Errorf("Expected player 0 to win at hand boundary, got %d", winner)
+	}
+}
+
+// TestFirstToScoreZeroThresholdNeverFires verifies a zero target doesn't end the game immediately
+func TestFirstToScoreZeroThresholdNeverFires(t *testing.T) {
+	state := NewGameState(2)
+	if winner := CheckWinConditions(state, scoreRaceGenome(0)); winner != -1 {
+		t.Errorf("Expected no winner with zero threshold, got %d", winner)
+	}
+}
+
+// TestFirstToScoreNegativeContractGame plays out hands of a contract game
+// where failed contracts subtract points, using both threshold variants
+func TestFirstToScoreNegativeContractGame(t *testing.T) {
+	// Per-hand score deltas: player 1 keeps failing contracts
+	hands := [][2]int32{
+		{40, -30},
+		{50, -60},
+		{-20, -50},
+	}
+
+	// Reach-target variant: nobody reaches 100, so scores going negative
+	// must not end the game
+	state := NewGameState(2)
+	for i, delta := range hands {
+		state.Players[0].Score += delta[0]
+		state.Players[1].Score += delta[1]
+		if winner := CheckWinConditions(state, scoreRaceGenome(100)); winner != -1 {
+			t.Fatalf("Hand %d: expected no winner below target, got %d", i, winner)
+		}
+	}
+
+	// Avoid-floor variant: the game ends once a player drops below -100
+	// (after hand 2, player 1 is at -90; after hand 3, at -140)
+	state = NewGameState(2)
+	ended := -1
+	for i, delta := range hands {
+		state.Players[0].Score += delta[0]
+		state.Players[1].Score += delta[1]
+		if winner := CheckWinConditions(state, scoreRaceGenome(-100)); winner >= 0 {
+			if winner != 0 {
+				t.Errorf("Expected player 0 (highest score) to win, got %d", winner)
+			}
+			ended = i
+			break
+		}
+	}
+	if ended != 2 {
+		t.Errorf("Expected game to end after hand 2 (0-based), ended at %d", ended)
+	}
+}
+
+// movegenBenchState returns a 2-player state mid-game with a full hand to
+// play from, for the move generation benchmarks
+func movegenBenchState() *GameState {
+	state := NewGameState(2)
+	for i := 0; i < 8; i++ {
+		state.Players[0].Hand = append(state.Players[0].Hand, Card{Rank: uint8(i), Suit: uint8(i % 4)})
+	}
+	state.Tableau = [][]Card{{{Rank: 0, Suit: 0}}}
+	return state
+}
+
+func TestGenerateLegalMovesIntoMatchesAllocating(t *testing.T) {
+	state := movegenBenchState()
+	defer PutState(state)
+	genome := minimalPlayPhaseGenome()
+
+	want := GenerateLegalMoves(state, genome)
+	if len(want) == 0 {
+		t.Fatal("Expected some legal moves")
+	}
+
+	// Appends after existing entries
+	sentinel := LegalMove{PhaseIndex: 99}
+	got := GenerateLegalMovesInto(state, genome, []LegalMove{sentinel})
+	if len(got) != len(want)+1 || got[0] != sentinel {
+		t.Fatalf("Expected sentinel followed by %d moves, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i+1] != want[i] {
+			t.Errorf("Move %d: expected %v, got %v", i, want[i], got[i+1])
+		}
+	}
+
+	// Reusing the buffer doesn't allocate once it has grown
+	buf := make([]LegalMove, 0, 16)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = GenerateLegalMovesInto(state, genome, buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations with a reused buffer, got %.1f", allocs)
+	}
+}
+
+// benchMoves keeps benchmark results alive so allocations aren't optimized away
+var benchMoves []LegalMove
+
+func BenchmarkGenerateLegalMoves(b *testing.B) {
+	state := movegenBenchState()
+	defer PutState(state)
+	genome := minimalPlayPhaseGenome()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchMoves = GenerateLegalMoves(state, genome)
+	}
+}
+
+func BenchmarkGenerateLegalMovesInto(b *testing.B) {
+	state := movegenBenchState()
+	defer PutState(state)
+	genome := minimalPlayPhaseGenome()
+	buf := make([]LegalMove, 0, 16)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = GenerateLegalMovesInto(state, genome, buf[:0])
+	}
+	benchMoves = buf
+}
+
+func TestSingleForcedMoveAgreesWithGenerator(t *testing.T) {
+	drawGenome := func(source Location, mandatory byte) *Genome {
+		return &Genome{TurnPhases: []PhaseDescriptor{
+			{PhaseType: 1, Data: []byte{byte(source), 0, 0, 0, 1, mandatory, 0}},
+		}}
+	}
+	discardGenome := &Genome{TurnPhases: []PhaseDescriptor{{PhaseType: 3}}}
+	oneCard := []Card{{Rank: 4, Suit: 1}}
+	twoCards := []Card{{Rank: 4, Suit: 1}, {Rank: 9, Suit: 2}}
+
+	tests := []struct {
+		name        string
+		genome      *Genome
+		tableauMode uint8
+		hand        []Card
+		deck        []Card
+		forced      bool
+	}{
+		{"mandatory draw", drawGenome(LocationDeck, 1), 0, twoCards, oneCard, true},
+		{"optional draw", drawGenome(LocationDeck, 0), 0, twoCards, oneCard, false},
+		{"draw from empty deck", drawGenome(LocationDeck, 1), 0, twoCards, nil, false},
+		{"single-card play", minimalPlayPhaseGenome(), 0, oneCard, nil, true},
+		{"two-card play", minimalPlayPhaseGenome(), 0, twoCards, nil, false},
+		{"single-card sequence play", sequencePhaseGenome(), 3, oneCard, nil, false},
+		{"single-card discard", discardGenome, 0, oneCard, nil, true},
+		{"action phase", &Genome{TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypeAction, Data: []byte{1, byte(OpDiscardPairs), 0}}}}, 0, twoCards, nil, true},
+		{"two-phase genome", &Genome{TurnPhases: append(drawGenome(LocationDeck, 1).TurnPhases, discardGenome.TurnPhases...)}, 0, oneCard, oneCard, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := NewGameState(2)
+			defer PutState(state)
+			state.Players[0].Hand = append(state.Players[0].Hand, tt.hand...)
+			state.Deck = append(state.Deck, tt.deck...)
+			state.TableauMode = tt.tableauMode
+
+			forced, ok := SingleForcedMove(state, tt.genome)
+			if ok != tt.forced {
+				t.Fatalf("Expected forced=%v, got %v", tt.forced, ok)
+			}
+			if !ok {
+				return
+			}
+			moves := GenerateLegalMoves(state, tt.genome)
+			if len(moves) != 1 || moves[0] != *forced {
+				t.Errorf("Forced move %v disagrees with generator %v", *forced, moves)
+			}
+		})
+	}
+}
+
+// drawThenDiscardGenome draws one card from the deck, then discards one
+func drawThenDiscardGenome() *Genome {
+	return &Genome{
+		TurnPhases: []PhaseDescriptor{
+			{PhaseType: PhaseTypeDraw, Data: []byte{byte(LocationDeck), 0, 0, 0, 1, 1, 0}},
+			{PhaseType: PhaseTypeDiscard},
+		},
+		SequentialPhases: true,
+	}
+}
+
+func TestSequentialPhasesDrawThenDiscard(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}}
+	state.Players[1].Hand = []Card{{Rank: 4, Suit: 1}}
+	state.Deck = []Card{{Rank: 8, Suit: 2}, {Rank: 9, Suit: 3}}
+	state.RecordHistory = true
+	genome := drawThenDiscardGenome()
+
+	moves := GenerateLegalMoves(state, genome)
+	if len(moves) != 1 || moves[0].PhaseIndex != 0 || moves[0].CardIndex != MoveDraw {
+		t.Fatalf("Expected only the draw at the start of the turn, got %+v", moves)
+	}
+	ApplyMove(state, &moves[0], genome)
+	if state.CurrentPlayer != 0 || state.CurrentPhase != 1 || len(state.Players[0].Hand) != 2 {
+		t.Fatalf("Expected player 0 to move on to the discard, got player %d phase %d", state.CurrentPlayer, state.CurrentPhase)
+	}
+
+	moves = GenerateLegalMoves(state, genome)
+	if len(moves) != 2 || moves[0].PhaseIndex != 1 || moves[1].PhaseIndex != 1 {
+		t.Fatalf("Expected a discard of either card, got %+v", moves)
+	}
+	ApplyMove(state, &moves[1], genome)
+	if state.CurrentPlayer != 1 || state.CurrentPhase != 0 || state.TurnNumber != 1 {
+		t.Errorf("Expected player 1 to start at the first phase, got player %d phase %d turn %d", state.CurrentPlayer, state.CurrentPhase, state.TurnNumber)
+	}
+
+	UndoLastMove(state, genome)
+	UndoLastMove(state, genome)
+	if state.CurrentPlayer != 0 || state.CurrentPhase != 0 || len(state.Deck) != 2 {
+		t.Errorf("Expected undo to return to the start of player 0's turn, got player %d phase %d", state.CurrentPlayer, state.CurrentPhase)
+	}
+}
+
+func TestSequentialPhasesSkipsEmptyPhase(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}, {Rank: 5, Suit: 0}}
+	state.Players[1].Hand = []Card{{Rank: 4, Suit: 1}}
+	genome := drawThenDiscardGenome()
+
+	// Nothing to draw: the turn starts at the discard
+	moves := GenerateLegalMoves(state, genome)
+	if len(moves) != 2 || moves[0].PhaseIndex != 1 {
+		t.Fatalf("Expected the empty draw to be skipped, got %+v", moves)
+	}
+	ApplyMove(state, &moves[0], genome)
+	if state.CurrentPlayer != 1 || state.CurrentPhase != 0 {
+		t.Errorf("Expected the turn to pass after the discard, got player %d phase %d", state.CurrentPlayer, state.CurrentPhase)
+	}
+
+	// A draw that leaves no pair to lay down ends the turn at once
+	state.Players[1].Hand = nil
+	state.Deck = []Card{{Rank: 9, Suit: 3}}
+	genome.TurnPhases[1] = PhaseDescriptor{PhaseType: PhaseTypePlay, Data: []byte{byte(LocationDiscard), 2, 2, 1, 0, 0, 0, 0, 0}}
+	moves = GenerateLegalMoves(state, genome)
+	ApplyMove(state, &moves[0], genome)
+	if state.CurrentPlayer != 0 || state.CurrentPhase != 0 {
+		t.Errorf("Expected no open phase after the draw, got player %d phase %d", state.CurrentPlayer, state.CurrentPhase)
+	}
+}
+
+func TestMaxHandSizeForcesDiscard(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}, {Rank: 5, Suit: 1}}
+	state.Players[1].Hand = []Card{{Rank: 4, Suit: 1}}
+	state.Deck = []Card{{Rank: 8, Suit: 2}, {Rank: 9, Suit: 3}}
+	genome := &Genome{
+		TurnPhases: []PhaseDescriptor{
+			{PhaseType: PhaseTypeDraw, Data: []byte{byte(LocationDeck), 0, 0, 0, 2, 1, 0}},
+		},
+		MaxHandSize: 3,
+	}
+
+	moves := GenerateLegalMoves(state, genome)
+	ApplyMove(state, &moves[0], genome)
+	if state.CurrentPlayer != 0 || state.TurnNumber != 0 || len(state.Players[0].Hand) != 4 {
+		t.Fatalf("Expected player 0 to keep the turn over the cap, got player %d turn %d", state.CurrentPlayer, state.TurnNumber)
+	}
+	if _, ok := SingleForcedMove(state, genome); ok {
+		t.Error("Expected no forced draw while a discard is owed")
+	}
+
+	moves = GenerateLegalMoves(state, genome)
+	if len(moves) != 4 {
+		t.Fatalf("Expected a forced discard of each of 4 cards, got %+v", moves)
+	}
+	for i, move := range moves {
+		if move.CardIndex != MoveForcedDiscardOffset-i || move.TargetLoc != LocationDiscard {
+			t.Errorf("Expected only discards, got %+v", move)
+		}
+	}
+	discarded := state.Players[0].Hand[2]
+	ApplyMove(state, &moves[2], genome)
+	if len(state.Players[0].Hand) != 3 || state.Discard[len(state.Discard)-1] != discarded {
+		t.Errorf("Expected hand back at the cap with %v discarded, got %v", discarded, state.Players[0].Hand)
+	}
+	if state.CurrentPlayer != 1 || state.TurnNumber != 1 {
+		t.Errorf("Expected the turn to pass after the discard, got player %d turn %d", state.CurrentPlayer, state.TurnNumber)
+	}
+}
+
+// passRuleGenome returns a play-to-discard phase with the given pass rule
+// that only accepts Hearts
+func passRuleGenome(passRule byte) *Genome {
+	data := []byte{byte(LocationDiscard), 1, 1, 1, passRule, 0, 0, 0, 7}
+	data = append(data, byte(OpCheckCardSuit), 0, 0, 0, 0, 0, 0)
+	return &Genome{TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypePlay, Data: data}}}
+}
+
+func TestPlayPhaseVoluntaryPass(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}, {Rank: 8, Suit: 2}}
+	genome := passRuleGenome(PlayPassAllowed)
+
+	moves := GenerateLegalMoves(state, genome)
+	if len(moves) != 2 || moves[0].CardIndex != 0 || moves[1].CardIndex != MovePlayPass {
+		t.Fatalf("Expected the Heart or a pass, got %v", moves)
+	}
+
+	ApplyMove(state, &moves[1], genome)
+	if state.CurrentPlayer != 1 || len(state.Players[0].Hand) != 2 || len(state.Discard) != 0 {
+		t.Errorf("Expected the pass to only move the turn on, got player %d with hand %v and discard %v",
+			state.CurrentPlayer, state.Players[0].Hand, state.Discard)
+	}
+}
+
+func TestPlayPhasePassForbidden(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}, {Rank: 8, Suit: 2}}
+
+	moves := GenerateLegalMoves(state, passRuleGenome(PlayPassNever))
+	if len(moves) != 1 || moves[0].CardIndex != 0 {
+		t.Fatalf("Expected only the Heart, got %v", moves)
+	}
+	moves = GenerateLegalMoves(state, passRuleGenome(PlayPassIfUnable))
+	if len(moves) != 1 || moves[0].CardIndex != 0 {
+		t.Fatalf("Expected no pass while the Heart can be played, got %v", moves)
+	}
+
+	// Without a Heart only a phase that passes when unable offers a move
+	state.Players[0].Hand = state.Players[0].Hand[1:]
+	if moves := GenerateLegalMoves(state, passRuleGenome(PlayPassNever)); len(moves) != 0 {
+		t.Errorf("Expected no moves when passing is forbidden, got %v", moves)
+	}
+	if moves := GenerateLegalMoves(state, passRuleGenome(PlayPassIfUnable)); len(moves) != 1 || moves[0].CardIndex != MovePlayPass {
+		t.Errorf("Expected only a pass without a Heart, got %v", moves)
+	}
+}
+
+// TestFirstToScoreDrawGame verifies that a game whose hands are refilled
+// from the deck, and so never all empty, ends as soon as a score reaches the
+// target
+func TestFirstToScoreDrawGame(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}}
+	state.Players[1].Hand = []Card{{Rank: 5, Suit: 1}}
+	genome := scoreRaceGenome(100)
+	genome.TurnPhases = []PhaseDescriptor{
+		{PhaseType: PhaseTypeDraw, Data: []byte{byte(LocationDeck), 0, 0, 0, 1, 1, 0}},
+		{PhaseType: PhaseTypePlay, Data: []byte{byte(LocationDiscard), 1, 1, 1, 0}},
+	}
+
+	state.Players[1].Score = 99
+	if winner := CheckWinConditions(state, genome); winner != -1 {
+		t.Fatalf("Expected no winner below the target, got %d", winner)
+	}
+	state.Players[1].Score = 100
+	if winner := CheckWinConditions(state, genome); winner != 1 {
+		t.Errorf("Expected player 1 to win on reaching the target with cards in hand, got %d", winner)
+	}
+
+	// In match play the target belongs to the match, settled between hands
+	genome.WinConditions = append([]WinCondition{{WinType: WinTypeEmptyHand}}, genome.WinConditions...)
+	genome.MatchPlay = true
+	if HandBoundary(state, genome) {
+		t.Error("Expected no hand boundary in match play until a hand is complete")
+	}
+}
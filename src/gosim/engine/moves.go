@@ -2,9 +2,49 @@ package engine
 
 // DrawCard moves a card from source to player hand
 func (s *GameState) DrawCard(playerID uint8, source Location) bool {
+	srcPile := s.drawPile(playerID, source)
+	if srcPile == nil || len(*srcPile) == 0 {
+		return false
+	}
+
+	// Pop from source
+	card := (*srcPile)[len(*srcPile)-1]
+	*srcPile = (*srcPile)[:len(*srcPile)-1]
+
+	// Add to player hand
+	s.Players[playerID].Hand = append(s.Players[playerID].Hand, card)
+	return true
+}
+
+// DrawCardAt moves the card at index in source (0 = bottom) to player hand.
+// With takeAbove, every card above it follows in pile order, as when a
+// Rummy player picks up a buried discard.
+func (s *GameState) DrawCardAt(playerID uint8, source Location, index int, takeAbove bool) bool {
+	srcPile := s.drawPile(playerID, source)
+	if srcPile == nil || index < 0 || index >= len(*srcPile) {
+		return false
+	}
+
+	end := index + 1
+	if takeAbove {
+		end = len(*srcPile)
+	}
+	s.Players[playerID].Hand = append(s.Players[playerID].Hand, (*srcPile)[index:end]...)
+	*srcPile = append((*srcPile)[:index], (*srcPile)[end:]...)
+	return true
+}
+
+// DrawBottom moves the bottom card of source (index 0) to player hand
+func (s *GameState) DrawBottom(playerID uint8, source Location) bool {
+	return s.DrawCardAt(playerID, source, 0, false)
+}
+
+// drawPile returns the pile playerID draws from for source, or nil if the
+// source is unsupported
+func (s *GameState) drawPile(playerID uint8, source Location) *[]Card {
 	// Bounds check to prevent panic on invalid playerID
 	if int(playerID) >= len(s.Players) {
-		return false
+		return nil
 	}
 
 	var srcPile *[]Card
@@ -19,32 +59,21 @@ func (s *GameState) DrawCard(playerID uint8, source Location) bool {
 		// For multi-player games, use next player as opponent
 		// Ensure valid index even with edge cases
 		if s.NumPlayers == 0 || int(playerID) >= len(s.Players) {
-			return false
+			return nil
 		}
 		opponentID := (playerID + 1) % s.NumPlayers
 		if int(opponentID) >= len(s.Players) {
-			return false
+			return nil
 		}
 		srcPile = &s.Players[opponentID].Hand
 	case LocationOpponentDiscard:
 		// Optional extension: draw from opponent's discard (not standard)
 		// Would need per-player discard piles
-		return false
+		return nil
 	default:
-		return false
-	}
-
-	if len(*srcPile) == 0 {
-		return false
+		return nil
 	}
-
-	// Pop from source
-	card := (*srcPile)[len(*srcPile)-1]
-	*srcPile = (*srcPile)[:len(*srcPile)-1]
-
-	// Add to player hand
-	s.Players[playerID].Hand = append(s.Players[playerID].Hand, card)
-	return true
+	return srcPile
 }
 
 // PlayCard moves a card from player hand to target location
@@ -76,10 +105,44 @@ func (s *GameState) PlayCard(playerID uint8, cardIndex int, target Location) boo
 	default:
 		return false
 	}
+	s.MarkSeen(card)
 
 	return true
 }
 
+// PlaceBottom moves a card from player hand to the bottom of target: the
+// deck (returning an unwanted card under it), the discard pile, or the
+// first tableau pile
+func (s *GameState) PlaceBottom(playerID uint8, cardIndex int, target Location) bool {
+	if int(playerID) >= len(s.Players) {
+		return false
+	}
+	hand := &s.Players[playerID].Hand
+	if cardIndex < 0 || cardIndex >= len(*hand) {
+		return false
+	}
+
+	var pile *[]Card
+	switch target {
+	case LocationDeck:
+		pile = &s.Deck
+	case LocationDiscard:
+		pile = &s.Discard
+	case LocationTableau:
+		if len(s.Tableau) == 0 {
+			s.Tableau = append(s.Tableau, make([]Card, 0, 10))
+		}
+		pile = &s.Tableau[0]
+	default:
+		return false
+	}
+
+	card := (*hand)[cardIndex]
+	*hand = append((*hand)[:cardIndex], (*hand)[cardIndex+1:]...)
+	insertCard(pile, 0, card)
+	return true
+}
+
 // ShuffleDeck randomizes deck order (in-place)
 func (s *GameState) ShuffleDeck(seed uint64) {
 	// Simple LCG for deterministic shuffle
@@ -92,3 +155,19 @@ func (s *GameState) ShuffleDeck(seed uint64) {
 		s.Deck[i], s.Deck[j] = s.Deck[j], s.Deck[i]
 	}
 }
+
+// RecycleDiscard moves every discard card except the top one into the deck
+// and shuffles it with seed, as shedding games do when the draw deck runs
+// dry. Returns false if the discard had nothing below its top card.
+func RecycleDiscard(state *GameState, seed uint64) bool {
+	if len(state.Discard) <= 1 {
+		return false
+	}
+
+	topCard := state.Discard[len(state.Discard)-1]
+	state.Deck = append(state.Deck, state.Discard[:len(state.Discard)-1]...)
+	state.Discard = append(state.Discard[:0], topCard)
+
+	state.ShuffleDeck(seed)
+	return true
+}
@@ -0,0 +1,198 @@
+package engine
+
+import "testing"
+
+func TestRecycleDiscard(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	top := Card{Rank: 11, Suit: 2}
+	state.Discard = append(state.Discard, Card{Rank: 1, Suit: 0}, Card{Rank: 2, Suit: 1}, Card{Rank: 3, Suit: 3}, top)
+
+	if !RecycleDiscard(state, 7) {
+		t.Fatal("Expected recycle to succeed")
+	}
+	if len(state.Discard) != 1 || state.Discard[0] != top {
+		t.Errorf("Expected only the top card %v to stay in discard, got %v", top, state.Discard)
+	}
+	if len(state.Deck) != 3 {
+		t.Fatalf("Expected 3 cards recycled into the deck, got %d", len(state.Deck))
+	}
+	for _, card := range state.Deck {
+		if card == top {
+			t.Error("Top discard card should not be recycled")
+		}
+	}
+
+	// Only the top card left: nothing to recycle
+	state.Deck = state.Deck[:0]
+	if RecycleDiscard(state, 7) {
+		t.Error("Expected recycle with a single discard card to fail")
+	}
+}
+
+func TestMandatoryDrawRecyclesDiscard(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	top := Card{Rank: 5, Suit: 0}
+	state.Discard = append(state.Discard, Card{Rank: 8, Suit: 1}, Card{Rank: 9, Suit: 2}, top)
+	genome := &Genome{TurnPhases: []PhaseDescriptor{
+		{PhaseType: 1, Data: []byte{byte(LocationDeck), 0, 0, 0, 1, 1, 0}}, // Mandatory draw 1
+	}}
+
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: MoveDraw, TargetLoc: LocationDeck}, genome)
+
+	if len(state.Players[0].Hand) != 1 {
+		t.Fatalf("Expected the draw to succeed after recycling, hand has %d cards", len(state.Players[0].Hand))
+	}
+	if state.Players[0].Hand[0] == top {
+		t.Error("Drew the top discard card, which should have stayed on the discard")
+	}
+	if len(state.Discard) != 1 || state.Discard[0] != top {
+		t.Errorf("Expected top discard %v preserved, got %v", top, state.Discard)
+	}
+	if len(state.Deck) != 1 {
+		t.Errorf("Expected 1 card left in deck, got %d", len(state.Deck))
+	}
+}
+
+func TestDrawCardAtBuriedDiscard(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	a, b, c := Card{Rank: 1, Suit: 0}, Card{Rank: 2, Suit: 1}, Card{Rank: 3, Suit: 2}
+	state.Discard = append(state.Discard, a, b, c)
+
+	if !state.DrawCardAt(0, LocationDiscard, 1, false) {
+		t.Fatal("Expected draw to succeed")
+	}
+	if hand := state.Players[0].Hand; len(hand) != 1 || hand[0] != b {
+		t.Errorf("Expected hand [%v], got %v", b, hand)
+	}
+	if len(state.Discard) != 2 || state.Discard[0] != a || state.Discard[1] != c {
+		t.Errorf("Expected discard [%v %v], got %v", a, c, state.Discard)
+	}
+
+	if state.DrawCardAt(0, LocationDiscard, 2, false) || state.DrawCardAt(0, LocationDiscard, -1, false) {
+		t.Error("Expected out-of-range index to fail")
+	}
+}
+
+func TestDrawCardAtTakesCardsAbove(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	a, b, c, d := Card{Rank: 1, Suit: 0}, Card{Rank: 2, Suit: 1}, Card{Rank: 3, Suit: 2}, Card{Rank: 4, Suit: 3}
+	state.Discard = append(state.Discard, a, b, c, d)
+	state.Players[1].Hand = append(state.Players[1].Hand, Card{Rank: 12, Suit: 0})
+
+	if !state.DrawCardAt(1, LocationDiscard, 1, true) {
+		t.Fatal("Expected draw to succeed")
+	}
+	want := []Card{{Rank: 12, Suit: 0}, b, c, d}
+	hand := state.Players[1].Hand
+	if len(hand) != len(want) {
+		t.Fatalf("Expected hand %v, got %v", want, hand)
+	}
+	for i := range want {
+		if hand[i] != want[i] {
+			t.Errorf("Hand card %d: expected %v, got %v", i, want[i], hand[i])
+		}
+	}
+	if len(state.Discard) != 1 || state.Discard[0] != a {
+		t.Errorf("Expected discard [%v], got %v", a, state.Discard)
+	}
+}
+
+func TestDrawBottom(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	bottom, top := Card{Rank: 2, Suit: 0}, Card{Rank: 9, Suit: 3}
+	state.Discard = append(state.Discard, bottom, Card{Rank: 5, Suit: 1}, top)
+
+	if !state.DrawBottom(0, LocationDiscard) {
+		t.Fatal("Expected bottom draw to succeed")
+	}
+	if hand := state.Players[0].Hand; len(hand) != 1 || hand[0] != bottom {
+		t.Errorf("Expected to draw the bottom card %v, got %v", bottom, hand)
+	}
+	if len(state.Discard) != 2 || state.Discard[1] != top {
+		t.Errorf("Expected the top card to stay on top, got %v", state.Discard)
+	}
+
+	if state.DrawBottom(0, LocationDeck) {
+		t.Error("Expected bottom draw from an empty deck to fail")
+	}
+}
+
+func TestPlaceBottom(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	unwanted := Card{Rank: 7, Suit: 2}
+	state.Deck = append(state.Deck, Card{Rank: 0, Suit: 0}, Card{Rank: 1, Suit: 0})
+	state.Players[0].Hand = []Card{{Rank: 12, Suit: 1}, unwanted}
+
+	if !state.PlaceBottom(0, 1, LocationDeck) {
+		t.Fatal("Expected bottom place to succeed")
+	}
+	if len(state.Deck) != 3 || state.Deck[0] != unwanted {
+		t.Errorf("Expected %v under the deck, got %v", unwanted, state.Deck)
+	}
+	if len(state.Players[0].Hand) != 1 {
+		t.Errorf("Expected 1 card left in hand, got %v", state.Players[0].Hand)
+	}
+
+	// The next draw still comes off the top
+	state.DrawCard(1, LocationDeck)
+	if state.Players[1].Hand[0] != (Card{Rank: 1, Suit: 0}) {
+		t.Errorf("Expected the top card drawn, got %v", state.Players[1].Hand[0])
+	}
+
+	// Onto an empty tableau, which the place creates
+	if !state.PlaceBottom(0, 0, LocationTableau) || len(state.Tableau) != 1 || len(state.Tableau[0]) != 1 {
+		t.Errorf("Expected a new tableau pile, got %v", state.Tableau)
+	}
+
+	// Invalid index or target leaves the hand alone
+	state.Players[0].Hand = []Card{{Rank: 4, Suit: 0}}
+	if state.PlaceBottom(0, 3, LocationDiscard) || state.PlaceBottom(0, 0, LocationOpponentHand) {
+		t.Error("Expected invalid bottom places to fail")
+	}
+	if len(state.Players[0].Hand) != 1 {
+		t.Errorf("Expected failed places to keep the card, got %v", state.Players[0].Hand)
+	}
+}
+
+func TestPickDiscardMoves(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Discard = append(state.Discard, Card{Rank: 1, Suit: 0}, Card{Rank: 2, Suit: 1}, Card{Rank: 3, Suit: 2})
+	genome := &Genome{TurnPhases: []PhaseDescriptor{
+		{PhaseType: PhaseTypeDraw, Data: []byte{byte(LocationDiscard), 0, 0, 0, 1, 1, DrawFlagPickDiscard | DrawFlagTakeAbove}},
+	}}
+
+	moves := GenerateLegalMoves(state, genome)
+	want := []int{MoveDraw, MoveDrawAtOffset - 1, MoveDrawAtOffset}
+	if len(moves) != len(want) {
+		t.Fatalf("Expected %d moves, got %v", len(want), moves)
+	}
+	for i, cardIndex := range want {
+		if moves[i].CardIndex != cardIndex {
+			t.Errorf("Move %d: expected CardIndex %d, got %d", i, cardIndex, moves[i].CardIndex)
+		}
+	}
+	if _, ok := SingleForcedMove(state, genome); ok {
+		t.Error("Expected no forced move with buried discards to pick from")
+	}
+
+	// Taking the bottom card picks up the whole pile
+	ApplyMove(state, &moves[2], genome)
+	if len(state.Players[0].Hand) != 3 || len(state.Discard) != 0 {
+		t.Errorf("Expected all 3 discards in hand, got hand %v and discard %v", state.Players[0].Hand, state.Discard)
+	}
+
+	// Without the flag only the top card is offered
+	genome.TurnPhases[0].Data[6] = 0
+	state.Discard = append(state.Discard, Card{Rank: 5, Suit: 0}, Card{Rank: 6, Suit: 0})
+	if moves := GenerateLegalMoves(state, genome); len(moves) != 1 || moves[0].CardIndex != MoveDraw {
+		t.Errorf("Expected only the top-card draw, got %v", moves)
+	}
+}
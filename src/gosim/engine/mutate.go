@@ -0,0 +1,212 @@
+package engine
+
+import "encoding/binary"
+
+// Bytecode mutation
+//
+// MutateGenome mutates genome bytecode in place of a round trip through the
+// Python genome: it only rewrites fields whose encoding has a fixed width,
+// so every section keeps its length and every offset stays valid. Each
+// mutable field (max turns, win condition types and thresholds, draw and
+// discard counts, play limits and flags, trick rules, betting stakes,
+// simultaneous outcomes, and the order of adjacent phases) changes with
+// probability rate. The result has to parse and pass playableGenome, or the
+// mutations are drawn again, up to maxMutateAttempts times.
+
+// maxMutateAttempts is how many mutation draws MutateGenome tries before
+// returning the genome unchanged
+const maxMutateAttempts = 10
+
+// mutator draws mutations from a seeded LCG
+type mutator struct {
+	rng  uint64
+	rate float64
+}
+
+// intn returns a number in [0, n)
+func (m *mutator) intn(n int) int {
+	m.rng = m.rng*6364136223846793005 + 1442695040888963407
+	return int((m.rng >> 33) % uint64(n))
+}
+
+// hit reports whether a field should mutate, with probability m.rate
+func (m *mutator) hit() bool {
+	return float64(m.intn(1<<20)) < m.rate*(1<<20)
+}
+
+// tweak returns v moved by up to a quarter of itself (at least 1) either
+// way, kept within [lo, hi]
+func (m *mutator) tweak(v, lo, hi int) int {
+	step := v / 4
+	if step < 1 {
+		step = 1
+	}
+	v += m.intn(2*step+1) - step
+	if v < lo {
+		v = lo
+	}
+	if v > hi {
+		v = hi
+	}
+	return v
+}
+
+// tweakUint32 tweaks the big-endian uint32 at b[0:4]
+func (m *mutator) tweakUint32(b []byte, lo, hi int) {
+	binary.BigEndian.PutUint32(b, uint32(m.tweak(int(int32(binary.BigEndian.Uint32(b))), lo, hi)))
+}
+
+// flip toggles a 0/1 flag byte
+func flip(b *byte) {
+	if *b == 0 {
+		*b = 1
+	} else {
+		*b = 0
+	}
+}
+
+// MutateGenome returns a mutated copy of bytecode (see above), drawing
+// mutations from seed so the same inputs always give the same genome.
+// Bytecode that doesn't parse, and rate 0, come back as an unchanged copy.
+func MutateGenome(bytecode []byte, seed uint64, rate float64) []byte {
+	original := append([]byte(nil), bytecode...)
+	genome, err := ParseGenome(original)
+	if err != nil || rate <= 0 {
+		return original
+	}
+
+	m := &mutator{rng: seed, rate: rate}
+	for attempt := 0; attempt < maxMutateAttempts; attempt++ {
+		mutant := append([]byte(nil), bytecode...)
+		m.mutate(mutant, genome)
+		if parsed, err := ParseGenome(mutant); err == nil && playableGenome(parsed) {
+			return mutant
+		}
+	}
+	return original
+}
+
+// mutate applies one draw of mutations to b, the bytecode genome was parsed from
+func (m *mutator) mutate(b []byte, genome *Genome) {
+	maxTurns := 16
+	if genome.Header.BytecodeVersion >= 2 {
+		maxTurns = 17
+	}
+	if m.hit() {
+		m.tweakUint32(b[maxTurns:maxTurns+4], 10, 10000)
+	}
+
+	offset := int(genome.Header.WinConditionsOffset) + 4
+	for range genome.WinConditions {
+		if m.hit() {
+			b[offset] = uint8(m.intn(int(WinTypeBlackjack) + 1))
+		}
+		if m.hit() {
+			m.tweakUint32(b[offset+1:offset+5], 0, 1<<20)
+		}
+		offset += 5
+	}
+
+	// Phases are walked by the lengths the parser found
+	offset = int(genome.Header.TurnStructureOffset) + 4
+	starts := make([]int, len(genome.TurnPhases)+1)
+	for i, phase := range genome.TurnPhases {
+		starts[i] = offset
+		m.mutatePhase(phase.PhaseType, b[offset+1:offset+1+len(phase.Data)])
+		offset += 1 + len(phase.Data)
+	}
+	starts[len(genome.TurnPhases)] = offset
+	for i := 0; i+1 < len(genome.TurnPhases); i++ {
+		if m.hit() {
+			// Swap phases i and i+1 by rotating their bytes
+			first := append([]byte(nil), b[starts[i]:starts[i+1]]...)
+			n := copy(b[starts[i]:], b[starts[i+1]:starts[i+2]])
+			copy(b[starts[i]+n:], first)
+			starts[i+1] = starts[i] + n
+		}
+	}
+}
+
+// mutatePhase mutates the fixed-width fields of one phase's data
+func (m *mutator) mutatePhase(phaseType uint8, data []byte) {
+	switch phaseType {
+	case PhaseTypeDraw: // source:1 count:4 mandatory:1 flags:1
+		if m.hit() {
+			m.tweakUint32(data[1:5], 1, 5)
+		}
+		if m.hit() {
+			flip(&data[5])
+		}
+	case PhaseTypePlay: // target:1 min:1 max:1 mandatory:1 pass_if_unable:1
+		if m.hit() {
+			data[2] = uint8(m.tweak(int(data[2]), 1, 13))
+		}
+		if m.hit() {
+			data[1] = uint8(m.tweak(int(data[1]), 0, int(data[2])))
+		}
+		if data[1] > data[2] {
+			data[1] = data[2]
+		}
+		if m.hit() {
+			flip(&data[3])
+		}
+		if m.hit() {
+			flip(&data[4])
+		}
+	case PhaseTypeDiscard: // target:1 count:4 mandatory:1
+		if m.hit() {
+			m.tweakUint32(data[1:5], 1, 5)
+		}
+		if m.hit() {
+			flip(&data[5])
+		}
+	case PhaseTypeTrick: // lead_suit_required:1 trump_suit:1 high_card_wins:1 breaking_suit:1
+		if m.hit() {
+			flip(&data[0])
+		}
+		if m.hit() {
+			// A suit, or 255 for no trumps
+			if suit := m.intn(5); suit < 4 {
+				data[1] = uint8(suit)
+			} else {
+				data[1] = 255
+			}
+		}
+		if m.hit() {
+			flip(&data[2])
+		}
+	case PhaseTypeBetting: // min_bet:4 max_raises:4
+		if m.hit() {
+			m.tweakUint32(data[0:4], 1, 1000)
+		}
+		if m.hit() {
+			m.tweakUint32(data[4:8], 1, 10)
+		}
+	case PhaseTypeSimultaneous: // outcome:1
+		if m.hit() {
+			data[0] = uint8(m.intn(2))
+		}
+	}
+}
+
+// playableGenome reports whether a mutant or offspring still makes a
+// playable game: it can end, its play phases allow at least one card, and
+// players have chips to bet if it has betting
+func playableGenome(genome *Genome) bool {
+	if genome.Header.MaxTurns == 0 || len(genome.WinConditions) == 0 || len(genome.TurnPhases) == 0 {
+		return false
+	}
+	for _, phase := range genome.TurnPhases {
+		switch phase.PhaseType {
+		case PhaseTypePlay:
+			if phase.Data[2] == 0 || phase.Data[1] > phase.Data[2] {
+				return false
+			}
+		case PhaseTypeBetting:
+			if ReadSetupParams(genome).StartingChips <= 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
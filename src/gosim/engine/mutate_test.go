@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMutateGenomeStillParses(t *testing.T) {
+	for _, name := range []string{"war_genome.bin", "simple_poker_genome.bin", "hearts_genome.bin"} {
+		original := loadGoldenGenome(t, name).Bytecode
+		saved := append([]byte(nil), original...)
+		changed := 0
+		for seed := uint64(1); seed <= 50; seed++ {
+			mutant := MutateGenome(original, seed, 0.3)
+			genome, err := ParseGenome(mutant)
+			if err != nil || !playableGenome(genome) {
+				t.Fatalf("%s seed %d: mutant doesn't make a valid genome: %v", name, seed, err)
+			}
+			if len(mutant) != len(original) {
+				t.Errorf("%s seed %d: expected the length kept at %d, got %d", name, seed, len(original), len(mutant))
+			}
+			if !bytes.Equal(mutant, original) {
+				changed++
+			}
+
+			// Mutants play
+			state := SetupGame(genome, seed)
+			GenerateLegalMoves(state, genome)
+			PutState(state)
+		}
+		if changed == 0 {
+			t.Errorf("%s: expected some seeds to mutate the genome", name)
+		}
+		if !bytes.Equal(original, saved) {
+			t.Fatalf("%s: expected the input bytecode left alone", name)
+		}
+	}
+}
+
+func TestMutateGenomeDeterministic(t *testing.T) {
+	original := loadGoldenGenome(t, "hearts_genome.bin").Bytecode
+	first := MutateGenome(original, 42, 0.5)
+	if second := MutateGenome(original, 42, 0.5); !bytes.Equal(first, second) {
+		t.Error("Expected the same seed to give the same mutant")
+	}
+	if other := MutateGenome(original, 43, 0.5); bytes.Equal(first, other) {
+		t.Error("Expected another seed to give another mutant")
+	}
+	if same := MutateGenome(original, 42, 0); !bytes.Equal(same, original) {
+		t.Error("Expected rate 0 to leave the genome unchanged")
+	}
+}
@@ -0,0 +1,164 @@
+package engine
+
+// Observations
+//
+// EncodeObservation flattens what one player can see of a GameState into a
+// fixed-length feature vector for training policies. The layout only
+// depends on the genome's player count, and only grows by appending, so
+// vectors from the same genome stay comparable across engine versions.
+//
+// Card blocks hold obsCards one-hot entries indexed suit*13 + rank (jokers
+// at 52 and 53). Players are seated relative to the viewer: player slot 0
+// is the viewer, slot 1 the next seat, and so on. A viewer outside the
+// game (a spectator) sees no hand, and the slots start from seat 0.
+//
+//	[0, 54)     viewer's hand
+//	[54, 108)   discard pile
+//	[108, 162)  tableau, all piles
+//	[162, 216)  community cards
+//	[216, 270)  current trick
+//	[270, 324)  face-up cards in opponents' hands
+//	[324, 337)  table:
+//	            deck, discard and kitty sizes / 54,
+//	            pot and current bet / chips in play,
+//	            cards in trick / players, lead suit one-hot (4), hearts broken,
+//	            turn number / max turns, current phase / phases
+//	[337, ...)  10 features per player slot:
+//	            hand size / 54, captured cards / 54, score / 100,
+//	            tricks won / 13, chips and bet / chips in play,
+//	            folded, all in, eliminated, to move
+//	[337+10n, 391+10n)  cards seen this hand (GameState.SeenCards), for n players
+//
+// Flags are 0 or 1. Chips in play is every player's chips plus the pot.
+
+const (
+	obsCards          = 52 + MaxJokers
+	obsHand           = 0
+	obsDiscard        = obsHand + obsCards
+	obsTableau        = obsDiscard + obsCards
+	obsCommunity      = obsTableau + obsCards
+	obsTrick          = obsCommunity + obsCards
+	obsFaceUp         = obsTrick + obsCards
+	obsTable          = obsFaceUp + obsCards
+	obsTableFeatures  = 13
+	obsPlayers        = obsTable + obsTableFeatures
+	obsPlayerFeatures = 10
+)
+
+// ObservationSize returns the length of EncodeObservation's vectors for genome
+func ObservationSize(genome *Genome) int {
+	return obsPlayers + ReadSetupParams(genome).NumPlayers*obsPlayerFeatures + obsCards
+}
+
+// obsCardIndex returns card's offset within a card block, or -1
+func obsCardIndex(card Card) int {
+	if (card.Rank > AceRank || card.Suit > 3) && !card.IsJoker() {
+		return -1
+	}
+	return int(card.Suit)*13 + int(card.Rank)
+}
+
+// EncodeObservation returns what viewerID can see of state as an
+// ObservationSize(genome) feature vector (see the layout above)
+func EncodeObservation(state *GameState, genome *Genome, viewerID int) []float32 {
+	numPlayers := ReadSetupParams(genome).NumPlayers
+	obsSeen := obsPlayers + numPlayers*obsPlayerFeatures
+	obs := make([]float32, obsSeen+obsCards)
+
+	setCards := func(block int, cards []Card) {
+		for _, card := range cards {
+			if i := obsCardIndex(card); i >= 0 {
+				obs[block+i] = 1
+			}
+		}
+	}
+
+	firstSeat := 0
+	if viewerID >= 0 && viewerID < len(state.Players) {
+		setCards(obsHand, state.Players[viewerID].Hand)
+		firstSeat = viewerID
+	}
+	setCards(obsDiscard, state.Discard)
+	for _, pile := range state.Tableau {
+		setCards(obsTableau, pile)
+	}
+	setCards(obsCommunity, state.Community)
+	for _, tc := range state.CurrentTrick {
+		setCards(obsTrick, []Card{tc.Card})
+	}
+
+	chipsInPlay := state.Pot
+	for p := 0; p < numPlayers && p < len(state.Players); p++ {
+		chipsInPlay += state.Players[p].Chips
+	}
+	chipShare := func(chips int64) float32 {
+		if chipsInPlay <= 0 {
+			return 0
+		}
+		return float32(float64(chips) / float64(chipsInPlay))
+	}
+	flag := func(b bool) float32 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	table := obs[obsTable : obsTable+obsTableFeatures]
+	table[0] = float32(len(state.Deck)) / obsCards
+	table[1] = float32(len(state.Discard)) / obsCards
+	table[2] = float32(len(state.Kitty)) / obsCards
+	table[3] = chipShare(state.Pot)
+	table[4] = chipShare(state.CurrentBet)
+	table[5] = float32(len(state.CurrentTrick)) / float32(numPlayers)
+	if len(state.CurrentTrick) > 0 {
+		if lead := state.CurrentTrick[0].Card.Suit; lead < 4 {
+			table[6+lead] = 1
+		}
+	}
+	table[10] = flag(state.HeartsBroken)
+	maxTurns := uint32(1000) // Default, as in the simulation runners
+	if genome.Header != nil && genome.Header.MaxTurns > 0 {
+		maxTurns = genome.Header.MaxTurns
+	}
+	table[11] = float32(state.TurnNumber) / float32(maxTurns)
+	if len(genome.TurnPhases) > 0 {
+		table[12] = float32(state.CurrentPhase) / float32(len(genome.TurnPhases))
+	}
+
+	for slot := 0; slot < numPlayers; slot++ {
+		p := (firstSeat + slot) % numPlayers
+		if p >= len(state.Players) {
+			continue
+		}
+		player := &state.Players[p]
+		if p != viewerID {
+			for _, card := range player.Hand {
+				if player.IsFaceUp(card) {
+					setCards(obsFaceUp, []Card{card})
+				}
+			}
+		}
+
+		features := obs[obsPlayers+slot*obsPlayerFeatures : obsPlayers+(slot+1)*obsPlayerFeatures]
+		features[0] = float32(len(player.Hand)) / obsCards
+		features[1] = float32(len(player.Captured)) / obsCards
+		features[2] = float32(player.Score) / 100
+		if p < len(state.TricksWon) {
+			features[3] = float32(state.TricksWon[p]) / 13
+		}
+		features[4] = chipShare(player.Chips)
+		features[5] = chipShare(player.CurrentBet)
+		features[6] = flag(player.HasFolded)
+		features[7] = flag(player.IsAllIn)
+		features[8] = flag(player.Eliminated)
+		features[9] = flag(p == int(state.CurrentPlayer))
+	}
+
+	for i := 0; i < obsCards; i++ {
+		if state.SeenCards&(1<<uint(i)) != 0 {
+			obs[obsSeen+i] = 1
+		}
+	}
+	return obs
+}
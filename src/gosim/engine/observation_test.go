@@ -0,0 +1,42 @@
+package engine
+
+import "testing"
+
+func TestEncodeObservationLayout(t *testing.T) {
+	genome := &Genome{Header: &BytecodeHeader{PlayerCount: 3}}
+	state := NewGameState(3)
+	defer PutState(state)
+	state.NumPlayers = 3
+	state.CurrentPlayer = 2
+	state.Players[1].Hand = []Card{{Rank: 5, Suit: 2}, {Rank: 12, Suit: 3}} // 7 of Clubs, Ace of Spades
+	state.Players[2].Hand = []Card{{Rank: 0, Suit: 0}, {Rank: 9, Suit: 1}}
+	state.Players[2].setFaceUp(state.Players[2].Hand[1])
+	state.Discard = []Card{Joker(1)}
+
+	obs := EncodeObservation(state, genome, 1)
+	if len(obs) != ObservationSize(genome) || ObservationSize(genome) != 337+3*10+obsCards {
+		t.Fatalf("Expected %d features, got %d", ObservationSize(genome), len(obs))
+	}
+
+	for i, v := range obs[obsHand : obsHand+obsCards] {
+		want := float32(0)
+		if i == 2*13+5 || i == 3*13+12 {
+			want = 1
+		}
+		if v != want {
+			t.Errorf("Hand index %d: expected %v, got %v", i, want, v)
+		}
+	}
+	if obs[obsDiscard+53] != 1 {
+		t.Error("Expected joker 1 in the discard block at index 53")
+	}
+	if obs[obsFaceUp+1*13+9] != 1 || obs[obsFaceUp+0] != 0 {
+		t.Error("Expected only player 2's face-up card in the face-up block")
+	}
+
+	// Player 2 is the next seat after the viewer, and to move
+	next := obs[obsPlayers+obsPlayerFeatures : obsPlayers+2*obsPlayerFeatures]
+	if next[0] != 2.0/obsCards || next[9] != 1 {
+		t.Errorf("Expected slot 1 to be player 2 with 2 cards and the move, got %v", next)
+	}
+}
@@ -0,0 +1,178 @@
+package engine
+
+import "encoding/binary"
+
+// Setup options
+//
+// Settings that the fixed 12-byte setup section has no room for travel as
+// setup options, after any deal pattern and before the turn structure:
+//
+//	setup:   cards_per_player:4 + initial_discard_count:4 + starting_chips:4
+//	pattern: stage_count:1 + stages (see ParseDealPattern; 0 when absent)
+//	options: option_count:1 + [tag:1 + value]...
+//
+// Each tag has a fixed value width (see setupOptionWidth), so an option the
+// genome doesn't use is simply left out, and bytecode from before options
+// existed (nothing after the pattern) parses with every option off. The
+// Python compiler writes no options yet; WithSetupOptions adds them to
+// compiled bytecode.
+
+// Setup option tags
+const (
+	SetupOptMatchPlay          uint8 = 1 // Genome.MatchPlay; no value
+	SetupOptAceLow             uint8 = 2 // Genome.AceLow; no value
+	SetupOptSuitOrder          uint8 = 3 // Genome.SuitOrder; one byte per suit
+	SetupOptKitty              uint8 = 4 // Genome.KittySize and KittyFaceUp; size:1 + face_up:1
+	SetupOptSequentialPhases   uint8 = 5 // Genome.SequentialPhases; no value
+	SetupOptRankValues         uint8 = 6 // Genome.RankValues; 13 x int32, ranks 2..A
+	SetupOptMaxHandSize        uint8 = 7 // Genome.MaxHandSize; cap:1
+	SetupOptWarToBottom        uint8 = 8 // Genome.WarToBottom; no value
+	SetupOptPerfectInformation uint8 = 9 // Genome.PerfectInformation; no value
+)
+
+// setupOptionWidth is the value width of each known tag
+var setupOptionWidth = map[uint8]int{
+	SetupOptMatchPlay:          0,
+	SetupOptAceLow:             0,
+	SetupOptSuitOrder:          4,
+	SetupOptKitty:              2,
+	SetupOptSequentialPhases:   0,
+	SetupOptRankValues:         4 * 13,
+	SetupOptMaxHandSize:        1,
+	SetupOptWarToBottom:        0,
+	SetupOptPerfectInformation: 0,
+}
+
+// parseSetupOptions sets genome's option fields from an options block
+func parseSetupOptions(data []byte, genome *Genome) error {
+	if len(data) == 0 {
+		return nil
+	}
+	count := int(data[0])
+	offset := 1
+	for i := 0; i < count; i++ {
+		if offset >= len(data) {
+			return parseError(SectionSetupOptions, ErrTruncated, "option %d of %d", i, count)
+		}
+		tag := data[offset]
+		width, ok := setupOptionWidth[tag]
+		if !ok {
+			return parseError(SectionSetupOptions, ErrUnknownSetupOption, "option %d has tag %d", i, tag)
+		}
+		offset++
+		if offset+width > len(data) {
+			return parseError(SectionSetupOptions, ErrTruncated, "option %d (tag %d) needs %d bytes", i, tag, width)
+		}
+		switch tag {
+		case SetupOptMatchPlay:
+			genome.MatchPlay = true
+		case SetupOptAceLow:
+			genome.AceLow = true
+		case SetupOptSuitOrder:
+			copy(genome.SuitOrder[:], data[offset:offset+width])
+		case SetupOptKitty:
+			genome.KittySize = int(data[offset])
+			genome.KittyFaceUp = data[offset+1] != 0
+		case SetupOptSequentialPhases:
+			genome.SequentialPhases = true
+		case SetupOptRankValues:
+			for r := range genome.RankValues {
+				genome.RankValues[r] = int32(binary.BigEndian.Uint32(data[offset+4*r:]))
+			}
+		case SetupOptMaxHandSize:
+			genome.MaxHandSize = int(data[offset])
+		case SetupOptWarToBottom:
+			genome.WarToBottom = true
+		case SetupOptPerfectInformation:
+			genome.PerfectInformation = true
+		}
+		offset += width
+	}
+	return nil
+}
+
+// EncodeSetupOptions returns the options block for genome's settings, or
+// nil if it uses none
+func EncodeSetupOptions(genome *Genome) []byte {
+	block := []byte{0}
+	add := func(tag uint8, value ...byte) {
+		block[0]++
+		block = append(block, tag)
+		block = append(block, value...)
+	}
+	if genome.MatchPlay {
+		add(SetupOptMatchPlay)
+	}
+	if genome.AceLow {
+		add(SetupOptAceLow)
+	}
+	if genome.SuitOrder != [4]uint8{} {
+		add(SetupOptSuitOrder, genome.SuitOrder[:]...)
+	}
+	if genome.KittySize > 0 {
+		faceUp := byte(0)
+		if genome.KittyFaceUp {
+			faceUp = 1
+		}
+		add(SetupOptKitty, byte(genome.KittySize), faceUp)
+	}
+	if genome.SequentialPhases {
+		add(SetupOptSequentialPhases)
+	}
+	if genome.RankValues != [13]int32{} {
+		var values []byte
+		for _, v := range genome.RankValues {
+			values = binary.BigEndian.AppendUint32(values, uint32(v))
+		}
+		add(SetupOptRankValues, values...)
+	}
+	if genome.MaxHandSize > 0 {
+		add(SetupOptMaxHandSize, byte(genome.MaxHandSize))
+	}
+	if genome.WarToBottom {
+		add(SetupOptWarToBottom)
+	}
+	if genome.PerfectInformation {
+		add(SetupOptPerfectInformation)
+	}
+	if block[0] == 0 {
+		return nil
+	}
+	return block
+}
+
+// WithSetupOptions returns a copy of bytecode whose setup options are
+// genome's (see EncodeSetupOptions), replacing any it had. The sections are
+// laid out again after a 53-byte header, as CrossoverGenomes writes them.
+func WithSetupOptions(bytecode []byte, genome *Genome) ([]byte, error) {
+	parsed, err := ParseGenome(bytecode)
+	if err != nil {
+		return nil, err
+	}
+	sections, ok := splitSections(bytecode)
+	if !ok {
+		return nil, parseError(SectionSetupOptions, ErrInvalidOffset, "sections out of compiler order")
+	}
+
+	if len(sections.setup) < 12 {
+		return nil, parseError(SectionSetupOptions, ErrTruncated, "setup section has %d bytes", len(sections.setup))
+	}
+
+	// Keep the fixed setup and the deal pattern, if any
+	setup := append([]byte(nil), sections.setup[:12]...)
+	if parsed.DealPattern != nil {
+		setup = append(setup, sections.setup[12:12+1+3*len(parsed.DealPattern.Stages)]...)
+	}
+	if options := EncodeSetupOptions(genome); options != nil {
+		if parsed.DealPattern == nil {
+			setup = append(setup, 0) // No deal stages
+		}
+		setup = append(setup, options...)
+	}
+
+	withOptions := *sections
+	withOptions.setup = setup
+	child := assembleChild([2]*genomeSections{&withOptions, &withOptions}, [numTraits]int{})
+	binary.BigEndian.PutUint64(child[5:13], sections.header.GenomeIDHash)
+	return child, nil
+}
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSetupOptionsRoundTrip(t *testing.T) {
+	poker := loadGoldenGenome(t, "simple_poker_genome.bin")
+	tests := []struct {
+		name  string
+		set   func(g *Genome)
+		check func(g *Genome) bool
+	}{
+		{"match play", func(g *Genome) { g.MatchPlay = true }, func(g *Genome) bool { return g.MatchPlay }},
+		{"ace low", func(g *Genome) { g.AceLow = true }, func(g *Genome) bool { return g.AceLow }},
+		{"suit order", func(g *Genome) { g.SuitOrder = [4]uint8{1, 2, 3, 4} }, func(g *Genome) bool { return g.SuitOrder == [4]uint8{1, 2, 3, 4} }},
+		{"face-down kitty", func(g *Genome) { g.KittySize = 3 }, func(g *Genome) bool { return ReadSetupParams(g).KittySize == 3 && !g.KittyFaceUp }},
+		{"widow", func(g *Genome) { g.KittySize, g.KittyFaceUp = 2, true }, func(g *Genome) bool { return ReadSetupParams(g).KittySize == 2 && g.KittyFaceUp }},
+		{"sequential phases", func(g *Genome) { g.SequentialPhases = true }, func(g *Genome) bool { return g.SequentialPhases }},
+		{"rank values", func(g *Genome) { g.RankValues = [13]int32{12: 15, 11: 10, 0: -5} }, func(g *Genome) bool { return g.RankValues == [13]int32{12: 15, 11: 10, 0: -5} }},
+		{"max hand size", func(g *Genome) { g.MaxHandSize = 7 }, func(g *Genome) bool { return g.MaxHandSize == 7 }},
+		{"war to bottom", func(g *Genome) { g.WarToBottom = true }, func(g *Genome) bool { return g.WarToBottom }},
+		{"perfect information", func(g *Genome) { g.PerfectInformation = true }, func(g *Genome) bool { return g.PerfectInformation }},
+	}
+	for _, tt := range tests {
+		var options Genome
+		tt.set(&options)
+		bytecode, err := WithSetupOptions(poker.Bytecode, &options)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		parsed, err := ParseGenome(bytecode)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", tt.name, err)
+		}
+		if !tt.check(parsed) {
+			t.Errorf("%s: expected the option set after parsing", tt.name)
+		}
+
+		// Everything else is as compiled
+		if !reflect.DeepEqual(parsed.TurnPhases, poker.TurnPhases) || !reflect.DeepEqual(parsed.WinConditions, poker.WinConditions) {
+			t.Errorf("%s: expected the turn structure and win conditions kept", tt.name)
+		}
+		params, want := ReadSetupParams(parsed), ReadSetupParams(poker)
+		if params.CardsPerPlayer != want.CardsPerPlayer || params.InitialDiscardCount != want.InitialDiscardCount ||
+			params.StartingChips != want.StartingChips || parsed.Header.GenomeIDHash != poker.Header.GenomeIDHash {
+			t.Errorf("%s: expected the setup and genome ID kept", tt.name)
+		}
+
+		// And the option comes off again
+		cleared, err := WithSetupOptions(bytecode, &Genome{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if reparsed, err := ParseGenome(cleared); err != nil || tt.check(reparsed) {
+			t.Errorf("%s: expected the option cleared, got error %v", tt.name, err)
+		}
+	}
+
+	// Compiled bytecode has no options
+	if poker.MatchPlay {
+		t.Error("Expected no options in the golden genome")
+	}
+}
+
+func TestSetupOptionsFollowDealPattern(t *testing.T) {
+	genome := loadGoldenGenome(t, "simple_poker_genome.bin")
+
+	// One stage of five face-down cards after the fixed setup
+	sections, _ := splitSections(genome.Bytecode)
+	withPattern := *sections
+	withPattern.setup = append(append([]byte(nil), sections.setup[:12]...), 1, 5, 0, 0)
+	bytecode := assembleChild([2]*genomeSections{&withPattern, &withPattern}, [numTraits]int{})
+
+	bytecode, err := WithSetupOptions(bytecode, &Genome{MatchPlay: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseGenome(bytecode)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if parsed.DealPattern == nil || !reflect.DeepEqual(parsed.DealPattern.Stages, []DealStage{{FaceDown: 5}}) {
+		t.Errorf("Expected the deal pattern kept, got %+v", parsed.DealPattern)
+	}
+	if !parsed.MatchPlay {
+		t.Error("Expected match play after the deal pattern")
+	}
+}
+
+func TestSetupOptionsErrors(t *testing.T) {
+	genome := loadGoldenGenome(t, "simple_poker_genome.bin")
+	bytecode, err := WithSetupOptions(genome.Bytecode, &Genome{MatchPlay: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := ParseGenome(bytecode)
+	// No deal stages, then one option
+	count := parsed.Header.SetupOffset + 13
+
+	unknown := append([]byte(nil), bytecode...)
+	unknown[count+1] = 200
+	_, err = ParseGenome(unknown)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Section != SectionSetupOptions || !errors.Is(err, ErrUnknownSetupOption) {
+		t.Errorf("Expected an unknown setup option, got %v", err)
+	}
+
+	truncated := append([]byte(nil), bytecode...)
+	truncated[count] = 2
+	if _, err := ParseGenome(truncated); !errors.Is(err, ErrTruncated) {
+		t.Errorf("Expected truncated setup options, got %v", err)
+	}
+}
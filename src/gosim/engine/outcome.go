@@ -0,0 +1,97 @@
+package engine
+
+// EndReason records why a game ended
+type EndReason uint8
+
+const (
+	EndReasonNone         EndReason = iota // Game still in progress
+	EndReasonEmptyHand                     // A player emptied their hand (empty_hand)
+	EndReasonScoreTarget                   // A score threshold was reached (high_score, first_to_score, low_score)
+	EndReasonCaptureAll                    // A player captured the whole deck (capture_all)
+	EndReasonHandsPlayed                   // All cards were played out (all_hands_empty, most_captured, most_cards)
+	EndReasonShowdown                      // Hands were compared at showdown (best_hand, blackjack)
+	EndReasonFoldOut                       // Everyone else folded
+	EndReasonMaxTurns                      // Turn limit reached without a winner
+	EndReasonStalemate                     // No legal moves and no winner
+	EndReasonLastStanding                  // Everyone else was eliminated
+	EndReasonKnock                         // A player knocked (Gin Rummy)
+)
+
+// String returns the snake_case name used in worker responses
+func (r EndReason) String() string {
+	switch r {
+	case EndReasonNone:
+		return "none"
+	case EndReasonEmptyHand:
+		return "empty_hand"
+	case EndReasonScoreTarget:
+		return "score_target"
+	case EndReasonCaptureAll:
+		return "capture_all"
+	case EndReasonHandsPlayed:
+		return "hands_played"
+	case EndReasonShowdown:
+		return "showdown"
+	case EndReasonFoldOut:
+		return "fold_out"
+	case EndReasonMaxTurns:
+		return "max_turns"
+	case EndReasonStalemate:
+		return "stalemate"
+	case EndReasonLastStanding:
+		return "last_standing"
+	case EndReasonKnock:
+		return "knock"
+	}
+	return "unknown"
+}
+
+// GameResult is the outcome of a terminal check.
+// Winner is -1 for a draw or a game still in progress; Reason tells them apart.
+type GameResult struct {
+	Winner int8
+	Reason EndReason
+	Turn   uint32
+}
+
+// Over returns true if the game has ended, with or without a winner
+func (r GameResult) Over() bool {
+	return r.Reason != EndReasonNone
+}
+
+// newGameResult builds a result at the current turn; a missing winner means no end
+func newGameResult(state *GameState, winner int8, reason EndReason) GameResult {
+	if winner < 0 {
+		reason = EndReasonNone
+	} else if state.Trace != nil {
+		state.Trace(TraceEvent{Kind: TraceWin, Turn: state.TurnNumber, Player: int(winner), Reason: reason})
+	}
+	return GameResult{Winner: winner, Reason: reason, Turn: state.TurnNumber}
+}
+
+// CheckGameEnd is the full terminal check: the genome's win conditions first,
+// then a last player standing, a fold-out, the turn limit, and finally a
+// stalemate (no legal moves).
+func CheckGameEnd(state *GameState, genome *Genome) GameResult {
+	if result := CheckGameOutcome(state, genome); result.Over() {
+		return result
+	}
+
+	if winner, ok := LastStanding(state); ok {
+		return newGameResult(state, setWinnerWithTeam(state, winner), EndReasonLastStanding)
+	}
+
+	if winner, ok := OnlyOneActive(state); ok && state.NumPlayers > 1 {
+		return newGameResult(state, setWinnerWithTeam(state, winner), EndReasonFoldOut)
+	}
+
+	if genome.Header != nil && genome.Header.MaxTurns > 0 && state.TurnNumber >= genome.Header.MaxTurns {
+		return GameResult{Winner: -1, Reason: EndReasonMaxTurns, Turn: state.TurnNumber}
+	}
+
+	if len(GenerateLegalMoves(state, genome)) == 0 {
+		return GameResult{Winner: -1, Reason: EndReasonStalemate, Turn: state.TurnNumber}
+	}
+
+	return GameResult{Winner: -1, Reason: EndReasonNone, Turn: state.TurnNumber}
+}
@@ -0,0 +1,141 @@
+package engine
+
+import "testing"
+
+// outcomeGenome builds a 2-player play-to-discard genome with the given win conditions
+func outcomeGenome(wcs ...WinCondition) *Genome {
+	return &Genome{
+		Header: &BytecodeHeader{PlayerCount: 2, MaxTurns: 50},
+		TurnPhases: []PhaseDescriptor{{
+			PhaseType: PhaseTypePlay,
+			Data:      []byte{byte(LocationDiscard), 1, 1, 1, 0, 0, 0, 0, 0},
+		}},
+		WinConditions: wcs,
+	}
+}
+
+// outcomeState returns a 2-player state where both players hold the given hand sizes
+func outcomeState(sizes ...int) *GameState {
+	state := GetStateN(2)
+	state.NumPlayers = 2
+	dealTestHands(state, sizes...)
+	return state
+}
+
+func TestCheckGameEnd_Reasons(t *testing.T) {
+	tests := []struct {
+		name       string
+		genome     *Genome
+		setup      func(*GameState)
+		wantWinner int8
+		wantReason EndReason
+	}{
+		{
+			name:       "in progress",
+			genome:     outcomeGenome(WinCondition{WinType: WinTypeEmptyHand}),
+			wantWinner: -1,
+			wantReason: EndReasonNone,
+		},
+		{
+			name:   "empty hand",
+			genome: outcomeGenome(WinCondition{WinType: WinTypeEmptyHand}),
+			setup: func(s *GameState) {
+				s.Players[1].Hand = s.Players[1].Hand[:0]
+			},
+			wantWinner: 1,
+			wantReason: EndReasonEmptyHand,
+		},
+		{
+			name:   "score target",
+			genome: outcomeGenome(WinCondition{WinType: WinTypeHighScore, Threshold: 10}),
+			setup: func(s *GameState) {
+				s.Players[0].Score = 12
+			},
+			wantWinner: 0,
+			wantReason: EndReasonScoreTarget,
+		},
+		{
+			name:   "hands played out",
+			genome: outcomeGenome(WinCondition{WinType: WinTypeAllHandEmpty}),
+			setup: func(s *GameState) {
+				dealTestHands(s, 0, 0)
+				s.Players[0].Score = 5
+			},
+			wantWinner: 1,
+			wantReason: EndReasonHandsPlayed,
+		},
+		{
+			name:   "fold out",
+			genome: outcomeGenome(),
+			setup: func(s *GameState) {
+				s.Players[0].HasFolded = true
+			},
+			wantWinner: 1,
+			wantReason: EndReasonFoldOut,
+		},
+		{
+			name:   "max turns",
+			genome: outcomeGenome(WinCondition{WinType: WinTypeEmptyHand}),
+			setup: func(s *GameState) {
+				s.TurnNumber = 50
+			},
+			wantWinner: -1,
+			wantReason: EndReasonMaxTurns,
+		},
+		{
+			name: "stalemate",
+			genome: &Genome{
+				Header:        &BytecodeHeader{PlayerCount: 2, MaxTurns: 50},
+				WinConditions: []WinCondition{{WinType: WinTypeEmptyHand}},
+			},
+			wantWinner: -1,
+			wantReason: EndReasonStalemate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := outcomeState(3, 3)
+			defer PutState(state)
+			state.TurnNumber = 7
+			if tt.setup != nil {
+				tt.setup(state)
+			}
+
+			result := CheckGameEnd(state, tt.genome)
+			if result.Winner != tt.wantWinner || result.Reason != tt.wantReason {
+				t.Errorf("Expected winner %d (%s), got %d (%s)",
+					tt.wantWinner, tt.wantReason, result.Winner, result.Reason)
+			}
+			if result.Turn != state.TurnNumber {
+				t.Errorf("Expected turn %d, got %d", state.TurnNumber, result.Turn)
+			}
+			if result.Over() != (tt.wantReason != EndReasonNone) {
+				t.Errorf("Over() = %v for reason %s", result.Over(), result.Reason)
+			}
+		})
+	}
+}
+
+func TestCheckGameOutcome_MatchesCheckWinConditions(t *testing.T) {
+	state := outcomeState(3, 0)
+	defer PutState(state)
+	genome := outcomeGenome(WinCondition{WinType: WinTypeEmptyHand})
+
+	result := CheckGameOutcome(state, genome)
+	if result.Winner != CheckWinConditions(state, genome) {
+		t.Errorf("Expected CheckGameOutcome winner %d to match CheckWinConditions", result.Winner)
+	}
+	if result.Reason != EndReasonEmptyHand {
+		t.Errorf("Expected empty_hand, got %s", result.Reason)
+	}
+}
+
+func TestEndReason_String(t *testing.T) {
+	if EndReasonMaxTurns.String() != "max_turns" {
+		t.Errorf("Expected max_turns, got %s", EndReasonMaxTurns)
+	}
+	if EndReason(200).String() != "unknown" {
+		t.Errorf("Expected unknown for out-of-range reason, got %s", EndReason(200))
+	}
+}
@@ -24,15 +24,32 @@ type PokerHand struct {
 	Kickers  []uint8 // For tie-breaking (high cards)
 }
 
-// EvaluatePokerHand evaluates a 5-card poker hand
+// EvaluatePokerHand evaluates a 5-card poker hand with the Ace high
+// (it also plays low in the A-2-3-4-5 wheel)
 func EvaluatePokerHand(cards []Card) PokerHand {
+	return EvaluatePokerHandOrdered(cards, false)
+}
+
+// EvaluatePokerHandOrdered evaluates a 5-card poker hand under the given Ace
+// rule. With aceLow the Ace is the lowest card: A-2-3-4-5 is an ordinary
+// 5-high straight, 10-J-Q-K-A is not a straight, and kickers hold RankValues.
+// Jokers are wild (see evaluateWildHand).
+func EvaluatePokerHandOrdered(cards []Card, aceLow bool) PokerHand {
 	if len(cards) != 5 {
 		return PokerHand{Rank: HighCard}
 	}
+	for _, card := range cards {
+		if card.IsJoker() {
+			return evaluateWildHand(cards, aceLow)
+		}
+	}
 
 	// Sort cards by rank descending
 	sorted := make([]Card, 5)
 	copy(sorted, cards)
+	for i := range sorted {
+		sorted[i].Rank = RankValue(sorted[i].Rank, aceLow)
+	}
 	sort.Slice(sorted, func(i, j int) bool {
 		return sorted[i].Rank > sorted[j].Rank
 	})
@@ -46,30 +63,28 @@ func EvaluatePokerHand(cards []Card) PokerHand {
 		}
 	}
 
-	// Check for straight (5 consecutive ranks)
-	isStraight := true
-	for i := 1; i < 5; i++ {
+	// Count ranks
+	rankCounts := CountRanks(sorted)
+	distinct := rankCounts.Distinct()
+
+	// Check for straight (5 consecutive ranks). A hand with any repeated
+	// rank can never be a straight, so only check when all ranks are distinct.
+	isStraight := distinct == 5
+	for i := 1; i < 5 && isStraight; i++ {
 		if sorted[i-1].Rank != sorted[i].Rank+1 {
 			isStraight = false
-			break
 		}
 	}
 
 	// Special case: A-2-3-4-5 (wheel straight)
-	// Ace is rank 12, so check for 12-3-2-1-0
-	if !isStraight && sorted[0].Rank == 12 && sorted[1].Rank == 3 &&
+	// Ace is rank 12, so check for 12-3-2-1-0 (ace-low already ranks it 0-4)
+	if !aceLow && !isStraight && distinct == 5 && sorted[0].Rank == 12 && sorted[1].Rank == 3 &&
 		sorted[2].Rank == 2 && sorted[3].Rank == 1 && sorted[4].Rank == 0 {
 		isStraight = true
 		// Reorder for wheel: 3-2-1-0-12 becomes 5-high straight
 		sorted = []Card{sorted[1], sorted[2], sorted[3], sorted[4], sorted[0]}
 	}
 
-	// Count ranks
-	rankCounts := make(map[uint8]int)
-	for _, card := range sorted {
-		rankCounts[card.Rank]++
-	}
-
 	// Determine hand type based on rank counts
 	var pairs, threes, fours int
 	for _, count := range rankCounts {
@@ -83,15 +98,26 @@ func EvaluatePokerHand(cards []Card) PokerHand {
 		}
 	}
 
-	// Build kickers list (all ranks sorted descending)
+	// Build kickers list: ranks grouped by count (quads, trips, pairs), then by
+	// rank descending, so 9-9-9-2-2 compares as trip 9s before the pair.
+	// Straights keep sorted order so the wheel stays 5-high.
 	kickers := make([]uint8, 5)
 	for i, card := range sorted {
 		kickers[i] = card.Rank
 	}
+	if !isStraight {
+		sort.SliceStable(kickers, func(i, j int) bool {
+			ci, cj := rankCounts[kickers[i]], rankCounts[kickers[j]]
+			if ci != cj {
+				return ci > cj
+			}
+			return kickers[i] > kickers[j]
+		})
+	}
 
 	// Determine hand rank
 	if isStraight && isFlush {
-		if sorted[0].Rank == 12 && sorted[1].Rank == 11 {
+		if !aceLow && sorted[0].Rank == 12 && sorted[1].Rank == 11 {
 			// A-K-Q-J-10 of same suit
 			return PokerHand{Rank: RoyalFlush, Kickers: kickers}
 		}
@@ -129,6 +155,53 @@ func EvaluatePokerHand(cards []Card) PokerHand {
 	return PokerHand{Rank: HighCard, Kickers: kickers}
 }
 
+// evaluateWildHand evaluates a 5-card hand holding jokers by playing each
+// joker as whichever card not already in the hand makes the best hand. A
+// wild card never duplicates a real one, so there is no five of a kind.
+func evaluateWildHand(cards []Card, aceLow bool) PokerHand {
+	hand := make([]Card, 5)
+	copy(hand, cards)
+
+	var best PokerHand
+	found := false
+	var substitute func(i int)
+	substitute = func(i int) {
+		for i < len(hand) && !hand[i].IsJoker() {
+			i++
+		}
+		if i == len(hand) {
+			if h := EvaluatePokerHandOrdered(hand, aceLow); !found || ComparePokerHands(h, best) > 0 {
+				best, found = h, true
+			}
+			return
+		}
+
+		joker := hand[i]
+		for suit := uint8(0); suit < 4; suit++ {
+			for rank := uint8(0); rank <= AceRank; rank++ {
+				card := Card{Rank: rank, Suit: suit}
+				if !containsCard(hand, card) {
+					hand[i] = card
+					substitute(i + 1)
+				}
+			}
+		}
+		hand[i] = joker
+	}
+	substitute(0)
+	return best
+}
+
+// containsCard returns true if card is in cards
+func containsCard(cards []Card, card Card) bool {
+	for _, c := range cards {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
+
 // ComparePokerHands compares two poker hands, returns:
 // -1 if hand1 < hand2
 //  0 if hand1 == hand2
@@ -154,38 +227,97 @@ func ComparePokerHands(hand1, hand2 PokerHand) int {
 	return 0 // Exact tie
 }
 
-// FindBestPokerWinner finds the player with the best poker hand
-// Returns player ID or -1 for tie
-func FindBestPokerWinner(state *GameState, numPlayers int) int8 {
+// FindBestPokerWinner finds the players with the best poker hand
+// Returns all players tied for the best hand (split pot), or nil if no player
+// has at least 5 cards. See PokerShowdown for how other hand sizes are handled.
+func FindBestPokerWinner(state *GameState, numPlayers int) []int8 {
+	winners, _ := PokerShowdown(state, numPlayers)
+	return winners
+}
+
+// PokerShowdown compares every player's hand and returns the players tied for
+// the best one, plus the players skipped because they hold fewer than 5 cards
+// (usually a sign that an earlier phase removed too many). Folded and
+// eliminated players are ignored. Each hand is combined with the community cards, and more than 5
+// cards play their best 5-card combination.
+func PokerShowdown(state *GameState, numPlayers int) (winners []int8, skipped []int8) {
 	if numPlayers == 0 {
 		numPlayers = 2
 	}
 
-	bestPlayer := int8(-1)
 	var bestHand PokerHand
+	var cards []Card // Hand plus community cards
 
 	for playerID := 0; playerID < numPlayers; playerID++ {
+		if state.Players[playerID].HasFolded || state.Players[playerID].Eliminated {
+			continue
+		}
 		hand := state.Players[playerID].Hand
-		if len(hand) != 5 {
-			continue // Skip players without exactly 5 cards
+		if len(state.Community) > 0 {
+			cards = append(append(cards[:0], hand...), state.Community...)
+			hand = cards
+		}
+		if len(hand) < 5 {
+			skipped = append(skipped, int8(playerID))
+			continue
 		}
 
-		pokerHand := EvaluatePokerHand(hand)
+		pokerHand := BestPokerHand(hand, state.AceLow)
 
-		if bestPlayer == -1 {
-			bestPlayer = int8(playerID)
+		if len(winners) == 0 {
+			winners = append(winners, int8(playerID))
 			bestHand = pokerHand
 		} else {
 			cmp := ComparePokerHands(pokerHand, bestHand)
 			if cmp > 0 {
-				bestPlayer = int8(playerID)
+				winners = append(winners[:0], int8(playerID))
 				bestHand = pokerHand
 			} else if cmp == 0 {
-				// Tie - for simplicity, first player wins ties
-				// In real poker, pot would be split
+				// Tie - pot is split between all tied players
+				winners = append(winners, int8(playerID))
 			}
 		}
 	}
 
-	return bestPlayer
+	return winners, skipped
+}
+
+// BestPokerHand evaluates the best 5-card hand that can be made from cards
+// under the given Ace rule. Fewer than 5 cards evaluate as an empty HighCard.
+func BestPokerHand(cards []Card, aceLow bool) PokerHand {
+	if len(cards) <= 5 {
+		return EvaluatePokerHandOrdered(cards, aceLow)
+	}
+
+	var best PokerHand
+	found := false
+	combo := make([]Card, 5)
+	n := len(cards)
+	// Walk all 5-card index combinations a<b<c<d<e
+	for a := 0; a < n-4; a++ {
+		for b := a + 1; b < n-3; b++ {
+			for c := b + 1; c < n-2; c++ {
+				for d := c + 1; d < n-1; d++ {
+					for e := d + 1; e < n; e++ {
+						combo[0], combo[1], combo[2], combo[3], combo[4] = cards[a], cards[b], cards[c], cards[d], cards[e]
+						hand := EvaluatePokerHandOrdered(combo, aceLow)
+						if !found || ComparePokerHands(hand, best) > 0 {
+							best = hand
+							found = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return best
+}
+
+// PokerWinnerIDs converts FindBestPokerWinner's result to the player IDs AwardPot expects
+func PokerWinnerIDs(winners []int8) []int {
+	ids := make([]int, len(winners))
+	for i, w := range winners {
+		ids[i] = int(w)
+	}
+	return ids
 }
@@ -0,0 +1,308 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// acesHighHand returns A-Q-9-6-3 (no flush) using the given suit rotation
+func acesHighHand(suitOffset uint8) []Card {
+	ranks := []uint8{12, 10, 7, 4, 1}
+	hand := make([]Card, len(ranks))
+	for i, r := range ranks {
+		hand[i] = Card{Rank: r, Suit: (uint8(i) + suitOffset) % 4}
+	}
+	return hand
+}
+
+func TestFindBestPokerWinner_SingleWinner(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 2
+
+	gs.Players[0].Hand = acesHighHand(0)
+	// Pair of twos beats ace high
+	gs.Players[1].Hand = []Card{{0, 0}, {0, 1}, {5, 2}, {8, 3}, {11, 0}}
+
+	winners := FindBestPokerWinner(gs, 2)
+	if !reflect.DeepEqual(winners, []int8{1}) {
+		t.Errorf("Expected winners [1], got %v", winners)
+	}
+}
+
+func TestFindBestPokerWinner_TwoWayChop(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 3
+
+	gs.Players[0].Hand = acesHighHand(0)
+	gs.Players[1].Hand = []Card{{11, 0}, {10, 1}, {7, 2}, {4, 3}, {1, 0}} // King high loses
+	gs.Players[2].Hand = acesHighHand(1)
+
+	winners := FindBestPokerWinner(gs, 3)
+	if !reflect.DeepEqual(winners, []int8{0, 2}) {
+		t.Fatalf("Expected winners [0 2], got %v", winners)
+	}
+
+	gs.Players[0].Chips = 0
+	gs.Players[2].Chips = 0
+	gs.Pot = 100
+	AwardPot(gs, nil, PokerWinnerIDs(winners))
+	if gs.Players[0].Chips != 50 || gs.Players[2].Chips != 50 {
+		t.Errorf("Expected 50/50 split, got %d/%d", gs.Players[0].Chips, gs.Players[2].Chips)
+	}
+}
+
+func TestBestHandChopIsDraw(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 3
+	gs.TurnNumber = 6
+	genome := &Genome{
+		Header:        &BytecodeHeader{PlayerCount: 3},
+		WinConditions: []WinCondition{{WinType: WinTypeBestHand}},
+	}
+
+	gs.Players[0].Hand = acesHighHand(0)
+	gs.Players[1].Hand = []Card{{11, 0}, {10, 1}, {7, 2}, {4, 3}, {1, 0}} // King high loses
+	gs.Players[2].Hand = acesHighHand(1)
+
+	if winner := CheckWinConditions(gs, genome); winner != -1 {
+		t.Errorf("Expected a chopped showdown to have no winner, got %d", winner)
+	}
+	if result := CheckGameOutcome(gs, genome); result.Reason != EndReasonShowdown {
+		t.Errorf("Expected the chop to end the game at showdown, got %v", result.Reason)
+	}
+
+	// A single best hand still wins
+	gs.Players[2].Hand = []Card{{0, 0}, {0, 1}, {5, 2}, {8, 3}, {11, 0}} // Pair of twos
+	if winner := CheckWinConditions(gs, genome); winner != 2 {
+		t.Errorf("Expected player 2 to win the showdown, got %d", winner)
+	}
+}
+
+func TestFindBestPokerWinner_ThreeWayChop(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 3
+
+	gs.Players[0].Hand = acesHighHand(0)
+	gs.Players[1].Hand = acesHighHand(1)
+	gs.Players[2].Hand = acesHighHand(2)
+
+	winners := FindBestPokerWinner(gs, 3)
+	if !reflect.DeepEqual(winners, []int8{0, 1, 2}) {
+		t.Fatalf("Expected winners [0 1 2], got %v", winners)
+	}
+
+	gs.Pot = 90
+	AwardPot(gs, nil, PokerWinnerIDs(winners))
+	for i := 0; i < 3; i++ {
+		if gs.Players[i].Chips != 30 {
+			t.Errorf("Expected player %d to have 30 chips, got %d", i, gs.Players[i].Chips)
+		}
+	}
+}
+
+func TestFindBestPokerWinner_IgnoresFolded(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 3
+
+	gs.Players[0].Hand = acesHighHand(0)
+	gs.Players[1].Hand = []Card{{0, 0}, {0, 1}, {5, 2}, {8, 3}, {11, 0}}
+	gs.Players[2].Hand = acesHighHand(1)
+	gs.Players[1].HasFolded = true // Best hand, but out of the pot
+
+	winners := FindBestPokerWinner(gs, 3)
+	if !reflect.DeepEqual(winners, []int8{0, 2}) {
+		t.Errorf("Expected winners [0 2], got %v", winners)
+	}
+}
+
+func TestFindBestPokerWinner_UsesCommunity(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 2
+
+	// Hole cards alone are too few; the board makes them playable
+	gs.Players[0].Hand = []Card{{12, 0}, {12, 1}} // Pocket aces
+	gs.Players[1].Hand = []Card{{11, 2}, {10, 3}}
+	gs.Community = []Card{{0, 0}, {3, 1}, {5, 2}, {7, 3}, {9, 0}}
+
+	winners, skipped := PokerShowdown(gs, 2)
+	if !reflect.DeepEqual(winners, []int8{0}) || len(skipped) != 0 {
+		t.Errorf("Expected winners [0] and none skipped, got %v and %v", winners, skipped)
+	}
+}
+
+func TestFindBestPokerWinner_NoFiveCardHands(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 2
+
+	if winners := FindBestPokerWinner(gs, 2); len(winners) != 0 {
+		t.Errorf("Expected no winners, got %v", winners)
+	}
+}
+
+func TestPokerShowdown_FourCardHandSkipped(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 2
+
+	gs.Players[0].Hand = acesHighHand(0)
+	// Four cards holding quads still can't make a 5-card hand
+	gs.Players[1].Hand = []Card{{9, 0}, {9, 1}, {9, 2}, {9, 3}}
+
+	winners, skipped := PokerShowdown(gs, 2)
+	if !reflect.DeepEqual(winners, []int8{0}) {
+		t.Errorf("Expected winners [0], got %v", winners)
+	}
+	if !reflect.DeepEqual(skipped, []int8{1}) {
+		t.Errorf("Expected skipped [1], got %v", skipped)
+	}
+}
+
+func TestPokerShowdown_SixCardHandPlaysBestFive(t *testing.T) {
+	gs := GetState()
+	defer PutState(gs)
+	gs.NumPlayers = 2
+
+	// Pair of twos
+	gs.Players[0].Hand = []Card{{0, 0}, {0, 1}, {5, 2}, {8, 3}, {11, 0}}
+	// Six cards: 5-6-7-8-9 straight plus a stray Ace
+	gs.Players[1].Hand = []Card{{3, 0}, {4, 1}, {5, 2}, {6, 3}, {7, 0}, {12, 1}}
+
+	winners, skipped := PokerShowdown(gs, 2)
+	if !reflect.DeepEqual(winners, []int8{1}) {
+		t.Errorf("Expected winners [1], got %v", winners)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("Expected no skipped players, got %v", skipped)
+	}
+	if best := BestPokerHand(gs.Players[1].Hand, false); best.Rank != Straight {
+		t.Errorf("Expected best hand to be a straight, got %v", best.Rank)
+	}
+}
+
+func TestEvaluatePokerHand_KickersGroupedByCount(t *testing.T) {
+	// 9-9-9-2-2: trip rank first, then pair rank
+	fullHouse := EvaluatePokerHand([]Card{{0, 0}, {7, 0}, {7, 1}, {0, 1}, {7, 2}})
+	if !reflect.DeepEqual(fullHouse.Kickers, []uint8{7, 7, 7, 0, 0}) {
+		t.Errorf("Expected kickers [7 7 7 0 0], got %v", fullHouse.Kickers)
+	}
+
+	// K-4-4-4-4: quad rank before the side card
+	quads := EvaluatePokerHand([]Card{{11, 0}, {2, 0}, {2, 1}, {2, 2}, {2, 3}})
+	if !reflect.DeepEqual(quads.Kickers, []uint8{2, 2, 2, 2, 11}) {
+		t.Errorf("Expected kickers [2 2 2 2 11], got %v", quads.Kickers)
+	}
+}
+
+func TestComparePokerHands_FullHouseOverFullHouse(t *testing.T) {
+	// 9-9-9-2-2 beats 8-8-8-A-A: the trips decide, not the higher pair
+	ninesFull := EvaluatePokerHand([]Card{{7, 0}, {7, 1}, {7, 2}, {0, 0}, {0, 1}})
+	eightsFull := EvaluatePokerHand([]Card{{6, 0}, {6, 1}, {6, 2}, {12, 0}, {12, 1}})
+
+	if ninesFull.Rank != FullHouse || eightsFull.Rank != FullHouse {
+		t.Fatalf("Expected two full houses, got %v and %v", ninesFull.Rank, eightsFull.Rank)
+	}
+	if ComparePokerHands(ninesFull, eightsFull) != 1 {
+		t.Error("Expected nines full to beat eights full")
+	}
+	if ComparePokerHands(eightsFull, ninesFull) != -1 {
+		t.Error("Expected eights full to lose to nines full")
+	}
+}
+
+func TestComparePokerHands_TripsWithDifferentKickers(t *testing.T) {
+	// 5-5-5-A-3 vs 5-5-5-K-Q: same trips, ace kicker wins
+	aceKicker := EvaluatePokerHand([]Card{{3, 0}, {3, 1}, {3, 2}, {12, 0}, {1, 1}})
+	kingKicker := EvaluatePokerHand([]Card{{3, 0}, {3, 1}, {3, 3}, {11, 2}, {10, 1}})
+	if ComparePokerHands(aceKicker, kingKicker) != 1 {
+		t.Error("Expected trips with ace kicker to win")
+	}
+
+	// 6-6-6-2-3 beats 5-5-5-A-K despite lower side cards
+	sixes := EvaluatePokerHand([]Card{{4, 0}, {4, 1}, {4, 2}, {0, 0}, {1, 1}})
+	fives := EvaluatePokerHand([]Card{{3, 0}, {3, 1}, {3, 2}, {12, 0}, {11, 1}})
+	if ComparePokerHands(sixes, fives) != 1 {
+		t.Error("Expected trip sixes to beat trip fives")
+	}
+}
+
+func TestComparePokerHands_QuadsKicker(t *testing.T) {
+	// 4-4-4-4-K beats 4-4-4-4-Q (only possible with multiple decks)
+	kingKicker := EvaluatePokerHand([]Card{{2, 0}, {2, 1}, {2, 2}, {2, 3}, {11, 0}})
+	queenKicker := EvaluatePokerHand([]Card{{2, 0}, {2, 1}, {2, 2}, {2, 3}, {10, 0}})
+	if ComparePokerHands(kingKicker, queenKicker) != 1 {
+		t.Error("Expected quads with king kicker to win")
+	}
+
+	// 3-3-3-3-2 beats 2-2-2-2-A: the quad rank outranks the kicker
+	deuces := EvaluatePokerHand([]Card{{0, 0}, {0, 1}, {0, 2}, {0, 3}, {12, 0}})
+	threes := EvaluatePokerHand([]Card{{1, 0}, {1, 1}, {1, 2}, {1, 3}, {0, 0}})
+	if ComparePokerHands(threes, deuces) != 1 {
+		t.Error("Expected quad threes to beat quad deuces with ace kicker")
+	}
+}
+
+func TestEvaluatePokerHand_NearStraightWithPair(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []Card
+		want  HandRank
+	}{
+		// 5-5-6-7-8
+		{"pair below run", []Card{{3, 0}, {3, 1}, {4, 2}, {5, 3}, {6, 0}}, OnePair},
+		// 5-6-7-8-8
+		{"pair above run", []Card{{3, 0}, {4, 1}, {5, 2}, {6, 3}, {6, 0}}, OnePair},
+		// A-2-3-4-4 (near wheel)
+		{"near wheel with pair", []Card{{12, 0}, {0, 1}, {1, 2}, {2, 3}, {2, 0}}, OnePair},
+		// 5-5-6-7-8 suited (multiple decks): flush, never a straight flush
+		{"near straight flush with pair", []Card{{3, 0}, {3, 0}, {4, 0}, {5, 0}, {6, 0}}, Flush},
+		// 5-6-7-8-9 sanity check
+		{"real straight", []Card{{3, 0}, {4, 1}, {5, 2}, {6, 3}, {7, 0}}, Straight},
+		// A-2-3-4-5 sanity check
+		{"wheel", []Card{{12, 0}, {0, 1}, {1, 2}, {2, 3}, {3, 0}}, Straight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluatePokerHand(tt.cards)
+			if got.Rank != tt.want {
+				t.Errorf("Expected rank %d, got %d", tt.want, got.Rank)
+			}
+		})
+	}
+}
+
+func TestEvaluatePokerHand_JokersWild(t *testing.T) {
+	tests := []struct {
+		name string
+		hand []Card
+		want HandRank
+	}{
+		{"joker completes a royal flush", []Card{{12, 0}, {11, 0}, {10, 0}, {9, 0}, Joker(0)}, RoyalFlush},
+		{"joker makes trips from a pair", []Card{{11, 0}, {11, 1}, {2, 2}, {5, 3}, Joker(1)}, ThreeOfAKind},
+		{"two jokers make trips", []Card{{0, 2}, {5, 1}, {7, 3}, Joker(0), Joker(1)}, ThreeOfAKind},
+		{"no fifth ace for four aces", []Card{{12, 0}, {12, 1}, {12, 2}, {12, 3}, Joker(0)}, FourOfAKind},
+	}
+	for _, tt := range tests {
+		if got := EvaluatePokerHand(tt.hand); got.Rank != tt.want {
+			t.Errorf("%s: expected rank %d, got %+v", tt.name, tt.want, got)
+		}
+	}
+
+	// Four aces with a joker play the king as kicker
+	if got := EvaluatePokerHand(tests[3].hand); !reflect.DeepEqual(got.Kickers, []uint8{12, 12, 12, 12, 11}) {
+		t.Errorf("Expected a king kicker, got %v", got.Kickers)
+	}
+
+	// From 7 cards, the joker fills the best straight
+	seven := []Card{{3, 0}, {4, 1}, {5, 2}, {7, 3}, {0, 0}, {0, 1}, Joker(0)}
+	if got := BestPokerHand(seven, false); got.Rank != Straight || got.Kickers[0] != 7 {
+		t.Errorf("Expected a 9-high straight, got %+v", got)
+	}
+}
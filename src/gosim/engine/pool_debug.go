@@ -0,0 +1,30 @@
+//go:build pooldebug
+
+package engine
+
+import "sync"
+
+// Debug builds (go test -tags pooldebug) track which states are sitting in
+// StatePool so a second PutState of the same state panics at the faulty call
+// site instead of surfacing later as two owners aliasing one state.
+var (
+	pooledMu     sync.Mutex
+	pooledStates = make(map[*GameState]struct{})
+)
+
+// trackGet records that a state has left the pool
+func trackGet(state *GameState) {
+	pooledMu.Lock()
+	delete(pooledStates, state)
+	pooledMu.Unlock()
+}
+
+// trackPut records that a state is back in the pool, panicking on double-Put
+func trackPut(state *GameState) {
+	pooledMu.Lock()
+	defer pooledMu.Unlock()
+	if _, ok := pooledStates[state]; ok {
+		panic("engine: GameState returned to StatePool twice")
+	}
+	pooledStates[state] = struct{}{}
+}
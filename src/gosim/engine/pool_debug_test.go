@@ -0,0 +1,17 @@
+//go:build pooldebug
+
+package engine
+
+import "testing"
+
+func TestPutState_DoublePutPanics(t *testing.T) {
+	s := GetState()
+	PutState(s)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on double PutState")
+		}
+	}()
+	PutState(s)
+}
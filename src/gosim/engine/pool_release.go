@@ -0,0 +1,9 @@
+//go:build !pooldebug
+
+package engine
+
+// Release builds skip pool ownership tracking; see pool_debug.go
+
+func trackGet(state *GameState) {}
+
+func trackPut(state *GameState) {}
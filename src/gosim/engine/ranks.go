@@ -0,0 +1,139 @@
+package engine
+
+// Rank ordering
+//
+// Card.Rank is 0-12 with 0=2, 1=3, ..., 9=J, 10=Q, 11=K, 12=A, so raw ranks
+// order the Ace highest. Games that play the Ace low (A-2-3 runs, an Ace
+// losing to a 2) set GameState.AceLow; every rank comparison, sequence and
+// straight check goes through RankValue so both orderings behave uniformly.
+
+// AceRank is the Card.Rank of an Ace
+const AceRank uint8 = 12
+
+// RankValue returns the ordering value of rank, 0 (lowest) to 12 (highest).
+// Ace-high keeps the raw rank; ace-low moves the Ace below the 2.
+func RankValue(rank uint8, aceLow bool) uint8 {
+	if aceLow {
+		return (rank + 1) % 13
+	}
+	return rank
+}
+
+// RankValue returns the ordering value of rank under this game's Ace rule
+func (s *GameState) RankValue(rank uint8) uint8 {
+	return RankValue(rank, s.AceLow)
+}
+
+// CompareCards orders a against b, returning -1, 0 or 1. Ranks compare by
+// raw Card.Rank and suits by suitOrder[suit], higher first. With rankFirst a
+// suit only breaks a rank tie (Big Two); otherwise the suit decides and the
+// rank breaks a suit tie (bridge bidding). An all-zero suitOrder compares
+// ranks alone. Jokers beat every other card and compare by joker number.
+func CompareCards(a, b Card, rankFirst bool, suitOrder [4]uint8) int {
+	if a.IsJoker() || b.IsJoker() {
+		return compareJokers(a, b)
+	}
+	rankCmp := compareUint8(a.Rank, b.Rank)
+	suitCmp := compareUint8(suitPrecedence(a.Suit, suitOrder), suitPrecedence(b.Suit, suitOrder))
+	if !rankFirst {
+		rankCmp, suitCmp = suitCmp, rankCmp
+	}
+	if rankCmp != 0 {
+		return rankCmp
+	}
+	return suitCmp
+}
+
+// CompareCards orders a against b under this game's Ace rule and suit order,
+// suits only breaking rank ties
+func (s *GameState) CompareCards(a, b Card) int {
+	if a.IsJoker() || b.IsJoker() {
+		return compareJokers(a, b)
+	}
+	return CompareCards(Card{Rank: s.RankValue(a.Rank), Suit: a.Suit}, Card{Rank: s.RankValue(b.Rank), Suit: b.Suit}, true, s.SuitOrder)
+}
+
+// compareJokers orders a against b when at least one is a joker
+func compareJokers(a, b Card) int {
+	switch {
+	case !a.IsJoker():
+		return -1
+	case !b.IsJoker():
+		return 1
+	}
+	return compareUint8(a.Rank, b.Rank)
+}
+
+// suitPrecedence returns suit's entry in suitOrder, 0 for an invalid suit
+func suitPrecedence(suit uint8, suitOrder [4]uint8) uint8 {
+	if suit > 3 {
+		return 0
+	}
+	return suitOrder[suit]
+}
+
+// compareUint8 returns -1, 0 or 1 as a is below, equal to or above b
+func compareUint8(a, b uint8) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// CardBeats reports whether candidate ranks strictly above reference, as a
+// climbing game (President, Daifugo) requires. With aceHigh false the Ace
+// ranks below the 2.
+func CardBeats(candidate, reference Card, aceHigh bool) bool {
+	return RankValue(candidate.Rank, !aceHigh) > RankValue(reference.Rank, !aceHigh)
+}
+
+// SetBeats reports whether candidate, a set of cards of one rank, beats
+// reference: a set of the same size and a strictly higher rank
+func SetBeats(candidate, reference []Card, aceHigh bool) bool {
+	if len(candidate) == 0 || len(candidate) != len(reference) {
+		return false
+	}
+	if !sameRank(candidate) || !sameRank(reference) {
+		return false
+	}
+	return CardBeats(candidate[0], reference[0], aceHigh)
+}
+
+// sameRank reports whether every card in cards has the same rank
+func sameRank(cards []Card) bool {
+	for _, card := range cards[1:] {
+		if card.Rank != cards[0].Rank {
+			return false
+		}
+	}
+	return true
+}
+
+// highestCard returns the card in cards that ranks highest under the game's
+// Ace rule and suit order
+func (s *GameState) highestCard(cards []Card) Card {
+	best := cards[0]
+	for _, card := range cards[1:] {
+		if s.CompareCards(card, best) > 0 {
+			best = card
+		}
+	}
+	return best
+}
+
+// topSet returns the run of equal-rank cards on top of pile. In a climbing
+// game each play must strictly beat the last, so that run is the last set
+// played.
+func topSet(pile []Card) []Card {
+	if len(pile) == 0 {
+		return nil
+	}
+	start := len(pile) - 1
+	for start > 0 && pile[start-1].Rank == pile[len(pile)-1].Rank {
+		start--
+	}
+	return pile[start:]
+}
@@ -0,0 +1,237 @@
+package engine
+
+import "testing"
+
+func TestRankValue(t *testing.T) {
+	if RankValue(AceRank, false) != 12 || RankValue(0, false) != 0 {
+		t.Error("Expected ace-high to keep raw ranks")
+	}
+	if RankValue(AceRank, true) != 0 {
+		t.Errorf("Expected ace-low Ace to be lowest, got %d", RankValue(AceRank, true))
+	}
+	if RankValue(0, true) != 1 || RankValue(11, true) != 12 {
+		t.Error("Expected ace-low 2 to follow the Ace and K to be highest")
+	}
+}
+
+func TestHasRunOfN_AceOrdering(t *testing.T) {
+	state := GetStateN(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	// A-2-3 of mixed suits
+	state.Players[0].Hand = []Card{{Rank: AceRank, Suit: 0}, {Rank: 0, Suit: 1}, {Rank: 1, Suit: 2}}
+	cond := []byte{byte(OpCheckHasRunOfN), 0, 0, 0, 0, 3, 0}
+
+	if EvaluateCondition(state, 0, cond) {
+		t.Error("Expected A-2-3 not to be a run when Ace is high")
+	}
+	state.AceLow = true
+	if !EvaluateCondition(state, 0, cond) {
+		t.Error("Expected A-2-3 to be a run when Ace is low")
+	}
+
+	// J-Q-K-A is a run only when Ace is high
+	state.Players[0].Hand = []Card{{Rank: 9, Suit: 0}, {Rank: 10, Suit: 1}, {Rank: 11, Suit: 2}, {Rank: AceRank, Suit: 3}}
+	cond[5] = 4
+	if EvaluateCondition(state, 0, cond) {
+		t.Error("Expected J-Q-K-A not to be a run when Ace is low")
+	}
+	state.AceLow = false
+	if !EvaluateCondition(state, 0, cond) {
+		t.Error("Expected J-Q-K-A to be a run when Ace is high")
+	}
+}
+
+func TestResolveWarBattle_AceOrdering(t *testing.T) {
+	for _, tt := range []struct {
+		aceLow bool
+		winner int
+	}{
+		{aceLow: false, winner: 0}, // Ace beats 2
+		{aceLow: true, winner: 1},  // 2 beats Ace
+	} {
+		state := GetStateN(2)
+		state.NumPlayers = 2
+		state.AceLow = tt.aceLow
+		state.Tableau = [][]Card{{{Rank: AceRank, Suit: 0}, {Rank: 0, Suit: 1}}}
+
+		resolveWarBattle(state)
+		if len(state.Players[tt.winner].Hand) != 2 {
+			t.Errorf("aceLow=%v: expected player %d to take the battle", tt.aceLow, tt.winner)
+		}
+		PutState(state)
+	}
+}
+
+func TestEvaluatePokerHandOrdered_AceLow(t *testing.T) {
+	wheel := []Card{{Rank: AceRank, Suit: 0}, {Rank: 0, Suit: 1}, {Rank: 1, Suit: 2}, {Rank: 2, Suit: 3}, {Rank: 3, Suit: 0}}
+	broadway := []Card{{Rank: AceRank, Suit: 0}, {Rank: 11, Suit: 1}, {Rank: 10, Suit: 2}, {Rank: 9, Suit: 3}, {Rank: 8, Suit: 0}}
+
+	if hand := EvaluatePokerHandOrdered(wheel, true); hand.Rank != Straight || hand.Kickers[0] != 4 {
+		t.Errorf("Expected A-2-3-4-5 to be a 5-high straight when Ace is low, got %v", hand)
+	}
+	if hand := EvaluatePokerHandOrdered(broadway, true); hand.Rank != HighCard {
+		t.Errorf("Expected 10-J-Q-K-A not to be a straight when Ace is low, got %v", hand.Rank)
+	}
+	if hand := EvaluatePokerHandOrdered(broadway, false); hand.Rank != Straight {
+		t.Errorf("Expected 10-J-Q-K-A to be a straight when Ace is high, got %v", hand.Rank)
+	}
+
+	// Ace-low high card: K-high beats A-high
+	aceHigh := []Card{{Rank: AceRank, Suit: 0}, {Rank: 7, Suit: 1}, {Rank: 5, Suit: 2}, {Rank: 3, Suit: 3}, {Rank: 1, Suit: 0}}
+	kingHigh := []Card{{Rank: 11, Suit: 0}, {Rank: 7, Suit: 2}, {Rank: 5, Suit: 3}, {Rank: 3, Suit: 1}, {Rank: 1, Suit: 1}}
+	if ComparePokerHands(EvaluatePokerHandOrdered(kingHigh, true), EvaluatePokerHandOrdered(aceHigh, true)) <= 0 {
+		t.Error("Expected K-high to beat A-high when Ace is low")
+	}
+	if ComparePokerHands(EvaluatePokerHand(kingHigh), EvaluatePokerHand(aceHigh)) >= 0 {
+		t.Error("Expected A-high to beat K-high when Ace is high")
+	}
+}
+
+func TestCardBeats(t *testing.T) {
+	seven, eight, ace := Card{Rank: 5, Suit: 0}, Card{Rank: 6, Suit: 1}, Card{Rank: AceRank, Suit: 2}
+	if !CardBeats(eight, seven, true) || CardBeats(seven, eight, true) {
+		t.Error("Expected 8 to beat 7 and not the reverse")
+	}
+	if CardBeats(seven, Card{Rank: 5, Suit: 3}, true) {
+		t.Error("Expected an equal rank not to beat")
+	}
+	if !CardBeats(ace, eight, true) || CardBeats(ace, eight, false) {
+		t.Error("Expected the Ace to beat 8 only when Ace is high")
+	}
+}
+
+func TestSetBeats(t *testing.T) {
+	sevens := []Card{{Rank: 5, Suit: 0}, {Rank: 5, Suit: 1}}
+	nines := []Card{{Rank: 7, Suit: 2}, {Rank: 7, Suit: 3}}
+	if !SetBeats(nines, sevens, true) || SetBeats(sevens, nines, true) {
+		t.Error("Expected a pair of 9s to beat a pair of 7s and not the reverse")
+	}
+	if SetBeats(nines[:1], sevens, true) {
+		t.Error("Expected a single card not to beat a pair")
+	}
+	if SetBeats([]Card{{Rank: 7, Suit: 2}, {Rank: 8, Suit: 3}}, sevens, true) {
+		t.Error("Expected mixed ranks not to form a set")
+	}
+}
+
+// beatsTopGenome returns a President-style play phase onto the tableau
+// for minCards..maxCards cards of a rank that must beat the last play
+func beatsTopGenome(minCards, maxCards uint8) *Genome {
+	data := []byte{byte(LocationTableau), minCards, maxCards, 1, 1, 0, 0, 0, 7}
+	data = append(data, byte(OpCheckCardBeatsTop), 0, 0, 0, 0, 0, 3)
+	return &Genome{TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypePlay, Data: data}}}
+}
+
+func TestBeatsTopSingleCard(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}, {Rank: 8, Suit: 1}, {Rank: 8, Suit: 2}}
+	state.Tableau = [][]Card{{{Rank: 2, Suit: 3}, {Rank: 8, Suit: 0}}}
+
+	// Matching the 10 on top is not enough: only a pass
+	moves := GenerateLegalMoves(state, beatsTopGenome(1, 1))
+	if len(moves) != 1 || moves[0].CardIndex != MovePlayPass {
+		t.Fatalf("Expected only a pass against a 10, got %v", moves)
+	}
+
+	state.Tableau[0][1] = Card{Rank: 6, Suit: 0} // An 8 on top
+	moves = GenerateLegalMoves(state, beatsTopGenome(1, 1))
+	if len(moves) != 2 || moves[0].CardIndex != 1 || moves[1].CardIndex != 2 {
+		t.Errorf("Expected both 10s to beat the 8, got %v", moves)
+	}
+}
+
+func TestBeatsTopPairBeatsPair(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	// A pair of 5s, a pair of Js and a lone King
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}, {Rank: 9, Suit: 1}, {Rank: 3, Suit: 2}, {Rank: 11, Suit: 0}, {Rank: 9, Suit: 3}}
+	// A pair of 7s was played last
+	state.Tableau = [][]Card{{{Rank: 2, Suit: 0}, {Rank: 5, Suit: 1}, {Rank: 5, Suit: 3}}}
+
+	moves := GenerateLegalMoves(state, beatsTopGenome(2, 2))
+	if len(moves) != 1 || moves[0].CardIndex != -9-100 {
+		t.Fatalf("Expected only the pair of Js to beat the 7s, got %v", moves)
+	}
+
+	ApplyMove(state, &moves[0], beatsTopGenome(2, 2))
+	if got := topSet(state.Tableau[0]); len(got) != 2 || got[0].Rank != 9 {
+		t.Errorf("Expected the Js on top of the pile, got %v", got)
+	}
+
+	// The King alone can't answer a pair
+	state.CurrentPlayer = 0
+	state.Players[0].Hand = []Card{{Rank: 11, Suit: 0}}
+	if moves := GenerateLegalMoves(state, beatsTopGenome(1, 2)); len(moves) != 1 || moves[0].CardIndex != MovePlayPass {
+		t.Errorf("Expected only a pass with a single card against a pair, got %v", moves)
+	}
+}
+
+func TestCompareCards(t *testing.T) {
+	sevenHearts, sevenSpades := Card{Rank: 5, Suit: 0}, Card{Rank: 5, Suit: 3}
+	eightClubs := Card{Rank: 6, Suit: 2}
+
+	// Rank only by default
+	if CompareCards(sevenHearts, sevenSpades, true, [4]uint8{}) != 0 {
+		t.Error("Expected equal ranks to tie without a suit order")
+	}
+	if CompareCards(eightClubs, sevenSpades, true, [4]uint8{}) != 1 {
+		t.Error("Expected the 8 to beat the 7")
+	}
+
+	// Big Two order: diamonds < clubs < hearts < spades
+	bigTwo := [4]uint8{2, 0, 1, 3}
+	if CompareCards(sevenSpades, sevenHearts, true, bigTwo) != 1 || CompareCards(sevenHearts, sevenSpades, true, bigTwo) != -1 {
+		t.Error("Expected spades to break the tie over hearts")
+	}
+	if CompareCards(sevenSpades, eightClubs, true, bigTwo) != -1 {
+		t.Error("Expected rank to decide before suit")
+	}
+
+	// Suit first, as in bridge bidding
+	if CompareCards(sevenSpades, eightClubs, false, bigTwo) != 1 {
+		t.Error("Expected suit to decide before rank")
+	}
+}
+
+func TestBeatsTopSuitBreaksTie(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 5, Suit: 1}, {Rank: 5, Suit: 3}}
+	state.Tableau = [][]Card{{{Rank: 5, Suit: 0}}} // 7 of hearts on top
+
+	// Rank only: another 7 can't beat it
+	moves := GenerateLegalMoves(state, beatsTopGenome(1, 1))
+	if len(moves) != 1 || moves[0].CardIndex != MovePlayPass {
+		t.Fatalf("Expected only a pass without a suit order, got %v", moves)
+	}
+
+	// Diamonds < clubs < hearts < spades: only the 7 of spades beats it
+	state.SuitOrder = [4]uint8{2, 0, 1, 3}
+	moves = GenerateLegalMoves(state, beatsTopGenome(1, 1))
+	if len(moves) != 1 || moves[0].CardIndex != 1 {
+		t.Errorf("Expected the 7 of spades to beat the 7 of hearts, got %v", moves)
+	}
+}
+
+func TestCompareCardsJokers(t *testing.T) {
+	ace := Card{Rank: AceRank, Suit: 3}
+	if CompareCards(Joker(0), ace, true, [4]uint8{}) != 1 || CompareCards(ace, Joker(0), false, [4]uint8{1, 2, 3, 4}) != -1 {
+		t.Error("Expected a joker to beat the ace")
+	}
+	if CompareCards(Joker(1), Joker(0), true, [4]uint8{}) != 1 || CompareCards(Joker(0), Joker(0), true, [4]uint8{}) != 0 {
+		t.Error("Expected jokers to compare by number")
+	}
+
+	// Ace-low doesn't renumber jokers
+	state := NewGameState(2)
+	defer PutState(state)
+	state.AceLow = true
+	if state.CompareCards(Joker(0), Card{Rank: 11, Suit: 0}) != 1 || state.CompareCards(Joker(0), Joker(1)) != -1 {
+		t.Error("Expected jokers highest under ace-low")
+	}
+}
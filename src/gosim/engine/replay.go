@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Replays
+//
+// A Replay records a game as the seed it was set up with and the index of
+// each move taken in the GenerateLegalMoves order of its turn. Replaying it
+// against the same genome must reproduce the same game, so a stored replay
+// whose outcome changes flags a change in engine behavior.
+
+// Replay is a recorded game
+type Replay struct {
+	Seed       uint64 `json:"seed"`
+	GenomeHash uint64 `json:"genome_hash"` // GenomeHash of the genome played
+	Moves      []int  `json:"moves"`       // Legal move index chosen at each turn
+}
+
+// GenomeHash returns a 64-bit FNV-1a hash of the genome bytecode
+func GenomeHash(genome *Genome) uint64 {
+	h := fnv.New64a()
+	h.Write(genome.Bytecode)
+	return h.Sum64()
+}
+
+// RecordReplay plays genome from seed until the game ends or maxMoves moves
+// have been made, letting choose pick the index of each move, and returns
+// the recorded replay with the game's result
+func RecordReplay(genome *Genome, seed uint64, maxMoves int, choose func(moves []LegalMove) int) (Replay, GameResult) {
+	state := SetupGame(genome, seed)
+	defer PutState(state)
+
+	replay := Replay{Seed: seed, GenomeHash: GenomeHash(genome)}
+	result := CheckGameEnd(state, genome)
+	for !result.Over() && len(replay.Moves) < maxMoves {
+		moves := GenerateLegalMoves(state, genome)
+		index := choose(moves)
+		ApplyMove(state, &moves[index], genome)
+		replay.Moves = append(replay.Moves, index)
+		result = CheckGameEnd(state, genome)
+	}
+	return replay, result
+}
+
+// VerifyReplay replays the recorded moves against genome and returns the
+// result of the game. It fails if the replay was recorded for a different
+// genome, if a recorded index isn't a legal move at its turn, or if the game
+// ends before every recorded move has been made.
+func VerifyReplay(genome *Genome, replay Replay) (GameResult, error) {
+	if hash := GenomeHash(genome); hash != replay.GenomeHash {
+		return GameResult{Winner: -1}, fmt.Errorf("genome hash %016x does not match replay hash %016x", hash, replay.GenomeHash)
+	}
+
+	state := SetupGame(genome, replay.Seed)
+	defer PutState(state)
+
+	for i, index := range replay.Moves {
+		if result := CheckGameEnd(state, genome); result.Over() {
+			return result, fmt.Errorf("game ended (%s) at move %d of %d", result.Reason, i, len(replay.Moves))
+		}
+		moves := GenerateLegalMoves(state, genome)
+		if index < 0 || index >= len(moves) {
+			return GameResult{Winner: -1, Turn: state.TurnNumber}, fmt.Errorf("move %d: index %d is not legal (have %d moves)", i, index, len(moves))
+		}
+		ApplyMove(state, &moves[index], genome)
+	}
+	return CheckGameEnd(state, genome), nil
+}
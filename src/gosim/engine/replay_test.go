@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// recordWarReplay records a War game with random move choices
+func recordWarReplay(t *testing.T, genome *Genome, seed uint64) (Replay, GameResult) {
+	t.Helper()
+	rng := rand.New(rand.NewSource(int64(seed)))
+	replay, result := RecordReplay(genome, seed, 100000, func(moves []LegalMove) int {
+		return rng.Intn(len(moves))
+	})
+	if !result.Over() {
+		t.Fatalf("Expected recorded game to end, got %+v after %d moves", result, len(replay.Moves))
+	}
+	return replay, result
+}
+
+func TestVerifyReplayWar(t *testing.T) {
+	genome := loadGoldenGenome(t, "war_genome.bin")
+
+	for _, seed := range []uint64{1, 42, 1234} {
+		replay, recorded := recordWarReplay(t, genome, seed)
+
+		result, err := VerifyReplay(genome, replay)
+		if err != nil {
+			t.Fatalf("Seed %d: replay failed: %v", seed, err)
+		}
+		if result != recorded {
+			t.Errorf("Seed %d: expected replay result %+v, got %+v", seed, recorded, result)
+		}
+	}
+}
+
+func TestVerifyReplayRejects(t *testing.T) {
+	genome := loadGoldenGenome(t, "war_genome.bin")
+	replay, _ := recordWarReplay(t, genome, 7)
+
+	illegal := replay
+	illegal.Moves = append([]int{5}, replay.Moves[1:]...) // War offers a single move
+	if _, err := VerifyReplay(genome, illegal); err == nil || !strings.Contains(err.Error(), "not legal") {
+		t.Errorf("Expected illegal move error, got %v", err)
+	}
+
+	extra := replay
+	extra.Moves = append(append([]int{}, replay.Moves...), 0)
+	if _, err := VerifyReplay(genome, extra); err == nil || !strings.Contains(err.Error(), "game ended") {
+		t.Errorf("Expected error for moves after the game ended, got %v", err)
+	}
+
+	other := loadGoldenGenome(t, "hearts_genome.bin")
+	if _, err := VerifyReplay(other, replay); err == nil || !strings.Contains(err.Error(), "hash") {
+		t.Errorf("Expected genome hash mismatch, got %v", err)
+	}
+}
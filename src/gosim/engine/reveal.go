@@ -0,0 +1,75 @@
+package engine
+
+// Card visibility
+//
+// Hand cards are face-down (seen only by their owner) unless listed in
+// PlayerState.FaceUp. Visibility is tracked by card rather than by hand
+// position so it survives cards being drawn, played and reordered; a face-up
+// card that leaves the hand is simply ignored until the next Reset or redeal.
+
+// IsFaceUp returns true if card is visible to the player's opponents
+func (p *PlayerState) IsFaceUp(card Card) bool {
+	for _, c := range p.FaceUp {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
+
+// HiddenCount returns how many cards in the player's hand are still face-down
+func (p *PlayerState) HiddenCount() int {
+	hidden := 0
+	for _, card := range p.Hand {
+		if !p.IsFaceUp(card) {
+			hidden++
+		}
+	}
+	return hidden
+}
+
+// setFaceUp marks card as visible
+func (p *PlayerState) setFaceUp(card Card) {
+	if !p.IsFaceUp(card) {
+		p.FaceUp = append(p.FaceUp, card)
+	}
+}
+
+// ApplyReveal turns all of a player's face-down hand cards face-up, in hand
+// order, and returns how many were flipped. Reveal phases call it for each
+// player in turn order so hands are shown in a defined order before showdown.
+func ApplyReveal(state *GameState, playerID int) int {
+	if playerID < 0 || playerID >= len(state.Players) {
+		return 0
+	}
+	player := &state.Players[playerID]
+
+	// Drop stale entries for cards no longer in hand
+	faceUp := player.FaceUp[:0]
+	for _, card := range player.FaceUp {
+		if handContains(player.Hand, card) {
+			faceUp = append(faceUp, card)
+		}
+	}
+	player.FaceUp = faceUp
+
+	flipped := 0
+	for _, card := range player.Hand {
+		if !player.IsFaceUp(card) {
+			player.FaceUp = append(player.FaceUp, card)
+			state.MarkSeen(card)
+			flipped++
+		}
+	}
+	return flipped
+}
+
+// handContains returns true if card is in hand
+func handContains(hand []Card, card Card) bool {
+	for _, c := range hand {
+		if c == card {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package engine
+
+import "testing"
+
+func TestDeal_StudMarksFaceUpCards(t *testing.T) {
+	state := newDealState(2)
+	defer PutState(state)
+	pattern := &DealPattern{Stages: []DealStage{{FaceDown: 2, FaceUp: 1}}}
+
+	if !Deal(state, pattern, 0) {
+		t.Fatal("Expected deal to succeed")
+	}
+	for p := 0; p < 2; p++ {
+		player := &state.Players[p]
+		if got := player.HiddenCount(); got != 2 {
+			t.Errorf("Player %d: expected 2 hidden cards, got %d", p, got)
+		}
+		if !player.IsFaceUp(player.Hand[2]) {
+			t.Errorf("Player %d: expected door card to be face-up", p)
+		}
+		if player.IsFaceUp(player.Hand[0]) || player.IsFaceUp(player.Hand[1]) {
+			t.Errorf("Player %d: expected hole cards to be face-down", p)
+		}
+	}
+}
+
+func TestApplyReveal(t *testing.T) {
+	state := NewGameState(2)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 12, Suit: 0}, {Rank: 3, Suit: 1}, {Rank: 7, Suit: 2}}
+	state.Players[0].FaceUp = []Card{{Rank: 7, Suit: 2}}
+	state.Players[1].Hand = []Card{{Rank: 5, Suit: 3}}
+
+	if got := ApplyReveal(state, 0); got != 2 {
+		t.Errorf("Expected 2 cards flipped, got %d", got)
+	}
+	for _, card := range state.Players[0].Hand {
+		if !state.Players[0].IsFaceUp(card) {
+			t.Errorf("Expected %v to be face-up after reveal", card)
+		}
+	}
+	if state.Players[0].HiddenCount() != 0 {
+		t.Errorf("Expected no hidden cards, got %d", state.Players[0].HiddenCount())
+	}
+
+	// Other players are untouched
+	if state.Players[1].HiddenCount() != 1 {
+		t.Errorf("Expected player 1 to keep 1 hidden card, got %d", state.Players[1].HiddenCount())
+	}
+
+	// Revealing again is a no-op
+	if got := ApplyReveal(state, 0); got != 0 {
+		t.Errorf("Expected second reveal to flip 0 cards, got %d", got)
+	}
+	if got := ApplyReveal(state, 5); got != 0 {
+		t.Errorf("Expected out-of-range player to flip 0 cards, got %d", got)
+	}
+}
+
+func TestApplyRevealDropsStaleFaceUp(t *testing.T) {
+	state := NewGameState(2)
+	state.Players[0].Hand = []Card{{Rank: 1, Suit: 0}}
+	state.Players[0].FaceUp = []Card{{Rank: 9, Suit: 3}} // Played earlier
+
+	ApplyReveal(state, 0)
+
+	if len(state.Players[0].FaceUp) != 1 || state.Players[0].FaceUp[0] != (Card{Rank: 1, Suit: 0}) {
+		t.Errorf("Expected only the held card to be face-up, got %v", state.Players[0].FaceUp)
+	}
+}
+
+func TestRevealPhaseMoves(t *testing.T) {
+	state := NewGameState(2)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 12, Suit: 0}, {Rank: 11, Suit: 0}}
+	state.Players[1].Hand = []Card{{Rank: 2, Suit: 1}, {Rank: 4, Suit: 2}}
+	genome := &Genome{
+		TurnPhases: []PhaseDescriptor{{PhaseType: PhaseTypeReveal}},
+	}
+
+	moves := GenerateLegalMoves(state, genome)
+	if len(moves) != 1 || moves[0].CardIndex != MoveReveal {
+		t.Fatalf("Expected a single reveal move, got %v", moves)
+	}
+
+	ApplyMove(state, &moves[0], genome)
+	if state.Players[0].HiddenCount() != 0 {
+		t.Errorf("Expected player 0 hand revealed, %d still hidden", state.Players[0].HiddenCount())
+	}
+	if state.CurrentPlayer != 1 {
+		t.Errorf("Expected turn to pass to player 1, got %d", state.CurrentPlayer)
+	}
+
+	// Folded players don't reveal
+	state.Players[1].HasFolded = true
+	if moves := GenerateLegalMoves(state, genome); len(moves) != 0 {
+		t.Errorf("Expected no reveal move for folded player, got %v", moves)
+	}
+
+	// Nothing left to reveal
+	state.CurrentPlayer = 0
+	if moves := GenerateLegalMoves(state, genome); len(moves) != 0 {
+		t.Errorf("Expected no reveal move for fully revealed hand, got %v", moves)
+	}
+}
+
+func TestFaceUpResetAndClone(t *testing.T) {
+	state := NewGameState(2)
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}}
+	ApplyReveal(state, 0)
+
+	clone := state.Clone()
+	if !clone.Players[0].IsFaceUp(Card{Rank: 3, Suit: 0}) {
+		t.Error("Expected clone to preserve face-up cards")
+	}
+	clone.Players[0].FaceUp[0] = Card{Rank: 4, Suit: 0}
+	if !state.Players[0].IsFaceUp(Card{Rank: 3, Suit: 0}) {
+		t.Error("Expected clone FaceUp to be independent of original")
+	}
+	PutState(clone)
+
+	state.Reset()
+	if len(state.Players[0].FaceUp) != 0 {
+		t.Errorf("Expected Reset to clear FaceUp, got %v", state.Players[0].FaceUp)
+	}
+}
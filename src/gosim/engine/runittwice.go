@@ -0,0 +1,74 @@
+package engine
+
+// Running it twice
+//
+// When the betting is over before the board is complete, the rest of the
+// deal can be run out on several boards instead of one, each dealt from the
+// remaining deck, with the pot divided evenly between the boards and each
+// board's share going to that board's showdown winners. The expected payout
+// is the same as one run-out, but the spread is smaller, which makes
+// simulated betting genomes less noisy to evaluate.
+
+// RunItTwice deals the streets of pattern still to come boards times and
+// splits the pot between the boards, each board's share going to the
+// winners of its showdown (see PokerShowdown and AwardPot). Boards beyond the
+// first take fresh cards from the deck; once a board has been shown down its
+// cards go to the discard pile and hands and community cards are back as
+// they were. The odd chip of the division goes to the first board. A board
+// nobody can show down for leaves its share in the pot. Returns the winners
+// of each board, or nil when there is nothing left to deal.
+func RunItTwice(state *GameState, pattern *DealPattern, boards int) [][]int8 {
+	if !HasMoreStreets(state, pattern) {
+		return nil
+	}
+	if boards < 1 {
+		boards = 1
+	}
+
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 || numPlayers > len(state.Players) {
+		numPlayers = len(state.Players)
+	}
+	handSizes := make([]int, numPlayers)
+	faceUpSizes := make([]int, numPlayers)
+	for p := 0; p < numPlayers; p++ {
+		handSizes[p] = len(state.Players[p].Hand)
+		faceUpSizes[p] = len(state.Players[p].FaceUp)
+	}
+	communitySize := len(state.Community)
+	firstRound := state.DealRound
+
+	results := make([][]int8, boards)
+	for board := range results {
+		for round := firstRound; round < len(pattern.Stages); round++ {
+			Deal(state, pattern, round)
+		}
+		winners, _ := PokerShowdown(state, numPlayers)
+		results[board] = winners
+
+		// Muck the board's cards
+		for p := 0; p < numPlayers; p++ {
+			player := &state.Players[p]
+			state.Discard = append(state.Discard, player.Hand[handSizes[p]:]...)
+			player.Hand = player.Hand[:handSizes[p]]
+			player.FaceUp = player.FaceUp[:faceUpSizes[p]]
+		}
+		state.Discard = append(state.Discard, state.Community[communitySize:]...)
+		state.Community = state.Community[:communitySize]
+	}
+
+	pot := state.Pot
+	share := pot / int64(boards)
+	for board, winners := range results {
+		if len(winners) == 0 {
+			continue
+		}
+		amount := share
+		if board == 0 {
+			amount += pot % int64(boards)
+		}
+		splitChips(state, amount, PokerWinnerIDs(winners))
+		state.Pot -= amount
+	}
+	return results
+}
@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRunItTwiceSplitsPotPerBoard(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 12, Suit: 3}, {Rank: 11, Suit: 3}} // A K of Spades
+	state.Players[1].Hand = []Card{{Rank: 5, Suit: 0}, {Rank: 5, Suit: 1}}   // Pair of 7s
+	state.Community = []Card{{Rank: 0, Suit: 2}, {Rank: 7, Suit: 1}, {Rank: 9, Suit: 0}}
+	state.DealRound = 2 // Flop is out
+	// Dealt from the top (end): the first board pairs the Ace, the second
+	// gives player 1 trips
+	state.Deck = []Card{{Rank: 2, Suit: 1}, {Rank: 5, Suit: 2}, {Rank: 1, Suit: 2}, {Rank: 12, Suit: 1}}
+	state.Pot = 101
+
+	winners := RunItTwice(state, HoldemDealPattern(), 2)
+	if want := [][]int8{{0}, {1}}; !reflect.DeepEqual(winners, want) {
+		t.Fatalf("Expected board winners %v, got %v", want, winners)
+	}
+	if state.Players[0].Chips != 51 || state.Players[1].Chips != 50 || state.Pot != 0 {
+		t.Errorf("Expected a 51/50 split of the pot, got %d/%d with %d left",
+			state.Players[0].Chips, state.Players[1].Chips, state.Pot)
+	}
+	if len(state.Community) != 3 || len(state.Discard) != 4 || len(state.Deck) != 0 || state.DealRound != 4 {
+		t.Errorf("Expected the flop kept and both boards mucked, got community %v, discard %v, deal round %d",
+			state.Community, state.Discard, state.DealRound)
+	}
+	if err := ValidateState(state); err != nil {
+		t.Errorf("Expected a valid state after the run-outs, got %v", err)
+	}
+
+	if RunItTwice(state, HoldemDealPattern(), 2) != nil {
+		t.Error("Expected nothing to run out once the board is complete")
+	}
+}
@@ -0,0 +1,83 @@
+package engine
+
+// Scopa scoring
+//
+// At the end of a Scopa hand each player scores one point per sweep, and
+// one point each for most cards captured, most coins (diamonds) captured,
+// the settebello (7 of diamonds) and the primiera. A tie for most cards,
+// most coins or primiera scores for nobody. Cards outside the 40-card
+// Italian deck (8, 9 and 10) count as court cards for the primiera.
+
+// Scopa card identities in the engine encoding
+const (
+	scopaCoins uint8 = 1 // Diamonds
+	scopaSeven uint8 = 5
+)
+
+// primieraValue is each rank's primiera value, indexed by engine rank
+// (2 through K, then A)
+var primieraValue = [13]int{12, 13, 14, 15, 18, 21, 10, 10, 10, 10, 10, 10, 16}
+
+// ScoreScopa returns each player's Scopa points for the hand from their
+// Captured piles and Sweeps. It does not change the players' scores.
+func ScoreScopa(state *GameState) []int32 {
+	n := int(state.NumPlayers)
+	if n > len(state.Players) {
+		n = len(state.Players)
+	}
+	points := make([]int32, n)
+	cards := make([]int, n)
+	coins := make([]int, n)
+	primiera := make([]int, n)
+
+	for i := 0; i < n; i++ {
+		player := &state.Players[i]
+		points[i] += int32(player.Sweeps)
+
+		var best [4]int
+		for _, card := range player.Captured {
+			if card.IsJoker() || card.Suit >= 4 || card.Rank > AceRank {
+				continue
+			}
+			cards[i]++
+			if card.Suit == scopaCoins {
+				coins[i]++
+				if card.Rank == scopaSeven {
+					points[i]++ // Settebello
+				}
+			}
+			if v := primieraValue[card.Rank]; v > best[card.Suit] {
+				best[card.Suit] = v
+			}
+		}
+		// The primiera needs a card of every suit
+		if best[0] > 0 && best[1] > 0 && best[2] > 0 && best[3] > 0 {
+			primiera[i] = best[0] + best[1] + best[2] + best[3]
+		}
+	}
+
+	for _, counts := range [][]int{cards, coins, primiera} {
+		if leader := soleLeader(counts); leader >= 0 {
+			points[leader]++
+		}
+	}
+	return points
+}
+
+// soleLeader returns the index of the only highest positive count, or -1
+// if the highest is tied or zero
+func soleLeader(counts []int) int {
+	leader, best, tied := -1, 0, false
+	for i, c := range counts {
+		switch {
+		case c > best:
+			leader, best, tied = i, c, false
+		case c == best && c > 0:
+			tied = true
+		}
+	}
+	if tied {
+		return -1
+	}
+	return leader
+}
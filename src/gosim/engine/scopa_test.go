@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScopaSweepAndSettebello(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.TableauMode = 2 // MATCH_RANK
+	state.Tableau = [][]Card{{{Rank: scopaSeven, Suit: scopaCoins}}}
+	state.Players[0].Hand = []Card{{Rank: scopaSeven, Suit: 0}}
+	state.Players[1].Hand = []Card{{Rank: 9, Suit: 2}}
+	genome := &Genome{
+		TurnPhases: []PhaseDescriptor{
+			{PhaseType: PhaseTypePlay, Data: []byte{byte(LocationTableau), 1, 1, 1, 0, 0, 0, 0, 0}},
+		},
+	}
+
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationTableau}, genome)
+	if state.Players[0].Sweeps != 1 {
+		t.Fatalf("Expected the capture clearing the tableau to be a sweep, got %d", state.Players[0].Sweeps)
+	}
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationTableau}, genome)
+	if state.Players[1].Sweeps != 0 {
+		t.Errorf("Expected no sweep without a capture, got %d", state.Players[1].Sweeps)
+	}
+
+	// Sweep, most cards, most coins and the settebello
+	if got := ScoreScopa(state); !reflect.DeepEqual(got, []int32{4, 0}) {
+		t.Errorf("Expected points [4 0], got %v", got)
+	}
+}
+
+func TestScopaPrimieraAndTies(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	// Player 0: 7s in three suits but no spade, so no primiera
+	state.Players[0].Captured = []Card{{Rank: 5, Suit: 0}, {Rank: 5, Suit: 2}, {Rank: 0, Suit: 1}, {Rank: 5, Suit: 1}}
+	// Player 1: a card of every suit
+	state.Players[1].Captured = []Card{{Rank: 11, Suit: 0}, {Rank: 11, Suit: 1}, {Rank: 11, Suit: 2}, {Rank: 11, Suit: 3}}
+
+	// Cards tie, player 0 has the coins and settebello, player 1 the primiera
+	if got := ScoreScopa(state); !reflect.DeepEqual(got, []int32{2, 1}) {
+		t.Errorf("Expected points [2 1], got %v", got)
+	}
+}
@@ -1,5 +1,64 @@
 package engine
 
+// HandValue sums the rank values of cards, indexed by engine rank (2..A).
+// Jokers and out-of-range cards are worth nothing.
+func HandValue(cards []Card, values [13]int32) int32 {
+	total := int32(0)
+	for _, card := range cards {
+		if card.Suit < 4 && card.Rank <= AceRank {
+			total += values[card.Rank]
+		}
+	}
+	return total
+}
+
+// SoftValue lets cards of one rank count Alt instead of their table value
+// whenever that keeps the hand total at or under Target, like Blackjack
+// Aces (1, or 11 up to 21)
+type SoftValue struct {
+	Rank   uint8
+	Alt    int32
+	Target int32
+}
+
+// BlackjackAce is the soft Ace of a hand played to 21
+var BlackjackAce = SoftValue{Rank: AceRank, Alt: 11, Target: 21}
+
+// SoftHandValue is HandValue with soft's cards counted at soft.Alt, one at a
+// time, for as many of them as fit under soft.Target; the rest stay hard
+// (their table value)
+func SoftHandValue(cards []Card, values [13]int32, soft SoftValue) int32 {
+	total := HandValue(cards, values)
+	if soft.Rank > AceRank {
+		return total
+	}
+	diff := soft.Alt - values[soft.Rank]
+	if diff <= 0 {
+		return total
+	}
+	for _, card := range cards {
+		if card.Suit < 4 && card.Rank == soft.Rank && total+diff <= soft.Target {
+			total += diff
+		}
+	}
+	return total
+}
+
+// ScoreTrickPoints adds each player's TrickPoints for the hand to their
+// score, clears the tallies, and returns the points awarded per player
+func ScoreTrickPoints(state *GameState) []int32 {
+	points := make([]int32, len(state.Players))
+	for i := range state.Players {
+		points[i] = state.Players[i].TrickPoints
+		if points[i] != 0 {
+			state.Players[i].Score += points[i]
+			UpdateTeamScore(state, i, points[i])
+		}
+		state.Players[i].TrickPoints = 0
+	}
+	return points
+}
+
 // EvaluateContracts scores all teams based on their bids and tricks won.
 func EvaluateContracts(state *GameState, scoring *ContractScoring) {
 	numTeams := len(state.TeamScores)
@@ -65,6 +124,7 @@ func ResetHandState(state *GameState) {
 		state.Players[i].CurrentBid = -1
 		state.Players[i].IsNilBid = false
 		state.Players[i].TricksWon = 0
+		state.Players[i].TrickPoints = 0
 	}
 	state.BiddingComplete = false
 
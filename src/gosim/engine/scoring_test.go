@@ -242,3 +242,37 @@ func TestResetHandState(t *testing.T) {
 		t.Errorf("AccumulatedBags should persist")
 	}
 }
+
+// rummyValues scores aces 1, pips their face value and court cards 10
+var rummyValues = [13]int32{2, 3, 4, 5, 6, 7, 8, 9, 10, 10, 10, 10, 1}
+
+func TestHandValueDeadwood(t *testing.T) {
+	deadwood := []Card{
+		{Rank: AceRank, Suit: 0}, // 1
+		{Rank: 5, Suit: 1},       // 7
+		{Rank: 10, Suit: 2},      // Q = 10
+		{Rank: 11, Suit: 3},      // K = 10
+		Joker(0),                 // worth nothing
+	}
+	if got := HandValue(deadwood, rummyValues); got != 28 {
+		t.Errorf("Expected deadwood of 28, got %d", got)
+	}
+	if got := HandValue(nil, rummyValues); got != 0 {
+		t.Errorf("Expected an empty hand to be worth 0, got %d", got)
+	}
+}
+
+func TestSoftHandValueAce(t *testing.T) {
+	softSeventeen := []Card{{Rank: AceRank, Suit: 0}, {Rank: 4, Suit: 1}} // A 6
+	if got := SoftHandValue(softSeventeen, rummyValues, BlackjackAce); got != 17 {
+		t.Errorf("Expected A 6 to count a soft 17, got %d", got)
+	}
+	hardSeventeen := append(softSeventeen, Card{Rank: 8, Suit: 2}) // A 6 10
+	if got := SoftHandValue(hardSeventeen, rummyValues, BlackjackAce); got != 17 {
+		t.Errorf("Expected A 6 10 to count a hard 17, got %d", got)
+	}
+	twoAces := []Card{{Rank: AceRank, Suit: 0}, {Rank: AceRank, Suit: 3}} // A A
+	if got := SoftHandValue(twoAces, rummyValues, BlackjackAce); got != 12 {
+		t.Errorf("Expected only one of two Aces to count 11, got %d", got)
+	}
+}
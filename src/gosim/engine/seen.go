@@ -0,0 +1,34 @@
+package engine
+
+// Seen cards
+//
+// GameState.SeenCards records every card that has been shown to the whole
+// table this hand: played or discarded face up, dealt to the tableau, the
+// community or a face-up kitty, turned face up in a hand, or revealed by a
+// challenge or a simultaneous round. A card stays seen wherever it goes
+// next, so a card drawn from the discard pile is known to be in the
+// drawer's hand and one shuffled back into the deck is known to be in it.
+// This is what a card counter remembers; AIs read it through IsSeen or the
+// seen block of EncodeObservation. Bits are numbered like observation card
+// blocks: suit*13 + rank, jokers at 52 and 53.
+
+// seenBit returns card's bit in SeenCards, or 0 for a card outside the deck
+func seenBit(card Card) uint64 {
+	if i := obsCardIndex(card); i >= 0 {
+		return 1 << uint(i)
+	}
+	return 0
+}
+
+// MarkSeen records cards as shown to every player
+func (s *GameState) MarkSeen(cards ...Card) {
+	for _, card := range cards {
+		s.SeenCards |= seenBit(card)
+	}
+}
+
+// IsSeen reports whether card has been shown to every player this hand
+func (s *GameState) IsSeen(card Card) bool {
+	bit := seenBit(card)
+	return bit != 0 && s.SeenCards&bit != 0
+}
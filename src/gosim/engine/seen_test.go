@@ -0,0 +1,61 @@
+package engine
+
+import "testing"
+
+func TestPlayedCardIsSeen(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	played, kept := Card{Rank: 9, Suit: 2}, Card{Rank: 3, Suit: 0}
+	state.Players[0].Hand = []Card{kept, played}
+	genome := &Genome{Header: &BytecodeHeader{PlayerCount: 2}, TurnPhases: []PhaseDescriptor{
+		{PhaseType: 3, Data: []byte{byte(LocationDiscard), 1}}, // Discard one
+		{PhaseType: 1, Data: []byte{byte(LocationDiscard), 0, 0, 0, 1, 0, 0}},
+	}}
+
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 1, TargetLoc: LocationDiscard}, genome)
+	if !state.IsSeen(played) || state.IsSeen(kept) {
+		t.Fatalf("Expected only the discarded %s seen, got %#x", played, state.SeenCards)
+	}
+
+	// Drawn back into a hand, it stays known
+	ApplyMove(state, &LegalMove{PhaseIndex: 1, CardIndex: MoveDraw, TargetLoc: LocationDiscard}, genome)
+	if len(state.Players[1].Hand) != 1 || !state.IsSeen(played) {
+		t.Errorf("Expected the %s still seen in player 1's hand", played)
+	}
+	obs := EncodeObservation(state, genome, 1)
+	seen := obs[len(obs)-obsCards:]
+	if seen[2*13+9] != 1 || seen[0*13+3] != 0 {
+		t.Error("Expected the seen block to mark only the discarded card")
+	}
+
+	// Clones keep what has been seen; a new deal forgets it
+	clone := state.Clone()
+	defer PutState(clone)
+	if clone.SeenCards != state.SeenCards {
+		t.Error("Expected Clone to copy SeenCards")
+	}
+	DealHand(state, SetupParams{NumPlayers: 2})
+	if state.SeenCards != 0 {
+		t.Errorf("Expected a new hand to start with nothing seen, got %#x", state.SeenCards)
+	}
+}
+
+func TestTrickAndRevealMarkSeen(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	led, hidden := Card{Rank: 12, Suit: 3}, Card{Rank: 0, Suit: 1}
+	state.Players[0].Hand = []Card{led}
+	state.Players[1].Hand = []Card{hidden, Joker(0)}
+	genome := &Genome{TurnPhases: []PhaseDescriptor{{PhaseType: 4, Data: []byte{1, 0, 255, 255}}}}
+
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationTableau}, genome)
+	if !state.IsSeen(led) || state.IsSeen(hidden) {
+		t.Fatalf("Expected the led %s seen and player 1's hand not, got %#x", led, state.SeenCards)
+	}
+
+	ApplyReveal(state, 1)
+	if !state.IsSeen(hidden) || !state.IsSeen(Joker(0)) {
+		t.Errorf("Expected a revealed hand seen, got %#x", state.SeenCards)
+	}
+}
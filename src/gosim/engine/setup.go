@@ -0,0 +1,193 @@
+package engine
+
+import "encoding/binary"
+
+// SetupParams holds what a genome's setup section asks for at the start of a
+// hand. Callers that re-deal between hands (match play) keep it around and
+// pass it back to DealHand.
+type SetupParams struct {
+	NumPlayers          int
+	CardsPerPlayer      int
+	InitialDiscardCount int
+	StartingChips       int
+	Pattern             *DealPattern // nil = deal CardsPerPlayer up front
+	KittySize           int          // Cards set aside after the hands are dealt
+	KittyFaceUp         bool
+	StartingPlayer      int // Player who starts the first hand
+}
+
+// ReadSetupParams reads the setup section from genome bytecode.
+// Format: cards_per_player:4 + initial_discard_count:4 + starting_chips:4
+// Defaults to a 2-player, 26-card (War) deal when the section is missing.
+func ReadSetupParams(genome *Genome) SetupParams {
+	params := SetupParams{
+		NumPlayers:     int(genome.Header.PlayerCount),
+		CardsPerPlayer: 26, // Default for War
+		Pattern:        genome.DealPattern,
+		KittySize:      genome.KittySize,
+		KittyFaceUp:    genome.KittyFaceUp,
+		StartingPlayer: genome.StartingPlayer,
+	}
+	if params.NumPlayers == 0 || params.NumPlayers > 4 {
+		params.NumPlayers = 2 // Default to 2 players
+	}
+	if params.StartingPlayer < 0 || params.StartingPlayer >= params.NumPlayers {
+		params.StartingPlayer = 0
+	}
+
+	if genome.Header.SetupOffset > 0 && genome.Header.SetupOffset+12 <= int32(len(genome.Bytecode)) {
+		setupOffset := genome.Header.SetupOffset
+		params.CardsPerPlayer = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset : setupOffset+4])))
+		params.InitialDiscardCount = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset+4 : setupOffset+8])))
+		params.StartingChips = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset+8 : setupOffset+12])))
+	}
+	return params
+}
+
+// BuildDeck fills state.Deck with a standard 52-card deck and shuffles it with seed
+func BuildDeck(state *GameState, seed uint64) {
+	for suit := uint8(0); suit < 4; suit++ {
+		for rank := uint8(0); rank < 13; rank++ {
+			state.Deck = append(state.Deck, Card{Rank: rank, Suit: suit})
+		}
+	}
+	state.ShuffleDeck(seed)
+}
+
+// DealHand deals cards to each player from the deck, then the kitty, then
+// the initial cards to discard/tableau.
+// With a deal pattern only the first stage is dealt; the game loop deals
+// later streets between betting rounds.
+// For TableauMode games (Scopa), initial cards go to Tableau[0]
+// For other games (Uno), initial cards go to Discard
+func DealHand(state *GameState, params SetupParams) {
+	state.SeenCards = 0
+	if params.Pattern != nil {
+		Deal(state, params.Pattern, 0)
+	} else {
+		for i := 0; i < params.CardsPerPlayer; i++ {
+			for p := 0; p < params.NumPlayers; p++ {
+				state.DrawCard(uint8(p), LocationDeck)
+			}
+		}
+	}
+
+	DealKitty(state, params.KittySize, params.KittyFaceUp)
+
+	initialDiscardCount := params.InitialDiscardCount
+	if initialDiscardCount > 0 && len(state.Deck) >= initialDiscardCount {
+		// Initialize tableau pile if needed for TableauMode games
+		if state.TableauMode != 0 && len(state.Tableau) == 0 {
+			state.Tableau = make([][]Card, 1)
+			state.Tableau[0] = make([]Card, 0, initialDiscardCount)
+		}
+		for i := 0; i < initialDiscardCount; i++ {
+			if len(state.Deck) > 0 {
+				card := state.Deck[len(state.Deck)-1]
+				state.Deck = state.Deck[:len(state.Deck)-1]
+				if state.TableauMode != 0 {
+					// Scopa/MATCH_RANK/SEQUENCE: cards go to tableau[0]
+					state.Tableau[0] = append(state.Tableau[0], card)
+				} else {
+					// Uno-style: cards go to discard
+					state.Discard = append(state.Discard, card)
+				}
+				state.MarkSeen(card)
+			}
+		}
+	}
+}
+
+// DealKitty deals count cards from the top of the deck into state.Kitty,
+// face up if faceUp. It stops early if the deck runs out.
+func DealKitty(state *GameState, count int, faceUp bool) {
+	for i := 0; i < count && len(state.Deck) > 0; i++ {
+		card := state.Deck[len(state.Deck)-1]
+		state.Deck = state.Deck[:len(state.Deck)-1]
+		state.Kitty = append(state.Kitty, card)
+	}
+	if len(state.Kitty) > 0 {
+		state.KittyFaceUp = faceUp
+		if faceUp {
+			state.MarkSeen(state.Kitty...)
+		}
+	}
+}
+
+// TakeKitty moves the whole kitty into playerID's hand, as when the winning
+// bidder picks up the widow; a face-up kitty stays visible in the hand. It
+// returns the number of cards taken.
+func TakeKitty(state *GameState, playerID uint8) int {
+	n := len(state.Kitty)
+	player := &state.Players[playerID]
+	player.Hand = append(player.Hand, state.Kitty...)
+	if state.KittyFaceUp {
+		for _, card := range state.Kitty {
+			player.setFaceUp(card)
+		}
+	}
+	state.Kitty = state.Kitty[:0]
+	state.KittyFaceUp = false
+	return n
+}
+
+// RotateStartingPlayer passes the start of the next hand to the following
+// player still in the game, as the deal passes to the left between hands,
+// and makes them the current player and trick leader
+func RotateStartingPlayer(state *GameState) {
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+	next := int(state.StartingPlayer)
+	for i := 0; i < numPlayers; i++ {
+		next = (next + 1) % numPlayers
+		if next >= len(state.Players) || !state.Players[next].Eliminated {
+			break
+		}
+	}
+	state.StartingPlayer = uint8(next)
+	state.CurrentPlayer = state.StartingPlayer
+	state.TrickLeader = state.StartingPlayer
+}
+
+// SetupGame builds a ready-to-play state for genome: a shuffled deck, player
+// count and table modes from the header, teams, the initial deal and
+// starting chips, with the genome's StartingPlayer to move. The caller owns
+// the state and must return it with PutState.
+func SetupGame(genome *Genome, seed uint64) *GameState {
+	state := GetState()
+	BuildDeck(state, seed)
+
+	params := ReadSetupParams(genome)
+	state.NumPlayers = uint8(params.NumPlayers)
+	state.CardsPerPlayer = params.CardsPerPlayer
+	state.TableauMode = genome.Header.TableauMode
+	state.SequenceDirection = genome.Header.SequenceDirection
+	state.AceLow = genome.AceLow
+	state.WarToBottom = genome.WarToBottom
+	state.PerfectInformation = genome.PerfectInformation
+	state.SuitOrder = genome.SuitOrder
+
+	// Initialize teams if configured
+	if genome.Header.TeamMode && genome.Header.TeamCount > 0 && genome.Header.TeamDataOffset > 0 {
+		teamDataOffset := genome.Header.TeamDataOffset
+		if teamDataOffset < len(genome.Bytecode) {
+			state.InitializeTeams(ParseTeams(genome.Bytecode[teamDataOffset:]))
+		}
+	}
+
+	DealHand(state, params)
+
+	// Initialize chips if this genome uses betting
+	if params.StartingChips > 0 {
+		state.InitializeChips(int64(params.StartingChips))
+	}
+
+	state.StartingPlayer = uint8(params.StartingPlayer)
+	state.CurrentPlayer = state.StartingPlayer
+	state.TrickLeader = state.StartingPlayer
+	state.BettingStartPlayer = params.StartingPlayer
+
+	return state
+}
@@ -0,0 +1,244 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadGoldenGenome parses a golden bytecode file generated by Python
+func loadGoldenGenome(t *testing.T, name string) *Genome {
+	t.Helper()
+	bytecode, err := os.ReadFile(filepath.Join("..", "..", "..", "tests", "golden", name))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	genome, err := ParseGenome(bytecode)
+	if err != nil {
+		t.Fatalf("Failed to parse genome: %v", err)
+	}
+	return genome
+}
+
+// workerSetup reproduces the worker's original inline start-game setup,
+// which SetupGame replaces
+func workerSetup(genome *Genome, seed uint64) *GameState {
+	state := GetState()
+	for suit := uint8(0); suit < 4; suit++ {
+		for rank := uint8(0); rank < 13; rank++ {
+			state.Deck = append(state.Deck, Card{Rank: rank, Suit: suit})
+		}
+	}
+	state.ShuffleDeck(seed)
+
+	params := ReadSetupParams(genome)
+	state.NumPlayers = uint8(params.NumPlayers)
+	state.CardsPerPlayer = params.CardsPerPlayer
+	state.TableauMode = genome.Header.TableauMode
+	state.SequenceDirection = genome.Header.SequenceDirection
+
+	for i := 0; i < params.CardsPerPlayer; i++ {
+		for p := 0; p < params.NumPlayers; p++ {
+			state.DrawCard(uint8(p), LocationDeck)
+		}
+	}
+	for i := 0; i < params.InitialDiscardCount && len(state.Deck) > 0; i++ {
+		card := state.Deck[len(state.Deck)-1]
+		state.Deck = state.Deck[:len(state.Deck)-1]
+		state.Discard = append(state.Discard, card)
+	}
+	if params.StartingChips > 0 {
+		state.InitializeChips(int64(params.StartingChips))
+	}
+	return state
+}
+
+// assertSameSetup compares the parts of two states that setup touches
+func assertSameSetup(t *testing.T, got, want *GameState) {
+	t.Helper()
+	if got.NumPlayers != want.NumPlayers || got.CardsPerPlayer != want.CardsPerPlayer {
+		t.Errorf("Players/hand size mismatch: got %d/%d, want %d/%d",
+			got.NumPlayers, got.CardsPerPlayer, want.NumPlayers, want.CardsPerPlayer)
+	}
+	if !cardsEqual(got.Deck, want.Deck) {
+		t.Errorf("Deck mismatch: got %d cards, want %d", len(got.Deck), len(want.Deck))
+	}
+	if !cardsEqual(got.Discard, want.Discard) {
+		t.Errorf("Discard mismatch: got %v, want %v", got.Discard, want.Discard)
+	}
+	for p := range want.Players {
+		if !cardsEqual(got.Players[p].Hand, want.Players[p].Hand) {
+			t.Errorf("Player %d hand mismatch: got %v, want %v", p, got.Players[p].Hand, want.Players[p].Hand)
+		}
+		if got.Players[p].Chips != want.Players[p].Chips {
+			t.Errorf("Player %d chips: got %d, want %d", p, got.Players[p].Chips, want.Players[p].Chips)
+		}
+	}
+}
+
+func cardsEqual(a, b []Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSetupGame_MatchesWorkerWar(t *testing.T) {
+	genome := loadGoldenGenome(t, "war_genome.bin")
+
+	got := SetupGame(genome, 42)
+	defer PutState(got)
+	want := workerSetup(genome, 42)
+	defer PutState(want)
+
+	assertSameSetup(t, got, want)
+	if len(got.Players[0].Hand) != 26 || len(got.Players[1].Hand) != 26 || len(got.Deck) != 0 {
+		t.Errorf("Expected 26 cards each and an empty deck, got %d/%d/%d",
+			len(got.Players[0].Hand), len(got.Players[1].Hand), len(got.Deck))
+	}
+}
+
+func TestSetupGame_MatchesWorkerChips(t *testing.T) {
+	genome := loadGoldenGenome(t, "simple_poker_genome.bin")
+
+	got := SetupGame(genome, 7)
+	defer PutState(got)
+	want := workerSetup(genome, 7)
+	defer PutState(want)
+
+	assertSameSetup(t, got, want)
+	params := ReadSetupParams(genome)
+	if params.StartingChips <= 0 {
+		t.Fatalf("Expected simple_poker to start with chips, got %d", params.StartingChips)
+	}
+	if got.Players[0].Chips != int64(params.StartingChips) {
+		t.Errorf("Expected %d starting chips, got %d", params.StartingChips, got.Players[0].Chips)
+	}
+}
+
+func TestSetupGame_DealPattern(t *testing.T) {
+	genome := &Genome{
+		Header:      &BytecodeHeader{PlayerCount: 3},
+		DealPattern: HoldemDealPattern(),
+	}
+
+	state := SetupGame(genome, 1)
+	defer PutState(state)
+	for p := 0; p < 3; p++ {
+		if len(state.Players[p].Hand) != 2 {
+			t.Errorf("Player %d: expected 2 hole cards, got %d", p, len(state.Players[p].Hand))
+		}
+	}
+	if state.DealRound != 1 {
+		t.Errorf("Expected only the first street dealt, got DealRound %d", state.DealRound)
+	}
+}
+
+func TestReadSetupParams_Defaults(t *testing.T) {
+	params := ReadSetupParams(&Genome{Header: &BytecodeHeader{PlayerCount: 9}})
+	if params.NumPlayers != 2 || params.CardsPerPlayer != 26 || params.StartingChips != 0 {
+		t.Errorf("Expected War defaults, got %+v", params)
+	}
+}
+
+func TestDealHand_Kitty(t *testing.T) {
+	// Skat: 3 players of 10 with a 2-card skat (from a full deck here)
+	state := NewGameState(3)
+	defer PutState(state)
+	BuildDeck(state, 5)
+	params := SetupParams{NumPlayers: 3, CardsPerPlayer: 10, InitialDiscardCount: 1, KittySize: 2}
+	DealHand(state, params)
+
+	total := len(state.Deck) + len(state.Discard) + len(state.Kitty)
+	for p := 0; p < 3; p++ {
+		if len(state.Players[p].Hand) != 10 {
+			t.Errorf("Player %d: expected 10 cards, got %d", p, len(state.Players[p].Hand))
+		}
+		total += len(state.Players[p].Hand)
+	}
+	if len(state.Kitty) != 2 || state.KittyFaceUp {
+		t.Errorf("Expected a face-down kitty of 2, got %v (face up %v)", state.Kitty, state.KittyFaceUp)
+	}
+	if total != 52 {
+		t.Errorf("Expected deck, discard, kitty and hands to total 52, got %d", total)
+	}
+	if err := ValidateState(state); err != nil {
+		t.Errorf("Expected a valid deal, got %v", err)
+	}
+
+	if got := ReadSetupParams(&Genome{Header: &BytecodeHeader{}, KittySize: 2, KittyFaceUp: true}); got.KittySize != 2 || !got.KittyFaceUp {
+		t.Errorf("Expected kitty settings from the genome, got %+v", got)
+	}
+}
+
+func TestTakeKitty(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.Players[1].Hand = []Card{{Rank: 3, Suit: 0}}
+	widow := []Card{{Rank: 9, Suit: 1}, {Rank: 11, Suit: 2}}
+	state.Deck = append(state.Deck, widow...)
+	DealKitty(state, 5, true) // Only 2 left
+
+	if n := TakeKitty(state, 1); n != 2 {
+		t.Fatalf("Expected to take 2 cards, took %d", n)
+	}
+	hand := &state.Players[1]
+	if len(hand.Hand) != 3 || len(state.Kitty) != 0 || state.KittyFaceUp {
+		t.Errorf("Expected the kitty in player 1's hand, got hand %v and kitty %v", hand.Hand, state.Kitty)
+	}
+	if hand.IsFaceUp(Card{Rank: 3, Suit: 0}) || !hand.IsFaceUp(widow[0]) || !hand.IsFaceUp(widow[1]) {
+		t.Errorf("Expected only the widow cards face up, got %v", hand.FaceUp)
+	}
+}
+
+func TestSetupGame_StartingPlayer(t *testing.T) {
+	genome := loadGoldenGenome(t, "hearts_genome.bin")
+	genome.StartingPlayer = 2
+	state := SetupGame(genome, 11)
+	defer PutState(state)
+
+	if state.NumPlayers != 4 || state.StartingPlayer != 2 || state.CurrentPlayer != 2 || state.TrickLeader != 2 {
+		t.Fatalf("Expected player 2 of 4 to start, got start %d, current %d, leader %d of %d",
+			state.StartingPlayer, state.CurrentPlayer, state.TrickLeader, state.NumPlayers)
+	}
+	moves := GenerateLegalMoves(state, genome)
+	if len(moves) == 0 {
+		t.Fatal("Expected player 2 to have opening moves")
+	}
+	ApplyMove(state, &moves[0], genome)
+	if len(state.Players[2].Hand) != state.CardsPerPlayer-1 || state.CurrentPlayer != 3 {
+		t.Errorf("Expected player 2 to lead and pass to player 3, got current %d", state.CurrentPlayer)
+	}
+
+	genome.StartingPlayer = 7
+	if got := ReadSetupParams(genome).StartingPlayer; got != 0 {
+		t.Errorf("Expected an out-of-range starting player to fall back to 0, got %d", got)
+	}
+}
+
+func TestRotateStartingPlayer(t *testing.T) {
+	state := NewGameState(4)
+	defer PutState(state)
+	state.NumPlayers = 4
+	state.StartingPlayer = 2
+
+	for _, want := range []uint8{3, 0, 1} {
+		RotateStartingPlayer(state)
+		if state.StartingPlayer != want || state.CurrentPlayer != want || state.TrickLeader != want {
+			t.Fatalf("Expected player %d to start the next hand, got start %d, current %d, leader %d",
+				want, state.StartingPlayer, state.CurrentPlayer, state.TrickLeader)
+		}
+	}
+
+	// Eliminated players are passed over
+	state.Players[2].Eliminated = true
+	RotateStartingPlayer(state)
+	if state.StartingPlayer != 3 {
+		t.Errorf("Expected rotation to skip eliminated player 2, got %d", state.StartingPlayer)
+	}
+}
@@ -0,0 +1,135 @@
+package engine
+
+// Simultaneous play
+//
+// In a simultaneous phase (PhaseTypeSimultaneous) every player plays a card
+// face-down and the round is only resolved once all of them have, so no
+// play can depend on another's. The engine still asks for moves one seat at
+// a time, but a committed card leaves the hand for GameState.Committed,
+// where only its owner can see it, and nothing happens on the table until
+// the last active player commits. The highest card then wins the round
+// (Jokers beat every rank, see GameState.RankValue for Aces) and takes every
+// committed card. Ties leave the round's cards in GameState.CommitPot for
+// the winner of the next round, as in a War.
+//
+// Phase data is one byte saying where a won round's cards go.
+
+// Simultaneous phase outcomes, the phase's data byte
+const (
+	SimultaneousToHand    = 0 // Into the winner's hand (War)
+	SimultaneousToCapture = 1 // Into the winner's captured pile, scoring a point per card
+)
+
+// HasCommitted reports whether playerID has a card committed this round
+func HasCommitted(state *GameState, playerID int) bool {
+	for _, tc := range state.Committed {
+		if int(tc.PlayerID) == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// canCommit reports whether playerID still has to commit this round
+func canCommit(state *GameState, playerID int) bool {
+	player := &state.Players[playerID]
+	return !player.HasFolded && !player.Eliminated && len(player.Hand) > 0 && !HasCommitted(state, playerID)
+}
+
+// CommitCard moves the card at handIdx of playerID's hand face-down into
+// the current round. It returns false, changing nothing, for a player who
+// has already committed or an index outside their hand.
+func CommitCard(state *GameState, playerID int, handIdx int) bool {
+	if playerID < 0 || playerID >= len(state.Players) || HasCommitted(state, playerID) {
+		return false
+	}
+	player := &state.Players[playerID]
+	if handIdx < 0 || handIdx >= len(player.Hand) {
+		return false
+	}
+	card := player.Hand[handIdx]
+	player.Hand = append(player.Hand[:handIdx], player.Hand[handIdx+1:]...)
+	state.Committed = append(state.Committed, TrickCard{PlayerID: uint8(playerID), Card: card})
+	return true
+}
+
+// RoundCommitted reports whether every player who can commit a card this
+// round has done so
+func RoundCommitted(state *GameState) bool {
+	if len(state.Committed) == 0 {
+		return false
+	}
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 || numPlayers > len(state.Players) {
+		numPlayers = len(state.Players)
+	}
+	for p := 0; p < numPlayers; p++ {
+		if canCommit(state, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// commitValue orders committed cards: Jokers above every rank
+func commitValue(state *GameState, card Card) int {
+	if card.IsJoker() {
+		return int(AceRank) + 1 + int(card.Rank)
+	}
+	return int(state.RankValue(card.Rank))
+}
+
+// ResolveSimultaneous reveals the round's committed cards and gives them,
+// with any pot from tied rounds, to the player of the highest card as
+// outcome (SimultaneousToHand or SimultaneousToCapture) says. A tie for
+// highest moves them to CommitPot instead. Returns the winner, or -1 for a
+// tie or an empty round.
+func ResolveSimultaneous(state *GameState, outcome uint8) int {
+	if len(state.Committed) == 0 {
+		return -1
+	}
+	best := 0
+	tied := false
+	for i := 1; i < len(state.Committed); i++ {
+		v, top := commitValue(state, state.Committed[i].Card), commitValue(state, state.Committed[best].Card)
+		if v > top {
+			best, tied = i, false
+		} else if v == top {
+			tied = true
+		}
+	}
+
+	for _, tc := range state.Committed {
+		state.CommitPot = append(state.CommitPot, tc.Card)
+		state.MarkSeen(tc.Card)
+	}
+	winningCard := state.Committed[best].Card
+	winner := int(state.Committed[best].PlayerID)
+	state.Committed = state.Committed[:0]
+	if tied {
+		return -1
+	}
+
+	player := &state.Players[winner]
+	if outcome == SimultaneousToCapture {
+		player.Captured = append(player.Captured, state.CommitPot...)
+		player.Score += int32(len(state.CommitPot))
+		UpdateTeamScore(state, winner, int32(len(state.CommitPot)))
+	} else {
+		player.Hand = append(player.Hand, state.CommitPot...)
+	}
+	state.CommitPot = state.CommitPot[:0]
+
+	if state.Trace != nil {
+		state.Trace(TraceEvent{Kind: TraceTrick, Turn: state.TurnNumber, Player: winner, Card: winningCard})
+	}
+	return winner
+}
+
+// simultaneousOutcome returns the outcome byte of a simultaneous phase's data
+func simultaneousOutcome(data []byte) uint8 {
+	if len(data) < 1 {
+		return SimultaneousToHand
+	}
+	return data[0]
+}
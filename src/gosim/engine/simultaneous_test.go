@@ -0,0 +1,100 @@
+package engine
+
+import "testing"
+
+// simultaneousGame deals three players two cards each for a single
+// simultaneous phase with outcome
+func simultaneousGame(state *GameState, outcome uint8) *Genome {
+	state.NumPlayers = 3
+	state.Players[0].Hand = []Card{{Rank: 5, Suit: 0}, {Rank: 9, Suit: 0}}  // 7H JH
+	state.Players[1].Hand = []Card{{Rank: 11, Suit: 1}, {Rank: 2, Suit: 1}} // KD 4D
+	state.Players[2].Hand = []Card{{Rank: 9, Suit: 2}, {Rank: 0, Suit: 2}}  // JC 2C
+	return &Genome{
+		Header:        &BytecodeHeader{PlayerCount: 3, MaxTurns: 100},
+		TurnPhases:    []PhaseDescriptor{{PhaseType: PhaseTypeSimultaneous, Data: []byte{outcome}}},
+		WinConditions: []WinCondition{{WinType: WinTypeCaptureAll}},
+	}
+}
+
+// commit applies the current player's legal move for hand index handIdx
+func commit(t *testing.T, state *GameState, genome *Genome, handIdx int) {
+	t.Helper()
+	for _, move := range GenerateLegalMoves(state, genome) {
+		if move.CardIndex == handIdx {
+			ApplyMove(state, &move, genome)
+			return
+		}
+	}
+	t.Fatalf("Player %d has no move for hand index %d", state.CurrentPlayer, handIdx)
+}
+
+func TestSimultaneousCollectsAllPlaysBeforeResolving(t *testing.T) {
+	state := NewGameState(3)
+	defer PutState(state)
+	genome := simultaneousGame(state, SimultaneousToHand)
+
+	commit(t, state, genome, 1) // JH
+	commit(t, state, genome, 1) // 4D
+	if len(state.Committed) != 2 || len(state.Players[0].Hand) != 1 || len(state.Players[1].Hand) != 1 {
+		t.Fatalf("Expected two face-down plays held back, got %v", state.Committed)
+	}
+	if state.CurrentPlayer != 2 {
+		t.Fatalf("Expected the turn to pass on to player 2, got %d", state.CurrentPlayer)
+	}
+	if err := ValidateState(state); err != nil {
+		t.Errorf("Expected committed cards to be accounted for, got %v", err)
+	}
+
+	commit(t, state, genome, 1) // 2C: player 0's Jack takes the round
+	if len(state.Committed) != 0 {
+		t.Errorf("Expected the round resolved once everyone played, got %v", state.Committed)
+	}
+	if got := len(state.Players[0].Hand); got != 4 {
+		t.Errorf("Expected player 0 to take all three cards into a hand of 4, got %d", got)
+	}
+	if len(state.Players[1].Hand) != 1 || len(state.Players[2].Hand) != 1 {
+		t.Error("Expected the other players to have lost their plays")
+	}
+}
+
+func TestSimultaneousTieCarriesPot(t *testing.T) {
+	state := NewGameState(3)
+	defer PutState(state)
+	genome := simultaneousGame(state, SimultaneousToCapture)
+
+	commit(t, state, genome, 1) // JH
+	commit(t, state, genome, 1) // 4D
+	commit(t, state, genome, 0) // JC ties the Jack
+	if len(state.CommitPot) != 3 || state.Players[0].Score != 0 || state.Players[2].Score != 0 {
+		t.Fatalf("Expected a tie to leave three cards in the pot, got pot %v", state.CommitPot)
+	}
+
+	commit(t, state, genome, 0) // 7H
+	commit(t, state, genome, 0) // KD takes this round and the pot
+	if len(state.Committed) != 2 {
+		t.Fatalf("Expected the round to wait for player 2, got %v", state.Committed)
+	}
+	commit(t, state, genome, 0) // 2C
+	if state.Players[1].Score != 6 || len(state.Players[1].Captured) != 6 || len(state.CommitPot) != 0 {
+		t.Errorf("Expected player 1 to capture all six cards, got score %d and pot %v", state.Players[1].Score, state.CommitPot)
+	}
+}
+
+func TestSimultaneousSkipsPlayersWithoutCards(t *testing.T) {
+	state := NewGameState(3)
+	defer PutState(state)
+	genome := simultaneousGame(state, SimultaneousToHand)
+	state.Players[2].Hand = nil
+
+	commit(t, state, genome, 0)
+	if moves := GenerateLegalMoves(state, genome); len(moves) != 2 {
+		t.Fatalf("Expected player 1 to have two plays, got %v", moves)
+	}
+	commit(t, state, genome, 0) // KD beats 7H
+	if len(state.Committed) != 0 || len(state.Players[1].Hand) != 3 {
+		t.Errorf("Expected the round resolved without the empty-handed player, got %v", state.Committed)
+	}
+	if CommitCard(state, 2, 0) {
+		t.Error("Expected a commit from an empty hand to fail")
+	}
+}
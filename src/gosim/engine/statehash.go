@@ -0,0 +1,152 @@
+package engine
+
+// State hashing
+//
+// StateHash fingerprints a GameState in the style of Zobrist hashing: every
+// card in every zone, keyed by zone and position, and every scalar that
+// steers play maps to a pseudo-random 64-bit key, and the hash is the XOR of
+// those keys. Two states with the same hash are (up to collisions) the same
+// position, which is what the simulation runners use to spot games that
+// cycle. The keys are mixed from their inputs rather than read from a table,
+// so piles of any length hash without setup.
+//
+// TurnNumber only enters as TurnNumber % hashTurnCycle: the turn count grows
+// every move, so hashing it whole would make every state unique, but War
+// ties alternate with TurnNumber/2 and claims rotate with TurnNumber % 13.
+// Trace, history and other bookkeeping that don't change play are left out.
+
+// hashTurnCycle is the period of every rule driven by the turn number:
+// War's tie alternation (4 turns) and the claim rank rotation (13 turns)
+const hashTurnCycle = 52
+
+// Hash feature tags, one per zone or scalar
+const (
+	hashDeck uint64 = iota + 1
+	hashDiscard
+	hashTableau
+	hashCommunity
+	hashKitty
+	hashTrick
+	hashCommitted
+	hashCommitPot
+	hashHand
+	hashCaptured
+	hashFaceUp
+	hashPlayer
+	hashTable
+	hashClaim
+	hashTeam
+)
+
+// hashKey returns the key of a feature: a tag, an index within it (seat,
+// pile, position) and a value, run through the splitmix64 finalizer
+func hashKey(tag, index, value uint64) uint64 {
+	x := tag<<56 ^ index<<32 ^ value
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// hashPile returns the keys of pile's cards by position. owner
+// distinguishes piles sharing a tag, e.g. seats or tableau piles.
+func hashPile(tag uint64, owner int, pile []Card) uint64 {
+	var h uint64
+	for i, card := range pile {
+		h ^= hashKey(tag, uint64(owner)<<16|uint64(i), uint64(card.Rank)<<8|uint64(card.Suit)|1<<16)
+	}
+	// The length is keyed too, so an empty pile differs from a missing one
+	return h ^ hashKey(tag, uint64(owner)<<16|0xffff, uint64(len(pile)))
+}
+
+// hashFlag returns 1 for true
+func hashFlag(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// StateHash returns a fingerprint of the position in state (see above)
+func StateHash(state *GameState) uint64 {
+	h := hashPile(hashDeck, 0, state.Deck) ^
+		hashPile(hashDiscard, 0, state.Discard) ^
+		hashPile(hashCommunity, 0, state.Community) ^
+		hashPile(hashKitty, 0, state.Kitty) ^
+		hashPile(hashCommitPot, 0, state.CommitPot)
+	for i, pile := range state.Tableau {
+		h ^= hashPile(hashTableau, i, pile)
+	}
+	for i, tc := range state.CurrentTrick {
+		h ^= hashKey(hashTrick, uint64(i), uint64(tc.PlayerID)<<16|uint64(tc.Card.Rank)<<8|uint64(tc.Card.Suit))
+	}
+	for i, tc := range state.Committed {
+		h ^= hashKey(hashCommitted, uint64(i), uint64(tc.PlayerID)<<16|uint64(tc.Card.Rank)<<8|uint64(tc.Card.Suit))
+	}
+
+	for p := range state.Players {
+		player := &state.Players[p]
+		h ^= hashPile(hashHand, p, player.Hand) ^
+			hashPile(hashCaptured, p, player.Captured) ^
+			hashPile(hashFaceUp, p, player.FaceUp)
+		seat := uint64(p) << 8
+		h ^= hashKey(hashPlayer, seat|0, uint64(uint32(player.Score))) ^
+			hashKey(hashPlayer, seat|1, uint64(player.Chips)) ^
+			hashKey(hashPlayer, seat|2, uint64(player.CurrentBet)) ^
+			hashKey(hashPlayer, seat|10, uint64(player.TotalBet)) ^
+			hashKey(hashPlayer, seat|3, uint64(uint8(player.CurrentBid))) ^
+			hashKey(hashPlayer, seat|4, uint64(uint8(player.TricksWon))) ^
+			hashKey(hashPlayer, seat|5, uint64(uint32(player.TrickPoints))) ^
+			hashKey(hashPlayer, seat|6, uint64(player.Sweeps)) ^
+			hashKey(hashPlayer, seat|7, hashFlag(player.Active)|hashFlag(player.Eliminated)<<1|
+				hashFlag(player.HasFolded)<<2|hashFlag(player.IsAllIn)<<3|hashFlag(player.IsNilBid)<<4)
+	}
+	for p, stood := range state.HasStood {
+		h ^= hashKey(hashPlayer, uint64(p)<<8|8, hashFlag(stood))
+	}
+	for p, won := range state.TricksWon {
+		h ^= hashKey(hashPlayer, uint64(p)<<8|9, uint64(won))
+	}
+
+	h ^= hashKey(hashTable, 0, uint64(state.CurrentPlayer)) ^
+		hashKey(hashTable, 1, uint64(state.CurrentPhase)) ^
+		hashKey(hashTable, 2, uint64(state.TurnNumber%hashTurnCycle)) ^
+		hashKey(hashTable, 3, uint64(uint8(state.WinnerID))) ^
+		hashKey(hashTable, 4, uint64(uint8(state.Knocker))) ^
+		hashKey(hashTable, 5, uint64(state.StartingPlayer)) ^
+		hashKey(hashTable, 6, uint64(state.Pot)) ^
+		hashKey(hashTable, 7, uint64(state.CarryoverPot)) ^
+		hashKey(hashTable, 8, uint64(state.CurrentBet)) ^
+		hashKey(hashTable, 9, uint64(state.RaiseCount)) ^
+		hashKey(hashTable, 10, uint64(state.BettingStartPlayer)) ^
+		hashKey(hashTable, 11, uint64(state.DealRound)) ^
+		hashKey(hashTable, 12, uint64(state.TrickLeader)) ^
+		hashKey(hashTable, 13, uint64(uint8(state.PlayDirection))) ^
+		hashKey(hashTable, 14, uint64(state.SkipCount)) ^
+		hashKey(hashTable, 15, uint64(state.ConsecutivePasses)) ^
+		hashKey(hashTable, 16, hashFlag(state.BettingComplete)|hashFlag(state.HeartsBroken)<<1|
+			hashFlag(state.BiddingComplete)<<2|hashFlag(state.KittyFaceUp)<<3) ^
+		hashKey(hashTable, 17, uint64(state.BettingRound))
+	for i, p := range state.EliminationOrder {
+		h ^= hashKey(hashTable, 18+uint64(i), uint64(p))
+	}
+
+	if claim := state.CurrentClaim; claim != nil {
+		h ^= hashKey(hashClaim, 0, uint64(claim.ClaimerID)<<24|uint64(claim.ClaimedRank)<<16|
+			uint64(claim.ClaimedCount)<<8|hashFlag(claim.Challenged)) ^
+			hashKey(hashClaim, 1, uint64(claim.ChallengerID)) ^
+			hashPile(hashClaim, 2, claim.CardsPlayed)
+	}
+	for t, score := range state.TeamScores {
+		h ^= hashKey(hashTeam, uint64(t)<<8, uint64(uint32(score)))
+	}
+	for t, contract := range state.TeamContracts {
+		h ^= hashKey(hashTeam, uint64(t)<<8|1, uint64(uint8(contract)))
+	}
+	for t, bags := range state.AccumulatedBags {
+		h ^= hashKey(hashTeam, uint64(t)<<8|2, uint64(uint8(bags)))
+	}
+	return h
+}
@@ -0,0 +1,45 @@
+package engine
+
+import "testing"
+
+func TestStateHashTracksPosition(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.Players[0].Hand = []Card{{Rank: 11, Suit: 0}, {Rank: 0, Suit: 1}} // KH 2D
+	state.Players[1].Hand = []Card{{Rank: 0, Suit: 2}, {Rank: 11, Suit: 3}} // 2C KS
+	state.TurnNumber = 6
+	hash := StateHash(state)
+
+	clone := state.Clone()
+	defer PutState(clone)
+	if StateHash(clone) != hash {
+		t.Error("Expected a clone to hash like its original")
+	}
+	clone.TurnNumber += hashTurnCycle
+	if StateHash(clone) != hash {
+		t.Error("Expected turns a full rule cycle apart to hash alike")
+	}
+
+	changes := map[string]func(s *GameState){
+		"hand order": func(s *GameState) {
+			s.Players[0].Hand[0], s.Players[0].Hand[1] = s.Players[0].Hand[1], s.Players[0].Hand[0]
+		},
+		"card between seats": func(s *GameState) {
+			s.Players[1].Hand = append(s.Players[1].Hand, s.Players[0].Hand[1])
+			s.Players[0].Hand = s.Players[0].Hand[:1]
+		},
+		"tie parity":      func(s *GameState) { s.TurnNumber += 2 },
+		"current player":  func(s *GameState) { s.CurrentPlayer = 1 },
+		"score":           func(s *GameState) { s.Players[1].Score = 3 },
+		"empty tableau":   func(s *GameState) { s.Tableau = append(s.Tableau, nil) },
+		"committed cards": func(s *GameState) { CommitCard(s, 0, 0) },
+	}
+	for name, change := range changes {
+		changed := state.Clone()
+		change(changed)
+		if StateHash(changed) == hash {
+			t.Errorf("%s: expected the hash to change", name)
+		}
+		PutState(changed)
+	}
+}
@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GenomeSummary describes what a genome does without running it, for
+// logging and deduplication. Two genomes with equal summaries play the same
+// shape of game, though conditions and thresholds may still differ.
+type GenomeSummary struct {
+	PlayerCount   int
+	MaxTurns      int
+	Phases        []string // Phase types in turn order (e.g. "draw", "play")
+	WinConditions []string // Win types in priority order (e.g. "empty_hand")
+	TableauMode   string   // "none", "war", "match_rank" or "sequence"
+	Teams         int      // Number of teams, 0 without team play
+	UsesBetting   bool
+	UsesTricks    bool
+	UsesBidding   bool
+	EffectRanks   []uint8 // Ranks with special effects, ascending
+}
+
+// Summary returns a GenomeSummary for g
+func (g *Genome) Summary() GenomeSummary {
+	s := GenomeSummary{
+		Phases:        make([]string, 0, len(g.TurnPhases)),
+		WinConditions: make([]string, 0, len(g.WinConditions)),
+		TableauMode:   "none",
+	}
+	if g.Header != nil {
+		s.PlayerCount = int(g.Header.PlayerCount)
+		s.MaxTurns = int(g.Header.MaxTurns)
+		s.TableauMode = tableauModeName(g.Header.TableauMode)
+		if g.Header.TeamMode {
+			s.Teams = g.Header.TeamCount
+		}
+	}
+
+	for _, phase := range g.TurnPhases {
+		s.Phases = append(s.Phases, phaseTypeName(phase.PhaseType))
+		switch phase.PhaseType {
+		case PhaseTypeBetting:
+			s.UsesBetting = true
+		case PhaseTypeTrick:
+			s.UsesTricks = true
+		case PhaseTypeBidding:
+			s.UsesBidding = true
+		}
+	}
+	for _, wc := range g.WinConditions {
+		s.WinConditions = append(s.WinConditions, winTypeName(wc.WinType))
+	}
+	for rank := range g.Effects {
+		s.EffectRanks = append(s.EffectRanks, rank)
+	}
+	sort.Slice(s.EffectRanks, func(i, j int) bool { return s.EffectRanks[i] < s.EffectRanks[j] })
+	return s
+}
+
+// String returns a one-line summary, e.g.
+// "2p, phases [play], win [capture_all], tableau war, effects none"
+func (s GenomeSummary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%dp", s.PlayerCount)
+	if s.Teams > 0 {
+		fmt.Fprintf(&b, " in %d teams", s.Teams)
+	}
+	fmt.Fprintf(&b, ", phases [%s], win [%s], tableau %s",
+		strings.Join(s.Phases, " "), strings.Join(s.WinConditions, " "), s.TableauMode)
+
+	if len(s.EffectRanks) == 0 {
+		b.WriteString(", effects none")
+	} else {
+		labels := make([]string, len(s.EffectRanks))
+		for i, rank := range s.EffectRanks {
+			labels[i] = GameRank(rank).String()
+		}
+		fmt.Fprintf(&b, ", effects on %s", strings.Join(labels, " "))
+	}
+	return b.String()
+}
+
+// phaseTypeName returns the bytecode name of a phase type
+func phaseTypeName(phaseType uint8) string {
+	switch phaseType {
+	case PhaseTypeDraw:
+		return "draw"
+	case PhaseTypePlay:
+		return "play"
+	case PhaseTypeDiscard:
+		return "discard"
+	case PhaseTypeTrick:
+		return "trick"
+	case PhaseTypeBetting:
+		return "betting"
+	case PhaseTypeClaim:
+		return "claim"
+	case PhaseTypeBidding:
+		return "bidding"
+	case PhaseTypeReveal:
+		return "reveal"
+	case PhaseTypeAction:
+		return "action"
+	case PhaseTypeSimultaneous:
+		return "simultaneous"
+	}
+	return fmt.Sprintf("unknown(%d)", phaseType)
+}
+
+// winTypeName returns the genome name of a win type
+func winTypeName(winType uint8) string {
+	switch winType {
+	case WinTypeEmptyHand:
+		return "empty_hand"
+	case WinTypeHighScore:
+		return "high_score"
+	case WinTypeFirstToScore:
+		return "first_to_score"
+	case WinTypeCaptureAll:
+		return "capture_all"
+	case WinTypeLowScore:
+		return "low_score"
+	case WinTypeAllHandEmpty:
+		return "all_hands_empty"
+	case WinTypeBestHand:
+		return "best_hand"
+	case WinTypeMostCaptured:
+		return "most_captured"
+	case WinTypeMostTricks:
+		return "most_tricks"
+	case WinTypeFewestTricks:
+		return "fewest_tricks"
+	case WinTypeMostChips:
+		return "most_chips"
+	case WinTypeMostCards:
+		return "most_cards"
+	case WinTypeBlackjack:
+		return "blackjack"
+	}
+	return fmt.Sprintf("unknown(%d)", winType)
+}
+
+// tableauModeName returns the genome name of a tableau mode
+func tableauModeName(mode uint8) string {
+	switch mode {
+	case 0:
+		return "none"
+	case 1:
+		return "war"
+	case 2:
+		return "match_rank"
+	case 3:
+		return "sequence"
+	}
+	return fmt.Sprintf("unknown(%d)", mode)
+}
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummaryWar(t *testing.T) {
+	s := loadGoldenGenome(t, "war_genome.bin").Summary()
+
+	want := GenomeSummary{
+		PlayerCount:   2,
+		MaxTurns:      s.MaxTurns,
+		Phases:        []string{"play"},
+		WinConditions: []string{"capture_all"},
+		TableauMode:   "war",
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("Expected %+v, got %+v", want, s)
+	}
+	if s.MaxTurns == 0 {
+		t.Error("Expected a turn limit")
+	}
+	if got := s.String(); got != "2p, phases [play], win [capture_all], tableau war, effects none" {
+		t.Errorf("Unexpected summary string %q", got)
+	}
+}
+
+func TestSummaryPoker(t *testing.T) {
+	s := loadGoldenGenome(t, "simple_poker_genome.bin").Summary()
+
+	if s.PlayerCount != 2 || !reflect.DeepEqual(s.Phases, []string{"betting"}) ||
+		!reflect.DeepEqual(s.WinConditions, []string{"best_hand"}) {
+		t.Errorf("Unexpected poker summary %+v", s)
+	}
+	if !s.UsesBetting || s.UsesTricks || s.UsesBidding {
+		t.Errorf("Expected betting only, got betting=%v tricks=%v bidding=%v", s.UsesBetting, s.UsesTricks, s.UsesBidding)
+	}
+}
+
+func TestSummaryEffectsAndTeams(t *testing.T) {
+	genome := &Genome{
+		Header: &BytecodeHeader{PlayerCount: 4, TeamMode: true, TeamCount: 2},
+		TurnPhases: []PhaseDescriptor{
+			{PhaseType: PhaseTypeBidding}, {PhaseType: PhaseTypeTrick},
+		},
+		WinConditions: []WinCondition{{WinType: WinTypeMostTricks}},
+		Effects: map[uint8]SpecialEffect{
+			AceRank: {EffectType: EFFECT_SKIP_NEXT},
+			0:       {EffectType: EFFECT_DRAW_CARDS},
+		},
+	}
+
+	s := genome.Summary()
+	if !reflect.DeepEqual(s.EffectRanks, []uint8{0, AceRank}) {
+		t.Errorf("Expected sorted effect ranks [0 %d], got %v", AceRank, s.EffectRanks)
+	}
+	if !s.UsesTricks || !s.UsesBidding || s.UsesBetting {
+		t.Errorf("Expected tricks and bidding, got %+v", s)
+	}
+	if got := s.String(); got != "4p in 2 teams, phases [bidding trick], win [most_tricks], tableau none, effects on 2 A" {
+		t.Errorf("Unexpected summary string %q", got)
+	}
+}
@@ -1,5 +1,7 @@
 package engine
 
+import "math"
+
 // WinType constants for tension detection
 // These map to win condition types in bytecode
 const (
@@ -14,6 +16,8 @@ const (
 	WinTypeMostTricks   uint8 = 8 // Trick-collecting games (Spades)
 	WinTypeFewestTricks uint8 = 9 // Trick-avoidance games (Hearts)
 	WinTypeMostChips    uint8 = 10 // Poker cash games
+	WinTypeMostCards    uint8 = 11 // Casino/Scopa - largest capture pile wins
+	WinTypeBlackjack    uint8 = 12 // Hand value closest to the threshold without busting
 )
 
 // TensionMetrics tracks tension curve data during simulation
@@ -23,12 +27,20 @@ type TensionMetrics struct {
 	ClosestMargin    float32 // Smallest normalized gap between 1st and 2nd (0 = tied)
 	TotalTurns       int     // For computing decisive turn percentage
 	WinnerWasTrailing bool   // True if winner was behind at midpoint (comeback win)
+	MarginStdDev      float32 // Std deviation of margin over the game (set by Finalize)
+	CloseCallCount    int     // Times the margin dropped below CloseCallThreshold
 
 	// Internal tracking (not serialized)
 	currentLeader int   // Player ID of current leader (-1 for tie)
 	leaderHistory []int // Leader at each turn (for permanent lead calculation)
+	marginSum     float64
+	marginSumSq   float64
+	lastMargin    float32
 }
 
+// CloseCallThreshold is the normalized margin below which a game counts as close
+const CloseCallThreshold float32 = 0.1
+
 // LeaderDetector interface for game-type-specific leader detection
 type LeaderDetector interface {
 	GetLeader(state *GameState) int     // Returns player ID or -1 for tie
@@ -83,8 +95,8 @@ func (d *ScoreLeaderDetector) GetMargin(state *GameState) float32 {
 			second = p.Score
 		}
 	}
-	if first == 0 {
-		return 0
+	if first <= 0 {
+		return 0 // No positive scores yet (avoid divide by zero)
 	}
 	return float32(first-second) / float32(first)
 }
@@ -135,7 +147,7 @@ func (d *HandSizeLeaderDetector) GetMargin(state *GameState) float32 {
 		}
 	}
 	if maxCards == 0 || second == 999 {
-		return 0
+		return 0 // All hands empty (avoid divide by zero)
 	}
 	return float32(second-first) / float32(maxCards)
 }
@@ -184,7 +196,7 @@ func (d *HandSizeMaxLeaderDetector) GetMargin(state *GameState) float32 {
 		}
 	}
 	if totalCards == 0 {
-		return 0
+		return 0 // No cards in any hand (avoid divide by zero)
 	}
 	return float32(first-second) / float32(totalCards)
 }
@@ -220,9 +232,9 @@ func (d *TrickLeaderDetector) GetMargin(state *GameState) float32 {
 		return 0
 	}
 	var first, second uint8 = 0, 0
-	var totalTricks uint8 = 0
+	totalTricks := 0 // int so the sum cannot wrap
 	for _, tricks := range state.TricksWon {
-		totalTricks += tricks
+		totalTricks += int(tricks)
 		if tricks > first {
 			second = first
 			first = tricks
@@ -231,7 +243,7 @@ func (d *TrickLeaderDetector) GetMargin(state *GameState) float32 {
 		}
 	}
 	if totalTricks == 0 {
-		return 0
+		return 0 // No tricks taken yet (avoid divide by zero)
 	}
 	return float32(first-second) / float32(totalTricks)
 }
@@ -267,9 +279,9 @@ func (d *TrickAvoidanceLeaderDetector) GetMargin(state *GameState) float32 {
 		return 0
 	}
 	var first, second uint8 = 255, 255
-	var totalTricks uint8 = 0
+	totalTricks := 0 // int so the sum cannot wrap
 	for _, tricks := range state.TricksWon {
-		totalTricks += tricks
+		totalTricks += int(tricks)
 		if tricks < first {
 			second = first
 			first = tricks
@@ -278,7 +290,7 @@ func (d *TrickAvoidanceLeaderDetector) GetMargin(state *GameState) float32 {
 		}
 	}
 	if totalTricks == 0 || second == 255 {
-		return 0
+		return 0 // No tricks taken yet (avoid divide by zero)
 	}
 	return float32(second-first) / float32(totalTricks)
 }
@@ -324,8 +336,8 @@ func (d *ChipLeaderDetector) GetMargin(state *GameState) float32 {
 			second = p.Chips
 		}
 	}
-	if totalChips == 0 {
-		return 0
+	if totalChips <= 0 {
+		return 0 // No chips in play (avoid divide by zero)
 	}
 	return float32(first-second) / float32(totalChips)
 }
@@ -340,6 +352,15 @@ func (tm *TensionMetrics) Update(state *GameState, detector LeaderDetector) {
 		tm.ClosestMargin = margin
 	}
 
+	// Track volatility: running sums for std deviation, and entries into
+	// the close-game range from outside it (the first turn has no prior margin)
+	tm.marginSum += float64(margin)
+	tm.marginSumSq += float64(margin) * float64(margin)
+	if tm.TotalTurns > 0 && margin < CloseCallThreshold && tm.lastMargin >= CloseCallThreshold {
+		tm.CloseCallCount++
+	}
+	tm.lastMargin = margin
+
 	// Track lead changes (ignore ties)
 	if newLeader != -1 && tm.currentLeader != -1 && newLeader != tm.currentLeader {
 		tm.LeadChanges++
@@ -350,15 +371,26 @@ func (tm *TensionMetrics) Update(state *GameState, detector LeaderDetector) {
 		tm.currentLeader = newLeader
 	}
 
-	// Record leader for permanent lead calculation
-	tm.leaderHistory = append(tm.leaderHistory, tm.currentLeader)
+	// Record this turn's leader (-1 for tie) for permanent lead calculation
+	tm.leaderHistory = append(tm.leaderHistory, newLeader)
 	tm.TotalTurns++
 }
 
-// Finalize computes DecisiveTurn and WinnerWasTrailing based on winner
-// DecisiveTurn = first turn where winner took lead and NEVER lost it
+// Finalize computes DecisiveTurn, WinnerWasTrailing and MarginStdDev based on winner
+// DecisiveTurn = first turn where winner took lead and NEVER lost or tied it
 // WinnerWasTrailing = true if winner was behind at game midpoint
 func (tm *TensionMetrics) Finalize(winnerID int) {
+	// Margin volatility (population std deviation over all updates)
+	tm.MarginStdDev = 0
+	if tm.TotalTurns > 0 {
+		n := float64(tm.TotalTurns)
+		mean := tm.marginSum / n
+		variance := tm.marginSumSq/n - mean*mean
+		if variance > 0 {
+			tm.MarginStdDev = float32(math.Sqrt(variance))
+		}
+	}
+
 	// Handle invalid winner (draw or error)
 	if winnerID < 0 {
 		tm.DecisiveTurn = tm.TotalTurns
@@ -384,46 +416,22 @@ func (tm *TensionMetrics) Finalize(winnerID int) {
 		tm.WinnerWasTrailing = false
 	}
 
-	// Scan backwards to find when winner took permanent lead
-	// We're looking for the last turn where someone OTHER than the winner was leading
-	tm.DecisiveTurn = tm.TotalTurns
-	foundOtherLeader := false
-	lastWinnerLead := -1 // Track last turn winner was leading (scanning backwards)
-
+	// Scan backwards to find when winner took permanent lead: the turn after
+	// the last turn where the winner was NOT strictly leading. A tie breaks
+	// continuity just like another player leading, so lead-tie-lead counts
+	// from after the tie.
+	tm.DecisiveTurn = 0
 	for i := len(tm.leaderHistory) - 1; i >= 0; i-- {
-		if tm.leaderHistory[i] != winnerID && tm.leaderHistory[i] != -1 {
-			// Found a turn where someone else was leading
-			// Winner took permanent lead after this
-			foundOtherLeader = true
-			if i+1 < len(tm.leaderHistory) {
-				tm.DecisiveTurn = i + 1
+		if tm.leaderHistory[i] != winnerID {
+			// Winner took permanent lead after this turn. If the winner wasn't
+			// leading even at the end (ties until final resolution), the
+			// outcome was uncertain until the very end.
+			tm.DecisiveTurn = i + 1
+			if tm.DecisiveTurn >= len(tm.leaderHistory) {
+				tm.DecisiveTurn = tm.TotalTurns
 			}
 			break
 		}
-		// Track when winner was leading (for the no-other-leader case)
-		if tm.leaderHistory[i] == winnerID && lastWinnerLead == -1 {
-			lastWinnerLead = i
-		}
-	}
-
-	// If no other player was ever leading, find when winner FIRST took lead
-	if !foundOtherLeader {
-		winnerFirstLead := -1
-		for i, leader := range tm.leaderHistory {
-			if leader == winnerID {
-				winnerFirstLead = i
-				break
-			}
-		}
-
-		if winnerFirstLead >= 0 {
-			// Winner was leading from turn winnerFirstLead onwards
-			tm.DecisiveTurn = winnerFirstLead
-		} else {
-			// Winner was NEVER explicitly leading (all ties until final resolution)
-			// This means maximum tension - outcome was uncertain until the very end
-			tm.DecisiveTurn = tm.TotalTurns
-		}
 	}
 }
 
@@ -454,8 +462,8 @@ func SelectLeaderDetector(genome *Genome) LeaderDetector {
 		case WinTypeCaptureAll:
 			// War-style: captured cards go back to hand, more cards = winning
 			return &HandSizeMaxLeaderDetector{}
-		case WinTypeMostCaptured:
-			// Scopa-style: captured cards tracked via Score
+		case WinTypeMostCaptured, WinTypeMostCards:
+			// Scopa-style: each captured card also scores a point
 			return &ScoreLeaderDetector{}
 		}
 	}
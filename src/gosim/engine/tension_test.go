@@ -284,6 +284,55 @@ func TestChipLeaderDetector_GetMargin(t *testing.T) {
 
 // SelectLeaderDetector tests
 
+func TestLeaderDetectors_GetMargin_AllZero(t *testing.T) {
+	detectors := map[string]LeaderDetector{
+		"score":         &ScoreLeaderDetector{},
+		"hand_size":     &HandSizeLeaderDetector{},
+		"hand_size_max": &HandSizeMaxLeaderDetector{},
+		"trick":         &TrickLeaderDetector{},
+		"trick_avoid":   &TrickAvoidanceLeaderDetector{},
+		"chip":          &ChipLeaderDetector{},
+	}
+
+	// Start of game: no scores, no cards, no tricks, no chips
+	state := &GameState{
+		NumPlayers: 4,
+		Players:    []PlayerState{{}, {}, {}, {}},
+		TricksWon:  []uint8{0, 0, 0, 0},
+	}
+
+	for name, detector := range detectors {
+		margin := detector.GetMargin(state)
+		if margin != 0 {
+			t.Errorf("%s: expected margin=0 with all-zero totals, got %f", name, margin)
+		}
+	}
+
+	// A zero margin must not corrupt ClosestMargin
+	tm := NewTensionMetrics(4)
+	tm.Update(state, &TrickLeaderDetector{})
+	if tm.ClosestMargin != 0 {
+		t.Errorf("expected ClosestMargin=0, got %f", tm.ClosestMargin)
+	}
+}
+
+func TestTrickLeaderDetector_GetMargin_LargeTotals(t *testing.T) {
+	detector := &TrickLeaderDetector{}
+
+	// Totals above 255 must not wrap the denominator
+	state := &GameState{
+		NumPlayers: 2,
+		Players:    []PlayerState{{}, {}},
+		TricksWon:  []uint8{200, 100},
+	}
+
+	margin := detector.GetMargin(state)
+	// (200-100)/300 ≈ 0.333
+	if margin < 0.33 || margin > 0.34 {
+		t.Errorf("expected margin≈0.333, got %f", margin)
+	}
+}
+
 func TestSelectLeaderDetector_EmptyHand(t *testing.T) {
 	genome := &Genome{
 		WinConditions: []WinCondition{{WinType: WinTypeEmptyHand}},
@@ -449,6 +498,66 @@ func TestTensionMetrics_Update_ClosestMargin(t *testing.T) {
 	}
 }
 
+func TestTensionMetrics_Volatility_Blowout(t *testing.T) {
+	tm := NewTensionMetrics(2)
+	detector := &ScoreLeaderDetector{}
+
+	// Player 0 runs away with it: margin stays near 0.9 every turn
+	for turn := 1; turn <= 6; turn++ {
+		state := &GameState{Players: []PlayerState{{Score: int32(100 * turn)}, {Score: int32(10 * turn)}}}
+		tm.Update(state, detector)
+	}
+	tm.Finalize(0)
+
+	if tm.MarginStdDev > 0.01 {
+		t.Errorf("expected near-zero MarginStdDev for blowout, got %f", tm.MarginStdDev)
+	}
+	if tm.CloseCallCount != 0 {
+		t.Errorf("expected 0 close calls for blowout, got %d", tm.CloseCallCount)
+	}
+}
+
+func TestTensionMetrics_Volatility_SeeSaw(t *testing.T) {
+	tm := NewTensionMetrics(2)
+	detector := &ScoreLeaderDetector{}
+
+	// Margin swings between 0.8 (100 vs 20) and 0.05 (100 vs 95)
+	scores := [][2]int32{{100, 20}, {100, 95}, {20, 100}, {95, 100}, {100, 20}, {100, 95}}
+	for _, sc := range scores {
+		state := &GameState{Players: []PlayerState{{Score: sc[0]}, {Score: sc[1]}}}
+		tm.Update(state, detector)
+	}
+	tm.Finalize(0)
+
+	// Margins: 0.8, 0.05, 0.8, 0.05, 0.8, 0.05 -> std dev 0.375
+	if tm.MarginStdDev < 0.37 || tm.MarginStdDev > 0.38 {
+		t.Errorf("expected MarginStdDev~0.375 for see-saw, got %f", tm.MarginStdDev)
+	}
+	// Dropped into the close range three times
+	if tm.CloseCallCount != 3 {
+		t.Errorf("expected 3 close calls, got %d", tm.CloseCallCount)
+	}
+}
+
+func TestTensionMetrics_Volatility_CloseFromStart(t *testing.T) {
+	tm := NewTensionMetrics(2)
+	detector := &ScoreLeaderDetector{}
+
+	// Starting close (all zero) is not a crossing; staying close adds none
+	for i := 0; i < 3; i++ {
+		state := &GameState{Players: []PlayerState{{Score: 0}, {Score: 0}}}
+		tm.Update(state, detector)
+	}
+	tm.Finalize(-1)
+
+	if tm.CloseCallCount != 0 {
+		t.Errorf("expected 0 close calls, got %d", tm.CloseCallCount)
+	}
+	if tm.MarginStdDev != 0 {
+		t.Errorf("expected MarginStdDev=0, got %f", tm.MarginStdDev)
+	}
+}
+
 func TestTensionMetrics_Finalize_PermanentLead(t *testing.T) {
 	tm := NewTensionMetrics(2)
 	detector := &ScoreLeaderDetector{}
@@ -477,6 +586,49 @@ func TestTensionMetrics_Finalize_PermanentLead(t *testing.T) {
 	}
 }
 
+func TestTensionMetrics_Finalize_LeadTieLead(t *testing.T) {
+	tm := NewTensionMetrics(2)
+	detector := &ScoreLeaderDetector{}
+
+	scores := [][2]int32{
+		{10, 5},  // turn 0: player 0 leads
+		{15, 5},  // turn 1: player 0 leads
+		{15, 15}, // turn 2: tie
+		{20, 15}, // turn 3: player 0 leads again
+		{25, 15}, // turn 4: player 0 leads
+	}
+	for _, sc := range scores {
+		state := &GameState{Players: []PlayerState{{Score: sc[0]}, {Score: sc[1]}}}
+		tm.Update(state, detector)
+	}
+	tm.Finalize(0)
+
+	// The tie at turn 2 breaks the lead, so the permanent lead starts at turn 3
+	if tm.DecisiveTurn != 3 {
+		t.Errorf("expected DecisiveTurn=3 (after the tie), got %d", tm.DecisiveTurn)
+	}
+	// A tie is not a lead change
+	if tm.LeadChanges != 0 {
+		t.Errorf("expected 0 lead changes, got %d", tm.LeadChanges)
+	}
+}
+
+func TestTensionMetrics_Finalize_TiedAtEnd(t *testing.T) {
+	tm := NewTensionMetrics(2)
+	detector := &ScoreLeaderDetector{}
+
+	// Player 0 leads, then the game ends level and is decided by a tie-breaker
+	for _, sc := range [][2]int32{{10, 5}, {10, 10}} {
+		state := &GameState{Players: []PlayerState{{Score: sc[0]}, {Score: sc[1]}}}
+		tm.Update(state, detector)
+	}
+	tm.Finalize(0)
+
+	if tm.DecisiveTurn != tm.TotalTurns {
+		t.Errorf("expected DecisiveTurn=%d (undecided until the end), got %d", tm.TotalTurns, tm.DecisiveTurn)
+	}
+}
+
 func TestTensionMetrics_Finalize_Draw(t *testing.T) {
 	tm := NewTensionMetrics(2)
 	tm.TotalTurns = 50
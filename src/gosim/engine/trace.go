@@ -0,0 +1,55 @@
+package engine
+
+// Tracing
+//
+// While GameState.Trace is set, the engine reports what it does as it does
+// it: each applied move, then any card effect, trick or betting action the
+// move caused, and finally a win when a terminal check finds one. A nil
+// Trace costs a single nil check per hook. Like move history, the hook is
+// not copied by Clone, so search rollouts on clones stay silent.
+
+// TraceKind says what a TraceEvent reports
+type TraceKind uint8
+
+const (
+	TraceMove   TraceKind = iota // A move is being applied
+	TraceEffect                  // A card effect fired
+	TraceTrick                   // A trick was resolved
+	TraceBet                     // A betting action was applied
+	TraceWin                     // A win condition was met
+)
+
+// String returns the snake_case name used in worker responses
+func (k TraceKind) String() string {
+	switch k {
+	case TraceMove:
+		return "move"
+	case TraceEffect:
+		return "effect"
+	case TraceTrick:
+		return "trick"
+	case TraceBet:
+		return "bet"
+	case TraceWin:
+		return "win"
+	}
+	return "unknown"
+}
+
+// TraceEvent is one engine event. Player is the mover (TraceMove,
+// TraceEffect, TraceBet), the trick winner or the game winner; only the
+// fields for Kind are set.
+type TraceEvent struct {
+	Kind   TraceKind
+	Turn   uint32
+	Player int
+	Move   LegalMove     // TraceMove
+	Effect SpecialEffect // TraceEffect
+	Card   Card          // TraceTrick: the winning card
+	Action BettingAction // TraceBet
+	Amount int64         // TraceBet: chips put into the pot
+	Reason EndReason     // TraceWin
+}
+
+// TraceFunc receives engine events; see GameState.Trace
+type TraceFunc func(event TraceEvent)
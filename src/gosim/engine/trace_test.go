@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+// recordTrace hooks state's Trace and returns the recorded events
+func recordTrace(state *GameState) *[]TraceEvent {
+	events := &[]TraceEvent{}
+	state.Trace = func(e TraceEvent) { *events = append(*events, e) }
+	return events
+}
+
+// kindsOf lists the kinds of events in order
+func kindsOf(events []TraceEvent) []TraceKind {
+	kinds := make([]TraceKind, len(events))
+	for i, e := range events {
+		kinds[i] = e.Kind
+	}
+	return kinds
+}
+
+func TestTraceMoveAndEffect(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 3, Suit: 0}, {Rank: 9, Suit: 0}}
+	state.Players[1].Hand = []Card{{Rank: 4, Suit: 1}}
+	genome := &Genome{
+		TurnPhases: []PhaseDescriptor{
+			{PhaseType: PhaseTypePlay, Data: []byte{byte(LocationDiscard), 1, 1, 1, 0, 0, 0, 0, 0}},
+		},
+		Effects: map[uint8]SpecialEffect{9: {TriggerRank: 9, EffectType: EFFECT_REVERSE}},
+	}
+	events := recordTrace(state)
+
+	move := LegalMove{PhaseIndex: 0, CardIndex: 1, TargetLoc: LocationDiscard}
+	ApplyMove(state, &move, genome)
+
+	want := []TraceKind{TraceMove, TraceEffect}
+	if got := kindsOf(*events); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected events %v, got %v", want, got)
+	}
+	if e := (*events)[0]; e.Move != move || e.Player != 0 || e.Turn != 0 {
+		t.Errorf("Expected move event for player 0 at turn 0, got %+v", e)
+	}
+	if e := (*events)[1]; e.Effect.EffectType != EFFECT_REVERSE || e.Player != 0 {
+		t.Errorf("Expected reverse effect for player 0, got %+v", e)
+	}
+}
+
+func TestTraceTrickAndWin(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.Players[0].Hand = []Card{{Rank: 5, Suit: 2}}
+	state.Players[1].Hand = []Card{{Rank: 10, Suit: 2}}
+	genome := &Genome{
+		TurnPhases:    []PhaseDescriptor{{PhaseType: PhaseTypeTrick}},
+		WinConditions: []WinCondition{{WinType: WinTypeAllHandEmpty}},
+	}
+	events := recordTrace(state)
+
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationTableau}, genome)
+	ApplyMove(state, &LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: LocationTableau}, genome)
+	winner := CheckWinConditions(state, genome)
+
+	want := []TraceKind{TraceMove, TraceMove, TraceTrick, TraceWin}
+	if got := kindsOf(*events); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected events %v, got %v", want, got)
+	}
+	if e := (*events)[2]; e.Player != 1 || e.Card != (Card{Rank: 10, Suit: 2}) {
+		t.Errorf("Expected player 1 to take the trick with the ten, got %+v", e)
+	}
+	if e := (*events)[3]; e.Player != int(winner) || e.Reason != EndReasonHandsPlayed {
+		t.Errorf("Expected win event for player %d, got %+v", winner, e)
+	}
+}
+
+func TestTraceBet(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.NumPlayers = 2
+	state.InitializeChips(100)
+	events := recordTrace(state)
+
+	phase := &BettingPhaseData{MinBet: 10, MaxRaises: 3}
+	ApplyBettingAction(state, phase, 0, BettingBet)
+	ApplyBettingAction(state, phase, 1, BettingFold)
+
+	if len(*events) != 2 {
+		t.Fatalf("Expected 2 bet events, got %v", *events)
+	}
+	if e := (*events)[0]; e.Kind != TraceBet || e.Action != BettingBet || e.Amount != 10 {
+		t.Errorf("Expected a 10-chip bet, got %+v", e)
+	}
+	if e := (*events)[1]; e.Player != 1 || e.Action != BettingFold || e.Amount != 0 {
+		t.Errorf("Expected player 1 to fold, got %+v", e)
+	}
+}
+
+func TestTraceNotCopied(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	recordTrace(state)
+
+	clone := state.Clone()
+	defer PutState(clone)
+	if clone.Trace != nil {
+		t.Error("Expected Clone not to copy the trace hook")
+	}
+
+	state.Reset()
+	if state.Trace != nil {
+		t.Error("Expected Reset to clear the trace hook")
+	}
+}
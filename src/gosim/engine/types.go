@@ -2,11 +2,12 @@ package engine
 
 import (
 	"sync"
+	"sync/atomic"
 )
 
 // Card represents a playing card (1 byte)
 type Card struct {
-	Rank uint8 // 0-12 (A,2-10,J,Q,K)
+	Rank uint8 // 0-12 (2-10,J,Q,K,A); compare via RankValue
 	Suit uint8 // 0-3 (H,D,C,S)
 }
 
@@ -25,18 +26,25 @@ const (
 
 // PlayerState is mutable for performance
 type PlayerState struct {
-	Hand   []Card
-	Score  int32
-	Active bool // Still in the game (not folded/eliminated)
+	Hand       []Card
+	Score      int32
+	Active     bool   // Still in the game (not folded/eliminated)
+	Eliminated bool   // Out of the game for good (see EliminatePlayer)
+	Captured   []Card // Cards won by capture (Scopa/Casino-style)
+	Sweeps     int    // Captures that cleared the tableau (see ScoreScopa)
+	FaceUp     []Card // Hand cards visible to opponents (see IsFaceUp)
 	// Optional extensions for betting games
 	Chips      int64 // Chip/token count for betting games (int64 for precision)
 	CurrentBet int64 // Current bet in this round (int64 for precision)
+	TotalBet   int64 // Chips bet this hand over all its betting rounds
 	HasFolded  bool  // Folded this round
 	IsAllIn    bool  // Track all-in status (can't act but still in hand)
 	// Bidding fields (reset each hand)
 	CurrentBid int8 // -1 = not bid, 0+ = bid amount
 	IsNilBid   bool // True if this is a Nil bid
 	TricksWon  int8 // Tricks won this hand
+	// Point-card values taken in tricks this hand (Genome.RankValues)
+	TrickPoints int32
 }
 
 // Claim represents a bluffing claim for games like I Doubt It, Cheat, BS
@@ -61,15 +69,25 @@ type GameState struct {
 	Deck          []Card
 	Discard       []Card
 	Tableau       [][]Card // For games like War, Gin Rummy
+	Community     []Card   // Shared cards every player can use (poker board)
+	Kitty         []Card   // Cards set aside at the deal (Skat, widow)
+	KittyFaceUp   bool     // Kitty is visible to every player
 	CurrentPlayer uint8
+	CurrentPhase  int // First turn phase still open this turn (Genome.SequentialPhases)
 	TurnNumber    uint32
 	WinnerID      int8 // -1 = no winner yet, 0/1 = player ID
+	Knocker       int8 // Player who knocked to end the hand, -1 = none (Gin Rummy)
+	// Player who started the current hand (see RotateStartingPlayer)
+	StartingPlayer uint8
 	// Optional extensions for betting games
 	Pot                int64 // Current pot size (int64 for precision)
+	CarryoverPot       int64 // Chips rolled over from passed hands into the next pot (see CarryOverPot)
 	CurrentBet         int64 // Highest bet in current round (int64 for precision)
 	RaiseCount         int   // Raises this round
+	BettingRound       int   // Betting rounds finished this hand (see NextBettingRound)
 	BettingStartPlayer int   // Rotates each hand for position fairness
 	BettingComplete    bool  // True after betting round finishes (for blackjack: betting before draw)
+	DealRound          int   // Next DealPattern stage to deal (0 = nothing dealt yet)
 	// Optional extensions for bluffing games
 	CurrentClaim *Claim // nil if no active claim
 	// Trick-taking game state
@@ -79,12 +97,24 @@ type GameState struct {
 	HeartsBroken   bool        // For Hearts: whether hearts have been played
 	NumPlayers     uint8       // Number of players (for trick completion check)
 	CardsPerPlayer int         // Cards dealt to each player (for hand size check)
+	// Simultaneous play state (see simultaneous.go)
+	Committed []TrickCard // Face-down plays of the current round, in seat order of play
+	CommitPot []Card      // Cards of tied rounds, won with the next round
+	// SeenCards has a bit set for each card shown to the table this hand (see seen.go)
+	SeenCards uint64
 	// Tableau mode for card matching games
-	TableauMode       uint8 // 0=NONE, 1=WAR, 2=MATCH_RANK, 3=SEQUENCE
-	SequenceDirection uint8 // 0=ASC, 1=DESC, 2=BOTH
+	TableauMode       uint8    // 0=NONE, 1=WAR, 2=MATCH_RANK, 3=SEQUENCE
+	SequenceDirection uint8    // 0=ASC, 1=DESC, 2=BOTH
+	AceLow            bool     // Ace ranks below 2 (see RankValue)
+	WarToBottom       bool     // WAR hands are piles: play the top (last) card, won cards go underneath
+	SuitOrder         [4]uint8 // Suit precedence breaking rank ties, higher wins; all zero = rank only
+	// PerfectInformation plays hands open: no player's cards are hidden from the others
+	PerfectInformation bool
 	// Special effects state
 	PlayDirection int8  // 1 = clockwise, -1 = counter-clockwise
 	SkipCount     uint8 // Number of players to skip (capped at NumPlayers-1)
+	// Players knocked out, in the order they went (see EliminatePlayer)
+	EliminationOrder []uint8
 	// Blackjack-specific state
 	HasStood []bool // Track which players have stood (for blackjack)
 	// President/climbing game state
@@ -97,16 +127,29 @@ type GameState struct {
 	BiddingComplete bool   // True when all players have bid
 	TeamContracts   []int8 // Contract per team (sum of non-Nil bids)
 	AccumulatedBags []int8 // Bags per team, persists across hands
+	// Move history for UndoLastMove/RedoMove, kept only while RecordHistory is set
+	RecordHistory bool
+	History       []MoveRecord // Applied moves, oldest first
+	Undone        []LegalMove  // Moves undone since the last ApplyMove, most recent last
+	// Optional event hook (nil = silent), not copied by Clone
+	Trace TraceFunc
 }
 
 // StatePool manages GameState memory
+//
+// Ownership: whoever calls GetState, GetStateN or Clone owns the returned
+// state and must call PutState exactly once when done, after which the state
+// must not be touched again. Build with -tags pooldebug to panic on double-Put.
 var StatePool = sync.Pool{
 	New: func() interface{} {
+		poolMisses.Add(1)
 		return &GameState{
 			Players:      make([]PlayerState, 4), // Support up to 4 players
 			Deck:         make([]Card, 0, 52),
 			Discard:      make([]Card, 0, 52),
 			Tableau:      make([][]Card, 0, 10),
+			Community:    make([]Card, 0, 5),
+			Kitty:        make([]Card, 0, 4),
 			CurrentTrick: make([]TrickCard, 0, 4), // Max 4 players per trick
 			TricksWon:    make([]uint8, 0, 4),     // Max 4 players
 			HasStood:     make([]bool, 4),         // Max 4 players for blackjack
@@ -114,53 +157,120 @@ var StatePool = sync.Pool{
 	},
 }
 
+// pooledPlayers is the Players length handed out by GetState
+const pooledPlayers = 4
+
+// Pool counters: every GetState/GetStateN is a get, and every get the pool
+// had to satisfy by allocating is also a miss
+var poolGets, poolMisses atomic.Uint64
+
+// PoolStats returns how many states were taken from StatePool that were
+// reused (hits) and freshly allocated (misses) since the process started
+func PoolStats() (hits, misses uint64) {
+	misses = poolMisses.Load()
+	gets := poolGets.Load()
+	if gets < misses {
+		return 0, misses
+	}
+	return gets - misses, misses
+}
+
 // GetState acquires a GameState from pool
 func GetState() *GameState {
+	poolGets.Add(1)
 	state := StatePool.Get().(*GameState)
+	trackGet(state)
+	state.resizePlayers(pooledPlayers)
 	state.Reset()
 	return state
 }
 
+// GetStateN acquires a GameState from pool with exactly numPlayers clean
+// players, reusing the pooled slices' capacity where possible
+func GetStateN(numPlayers int) *GameState {
+	if numPlayers <= 0 {
+		numPlayers = 2 // Default fallback
+	}
+	poolGets.Add(1)
+	state := StatePool.Get().(*GameState)
+	trackGet(state)
+	state.resizePlayers(numPlayers)
+	state.Reset()
+	state.NumPlayers = uint8(numPlayers)
+	return state
+}
+
+// resizePlayers sets the length of the per-player slices, growing them only
+// when the existing capacity is too small. Reset clears the new entries.
+func (s *GameState) resizePlayers(n int) {
+	if cap(s.Players) >= n {
+		s.Players = s.Players[:n]
+	} else {
+		players := make([]PlayerState, n)
+		copy(players, s.Players)
+		s.Players = players
+	}
+	if cap(s.HasStood) >= n {
+		s.HasStood = s.HasStood[:n]
+	} else {
+		s.HasStood = make([]bool, n)
+	}
+}
+
 // PutState returns a GameState to pool
 func PutState(state *GameState) {
+	trackPut(state)
 	StatePool.Put(state)
 }
 
 // NewGameState creates a new GameState with the specified number of players
 func NewGameState(numPlayers int) *GameState {
-	state := GetState()
-	state.NumPlayers = uint8(numPlayers)
-	return state
+	return GetStateN(numPlayers)
 }
 
 // Reset clears state for reuse
 func (s *GameState) Reset() {
-	// Reset all 4 potential players
+	// Reset every player slot, including any beyond NumPlayers
 	for i := 0; i < len(s.Players); i++ {
 		s.Players[i].Hand = s.Players[i].Hand[:0]
+		s.Players[i].Captured = s.Players[i].Captured[:0]
+		s.Players[i].Sweeps = 0
+		s.Players[i].FaceUp = s.Players[i].FaceUp[:0]
 		s.Players[i].Score = 0
 		s.Players[i].Active = true
+		s.Players[i].Eliminated = false
 		s.Players[i].Chips = 0
 		s.Players[i].CurrentBet = 0
+		s.Players[i].TotalBet = 0
 		s.Players[i].HasFolded = false
 		s.Players[i].IsAllIn = false
 		// Bidding fields
 		s.Players[i].CurrentBid = -1
 		s.Players[i].IsNilBid = false
 		s.Players[i].TricksWon = 0
+		s.Players[i].TrickPoints = 0
 	}
 
 	s.Deck = s.Deck[:0]
 	s.Discard = s.Discard[:0]
 	s.Tableau = s.Tableau[:0]
+	s.Community = s.Community[:0]
+	s.Kitty = s.Kitty[:0]
+	s.KittyFaceUp = false
 	s.CurrentPlayer = 0
+	s.StartingPlayer = 0
+	s.CurrentPhase = 0
 	s.TurnNumber = 0
 	s.WinnerID = -1
+	s.Knocker = -1
 	s.Pot = 0
+	s.CarryoverPot = 0
 	s.CurrentBet = 0
 	s.RaiseCount = 0
+	s.BettingRound = 0
 	s.BettingComplete = false
 	s.BettingStartPlayer = 0
+	s.DealRound = 0
 	s.CurrentClaim = nil
 	// Trick-taking state
 	s.CurrentTrick = s.CurrentTrick[:0]
@@ -169,10 +279,18 @@ func (s *GameState) Reset() {
 	s.HeartsBroken = false
 	s.NumPlayers = 2
 	s.CardsPerPlayer = 0
+	s.Committed = s.Committed[:0]
+	s.CommitPot = s.CommitPot[:0]
+	s.SeenCards = 0
 	s.TableauMode = 0
 	s.SequenceDirection = 0
+	s.AceLow = false
+	s.WarToBottom = false
+	s.PerfectInformation = false
+	s.SuitOrder = [4]uint8{}
 	s.PlayDirection = 1
 	s.SkipCount = 0
+	s.EliminationOrder = s.EliminationOrder[:0]
 	// Blackjack state
 	for i := 0; i < len(s.HasStood); i++ {
 		s.HasStood[i] = false
@@ -187,114 +305,151 @@ func (s *GameState) Reset() {
 	s.BiddingComplete = false
 	s.TeamContracts = nil
 	s.AccumulatedBags = nil
+	// Move history
+	s.RecordHistory = false
+	s.clearHistory()
+	s.Trace = nil
 }
 
 // Clone creates a deep copy for MCTS tree search
+// The caller owns the copy and must return it with PutState.
+// Move history and the Trace hook are not copied.
 func (s *GameState) Clone() *GameState {
-	clone := GetState()
+	clone := GetStateN(len(s.Players))
+	clone.copyFrom(s)
+	return clone
+}
 
-	// Clone all active players
-	numPlayers := int(s.NumPlayers)
+// copyFrom deep-copies src's game state into s, which must be freshly Reset
+// with at least as many players as src
+func (s *GameState) copyFrom(src *GameState) {
+	// Copy all active players
+	numPlayers := int(src.NumPlayers)
 	if numPlayers == 0 {
 		numPlayers = 2 // Default fallback
 	}
-	for i := 0; i < numPlayers && i < len(s.Players); i++ {
-		clone.Players[i].Hand = append(clone.Players[i].Hand, s.Players[i].Hand...)
-		clone.Players[i].Score = s.Players[i].Score
-		clone.Players[i].Active = s.Players[i].Active
-		clone.Players[i].Chips = s.Players[i].Chips
-		clone.Players[i].CurrentBet = s.Players[i].CurrentBet
-		clone.Players[i].HasFolded = s.Players[i].HasFolded
-		clone.Players[i].IsAllIn = s.Players[i].IsAllIn
+	for i := 0; i < numPlayers && i < len(src.Players); i++ {
+		s.Players[i].Hand = append(s.Players[i].Hand, src.Players[i].Hand...)
+		s.Players[i].Captured = append(s.Players[i].Captured, src.Players[i].Captured...)
+		s.Players[i].Sweeps = src.Players[i].Sweeps
+		s.Players[i].FaceUp = append(s.Players[i].FaceUp, src.Players[i].FaceUp...)
+		s.Players[i].Score = src.Players[i].Score
+		s.Players[i].Active = src.Players[i].Active
+		s.Players[i].Eliminated = src.Players[i].Eliminated
+		s.Players[i].Chips = src.Players[i].Chips
+		s.Players[i].CurrentBet = src.Players[i].CurrentBet
+		s.Players[i].TotalBet = src.Players[i].TotalBet
+		s.Players[i].HasFolded = src.Players[i].HasFolded
+		s.Players[i].IsAllIn = src.Players[i].IsAllIn
 		// Bidding fields
-		clone.Players[i].CurrentBid = s.Players[i].CurrentBid
-		clone.Players[i].IsNilBid = s.Players[i].IsNilBid
-		clone.Players[i].TricksWon = s.Players[i].TricksWon
+		s.Players[i].CurrentBid = src.Players[i].CurrentBid
+		s.Players[i].IsNilBid = src.Players[i].IsNilBid
+		s.Players[i].TricksWon = src.Players[i].TricksWon
+		s.Players[i].TrickPoints = src.Players[i].TrickPoints
 	}
 
-	clone.Deck = append(clone.Deck, s.Deck...)
-	clone.Discard = append(clone.Discard, s.Discard...)
+	s.Deck = append(s.Deck, src.Deck...)
+	s.Discard = append(s.Discard, src.Discard...)
 
-	for _, pile := range s.Tableau {
-		tableuClone := make([]Card, len(pile))
-		copy(tableuClone, pile)
-		clone.Tableau = append(clone.Tableau, tableuClone)
+	for _, pile := range src.Tableau {
+		pileCopy := make([]Card, len(pile))
+		copy(pileCopy, pile)
+		s.Tableau = append(s.Tableau, pileCopy)
 	}
+	s.Community = append(s.Community, src.Community...)
+	s.Kitty = append(s.Kitty, src.Kitty...)
+	s.KittyFaceUp = src.KittyFaceUp
 
-	clone.CurrentPlayer = s.CurrentPlayer
-	clone.TurnNumber = s.TurnNumber
-	clone.WinnerID = s.WinnerID
-	clone.Pot = s.Pot
-	clone.CurrentBet = s.CurrentBet
-	clone.RaiseCount = s.RaiseCount
-	clone.BettingStartPlayer = s.BettingStartPlayer
-
-	// Clone claim if present
-	if s.CurrentClaim != nil {
-		clone.CurrentClaim = &Claim{
-			ClaimerID:    s.CurrentClaim.ClaimerID,
-			ClaimedRank:  s.CurrentClaim.ClaimedRank,
-			ClaimedCount: s.CurrentClaim.ClaimedCount,
-			CardsPlayed:  append([]Card{}, s.CurrentClaim.CardsPlayed...),
-			Challenged:   s.CurrentClaim.Challenged,
-			ChallengerID: s.CurrentClaim.ChallengerID,
+	s.CurrentPlayer = src.CurrentPlayer
+	s.StartingPlayer = src.StartingPlayer
+	s.CurrentPhase = src.CurrentPhase
+	s.TurnNumber = src.TurnNumber
+	s.WinnerID = src.WinnerID
+	s.Knocker = src.Knocker
+	s.Pot = src.Pot
+	s.CarryoverPot = src.CarryoverPot
+	s.CurrentBet = src.CurrentBet
+	s.RaiseCount = src.RaiseCount
+	s.BettingRound = src.BettingRound
+	s.BettingStartPlayer = src.BettingStartPlayer
+	s.BettingComplete = src.BettingComplete
+	s.DealRound = src.DealRound
+
+	// Copy claim if present
+	if src.CurrentClaim != nil {
+		s.CurrentClaim = &Claim{
+			ClaimerID:    src.CurrentClaim.ClaimerID,
+			ClaimedRank:  src.CurrentClaim.ClaimedRank,
+			ClaimedCount: src.CurrentClaim.ClaimedCount,
+			CardsPlayed:  append([]Card{}, src.CurrentClaim.CardsPlayed...),
+			Challenged:   src.CurrentClaim.Challenged,
+			ChallengerID: src.CurrentClaim.ChallengerID,
 		}
 	}
 
-	// Clone trick-taking state
-	clone.CurrentTrick = append(clone.CurrentTrick, s.CurrentTrick...)
-	clone.TrickLeader = s.TrickLeader
-	clone.TricksWon = append(clone.TricksWon, s.TricksWon...)
-	clone.HeartsBroken = s.HeartsBroken
-	clone.NumPlayers = s.NumPlayers
-	clone.CardsPerPlayer = s.CardsPerPlayer
-	clone.TableauMode = s.TableauMode
-	clone.SequenceDirection = s.SequenceDirection
-	clone.PlayDirection = s.PlayDirection
-	clone.SkipCount = s.SkipCount
-	// Clone blackjack state
-	for i := 0; i < len(s.HasStood) && i < len(clone.HasStood); i++ {
-		clone.HasStood[i] = s.HasStood[i]
+	// Copy trick-taking state
+	s.CurrentTrick = append(s.CurrentTrick, src.CurrentTrick...)
+	s.TrickLeader = src.TrickLeader
+	s.TricksWon = append(s.TricksWon, src.TricksWon...)
+	s.HeartsBroken = src.HeartsBroken
+	s.NumPlayers = src.NumPlayers
+	s.CardsPerPlayer = src.CardsPerPlayer
+	s.Committed = append(s.Committed, src.Committed...)
+	s.CommitPot = append(s.CommitPot, src.CommitPot...)
+	s.SeenCards = src.SeenCards
+	s.TableauMode = src.TableauMode
+	s.SequenceDirection = src.SequenceDirection
+	s.AceLow = src.AceLow
+	s.WarToBottom = src.WarToBottom
+	s.PerfectInformation = src.PerfectInformation
+	s.SuitOrder = src.SuitOrder
+	s.PlayDirection = src.PlayDirection
+	s.SkipCount = src.SkipCount
+	s.EliminationOrder = append(s.EliminationOrder, src.EliminationOrder...)
+	// Copy blackjack state
+	for i := 0; i < len(src.HasStood) && i < len(s.HasStood); i++ {
+		s.HasStood[i] = src.HasStood[i]
 	}
-	// Clone President state
-	clone.ConsecutivePasses = s.ConsecutivePasses
+	// Copy President state
+	s.ConsecutivePasses = src.ConsecutivePasses
 
-	// Clone team fields
-	if s.TeamScores != nil {
-		clone.TeamScores = make([]int32, len(s.TeamScores))
-		copy(clone.TeamScores, s.TeamScores)
+	// Copy team fields
+	if src.TeamScores != nil {
+		s.TeamScores = make([]int32, len(src.TeamScores))
+		copy(s.TeamScores, src.TeamScores)
 	}
-	if s.PlayerToTeam != nil {
-		clone.PlayerToTeam = make([]int8, len(s.PlayerToTeam))
-		copy(clone.PlayerToTeam, s.PlayerToTeam)
+	if src.PlayerToTeam != nil {
+		s.PlayerToTeam = make([]int8, len(src.PlayerToTeam))
+		copy(s.PlayerToTeam, src.PlayerToTeam)
 	}
-	clone.WinningTeam = s.WinningTeam
+	s.WinningTeam = src.WinningTeam
 
-	// Clone bidding fields
-	clone.BiddingComplete = s.BiddingComplete
-	if s.TeamContracts != nil {
-		clone.TeamContracts = make([]int8, len(s.TeamContracts))
-		copy(clone.TeamContracts, s.TeamContracts)
+	// Copy bidding fields
+	s.BiddingComplete = src.BiddingComplete
+	if src.TeamContracts != nil {
+		s.TeamContracts = make([]int8, len(src.TeamContracts))
+		copy(s.TeamContracts, src.TeamContracts)
 	}
-	if s.AccumulatedBags != nil {
-		clone.AccumulatedBags = make([]int8, len(s.AccumulatedBags))
-		copy(clone.AccumulatedBags, s.AccumulatedBags)
+	if src.AccumulatedBags != nil {
+		s.AccumulatedBags = make([]int8, len(src.AccumulatedBags))
+		copy(s.AccumulatedBags, src.AccumulatedBags)
 	}
 
-	return clone
 }
 
 // InitializeChips sets up starting chips for all players
-func (gs *GameState) InitializeChips(startingChips int) {
+func (gs *GameState) InitializeChips(startingChips int64) {
 	for i := range gs.Players {
-		gs.Players[i].Chips = int64(startingChips)
+		gs.Players[i].Chips = startingChips
 		gs.Players[i].CurrentBet = 0
+		gs.Players[i].TotalBet = 0
 		gs.Players[i].HasFolded = false
 		gs.Players[i].IsAllIn = false
 	}
 	gs.Pot = 0
 	gs.CurrentBet = 0
 	gs.RaiseCount = 0
+	gs.BettingRound = 0
 	gs.BettingStartPlayer = 0
 }
 
@@ -302,12 +457,14 @@ func (gs *GameState) InitializeChips(startingChips int) {
 func (gs *GameState) ResetHand() {
 	for i := range gs.Players {
 		gs.Players[i].CurrentBet = 0
+		gs.Players[i].TotalBet = 0
 		gs.Players[i].HasFolded = false
 		gs.Players[i].IsAllIn = false
 	}
 	gs.Pot = 0
 	gs.CurrentBet = 0
 	gs.RaiseCount = 0
+	gs.BettingRound = 0
 	gs.BettingComplete = false
 	gs.BettingStartPlayer = (gs.BettingStartPlayer + 1) % len(gs.Players)
 }
@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"sync"
 	"testing"
 )
 
@@ -104,6 +105,31 @@ func TestGameStateClonePreservesEffectFields(t *testing.T) {
 	}
 }
 
+func TestGameStateCommunity(t *testing.T) {
+	state := GetState()
+	defer PutState(state)
+	state.Tableau = append(state.Tableau, []Card{{Rank: 4, Suit: 2}})
+	state.Community = append(state.Community, Card{Rank: 12, Suit: 0}, Card{Rank: 12, Suit: 1})
+
+	clone := state.Clone()
+	defer PutState(clone)
+	if len(clone.Community) != 2 || clone.Community[1] != (Card{Rank: 12, Suit: 1}) {
+		t.Errorf("Expected clone to keep community cards, got %v", clone.Community)
+	}
+	if len(clone.Tableau) != 1 || len(clone.Tableau[0]) != 1 {
+		t.Errorf("Expected clone tableau separate from community cards, got %v", clone.Tableau)
+	}
+	clone.Community[0] = Card{Rank: 0, Suit: 0}
+	if state.Community[0] != (Card{Rank: 12, Suit: 0}) {
+		t.Error("Expected clone Community to be independent of original")
+	}
+
+	state.Reset()
+	if len(state.Community) != 0 {
+		t.Errorf("Expected Reset to clear Community, got %v", state.Community)
+	}
+}
+
 func TestGameStateHasTableauMode(t *testing.T) {
 	state := NewGameState(2)
 
@@ -457,3 +483,106 @@ func TestGameStateCloneWithNilBidding(t *testing.T) {
 		t.Errorf("Clone should have nil AccumulatedBags, got %v", clone.AccumulatedBags)
 	}
 }
+
+func TestGetStateN(t *testing.T) {
+	s1 := GetStateN(4)
+	if len(s1.Players) != 4 {
+		t.Fatalf("Expected 4 players, got %d", len(s1.Players))
+	}
+	if s1.NumPlayers != 4 {
+		t.Errorf("Expected NumPlayers 4, got %d", s1.NumPlayers)
+	}
+
+	// Dirty every player before returning to the pool
+	for i := range s1.Players {
+		s1.Players[i].Hand = append(s1.Players[i].Hand, Card{Rank: 5, Suit: 1})
+		s1.Players[i].Score = 10
+		s1.Players[i].Chips = 100
+		s1.Players[i].HasFolded = true
+		s1.HasStood[i] = true
+	}
+	PutState(s1)
+
+	// Whether or not the pool hands s1 back, the state comes out clean
+	s2 := GetStateN(4)
+	defer PutState(s2)
+	for i := range s2.Players {
+		p := s2.Players[i]
+		if len(p.Hand) != 0 || p.Score != 0 || p.Chips != 0 || p.HasFolded || !p.Active {
+			t.Errorf("Player %d not reset: %+v", i, p)
+		}
+		if s2.HasStood[i] {
+			t.Errorf("HasStood[%d] not reset", i)
+		}
+	}
+}
+
+func TestGetStateN_ShrinkAndGrow(t *testing.T) {
+	s := GetStateN(2)
+	if len(s.Players) != 2 || len(s.HasStood) != 2 {
+		t.Errorf("Expected 2 player slots, got %d players and %d HasStood", len(s.Players), len(s.HasStood))
+	}
+	PutState(s)
+
+	// GetState restores the default four slots
+	s = GetState()
+	if len(s.Players) != 4 {
+		t.Errorf("Expected 4 players from GetState, got %d", len(s.Players))
+	}
+	PutState(s)
+
+	// Growing beyond the pooled capacity allocates new slots
+	s = GetStateN(6)
+	defer PutState(s)
+	if len(s.Players) != 6 || len(s.HasStood) != 6 {
+		t.Errorf("Expected 6 player slots, got %d players and %d HasStood", len(s.Players), len(s.HasStood))
+	}
+	for i := range s.Players {
+		if !s.Players[i].Active || s.Players[i].CurrentBid != -1 {
+			t.Errorf("Player %d not reset: %+v", i, s.Players[i])
+		}
+	}
+}
+
+func TestStatePool_ConcurrentGetClonePut(t *testing.T) {
+	const workers = 8
+	iterations := 2000
+	if testing.Short() {
+		iterations = 200
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				s := GetStateN(2 + i%3)
+				s.Players[0].Hand = append(s.Players[0].Hand, Card{Rank: uint8(w), Suit: 0})
+				s.Deck = append(s.Deck, Card{Rank: uint8(i % 13), Suit: 1})
+
+				c := s.Clone()
+				PutState(s)
+
+				// The clone must not alias the released original
+				if len(c.Players[0].Hand) != 1 || c.Players[0].Hand[0].Rank != uint8(w) {
+					t.Errorf("worker %d: clone hand corrupted: %v", w, c.Players[0].Hand)
+					PutState(c)
+					return
+				}
+				PutState(c)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestPoolStats(t *testing.T) {
+	hits, misses := PoolStats()
+	state := GetState()
+	PutState(state)
+	afterHits, afterMisses := PoolStats()
+	if got := (afterHits + afterMisses) - (hits + misses); got != 1 {
+		t.Errorf("Expected one pool get to be counted, got %d", got)
+	}
+}
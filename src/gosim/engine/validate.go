@@ -0,0 +1,77 @@
+package engine
+
+import "fmt"
+
+// ValidateState checks that every card in state is a real card (rank 0-12,
+// suit 0-3) or a joker and that no card appears twice across the deck, discard pile,
+// tableau, community cards, current trick, hands and capture piles, as
+// BuildDeck deals from a single 52-card deck. States built from untrusted input, such as the
+// worker's JSON, should pass it before reaching move generation.
+func ValidateState(state *GameState) error {
+	var seen [52 + MaxJokers]bool
+	checkCard := func(card Card, zone string) error {
+		if (card.Rank > AceRank || card.Suit > 3) && !card.IsJoker() {
+			return fmt.Errorf("%s: card with rank %d and suit %d is out of range", zone, card.Rank, card.Suit)
+		}
+		id := int(card.Suit)*13 + int(card.Rank) // Jokers land at 52 and 53
+		if seen[id] {
+			return fmt.Errorf("%s: duplicate card %s", zone, card)
+		}
+		seen[id] = true
+		return nil
+	}
+	check := func(cards []Card, zone string) error {
+		for _, card := range cards {
+			if err := checkCard(card, zone); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := check(state.Deck, "deck"); err != nil {
+		return err
+	}
+	if err := check(state.Discard, "discard"); err != nil {
+		return err
+	}
+	for i, pile := range state.Tableau {
+		if err := check(pile, fmt.Sprintf("tableau pile %d", i)); err != nil {
+			return err
+		}
+	}
+	if err := check(state.Community, "community"); err != nil {
+		return err
+	}
+	if err := check(state.Kitty, "kitty"); err != nil {
+		return err
+	}
+	if state.Pot < 0 || state.CurrentBet < 0 || state.CarryoverPot < 0 {
+		return fmt.Errorf("negative pot %d, carryover %d or current bet %d", state.Pot, state.CarryoverPot, state.CurrentBet)
+	}
+	for _, tc := range state.CurrentTrick {
+		if err := checkCard(tc.Card, "current trick"); err != nil {
+			return err
+		}
+	}
+	for _, tc := range state.Committed {
+		if err := checkCard(tc.Card, "committed cards"); err != nil {
+			return err
+		}
+	}
+	if err := check(state.CommitPot, "commit pot"); err != nil {
+		return err
+	}
+	for i := range state.Players {
+		if err := check(state.Players[i].Hand, fmt.Sprintf("player %d hand", i)); err != nil {
+			return err
+		}
+		if err := check(state.Players[i].Captured, fmt.Sprintf("player %d captured", i)); err != nil {
+			return err
+		}
+		if state.Players[i].Chips < 0 || state.Players[i].CurrentBet < 0 {
+			return fmt.Errorf("player %d has negative chips %d or bet %d", i, state.Players[i].Chips, state.Players[i].CurrentBet)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateState(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	BuildDeck(state, 1)
+	for i := 0; i < 5; i++ {
+		state.DrawCard(0, LocationDeck)
+		state.DrawCard(1, LocationDeck)
+	}
+	state.DrawCard(0, LocationDeck)
+	state.PlayCard(0, 0, LocationDiscard)
+
+	if err := ValidateState(state); err != nil {
+		t.Fatalf("Expected a dealt state to be valid, got %v", err)
+	}
+}
+
+func TestValidateStateDuplicateAce(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	ace := Card{Rank: AceRank, Suit: 3}
+	state.Players[0].Hand = []Card{{Rank: 4, Suit: 0}, ace}
+	state.Discard = []Card{ace}
+
+	err := ValidateState(state)
+	if err == nil || !strings.Contains(err.Error(), "duplicate card AS") {
+		t.Errorf("Expected a duplicate ace error, got %v", err)
+	}
+}
+
+func TestValidateStateOutOfRange(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.Players[1].Captured = []Card{{Rank: 13, Suit: 0}}
+
+	err := ValidateState(state)
+	if err == nil || !strings.Contains(err.Error(), "player 1 captured") {
+		t.Errorf("Expected an out-of-range error for player 1's captured pile, got %v", err)
+	}
+
+	state.Players[1].Captured = nil
+	state.CurrentTrick = []TrickCard{{PlayerID: 0, Card: Card{Rank: 2, Suit: 4}}}
+	if err := ValidateState(state); err == nil {
+		t.Error("Expected an out-of-range suit to be rejected")
+	}
+}
+
+func TestValidateStateJokers(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.Players[0].Hand = []Card{Joker(0), {Rank: 0, Suit: 0}}
+	state.Deck = []Card{Joker(1)}
+	if err := ValidateState(state); err != nil {
+		t.Errorf("Expected jokers to be valid, got %v", err)
+	}
+
+	state.Discard = []Card{Joker(1)}
+	if err := ValidateState(state); err == nil || !strings.Contains(err.Error(), "duplicate card JK2") {
+		t.Errorf("Expected a duplicate joker error, got %v", err)
+	}
+}
+
+func TestValidateStateNegativeChips(t *testing.T) {
+	state := NewGameState(2)
+	defer PutState(state)
+	state.Players[1].Chips = -5
+	if err := ValidateState(state); err == nil || !strings.Contains(err.Error(), "player 1 has negative chips") {
+		t.Errorf("Expected a negative chips error, got %v", err)
+	}
+
+	state.Players[1].Chips = 0
+	state.Pot = -1
+	if err := ValidateState(state); err == nil || !strings.Contains(err.Error(), "negative pot") {
+		t.Errorf("Expected a negative pot error, got %v", err)
+	}
+}
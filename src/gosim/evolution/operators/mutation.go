@@ -47,11 +47,21 @@ func CloneGenome(g *genome.GameGenome) *genome.GameGenome {
 	clone := &genome.GameGenome{
 		Name:       g.Name,
 		Generation: g.Generation,
+		RankValues: g.RankValues,
 		Setup: genome.SetupRules{
-			CardsPerPlayer: g.Setup.CardsPerPlayer,
-			DealToTableau:  g.Setup.DealToTableau,
-			StartingChips:  g.Setup.StartingChips,
-			TableauSize:    g.Setup.TableauSize,
+			CardsPerPlayer:     g.Setup.CardsPerPlayer,
+			DealToTableau:      g.Setup.DealToTableau,
+			StartingChips:      g.Setup.StartingChips,
+			TableauSize:        g.Setup.TableauSize,
+			AceLow:             g.Setup.AceLow,
+			SuitOrder:          g.Setup.SuitOrder,
+			KittySize:          g.Setup.KittySize,
+			KittyFaceUp:        g.Setup.KittyFaceUp,
+			StartingPlayer:     g.Setup.StartingPlayer,
+			MaxHandSize:        g.Setup.MaxHandSize,
+			WarToBottom:        g.Setup.WarToBottom,
+			PerfectInformation: g.Setup.PerfectInformation,
+			MatchPlay:          g.Setup.MatchPlay,
 		},
 		TurnStructure: genome.TurnStructure{
 			MaxTurns:          g.TurnStructure.MaxTurns,
@@ -21,9 +21,12 @@ const (
 	King
 )
 
-// String returns the rank as a string
+// String returns the rank as a string ("?" if out of range)
 func (r Rank) String() string {
 	ranks := []string{"", "A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K"}
+	if r < Ace || r > King {
+		return "?"
+	}
 	return ranks[r]
 }
 
@@ -37,9 +40,21 @@ const (
 	Spades
 )
 
-// String returns the suit as a string
+// String returns the suit as a string ("?" if out of range)
 func (s Suit) String() string {
 	suits := []string{"", "H", "D", "C", "S"}
+	if s < Hearts || s > Spades {
+		return "?"
+	}
+	return suits[s]
+}
+
+// Symbol returns the suit as a display glyph (e.g., "♥")
+func (s Suit) Symbol() string {
+	suits := []string{"", "♥", "♦", "♣", "♠"}
+	if s < Hearts || s > Spades {
+		return "?"
+	}
 	return suits[s]
 }
 
@@ -54,6 +69,11 @@ func (c Card) String() string {
 	return fmt.Sprintf("%s%s", c.Rank.String(), c.Suit.String())
 }
 
+// Label returns the card for display with a suit glyph (e.g., "A♥")
+func (c Card) Label() string {
+	return fmt.Sprintf("%s%s", c.Rank.String(), c.Suit.Symbol())
+}
+
 // NewDeck creates a standard 52-card deck
 func NewDeck() []Card {
 	deck := make([]Card, 0, 52)
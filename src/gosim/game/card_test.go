@@ -21,6 +21,15 @@ func TestCard_String(t *testing.T) {
 	}
 }
 
+func TestCard_Label(t *testing.T) {
+	if got := (Card{Rank: Queen, Suit: Clubs}).Label(); got != "Q♣" {
+		t.Errorf("Card.Label() = %v, want Q♣", got)
+	}
+	if got := (Card{Rank: 0, Suit: 9}).String(); got != "??" {
+		t.Errorf("Card.String() for out-of-range card = %v, want ??", got)
+	}
+}
+
 func TestNewDeck(t *testing.T) {
 	deck := NewDeck()
 
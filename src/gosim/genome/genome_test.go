@@ -547,6 +547,11 @@ func TestJSONRoundTrip(t *testing.T) {
 			CardsPerPlayer: 7,
 			TableauSize:    4,
 			StartingChips:  500,
+			AceLow:         true,
+			SuitOrder:      [4]uint8{2, 1, 3, 4},
+			KittySize:      2,
+			KittyFaceUp:    true,
+			MatchPlay:      true,
 		},
 		TurnStructure: TurnStructure{
 			Phases: []Phase{
@@ -568,8 +573,10 @@ func TestJSONRoundTrip(t *testing.T) {
 					},
 				},
 				&BettingPhase{
-					MinBet:    25,
-					MaxRaises: 4,
+					MinBet:      25,
+					MaxRaises:   4,
+					RakePercent: 5,
+					RakeCap:     3,
 				},
 			},
 			MaxTurns:          150,
@@ -586,6 +593,7 @@ func TestJSONRoundTrip(t *testing.T) {
 		CardScoring: []CardScoringRule{
 			{Suit: 0, Rank: 255, Points: 1, Trigger: TriggerTrickWin},
 		},
+		RankValues: [13]int32{2, 3, 4, 5, 6, 7, 8, 9, 10, 10, 10, 10, 1},
 	}
 
 	// Serialize
@@ -612,6 +620,21 @@ func TestJSONRoundTrip(t *testing.T) {
 	if loaded.Setup.StartingChips != original.Setup.StartingChips {
 		t.Errorf("StartingChips mismatch")
 	}
+	if !loaded.Setup.AceLow {
+		t.Errorf("AceLow lost during round-trip")
+	}
+	if loaded.Setup.SuitOrder != original.Setup.SuitOrder {
+		t.Errorf("SuitOrder mismatch: got %v, want %v", loaded.Setup.SuitOrder, original.Setup.SuitOrder)
+	}
+	if loaded.Setup.KittySize != 2 || !loaded.Setup.KittyFaceUp {
+		t.Errorf("Kitty setup lost during round-trip: %+v", loaded.Setup)
+	}
+	if !loaded.Setup.MatchPlay {
+		t.Errorf("MatchPlay lost during round-trip")
+	}
+	if loaded.RankValues != original.RankValues {
+		t.Errorf("RankValues mismatch: got %v, want %v", loaded.RankValues, original.RankValues)
+	}
 	if len(loaded.TurnStructure.Phases) != len(original.TurnStructure.Phases) {
 		t.Errorf("Phase count mismatch: got %d, want %d",
 			len(loaded.TurnStructure.Phases), len(original.TurnStructure.Phases))
@@ -637,4 +660,7 @@ func TestJSONRoundTrip(t *testing.T) {
 	} else if playPhase.ValidPlayCondition.OpCode != 12 {
 		t.Errorf("Condition OpCode mismatch: got %d, want 12", playPhase.ValidPlayCondition.OpCode)
 	}
+	if betting := loaded.TurnStructure.Phases[2].(*BettingPhase); betting.RakePercent != 5 || betting.RakeCap != 3 {
+		t.Errorf("Rake lost during round-trip: %+v", betting)
+	}
 }
@@ -61,7 +61,7 @@ func appendDrawMoves(moves []engine.LegalMove, state *engine.GameState, currentP
 	case engine.LocationDeck:
 		// If deck is empty but discard has cards, reshuffle would happen
 		if len(state.Deck) == 0 && len(state.Discard) > 1 {
-			// reshuffleDeck would be called - for now just check
+			// engine.RecycleDiscard would be called - for now just check
 			canDraw = true // Would reshuffle
 		}
 		canDraw = canDraw || len(state.Deck) > 0
@@ -107,8 +107,8 @@ func appendPlayMoves(moves []engine.LegalMove, state *engine.GameState, currentP
 	if state.TableauMode == 3 && target == engine.LocationTableau {
 		moves, playMoveCount = appendSequenceMoves(moves, state, currentPlayer, phaseIdx, p, hand, target)
 
-		// If no valid plays but pass_if_unable is set, add pass move
-		if playMoveCount == 0 && p.PassIfUnable {
+		// Pass when the phase allows it (see engine.PlayPassIfUnable)
+		if p.PassAllowed || (playMoveCount == 0 && p.PassIfUnable) {
 			moves = append(moves, engine.LegalMove{
 				PhaseIndex: phaseIdx,
 				CardIndex:  engine.MovePlayPass,
@@ -120,7 +120,11 @@ func appendPlayMoves(moves []engine.LegalMove, state *engine.GameState, currentP
 
 	// Single-card plays (standard)
 	if p.MinCards <= 1 && p.MaxCards >= 1 {
+		fromTop := engine.PlaysFromTop(state, target)
 		for cardIdx, card := range hand {
+			if fromTop && cardIdx != len(hand)-1 {
+				continue
+			}
 			// Evaluate valid_play_condition if present
 			if p.ValidPlayCondition != nil {
 				if !evaluateCardConditionTyped(state, currentPlayer, card, p.ValidPlayCondition) {
@@ -138,16 +142,13 @@ func appendPlayMoves(moves []engine.LegalMove, state *engine.GameState, currentP
 
 	// Multi-card plays (Go Fish sets)
 	if p.MinCards > 1 {
-		rankCounts := make(map[uint8]int)
-		for _, card := range hand {
-			rankCounts[card.Rank]++
-		}
+		rankCounts := engine.CountRanks(hand)
 
 		for rank, count := range rankCounts {
 			if count >= p.MinCards && count <= p.MaxCards {
 				moves = append(moves, engine.LegalMove{
 					PhaseIndex: phaseIdx,
-					CardIndex:  -int(rank) - 100,
+					CardIndex:  -rank - 100,
 					TargetLoc:  target,
 				})
 				playMoveCount++
@@ -155,8 +156,8 @@ func appendPlayMoves(moves []engine.LegalMove, state *engine.GameState, currentP
 		}
 	}
 
-	// If no valid plays but pass_if_unable is set, add pass move
-	if playMoveCount == 0 && p.PassIfUnable {
+	// Pass when the phase allows it (see engine.PlayPassIfUnable)
+	if p.PassAllowed || (playMoveCount == 0 && p.PassIfUnable) {
 		moves = append(moves, engine.LegalMove{
 			PhaseIndex: phaseIdx,
 			CardIndex:  engine.MovePlayPass,
@@ -209,7 +210,7 @@ func appendSequenceMoves(moves []engine.LegalMove, state *engine.GameState, curr
 			for _, pile := range state.Tableau {
 				if len(pile) > 0 {
 					topCard := pile[len(pile)-1]
-					if isValidSequencePlayTyped(card, topCard, state.SequenceDirection) {
+					if isValidSequencePlayTyped(card, topCard, state.SequenceDirection, state.AceLow) {
 						canPlayOnExisting = true
 						break
 					}
@@ -343,8 +344,10 @@ func appendBettingMoves(moves []engine.LegalMove, state *engine.GameState, curre
 
 	// Convert typed BettingPhase to engine.BettingPhaseData for compatibility
 	bettingData := &engine.BettingPhaseData{
-		MinBet:    p.MinBet,
-		MaxRaises: p.MaxRaises,
+		MinBet:      p.MinBet,
+		MaxRaises:   p.MaxRaises,
+		RakePercent: p.RakePercent,
+		RakeCap:     p.RakeCap,
 	}
 
 	bettingMoves := engine.GenerateBettingMoves(state, bettingData, int(currentPlayer))
@@ -464,26 +467,22 @@ func evaluateCardConditionTyped(state *engine.GameState, playerID uint8, card en
 }
 
 // isValidSequencePlayTyped checks sequence validity using typed direction.
-func isValidSequencePlayTyped(card engine.Card, topCard engine.Card, direction uint8) bool {
+// Ranks are compared by engine.RankValue, so runs never wrap past the Ace.
+func isValidSequencePlayTyped(card engine.Card, topCard engine.Card, direction uint8, aceLow bool) bool {
 	if card.Suit != topCard.Suit {
 		return false
 	}
 
+	value := int(engine.RankValue(card.Rank, aceLow))
+	topValue := int(engine.RankValue(topCard.Rank, aceLow))
+
 	switch direction {
 	case 0: // ASCENDING
-		if topCard.Rank == 13 {
-			return false
-		}
-		return card.Rank == topCard.Rank+1
+		return value == topValue+1
 	case 1: // DESCENDING
-		if topCard.Rank == 2 {
-			return false
-		}
-		return card.Rank == topCard.Rank-1
+		return value == topValue-1
 	case 2: // BOTH
-		canAscend := topCard.Rank != 13 && card.Rank == topCard.Rank+1
-		canDescend := topCard.Rank != 2 && card.Rank == topCard.Rank-1
-		return canAscend || canDescend
+		return value == topValue+1 || value == topValue-1
 	}
 	return false
 }
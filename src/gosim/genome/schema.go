@@ -60,6 +60,7 @@ type PlayPhase struct {
 	MaxCards          int        // Maximum cards that can be played
 	Mandatory         bool       // If true, must play if able
 	PassIfUnable      bool       // If true, can pass when no valid plays
+	PassAllowed       bool       // If true, can pass even with valid plays
 	ValidPlayCondition *Condition // Optional condition cards must satisfy
 }
 
@@ -89,8 +90,10 @@ func (p *TrickPhase) phaseMarker()     {}
 
 // BettingPhase represents poker-style betting rounds.
 type BettingPhase struct {
-	MinBet    int // Minimum bet/raise amount
-	MaxRaises int // Maximum raises per round (prevents infinite loops)
+	MinBet      int // Minimum bet/raise amount
+	MaxRaises   int // Maximum raises per round (prevents infinite loops)
+	RakePercent int // House cut of each pot in percent, removed from play (0 = none)
+	RakeCap     int // Most chips raked from one pot (0 = uncapped)
 }
 
 func (p *BettingPhase) PhaseType() uint8 { return PhaseTypeBetting }
@@ -136,6 +139,8 @@ const (
 	WinTypeAllHandsEmpty WinConditionType = 5
 	WinTypeBestHand     WinConditionType = 6
 	WinTypeMostCaptured WinConditionType = 7
+	WinTypeMostCards    WinConditionType = 11 // Largest capture pile when the deck runs out
+	WinTypeBlackjack    WinConditionType = 12 // Hand value closest to the threshold without busting
 )
 
 // WinCondition defines how the game ends and who wins.
@@ -275,10 +280,22 @@ type HandEvaluation struct {
 
 // SetupRules defines initial game setup.
 type SetupRules struct {
-	CardsPerPlayer int  // Cards dealt to each player
-	TableauSize    int  // Number of tableau piles (0 = none)
-	StartingChips  int  // Chips for betting games (0 = no betting)
-	DealToTableau  int  // Cards dealt to tableau at start
+	CardsPerPlayer int      // Cards dealt to each player
+	TableauSize    int      // Number of tableau piles (0 = none)
+	StartingChips  int      // Chips for betting games (0 = no betting)
+	DealToTableau  int      // Cards dealt to tableau at start
+	AceLow         bool     // Ace ranks below 2 instead of above K
+	SuitOrder      [4]uint8 // Suit precedence for rank ties, higher wins (all zero = rank only)
+	KittySize      int      // Cards set aside after dealing hands (Skat, widow)
+	KittyFaceUp    bool     // Kitty is dealt face up
+	StartingPlayer int      // Player who starts the first hand; later hands rotate
+	MaxHandSize    int      // Hand size to discard down to at the end of a turn (0 = no cap)
+	WarToBottom    bool     // War hands are piles: play the top card, won cards go underneath
+	// PerfectInformation plays hands open: no player's cards are hidden
+	PerfectInformation bool
+	// MatchPlay plays hands to the first_to_score or most_chips target
+	// instead of ending the game when a player goes out
+	MatchPlay bool
 }
 
 // TurnStructure defines the phases of each turn.
@@ -306,6 +323,7 @@ type GameGenome struct {
 	WinConditions []WinCondition  // How the game ends
 	Effects       []SpecialEffect // Special card effects
 	CardScoring   []CardScoringRule // Scoring rules
+	RankValues    [13]int32         // Points per rank (2..A) for cards left at hand end; all zero = 1 per card
 	HandEval      *HandEvaluation // Hand evaluation (poker, blackjack)
 	Teams         *TeamConfig     // Optional team configuration
 }
@@ -320,6 +338,7 @@ func (g *GameGenome) Clone() *GameGenome {
 		Name:       g.Name,
 		Generation: g.Generation,
 		Setup:      g.Setup, // SetupRules is a value type
+		RankValues: g.RankValues,
 	}
 
 	// Clone TurnStructure
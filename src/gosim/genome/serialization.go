@@ -20,6 +20,7 @@ type PhaseJSON struct {
 	MinCards           int                `json:"min_cards,omitempty"`
 	MaxCards           int                `json:"max_cards,omitempty"`
 	ValidPlayCondition *ConditionJSON     `json:"valid_play_condition,omitempty"`
+	PassAllowed        bool               `json:"pass_allowed,omitempty"`
 	Condition          *ConditionJSON     `json:"condition,omitempty"`
 	LeadSuitRequired   bool               `json:"lead_suit_required,omitempty"`
 	TrumpSuit          *string            `json:"trump_suit,omitempty"`
@@ -27,6 +28,8 @@ type PhaseJSON struct {
 	BreakingSuit       *string            `json:"breaking_suit,omitempty"`
 	MinBet             int                `json:"min_bet,omitempty"`
 	MaxRaises          int                `json:"max_raises,omitempty"`
+	RakePercent        int                `json:"rake_percent,omitempty"`
+	RakeCap            int                `json:"rake_cap,omitempty"`
 	MinBid             int                `json:"min_bid,omitempty"`
 	MaxBid             int                `json:"max_bid,omitempty"`
 	AllowNil           bool               `json:"allow_nil,omitempty"`
@@ -53,6 +56,15 @@ type SetupRulesJSON struct {
 	TableauSize         int    `json:"tableau_size,omitempty"`
 	StartingChips       int    `json:"starting_chips,omitempty"`
 	DealToTableau       int    `json:"deal_to_tableau,omitempty"`
+	AceLow              bool   `json:"ace_low,omitempty"`
+	SuitOrder           []int  `json:"suit_order,omitempty"` // Precedence per suit (H, D, C, S)
+	KittySize           int    `json:"kitty_size,omitempty"`
+	KittyFaceUp         bool   `json:"kitty_face_up,omitempty"`
+	StartingPlayer      int    `json:"starting_player,omitempty"`
+	MaxHandSize         int    `json:"max_hand_size,omitempty"`
+	WarToBottom         bool   `json:"war_to_bottom,omitempty"`
+	PerfectInformation  bool   `json:"perfect_information,omitempty"`
+	MatchPlay           bool   `json:"match_play,omitempty"`
 	// Python format fields
 	InitialDeck         string `json:"initial_deck,omitempty"`
 	InitialDiscardCount int    `json:"initial_discard_count,omitempty"`
@@ -70,6 +82,7 @@ type GameGenomeJSON struct {
 	WinConditions []WinConditionJSON  `json:"win_conditions"`
 	Effects       []SpecialEffect     `json:"effects,omitempty"`
 	CardScoring   []CardScoringRule   `json:"card_scoring,omitempty"`
+	RankValues    []int32             `json:"rank_values,omitempty"` // 13 values, 2 through A
 	HandEval      *HandEvaluation     `json:"hand_evaluation,omitempty"`
 	Teams         *TeamConfig         `json:"teams,omitempty"`
 	// Python format fields
@@ -112,6 +125,7 @@ type PlayPhaseJSON struct {
 	MaxCards           int            `json:"max_cards"`
 	Mandatory          bool           `json:"mandatory"`
 	PassIfUnable       bool           `json:"pass_if_unable"`
+	PassAllowed        bool           `json:"pass_allowed,omitempty"`
 	ValidPlayCondition *ConditionJSON `json:"valid_play_condition,omitempty"`
 }
 
@@ -132,8 +146,10 @@ type TrickPhaseJSON struct {
 
 // BettingPhaseJSON for JSON serialization.
 type BettingPhaseJSON struct {
-	MinBet    int `json:"min_bet"`
-	MaxRaises int `json:"max_raises"`
+	MinBet      int `json:"min_bet"`
+	MaxRaises   int `json:"max_raises"`
+	RakePercent int `json:"rake_percent,omitempty"`
+	RakeCap     int `json:"rake_cap,omitempty"`
 }
 
 // ClaimPhaseJSON for JSON serialization.
@@ -191,14 +207,31 @@ func (g *GameGenome) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("failed to unmarshal setup: %w", err)
 	}
 	g.Setup = SetupRules{
-		CardsPerPlayer: setupJSON.CardsPerPlayer,
-		TableauSize:    setupJSON.TableauSize,
-		StartingChips:  setupJSON.StartingChips,
-		DealToTableau:  setupJSON.DealToTableau,
+		CardsPerPlayer:     setupJSON.CardsPerPlayer,
+		TableauSize:        setupJSON.TableauSize,
+		StartingChips:      setupJSON.StartingChips,
+		DealToTableau:      setupJSON.DealToTableau,
+		AceLow:             setupJSON.AceLow,
+		KittySize:          setupJSON.KittySize,
+		KittyFaceUp:        setupJSON.KittyFaceUp,
+		StartingPlayer:     setupJSON.StartingPlayer,
+		MaxHandSize:        setupJSON.MaxHandSize,
+		WarToBottom:        setupJSON.WarToBottom,
+		PerfectInformation: setupJSON.PerfectInformation,
+		MatchPlay:          setupJSON.MatchPlay,
+	}
+	for suit := 0; suit < len(setupJSON.SuitOrder) && suit < 4; suit++ {
+		g.Setup.SuitOrder[suit] = uint8(setupJSON.SuitOrder[suit])
 	}
 
 	g.Effects = jg.Effects
 	g.CardScoring = jg.CardScoring
+	if len(jg.RankValues) > 0 {
+		if len(jg.RankValues) != len(g.RankValues) {
+			return fmt.Errorf("rank_values has %d entries, want %d", len(jg.RankValues), len(g.RankValues))
+		}
+		copy(g.RankValues[:], jg.RankValues)
+	}
 	g.HandEval = jg.HandEval
 	g.Teams = jg.Teams
 
@@ -266,10 +299,24 @@ func (g *GameGenome) UnmarshalJSON(data []byte) error {
 func (g *GameGenome) MarshalJSON() ([]byte, error) {
 	// Serialize setup to raw JSON
 	setupJSON := SetupRulesJSON{
-		CardsPerPlayer: g.Setup.CardsPerPlayer,
-		TableauSize:    g.Setup.TableauSize,
-		StartingChips:  g.Setup.StartingChips,
-		DealToTableau:  g.Setup.DealToTableau,
+		CardsPerPlayer:     g.Setup.CardsPerPlayer,
+		TableauSize:        g.Setup.TableauSize,
+		StartingChips:      g.Setup.StartingChips,
+		DealToTableau:      g.Setup.DealToTableau,
+		AceLow:             g.Setup.AceLow,
+		KittySize:          g.Setup.KittySize,
+		KittyFaceUp:        g.Setup.KittyFaceUp,
+		StartingPlayer:     g.Setup.StartingPlayer,
+		MaxHandSize:        g.Setup.MaxHandSize,
+		WarToBottom:        g.Setup.WarToBottom,
+		PerfectInformation: g.Setup.PerfectInformation,
+		MatchPlay:          g.Setup.MatchPlay,
+	}
+	if g.Setup.SuitOrder != ([4]uint8{}) {
+		setupJSON.SuitOrder = make([]int, 4)
+		for suit, precedence := range g.Setup.SuitOrder {
+			setupJSON.SuitOrder[suit] = int(precedence)
+		}
 	}
 	setupBytes, err := json.Marshal(setupJSON)
 	if err != nil {
@@ -284,6 +331,9 @@ func (g *GameGenome) MarshalJSON() ([]byte, error) {
 		HandEval:    g.HandEval,
 		Teams:       g.Teams,
 	}
+	if g.RankValues != ([13]int32{}) {
+		jg.RankValues = g.RankValues[:]
+	}
 
 	// Convert turn structure
 	jg.TurnStructure.MaxTurns = g.TurnStructure.MaxTurns
@@ -358,6 +408,7 @@ func parsePhase(pj PhaseJSON) (Phase, error) {
 				MaxCards:           pp.MaxCards,
 				Mandatory:          pp.Mandatory,
 				PassIfUnable:       pp.PassIfUnable,
+				PassAllowed:        pp.PassAllowed,
 				ValidPlayCondition: parseCondition(pp.ValidPlayCondition),
 			}, nil
 		}
@@ -368,6 +419,7 @@ func parsePhase(pj PhaseJSON) (Phase, error) {
 			MaxCards:           pj.MaxCards,
 			Mandatory:          pj.Mandatory,
 			PassIfUnable:       !pj.Mandatory, // Python uses mandatory=false, Go uses pass_if_unable=true
+			PassAllowed:        pj.PassAllowed,
 			ValidPlayCondition: parseCondition(pj.ValidPlayCondition),
 		}, nil
 
@@ -426,14 +478,18 @@ func parsePhase(pj PhaseJSON) (Phase, error) {
 				return nil, fmt.Errorf("invalid betting phase: %w", err)
 			}
 			return &BettingPhase{
-				MinBet:    bp.MinBet,
-				MaxRaises: bp.MaxRaises,
+				MinBet:      bp.MinBet,
+				MaxRaises:   bp.MaxRaises,
+				RakePercent: bp.RakePercent,
+				RakeCap:     bp.RakeCap,
 			}, nil
 		}
 		// Python format
 		return &BettingPhase{
-			MinBet:    pj.MinBet,
-			MaxRaises: pj.MaxRaises,
+			MinBet:      pj.MinBet,
+			MaxRaises:   pj.MaxRaises,
+			RakePercent: pj.RakePercent,
+			RakeCap:     pj.RakeCap,
 		}, nil
 
 	case "claim":
@@ -492,6 +548,7 @@ func marshalPhase(phase Phase) (PhaseJSON, error) {
 			MaxCards:           p.MaxCards,
 			Mandatory:          p.Mandatory,
 			PassIfUnable:       p.PassIfUnable,
+			PassAllowed:        p.PassAllowed,
 			ValidPlayCondition: marshalCondition(p.ValidPlayCondition),
 		}
 
@@ -515,8 +572,10 @@ func marshalPhase(phase Phase) (PhaseJSON, error) {
 	case *BettingPhase:
 		pj.Type = "betting"
 		data = BettingPhaseJSON{
-			MinBet:    p.MinBet,
-			MaxRaises: p.MaxRaises,
+			MinBet:      p.MinBet,
+			MaxRaises:   p.MaxRaises,
+			RakePercent: p.RakePercent,
+			RakeCap:     p.RakeCap,
 		}
 
 	case *ClaimPhase:
@@ -701,6 +760,10 @@ func parseWinConditionType(s string) WinConditionType {
 		return WinTypeBestHand
 	case "most_captured":
 		return WinTypeMostCaptured
+	case "most_cards":
+		return WinTypeMostCards
+	case "blackjack":
+		return WinTypeBlackjack
 	default:
 		return WinTypeEmptyHand
 	}
@@ -724,6 +787,10 @@ func winConditionTypeToString(wct WinConditionType) string {
 		return "best_hand"
 	case WinTypeMostCaptured:
 		return "most_captured"
+	case WinTypeMostCards:
+		return "most_cards"
+	case WinTypeBlackjack:
+		return "blackjack"
 	default:
 		return "empty_hand"
 	}
@@ -103,6 +103,7 @@ func (v *GenomeValidator) Validate(genome *GameGenome) []ValidationError {
 	captureWins := map[WinConditionType]bool{
 		WinTypeCaptureAll:   true,
 		WinTypeMostCaptured: true,
+		WinTypeMostCards:    true,
 	}
 	hasCaptureWin := false
 	for wt := range winTypes {
@@ -157,7 +158,7 @@ func (v *GenomeValidator) Validate(genome *GameGenome) []ValidationError {
 		})
 	}
 
-	// Check 8: Betting min_bet should allow meaningful play
+	// Check 8: Betting min_bet should allow meaningful play, and rake must be valid
 	for _, phase := range genome.TurnStructure.Phases {
 		if bp, ok := phase.(*BettingPhase); ok {
 			starting := genome.Setup.StartingChips
@@ -171,6 +172,14 @@ func (v *GenomeValidator) Validate(genome *GameGenome) []ValidationError {
 					})
 				}
 			}
+			// Rake is a percentage of the pot with an optional cap
+			if bp.RakePercent < 0 || bp.RakePercent > 100 || bp.RakeCap < 0 {
+				errors = append(errors, ValidationError{
+					Field:   "betting_phase.rake_percent",
+					Message: fmt.Sprintf("BettingPhase rake (%d%%, cap %d) must be 0-100%% with a non-negative cap",
+						bp.RakePercent, bp.RakeCap),
+				})
+			}
 		}
 	}
 
@@ -323,3 +323,33 @@ func TestIsValid(t *testing.T) {
 		t.Error("Expected IsValid to return false for invalid genome")
 	}
 }
+
+func TestValidateBettingRake(t *testing.T) {
+	genome := &GameGenome{
+		Name:  "BadRake",
+		Setup: SetupRules{CardsPerPlayer: 5, StartingChips: 500},
+		TurnStructure: TurnStructure{
+			Phases: []Phase{
+				&DrawPhase{Source: LocationDeck, Count: 5},
+				&BettingPhase{MinBet: 10, MaxRaises: 3, RakePercent: 120},
+			},
+		},
+		WinConditions: []WinCondition{{Type: WinTypeEmptyHand}},
+	}
+
+	found := func() bool {
+		for _, e := range ValidateGenome(genome) {
+			if e.Field == "betting_phase.rake_percent" {
+				return true
+			}
+		}
+		return false
+	}
+	if !found() {
+		t.Error("Expected a rake over 100% to be rejected")
+	}
+	genome.TurnStructure.Phases[1] = &BettingPhase{MinBet: 10, MaxRaises: 3, RakePercent: 5, RakeCap: 10}
+	if found() {
+		t.Error("Expected a 5% rake capped at 10 to be valid")
+	}
+}
@@ -2,4 +2,4 @@ module github.com/signalnine/darwindeck/gosim
 
 go 1.25.5
 
-require github.com/google/flatbuffers v25.12.19+incompatible // indirect
+require github.com/google/flatbuffers v25.12.19+incompatible
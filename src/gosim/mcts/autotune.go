@@ -0,0 +1,100 @@
+package mcts
+
+import (
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+// AutoTuneCandidates are the exploration constants tried by AutoTuneC
+var AutoTuneCandidates = []float64{0.5, 1.0, DefaultExplorationParam, 2.0, 3.0}
+
+// autoTuneIterations is the per-move search budget during tuning games.
+// Kept small so a full tuning run stays cheap relative to real evaluation.
+const autoTuneIterations = 50
+
+// AutoTuneC picks the exploration constant that performs best for this game.
+// For each candidate it plays budget short self-play games from state, with
+// the candidate controlling one seat (alternating each game) and every other
+// seat searching with DefaultExplorationParam. The candidate with the highest
+// score (win = 1, draw = 0.5) is returned; DefaultExplorationParam is kept
+// unless another candidate strictly beats it.
+func AutoTuneC(state *engine.GameState, genome *engine.Genome, budget int) float64 {
+	if budget <= 0 {
+		return DefaultExplorationParam
+	}
+
+	numPlayers := int(state.NumPlayers)
+	if numPlayers == 0 {
+		numPlayers = 2 // Default fallback
+	}
+
+	bestC := DefaultExplorationParam
+	bestScore := autoTuneScore(state, genome, DefaultExplorationParam, budget, numPlayers)
+
+	for _, c := range AutoTuneCandidates {
+		if c == DefaultExplorationParam {
+			continue
+		}
+		score := autoTuneScore(state, genome, c, budget, numPlayers)
+		if score > bestScore {
+			bestC = c
+			bestScore = score
+		}
+	}
+
+	return bestC
+}
+
+// autoTuneScore plays the given number of games for candidate c and returns its total score
+func autoTuneScore(state *engine.GameState, genome *engine.Genome, c float64, games int, numPlayers int) float64 {
+	score := 0.0
+	for g := 0; g < games; g++ {
+		tunedSeat := uint8(g % numPlayers)
+		winner := playTuningGame(state, genome, c, tunedSeat)
+		switch {
+		case winner < 0:
+			score += 0.5
+		case uint8(winner) == tunedSeat:
+			score += 1.0
+		}
+	}
+	return score
+}
+
+// playTuningGame plays one game from a copy of state and returns the winner (-1 for draw)
+func playTuningGame(state *engine.GameState, genome *engine.Genome, c float64, tunedSeat uint8) int8 {
+	gameState := state.Clone()
+	defer engine.PutState(gameState)
+
+	maxTurns := int(genome.Header.MaxTurns)
+	if maxTurns <= 0 {
+		maxTurns = 100
+	}
+
+	for turn := 0; turn < maxTurns; turn++ {
+		if outcome := engine.CheckGameOutcome(gameState, genome); outcome.Over() {
+			return outcome.Winner
+		}
+
+		moves := engine.GenerateLegalMoves(gameState, genome)
+		if len(moves) == 0 {
+			return -1
+		}
+
+		var move *engine.LegalMove
+		if len(moves) == 1 {
+			move = &moves[0]
+		} else {
+			explorationParam := DefaultExplorationParam
+			if gameState.CurrentPlayer == tunedSeat {
+				explorationParam = c
+			}
+			move = Search(gameState, genome, autoTuneIterations, explorationParam)
+			if move == nil {
+				return -1
+			}
+		}
+		engine.ApplyMove(gameState, move, genome)
+	}
+
+	return -1
+}
@@ -0,0 +1,68 @@
+package mcts
+
+import (
+	"math/rand"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+// Determinize returns a copy of state with the cards hidden from viewer dealt
+// again at random: the face-down, unseen cards of the other players' hands,
+// the deck and a face-down kitty are shuffled among those same places, so
+// every hand keeps its size. Cards the viewer knows the whereabouts of stay
+// put: its own hand, face-up hand cards, and cards shown to the table (see
+// GameState.SeenCards), so a seen card is never dealt into a hidden hand.
+// The deck is shuffled as well, since its order is hidden even where its
+// cards are seen. The caller must release the copy with engine.PutState. A
+// nil rng uses the global math/rand source.
+func Determinize(state *engine.GameState, viewer uint8, rng *rand.Rand) *engine.GameState {
+	world := state.Clone()
+	if world.PerfectInformation {
+		return world
+	}
+
+	// Slots holding cards the viewer cannot place, and the cards in them
+	var slots []*engine.Card
+	for p := range world.Players {
+		if p == int(viewer) {
+			continue
+		}
+		player := &world.Players[p]
+		for i, card := range player.Hand {
+			if !player.IsFaceUp(card) && !world.IsSeen(card) {
+				slots = append(slots, &player.Hand[i])
+			}
+		}
+	}
+	for i, card := range world.Deck {
+		if !world.IsSeen(card) {
+			slots = append(slots, &world.Deck[i])
+		}
+	}
+	if !world.KittyFaceUp {
+		for i, card := range world.Kitty {
+			if !world.IsSeen(card) {
+				slots = append(slots, &world.Kitty[i])
+			}
+		}
+	}
+
+	cards := make([]engine.Card, len(slots))
+	for i, slot := range slots {
+		cards[i] = *slot
+	}
+	shuffleCards(cards, rng)
+	for i, slot := range slots {
+		*slot = cards[i]
+	}
+	shuffleCards(world.Deck, rng)
+	return world
+}
+
+// shuffleCards is a Fisher-Yates shuffle drawing from rng (see randIntn)
+func shuffleCards(cards []engine.Card, rng *rand.Rand) {
+	for i := len(cards) - 1; i > 0; i-- {
+		j := randIntn(rng, i+1)
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
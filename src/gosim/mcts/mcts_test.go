@@ -1,6 +1,7 @@
 package mcts
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/signalnine/darwindeck/gosim/engine"
@@ -114,6 +115,72 @@ func TestMostVisitedChild(t *testing.T) {
 	PutNode(parent)
 }
 
+func TestSampleChildByVisitsTemperature(t *testing.T) {
+	parent := GetNode()
+	defer PutNode(parent)
+	for _, visits := range []int{10, 25, 15, 0} {
+		child := GetNode()
+		child.Visits = visits
+		parent.Children = append(parent.Children, child)
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		if SampleChildByVisits(parent, 0, rng) != parent.Children[1] {
+			t.Fatal("Expected temperature 0 to pick the most visited child")
+		}
+	}
+
+	// A large temperature flattens the visits to near uniform over the
+	// visited children; the unvisited one is never picked
+	const samples = 30000
+	counts := make(map[*MCTSNode]int)
+	for i := 0; i < samples; i++ {
+		counts[SampleChildByVisits(parent, 1e6, rng)]++
+	}
+	if counts[parent.Children[3]] != 0 {
+		t.Errorf("Expected the unvisited child never sampled, got %d", counts[parent.Children[3]])
+	}
+	for i, child := range parent.Children[:3] {
+		if share := float64(counts[child]) / samples; share < 0.3 || share > 0.37 {
+			t.Errorf("Expected child %d sampled about a third of the time, got %.3f", i, share)
+		}
+	}
+}
+
+func TestChildTieBreakIsStable(t *testing.T) {
+	moves := []engine.LegalMove{
+		{PhaseIndex: 0, CardIndex: 2, TargetLoc: engine.LocationDiscard},
+		{PhaseIndex: 0, CardIndex: 1, TargetLoc: engine.LocationDiscard},
+	}
+
+	// The same pair of equal children, expanded in either order
+	for _, order := range [][]int{{0, 1}, {1, 0}} {
+		parent := GetNode()
+		parent.Visits = 20
+		for _, i := range order {
+			child := GetNode()
+			child.Parent = parent
+			child.Move = &moves[i]
+			child.Visits = 10
+			child.Wins = 5
+			parent.Children = append(parent.Children, child)
+		}
+
+		if most := parent.MostVisitedChild(); most.Move.CardIndex != 1 {
+			t.Errorf("Order %v: expected MostVisitedChild to pick card 1, got %+v", order, *most.Move)
+		}
+		if best := parent.BestChild(DefaultExplorationParam); best.Move.CardIndex != 1 {
+			t.Errorf("Order %v: expected BestChild to pick card 1, got %+v", order, *best.Move)
+		}
+		// SearchWithStats returns the first root stat
+		if stats := rootStats(parent); len(stats) != 2 || stats[0].Move.CardIndex != 1 {
+			t.Errorf("Order %v: expected rootStats to put card 1 first, got %+v", order, stats)
+		}
+		PutNode(parent)
+	}
+}
+
 func TestIsFullyExpanded(t *testing.T) {
 	node := GetNode()
 	node.UntriedMoves = []engine.LegalMove{
@@ -148,8 +215,7 @@ func TestIsTerminal(t *testing.T) {
 		t.Error("Node should be terminal with winner 0")
 	}
 
-	engine.PutState(node.State)
-	PutNode(node)
+	PutNode(node) // Also returns node.State
 }
 
 func TestMCTSSearch(t *testing.T) {
@@ -235,8 +301,527 @@ func BenchmarkMCTSSearch(b *testing.B) {
 		},
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		Search(state, genome, 100, 1.414)
 	}
 }
+
+// playToDiscardGame sets up a two-player game where each player holds three
+// cards and the first to empty their hand by playing to the discard wins
+func playToDiscardGame(state *engine.GameState) *engine.Genome {
+	for i := 0; i < 3; i++ {
+		state.Players[0].Hand = append(state.Players[0].Hand, engine.Card{Rank: uint8(i), Suit: 0})
+		state.Players[1].Hand = append(state.Players[1].Hand, engine.Card{Rank: uint8(i), Suit: 1})
+	}
+	state.CurrentPlayer = 0
+	state.WinnerID = -1
+
+	return &engine.Genome{
+		Header: &engine.BytecodeHeader{
+			PlayerCount: 2,
+			MaxTurns:    20,
+		},
+		TurnPhases: []engine.PhaseDescriptor{
+			{
+				PhaseType: 2, // Play phase
+				Data: []byte{
+					byte(engine.LocationDiscard), // target
+					1,                            // min cards
+					1,                            // max cards
+					1,                            // mandatory
+					0,                            // pass_if_unable
+					0, 0, 0, 0,                   // condition length: 0
+				},
+			},
+		},
+		WinConditions: []engine.WinCondition{
+			{WinType: 0, Threshold: 0}, // empty_hand
+		},
+	}
+}
+
+func TestAutoTuneC(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+
+	c := AutoTuneC(state, genome, 2)
+	if c < 0.1 || c > 5.0 {
+		t.Errorf("AutoTuneC returned out-of-range constant %f", c)
+	}
+
+	found := false
+	for _, candidate := range AutoTuneCandidates {
+		if c == candidate {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("AutoTuneC returned %f, which is not a candidate", c)
+	}
+
+	// The tuning games must not touch the caller's state
+	if len(state.Players[0].Hand) != 3 || state.WinnerID != -1 {
+		t.Error("AutoTuneC modified the input state")
+	}
+}
+
+func TestAutoTuneC_ZeroBudget(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+
+	genome := &engine.Genome{
+		Header: &engine.BytecodeHeader{PlayerCount: 2, MaxTurns: 10},
+	}
+
+	if c := AutoTuneC(state, genome, 0); c != DefaultExplorationParam {
+		t.Errorf("Expected default constant for zero budget, got %f", c)
+	}
+}
+
+func TestSearchParallel_ValidMove(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+
+	move := SearchParallel(state, genome, 400, DefaultExplorationParam, 4, 42)
+	if move == nil {
+		t.Fatal("SearchParallel returned nil move")
+	}
+
+	legal := false
+	for _, m := range engine.GenerateLegalMoves(state, genome) {
+		if m == *move {
+			legal = true
+		}
+	}
+	if !legal {
+		t.Errorf("SearchParallel returned illegal move %+v", *move)
+	}
+}
+
+func TestSearchParallel_DeterministicWithSeed(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+
+	first := SearchParallel(state, genome, 400, DefaultExplorationParam, 4, 7)
+	for i := 0; i < 5; i++ {
+		again := SearchParallel(state, genome, 400, DefaultExplorationParam, 4, 7)
+		if first == nil || again == nil || *first != *again {
+			t.Fatalf("Run %d: expected %+v, got %+v", i, first, again)
+		}
+	}
+}
+
+func TestSearchWithParams_Parallel(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+
+	move := SearchWithParams(state, genome, SearchParams{
+		Iterations:      200,
+		ParallelWorkers: 2,
+		Seed:            1,
+	})
+	if move == nil {
+		t.Error("SearchWithParams returned nil move")
+	}
+}
+
+func TestSearchWithParams_DeterministicWithSeed(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+
+	// A flat temperature samples among the moves, so only the seed repeats one
+	distinct := make(map[engine.LegalMove]bool)
+	for seed := int64(1); seed <= 8; seed++ {
+		params := SearchParams{Iterations: 100, Temperature: 1e6, Seed: seed}
+		first := SearchWithParams(state, genome, params)
+		for i := 0; i < 5; i++ {
+			if again := SearchWithParams(state, genome, params); first == nil || again == nil || *first != *again {
+				t.Fatalf("Seed %d run %d: expected %+v, got %+v", seed, i, first, again)
+			}
+		}
+		distinct[*first] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("Expected different seeds to sample different moves, got %v", distinct)
+	}
+}
+
+func TestSimulate_RolloutCutoffValueInRange(t *testing.T) {
+	// Detectors look at every player slot, so size the state exactly
+	state := engine.GetStateN(2)
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+	genome.Header.MaxTurns = 1000
+	// Give player 0 a longer hand so the game cannot end within the cap
+	for i := 0; i < 10; i++ {
+		state.Players[0].Hand = append(state.Players[0].Hand, engine.Card{Rank: uint8(i), Suit: 2})
+	}
+
+	detector := engine.SelectLeaderDetector(genome)
+	rng := rand.New(rand.NewSource(1))
+	result := simulate(state, genome, rng, nil, 2, detector, new([]engine.LegalMove))
+
+	if !result.cutoff {
+		t.Fatal("Expected rollout to be cut off at depth 2")
+	}
+	for p := uint8(0); p < 2; p++ {
+		v := result.valueFor(p)
+		if v < 0 || v > 1 {
+			t.Errorf("Player %d: cut-off value %f outside [0,1]", p, v)
+		}
+	}
+	// Player 1 holds fewer cards, so leads under the hand-size detector
+	if result.valueFor(1) <= result.valueFor(0) {
+		t.Errorf("Expected leader (player 1) to value position higher: %f vs %f",
+			result.valueFor(1), result.valueFor(0))
+	}
+}
+
+func TestSimulate_LargeCapMatchesUncapped(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+	detector := engine.SelectLeaderDetector(genome)
+
+	for seed := int64(0); seed < 10; seed++ {
+		uncapped := simulate(state, genome, rand.New(rand.NewSource(seed)), nil, 0, nil, new([]engine.LegalMove))
+		capped := simulate(state, genome, rand.New(rand.NewSource(seed)), nil, 1000000, detector, new([]engine.LegalMove))
+		if uncapped != capped {
+			t.Errorf("Seed %d: uncapped %+v differs from large cap %+v", seed, uncapped, capped)
+		}
+		if capped.cutoff {
+			t.Errorf("Seed %d: rollout should reach a terminal state", seed)
+		}
+	}
+}
+
+func TestSearchWithParams_RolloutDepth(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+
+	move := SearchWithParams(state, genome, SearchParams{
+		Iterations:   200,
+		RolloutDepth: 2,
+	})
+	if move == nil {
+		t.Error("SearchWithParams with rollout cap returned nil move")
+	}
+}
+
+func TestDeterminizeNeverDealsSeenCards(t *testing.T) {
+	state := engine.GetStateN(2)
+	defer engine.PutState(state)
+	for rank := uint8(0); rank < 4; rank++ {
+		state.Players[0].Hand = append(state.Players[0].Hand, engine.Card{Rank: rank, Suit: 0})
+		state.Players[1].Hand = append(state.Players[1].Hand, engine.Card{Rank: rank, Suit: 1})
+		state.Deck = append(state.Deck, engine.Card{Rank: rank, Suit: 2}, engine.Card{Rank: rank, Suit: 3})
+	}
+	// Opponent drew a seen card; two seen cards went back into the deck
+	drawn := engine.Card{Rank: 3, Suit: 1}
+	state.MarkSeen(drawn, engine.Card{Rank: 0, Suit: 2}, engine.Card{Rank: 1, Suit: 3})
+
+	rng := rand.New(rand.NewSource(1))
+	moved := false
+	for i := 0; i < 200; i++ {
+		world := Determinize(state, 0, rng)
+		if len(world.Players[1].Hand) != 4 || len(world.Deck) != 8 {
+			t.Fatalf("Expected hand and deck sizes kept, got %d and %d", len(world.Players[1].Hand), len(world.Deck))
+		}
+		for j, card := range world.Players[0].Hand {
+			if card != state.Players[0].Hand[j] {
+				t.Fatalf("Expected the viewer's hand untouched, got %v", world.Players[0].Hand)
+			}
+		}
+		holdsDrawn := false
+		for _, card := range world.Players[1].Hand {
+			if card == drawn {
+				holdsDrawn = true
+			} else if world.IsSeen(card) {
+				t.Fatalf("Seen card %v dealt to a hidden hand", card)
+			}
+			if card.Suit != 1 {
+				moved = true
+			}
+		}
+		if !holdsDrawn {
+			t.Fatalf("Expected the opponent to keep the seen card it drew, got %v", world.Players[1].Hand)
+		}
+		engine.PutState(world)
+	}
+	if !moved {
+		t.Error("Expected the opponent's unseen cards to be dealt again")
+	}
+
+	// With open hands nothing is hidden
+	state.PerfectInformation = true
+	world := Determinize(state, 0, rng)
+	defer engine.PutState(world)
+	for j, card := range world.Players[1].Hand {
+		if card != state.Players[1].Hand[j] {
+			t.Fatalf("Expected open hands kept, got %v", world.Players[1].Hand)
+		}
+	}
+}
+
+func TestSearchWithParams_Determinize(t *testing.T) {
+	state := engine.GetStateN(2)
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+
+	for _, workers := range []int{1, 4} {
+		params := SearchParams{Iterations: 200, Determinize: true, ParallelWorkers: workers, Seed: 3}
+		move := SearchWithParams(state, genome, params)
+		if move == nil || move.PhaseIndex != 0 {
+			t.Fatalf("Expected a play from a determinized search with %d workers, got %v", workers, move)
+		}
+	}
+	if len(state.Players[1].Hand) != 3 || state.Players[1].Hand[0].Suit != 1 {
+		t.Errorf("Expected the searched state untouched, got %v", state.Players[1].Hand)
+	}
+}
+
+func TestSearchDeterminizesHiddenHands(t *testing.T) {
+	// Player 0 holds four aces, but player 1's hidden hand is a king-high
+	// straight flush; the showdown comes right after player 0's move
+	state := engine.GetStateN(2)
+	defer engine.PutState(state)
+	for suit := uint8(0); suit < 4; suit++ {
+		state.Players[0].Hand = append(state.Players[0].Hand, engine.Card{Rank: engine.AceRank, Suit: suit})
+	}
+	state.Players[0].Hand = append(state.Players[0].Hand, engine.Card{Rank: 0, Suit: 0})
+	for rank := engine.AceRank - 5; rank < engine.AceRank; rank++ {
+		state.Players[1].Hand = append(state.Players[1].Hand, engine.Card{Rank: rank, Suit: 3})
+	}
+	held := make(map[engine.Card]bool)
+	for _, p := range state.Players {
+		for _, card := range p.Hand {
+			held[card] = true
+		}
+	}
+	for suit := uint8(0); suit < 4; suit++ {
+		for rank := uint8(0); rank <= engine.AceRank; rank++ {
+			if card := (engine.Card{Rank: rank, Suit: suit}); !held[card] {
+				state.Deck = append(state.Deck, card)
+			}
+		}
+	}
+	state.TurnNumber = 3
+	genome := &engine.Genome{
+		Header:        &engine.BytecodeHeader{PlayerCount: 2, MaxTurns: 100},
+		TurnPhases:    []engine.PhaseDescriptor{{PhaseType: engine.PhaseTypeAction, Data: []byte{1, byte(engine.OpReverseOrder), 0}}},
+		WinConditions: []engine.WinCondition{{WinType: engine.WinTypeBestHand}},
+	}
+
+	// Peeking at the open hand, the search knows the aces lose
+	state.PerfectInformation = true
+	if _, stats := SearchWithStats(state, genome, 50, DefaultExplorationParam); len(stats) != 1 || stats[0].WinRate != 0 {
+		t.Errorf("Expected a certain loss with open hands, got %+v", stats)
+	}
+
+	// Hidden, player 1 is dealt a guess, which the aces all but surely beat
+	state.PerfectInformation = false
+	if _, stats := SearchWithStats(state, genome, 50, DefaultExplorationParam); len(stats) != 1 || stats[0].WinRate != 1 {
+		t.Errorf("Expected a win against a guessed hand, got %+v", stats)
+	}
+}
+
+func TestSearchWithStats(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+
+	move, stats := SearchWithStats(state, genome, 300, DefaultExplorationParam)
+	if move == nil {
+		t.Fatal("SearchWithStats returned nil move")
+	}
+	if len(stats) == 0 {
+		t.Fatal("Expected per-move stats")
+	}
+
+	if stats[0].Move != *move {
+		t.Errorf("Expected returned move %v to be the most visited %v", *move, stats[0].Move)
+	}
+	total := 0
+	for i, st := range stats {
+		if st.WinRate < 0 || st.WinRate > 1 {
+			t.Errorf("Move %v: win rate %f out of [0,1]", st.Move, st.WinRate)
+		}
+		if st.Visits > stats[0].Visits {
+			t.Errorf("Move %d has %d visits, more than the returned move's %d", i, st.Visits, stats[0].Visits)
+		}
+		total += st.Visits
+	}
+	if total > 300 {
+		t.Errorf("Expected at most 300 root visits across moves, got %d", total)
+	}
+}
+
+func TestSearchWithParams_RolloutPoliciesPerSeat(t *testing.T) {
+	state := engine.GetStateN(2)
+	defer engine.PutState(state)
+	genome := playToDiscardGame(state)
+
+	// Seat 0 keeps the random default; seat 1 always plays its first card
+	var calls [2]int
+	wrongSeat := false
+	seatPolicy := func(seat uint8, pick func(moves []engine.LegalMove, rng *rand.Rand) int) RolloutPolicy {
+		return func(s *engine.GameState, moves []engine.LegalMove, rng *rand.Rand) int {
+			if s.CurrentPlayer != seat {
+				wrongSeat = true
+			}
+			calls[seat]++
+			return pick(moves, rng)
+		}
+	}
+	policies := []RolloutPolicy{
+		seatPolicy(0, func(moves []engine.LegalMove, rng *rand.Rand) int { return RandomRollout(nil, moves, rng) }),
+		seatPolicy(1, func([]engine.LegalMove, *rand.Rand) int { return 0 }),
+	}
+
+	move := SearchWithParams(state, genome, SearchParams{Iterations: 100, RolloutPolicies: policies})
+	if move == nil {
+		t.Fatal("SearchWithParams with rollout policies returned nil move")
+	}
+	if wrongSeat {
+		t.Error("Expected each policy to be asked only on its own seat's turns")
+	}
+	if calls[0] == 0 || calls[1] == 0 {
+		t.Errorf("Expected both seats' policies to be used, got %v calls", calls)
+	}
+
+	// A missing entry falls back to random play
+	if choosePolicy(policies[:1], 1) == nil {
+		t.Error("Expected a default policy for a seat without one")
+	}
+}
+
+// raceGame sets up a two-card shedding race that player 0 loses unless
+// they play the 2 (hand index 0), which makes player 1 draw three, so one
+// root move clearly wins
+func raceGame(state *engine.GameState) *engine.Genome {
+	genome := playToDiscardGame(state)
+	state.Players[0].Hand = append(state.Players[0].Hand, engine.Card{Rank: 3, Suit: 0})
+	for i := 0; i < 3; i++ {
+		state.Deck = append(state.Deck, engine.Card{Rank: uint8(i), Suit: 2})
+	}
+	genome.Effects = map[uint8]engine.SpecialEffect{
+		0: {TriggerRank: 0, EffectType: engine.EFFECT_DRAW_CARDS, Target: engine.TARGET_NEXT_PLAYER, Value: 3},
+	}
+	return genome
+}
+
+// raceWin is raceGame's winning move
+var raceWin = engine.LegalMove{PhaseIndex: 0, CardIndex: 0, TargetLoc: engine.LocationDiscard}
+
+// meanRootShare returns the mean share of the root visits that went to move
+// over searches seeded 1 to searches
+func meanRootShare(state *engine.GameState, genome *engine.Genome, params SearchParams, move engine.LegalMove, searches int) float64 {
+	total := 0.0
+	for seed := int64(1); seed <= int64(searches); seed++ {
+		root := searchTree(state, genome, params, rand.New(rand.NewSource(seed)))
+		for _, child := range root.Children {
+			if *child.Move == move {
+				total += float64(child.Visits) / float64(root.Visits)
+			}
+		}
+		PutNode(root)
+	}
+	return total / float64(searches)
+}
+
+func TestDirichletNoiseSpreadsRootVisits(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := raceGame(state)
+
+	// A confident policy that only ever plays the winning move starves the
+	// others; noise should send some visits their way
+	confident := func(*engine.GameState) map[MoveSig]float32 {
+		return map[MoveSig]float32{raceWin: 1}
+	}
+	plain := meanRootShare(state, genome, SearchParams{Iterations: 300, Policy: confident}, raceWin, 50)
+	noisy := meanRootShare(state, genome, SearchParams{Iterations: 300, Policy: confident, DirichletEpsilon: 0.25}, raceWin, 50)
+	if 1-noisy < 1.5*(1-plain) {
+		t.Errorf("Expected noise to send the other moves more visits, got %.3f of them with noise and %.3f without", 1-noisy, 1-plain)
+	}
+}
+
+func TestPUCTStrongPriorConcentratesVisits(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := raceGame(state)
+
+	strong := func(s *engine.GameState) map[MoveSig]float32 {
+		probs := make(map[MoveSig]float32)
+		for _, move := range engine.GenerateLegalMoves(s, genome) {
+			probs[move] = 0.1
+		}
+		probs[raceWin] = 0.9
+		return probs
+	}
+	ucb := meanRootShare(state, genome, SearchParams{Iterations: 60}, raceWin, 20)
+	puct := meanRootShare(state, genome, SearchParams{Iterations: 60, Policy: strong}, raceWin, 20)
+	if puct <= ucb {
+		t.Errorf("Expected a strong prior to concentrate visits on the favored move, got a share of %.3f with PUCT and %.3f with UCB1", puct, ucb)
+	}
+}
+
+func TestRolloutsPerExpansionSteadiesEstimates(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := raceGame(state)
+
+	// Random play decides the race, so single rollouts are noisy; the
+	// root's estimate should spread less across seeds with several per node
+	rootVariance := func(rollouts int) float64 {
+		var estimates []float64
+		for seed := int64(1); seed <= 40; seed++ {
+			params := SearchParams{Iterations: 20, RolloutsPerExpansion: rollouts}
+			root := searchTree(state, genome, params, rand.New(rand.NewSource(seed)))
+			estimates = append(estimates, root.Wins/float64(root.Visits))
+			PutNode(root)
+		}
+		mean := 0.0
+		for _, e := range estimates {
+			mean += e
+		}
+		mean /= float64(len(estimates))
+		variance := 0.0
+		for _, e := range estimates {
+			variance += (e - mean) * (e - mean)
+		}
+		return variance / float64(len(estimates))
+	}
+
+	single, averaged := rootVariance(1), rootVariance(8)
+	if averaged >= single/2 {
+		t.Errorf("Expected 8 rollouts per expansion to at least halve the variance of the root estimate, got %.5f against %.5f", averaged, single)
+	}
+}
+
+func TestEarlyStopOnDominantMove(t *testing.T) {
+	state := engine.GetState()
+	defer engine.PutState(state)
+	genome := raceGame(state)
+
+	params := SearchParams{Iterations: 5000, EarlyStopInterval: 50}
+	root := searchTree(state, genome, params, rand.New(rand.NewSource(1)))
+	defer PutNode(root)
+	if root.Visits >= params.Iterations {
+		t.Errorf("Expected the search to stop before its %d iterations, ran %d", params.Iterations, root.Visits)
+	}
+	if best := root.MostVisitedChild(); best == nil || *best.Move != raceWin {
+		t.Errorf("Expected the early stop to keep the winning move %+v, got %+v", raceWin, best)
+	}
+	if !rootDecided(root, params.Iterations-root.Visits) {
+		t.Error("Expected the stop to leave the top move out of reach")
+	}
+}
@@ -2,6 +2,7 @@ package mcts
 
 import (
 	"math"
+	"math/rand"
 	"sync"
 
 	"github.com/signalnine/darwindeck/gosim/engine"
@@ -17,6 +18,14 @@ type MCTSNode struct {
 	Wins         float64
 	UntriedMoves []engine.LegalMove
 	PlayerID     uint8
+	// Prior is the probability of the node's move under its parent's
+	// priors; only used when the parent is Guided
+	Prior float64
+	// Guided nodes select children with PUCT on their priors instead of UCB1
+	Guided bool
+	// UntriedPriors holds the Prior of each untried move's child, in step
+	// with UntriedMoves, when the node is Guided
+	UntriedPriors []float64
 }
 
 // NodePool provides memory pooling for MCTS nodes
@@ -37,6 +46,7 @@ func GetNode() *MCTSNode {
 }
 
 // PutNode returns a node to the pool
+// A node owns its State, which is returned to the engine's StatePool as well
 func PutNode(node *MCTSNode) {
 	if node == nil {
 		return
@@ -45,6 +55,10 @@ func PutNode(node *MCTSNode) {
 	for _, child := range node.Children {
 		PutNode(child)
 	}
+	if node.State != nil {
+		engine.PutState(node.State)
+		node.State = nil
+	}
 	NodePool.Put(node)
 }
 
@@ -58,6 +72,9 @@ func (n *MCTSNode) Reset() {
 	n.Wins = 0
 	n.UntriedMoves = n.UntriedMoves[:0]
 	n.PlayerID = 0
+	n.Prior = 0
+	n.Guided = false
+	n.UntriedPriors = n.UntriedPriors[:0]
 }
 
 // UCB1 calculates the Upper Confidence Bound for Trees value
@@ -72,18 +89,36 @@ func (n *MCTSNode) UCB1(explorationParam float64) float64 {
 	return exploitation + exploration
 }
 
-// BestChild returns the child with the highest UCB1 value
+// PUCT calculates AlphaZero's predictor-weighted bound: the node's mean
+// value plus an exploration term proportional to its Prior that shrinks as
+// the node is visited
+func (n *MCTSNode) PUCT(explorationParam float64) float64 {
+	exploitation := 0.0
+	if n.Visits > 0 {
+		exploitation = n.Wins / float64(n.Visits)
+	}
+	exploration := explorationParam * n.Prior * math.Sqrt(float64(n.Parent.Visits)) / float64(1+n.Visits)
+
+	return exploitation + exploration
+}
+
+// BestChild returns the child with the highest UCB1 value (PUCT when n is
+// Guided), breaking ties by move (see moveLess)
 func (n *MCTSNode) BestChild(explorationParam float64) *MCTSNode {
 	if len(n.Children) == 0 {
 		return nil
 	}
 
+	score := (*MCTSNode).UCB1
+	if n.Guided {
+		score = (*MCTSNode).PUCT
+	}
 	bestChild := n.Children[0]
-	bestValue := bestChild.UCB1(explorationParam)
+	bestValue := score(bestChild, explorationParam)
 
 	for _, child := range n.Children[1:] {
-		value := child.UCB1(explorationParam)
-		if value > bestValue {
+		value := score(child, explorationParam)
+		if value > bestValue || (value == bestValue && moveLess(child.Move, bestChild.Move)) {
 			bestValue = value
 			bestChild = child
 		}
@@ -92,7 +127,8 @@ func (n *MCTSNode) BestChild(explorationParam float64) *MCTSNode {
 	return bestChild
 }
 
-// MostVisitedChild returns the child with the most visits
+// MostVisitedChild returns the child with the most visits, breaking ties by
+// move (see moveLess)
 func (n *MCTSNode) MostVisitedChild() *MCTSNode {
 	if len(n.Children) == 0 {
 		return nil
@@ -102,7 +138,7 @@ func (n *MCTSNode) MostVisitedChild() *MCTSNode {
 	maxVisits := bestChild.Visits
 
 	for _, child := range n.Children[1:] {
-		if child.Visits > maxVisits {
+		if child.Visits > maxVisits || (child.Visits == maxVisits && moveLess(child.Move, bestChild.Move)) {
 			maxVisits = child.Visits
 			bestChild = child
 		}
@@ -111,6 +147,78 @@ func (n *MCTSNode) MostVisitedChild() *MCTSNode {
 	return bestChild
 }
 
+// SampleChildByVisits picks one of root's children at random with
+// probability proportional to visits^(1/temperature). Temperature 1 samples
+// in proportion to the visits themselves, larger temperatures flatten the
+// distribution towards uniform, and temperature 0 (or below) is
+// MostVisitedChild. Unvisited children are never picked. A nil rng uses the
+// global math/rand source.
+func SampleChildByVisits(root *MCTSNode, temperature float64, rng *rand.Rand) *MCTSNode {
+	if temperature <= 0 {
+		return root.MostVisitedChild()
+	}
+	visits := make([]int, len(root.Children))
+	for i, child := range root.Children {
+		visits[i] = child.Visits
+	}
+	i := SampleByVisits(visits, temperature, rng)
+	if i < 0 {
+		return root.MostVisitedChild()
+	}
+	return root.Children[i]
+}
+
+// SampleByVisits returns an index into visits drawn as SampleChildByVisits
+// draws children, or -1 when nothing has been visited. Temperature 0 (or
+// below) returns the first of the most visited.
+func SampleByVisits(visits []int, temperature float64, rng *rand.Rand) int {
+	best := -1
+	for i, v := range visits {
+		if v > 0 && (best < 0 || v > visits[best]) {
+			best = i
+		}
+	}
+	if best < 0 || temperature <= 0 {
+		return best
+	}
+
+	// Weights relative to the most visited, in log space so that small
+	// temperatures can't overflow
+	weights := make([]float64, len(visits))
+	total := 0.0
+	logMax := math.Log(float64(visits[best]))
+	for i, v := range visits {
+		if v > 0 {
+			weights[i] = math.Exp((math.Log(float64(v)) - logMax) / temperature)
+			total += weights[i]
+		}
+	}
+
+	r := randFloat64(rng) * total
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+	return best // Rounding left r past the last weight
+}
+
+// moveLess orders moves by phase, then card index, then target, so that ties
+// between children go the same way whatever order they were expanded in. A
+// nil move sorts first.
+func moveLess(a, b *engine.LegalMove) bool {
+	switch {
+	case a == nil || b == nil:
+		return a == nil && b != nil
+	case a.PhaseIndex != b.PhaseIndex:
+		return a.PhaseIndex < b.PhaseIndex
+	case a.CardIndex != b.CardIndex:
+		return a.CardIndex < b.CardIndex
+	}
+	return a.TargetLoc < b.TargetLoc
+}
+
 // IsFullyExpanded checks if all possible moves have been tried
 func (n *MCTSNode) IsFullyExpanded() bool {
 	return len(n.UntriedMoves) == 0
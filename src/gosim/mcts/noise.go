@@ -0,0 +1,83 @@
+package mcts
+
+import (
+	"math"
+	"math/rand"
+)
+
+// addDirichletNoise replaces epsilon of each prior with a Dirichlet(alpha)
+// sample, so that each search favors a different few moves and self-play
+// sees moves the search would otherwise starve. A non-positive alpha uses
+// DefaultDirichletAlpha.
+func addDirichletNoise(priors []float64, alpha, epsilon float64, rng *rand.Rand) {
+	if alpha <= 0 {
+		alpha = DefaultDirichletAlpha
+	}
+	if epsilon > 1 {
+		epsilon = 1
+	}
+	noise := dirichlet(len(priors), alpha, rng)
+	for i := range priors {
+		priors[i] = (1-epsilon)*priors[i] + epsilon*noise[i]
+	}
+}
+
+// dirichlet samples a symmetric Dirichlet(alpha) distribution over n
+// outcomes by normalizing n Gamma(alpha) draws
+func dirichlet(n int, alpha float64, rng *rand.Rand) []float64 {
+	sample := make([]float64, n)
+	total := 0.0
+	for i := range sample {
+		sample[i] = gammaSample(alpha, rng)
+		total += sample[i]
+	}
+	if total == 0 {
+		// Every draw underflowed (tiny alpha); fall back to uniform
+		for i := range sample {
+			sample[i] = 1 / float64(n)
+		}
+		return sample
+	}
+	for i := range sample {
+		sample[i] /= total
+	}
+	return sample
+}
+
+// gammaSample draws from Gamma(alpha, 1) with Marsaglia and Tsang's method,
+// boosting alpha < 1 to alpha+1 and scaling by U^(1/alpha)
+func gammaSample(alpha float64, rng *rand.Rand) float64 {
+	if alpha < 1 {
+		return gammaSample(alpha+1, rng) * math.Pow(randFloat64(rng), 1/alpha)
+	}
+	d := alpha - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := randNormFloat64(rng)
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := randFloat64(rng)
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// randFloat64 draws from rng, or from the global source when rng is nil
+func randFloat64(rng *rand.Rand) float64 {
+	if rng == nil {
+		return rand.Float64()
+	}
+	return rng.Float64()
+}
+
+// randNormFloat64 draws from rng, or from the global source when rng is nil
+func randNormFloat64(rng *rand.Rand) float64 {
+	if rng == nil {
+		return rand.NormFloat64()
+	}
+	return rng.NormFloat64()
+}
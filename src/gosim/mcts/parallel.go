@@ -0,0 +1,82 @@
+package mcts
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+// SearchParallel runs root-parallel MCTS. It grows workers independent trees
+// from the same root state concurrently, splitting the iteration budget
+// between them, then sums the root children's visit counts across trees to
+// pick the final move. Trees share no nodes, so there is no lock contention.
+//
+// Worker w draws from its own RNG seeded with seed+w, so results are
+// deterministic for a fixed seed and worker count. With a Temperature the
+// final move is sampled from the summed visits using seed+workers. Like
+// Search, it determinizes unless the state has PerfectInformation.
+func SearchParallel(state *engine.GameState, genome *engine.Genome, iterations int, explorationParam float64, workers int, seed int64) *engine.LegalMove {
+	params := SearchParams{Iterations: iterations, ExplorationParam: explorationParam, Determinize: !state.PerfectInformation}
+	return searchParallel(state, genome, params, workers, seed)
+}
+
+// searchParallel is SearchParallel with the full set of search parameters
+func searchParallel(state *engine.GameState, genome *engine.Genome, params SearchParams, workers int, seed int64) *engine.LegalMove {
+	if workers < 1 {
+		workers = 1
+	}
+	perWorker := params
+	perWorker.Iterations = params.Iterations / workers
+	if perWorker.Iterations < 1 {
+		perWorker.Iterations = 1
+	}
+
+	roots := make([]*MCTSNode, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed + int64(w)))
+			roots[w] = searchTree(state, genome, perWorker, rng)
+		}(w)
+	}
+	wg.Wait()
+
+	defer func() {
+		for _, root := range roots {
+			PutNode(root)
+		}
+	}()
+
+	// Sum visits per root move, indexed by generation order so ties break
+	// the same way regardless of which tree expanded a move first
+	moves := engine.GenerateLegalMoves(state, genome)
+	visits := make([]int, len(moves))
+	for _, root := range roots {
+		for _, child := range root.Children {
+			if child.Move == nil {
+				continue
+			}
+			for i := range moves {
+				if moves[i] == *child.Move {
+					visits[i] += child.Visits
+					break
+				}
+			}
+		}
+	}
+
+	var rng *rand.Rand
+	if params.Temperature > 0 {
+		rng = rand.New(rand.NewSource(seed + int64(workers)))
+	}
+	best := SampleByVisits(visits, params.Temperature, rng)
+	if best < 0 {
+		return fallbackMove(state, genome)
+	}
+
+	moveCopy := moves[best]
+	return &moveCopy
+}
@@ -0,0 +1,51 @@
+package mcts
+
+import "github.com/signalnine/darwindeck/gosim/engine"
+
+// MoveSig identifies a move in a policy's output
+type MoveSig = engine.LegalMove
+
+// PolicyFunc returns prior probabilities for the current player's moves in
+// state, typically from a trained model. Moves it leaves out get no prior;
+// the rest are renormalized over the legal moves. Parallel searches call it
+// from every worker at once.
+type PolicyFunc func(state *engine.GameState) map[MoveSig]float32
+
+// assignPriors asks policy for priors over node's untried moves and marks
+// node Guided. A policy that gives every legal move 0 falls back to uniform
+// priors.
+func assignPriors(node *MCTSNode, policy PolicyFunc) {
+	if policy == nil || len(node.UntriedMoves) == 0 {
+		return
+	}
+	probs := policy(node.State)
+
+	priors := node.UntriedPriors[:0]
+	total := 0.0
+	for _, move := range node.UntriedMoves {
+		p := float64(probs[move])
+		if p < 0 {
+			p = 0
+		}
+		priors = append(priors, p)
+		total += p
+	}
+	if total == 0 {
+		priors = uniformPriors(priors, len(node.UntriedMoves))
+	} else {
+		for i := range priors {
+			priors[i] /= total
+		}
+	}
+	node.UntriedPriors = priors
+	node.Guided = true
+}
+
+// uniformPriors resets priors to n equal entries, reusing its storage
+func uniformPriors(priors []float64, n int) []float64 {
+	priors = priors[:0]
+	for i := 0; i < n; i++ {
+		priors = append(priors, 1/float64(n))
+	}
+	return priors
+}
@@ -2,78 +2,222 @@ package mcts
 
 import (
 	"math/rand"
+	"sort"
 
 	"github.com/signalnine/darwindeck/gosim/engine"
 )
 
 const (
 	DefaultExplorationParam = 1.414 // sqrt(2)
+	DefaultDirichletAlpha   = 0.3   // As AlphaZero used for chess
 )
 
-// Search performs MCTS from the given state and returns the best move
+// MoveStat summarizes one root move after a search
+type MoveStat struct {
+	Move    engine.LegalMove
+	Visits  int
+	WinRate float64 // Mean rollout result for the searching player, 0-1 (draws count 0.5)
+}
+
+// Search performs MCTS from the given state and returns the best move.
+// Unless the state has GameState.PerfectInformation set, it searches a
+// guess at the cards hidden from the player to move (see
+// SearchParams.Determinize), so it never sees opponents' hands or the deck
+// order.
 func Search(state *engine.GameState, genome *engine.Genome, iterations int, explorationParam float64) *engine.LegalMove {
+	move, _ := SearchWithStats(state, genome, iterations, explorationParam)
+	return move
+}
+
+// SearchWithStats is Search that also returns every expanded root move's
+// visit count and estimated win rate, most visited first. The returned move
+// is the first entry's; if the search expanded nothing it falls back to the
+// first legal move and the stats are nil.
+func SearchWithStats(state *engine.GameState, genome *engine.Genome, iterations int, explorationParam float64) (*engine.LegalMove, []MoveStat) {
+	params := SearchParams{Iterations: iterations, ExplorationParam: explorationParam, Determinize: !state.PerfectInformation}
+	return SearchStatsWithParams(state, genome, params)
+}
+
+// SearchStatsWithParams is SearchWithStats with custom parameters, so a
+// caller can seed it. It always searches a single tree: ParallelWorkers and
+// Temperature are ignored, and the returned move is the most visited.
+func SearchStatsWithParams(state *engine.GameState, genome *engine.Genome, params SearchParams) (*engine.LegalMove, []MoveStat) {
+	root := searchTree(state, genome, params, seededRand(params.Seed))
+	defer PutNode(root)
+
+	stats := rootStats(root)
+	if len(stats) == 0 {
+		return fallbackMove(state, genome), nil
+	}
+	move := stats[0].Move
+	return &move, stats
+}
+
+// rootStats collects MoveStats for root's children, most visited first.
+// Ties go by moveLess, matching MostVisitedChild.
+func rootStats(root *MCTSNode) []MoveStat {
+	stats := make([]MoveStat, 0, len(root.Children))
+	for _, child := range root.Children {
+		if child.Move == nil || child.Visits == 0 {
+			continue
+		}
+		// Child wins are credited to the root's player (see backpropagate)
+		stats = append(stats, MoveStat{
+			Move:    *child.Move,
+			Visits:  child.Visits,
+			WinRate: child.Wins / float64(child.Visits),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Visits != stats[j].Visits {
+			return stats[i].Visits > stats[j].Visits
+		}
+		return moveLess(&stats[i].Move, &stats[j].Move)
+	})
+	return stats
+}
+
+// searchSingle grows one tree and returns its most visited root move, or one
+// sampled by visits when params.Temperature is above 0
+func searchSingle(state *engine.GameState, genome *engine.Genome, params SearchParams, rng *rand.Rand) *engine.LegalMove {
+	root := searchTree(state, genome, params, rng)
+	defer PutNode(root)
+
+	bestChild := SampleChildByVisits(root, params.Temperature, rng)
+	if bestChild == nil || bestChild.Move == nil {
+		return fallbackMove(state, genome)
+	}
+
+	// Create a copy of the move to return
+	moveCopy := *bestChild.Move
+	return &moveCopy
+}
+
+// searchTree builds a search tree from state and returns its root, which the
+// caller must release with PutNode. A nil rng uses the global math/rand source.
+func searchTree(state *engine.GameState, genome *engine.Genome, params SearchParams, rng *rand.Rand) *MCTSNode {
+	explorationParam := params.ExplorationParam
 	if explorationParam == 0 {
 		explorationParam = DefaultExplorationParam
 	}
 
+	// Only needed to evaluate rollouts cut off by RolloutDepth
+	var detector engine.LeaderDetector
+	if params.RolloutDepth > 0 {
+		detector = engine.SelectLeaderDetector(genome)
+	}
+
 	// Create root node
 	root := GetNode()
-	defer PutNode(root)
-
-	root.State = state.Clone()
+	if params.Determinize {
+		root.State = Determinize(state, state.CurrentPlayer, rng)
+	} else {
+		root.State = state.Clone()
+	}
 	root.PlayerID = state.CurrentPlayer
 	root.UntriedMoves = engine.GenerateLegalMoves(root.State, genome)
+	assignPriors(root, params.Policy)
+	if params.DirichletEpsilon > 0 && len(root.UntriedMoves) > 1 {
+		if !root.Guided {
+			root.Guided = true
+			root.UntriedPriors = uniformPriors(root.UntriedPriors, len(root.UntriedMoves))
+		}
+		addDirichletNoise(root.UntriedPriors, params.DirichletAlpha, params.DirichletEpsilon, rng)
+	}
+
+	// Rollout move and result buffers, reused across iterations (one per
+	// searchTree call, so each parallel worker has its own)
+	movesBuf := make([]engine.LegalMove, 0, 16)
+	results := make([]rolloutResult, 0, params.RolloutsPerExpansion)
 
 	// Run MCTS iterations
-	for i := 0; i < iterations; i++ {
+	for i := 0; i < params.Iterations; i++ {
 		node := root
 
-		// 1. Selection - traverse tree using UCB1
+		// 1. Selection - traverse tree using UCB1 (PUCT on guided nodes)
 		for !node.IsTerminal() && node.IsFullyExpanded() {
-			node = node.BestChild(explorationParam)
-			if node == nil {
+			next := node.BestChild(explorationParam)
+			if next == nil {
+				// No moves: the game ended without a WinnerID, which the
+				// rollout below scores
 				break
 			}
-		}
-
-		// If selection resulted in nil node, skip this iteration
-		if node == nil {
-			continue
+			node = next
 		}
 
 		// 2. Expansion - add a new child node
+		rollouts := 1
 		if !node.IsTerminal() && len(node.UntriedMoves) > 0 {
-			node = expand(node, genome)
+			node = expand(node, genome, params.Policy, rng)
+			if params.RolloutsPerExpansion > 1 {
+				rollouts = params.RolloutsPerExpansion
+			}
 		}
 
-		// 3. Simulation - play out randomly to terminal state
-		winner := simulate(node.State, genome)
+		// 3. Simulation - play out randomly to terminal state (or the depth cap)
+		results = results[:0]
+		for r := 0; r < rollouts; r++ {
+			results = append(results, simulate(node.State, genome, rng, params.RolloutPolicies, params.RolloutDepth, detector, &movesBuf))
+		}
 
 		// 4. Backpropagation - update statistics
-		backpropagate(node, winner)
+		backpropagate(node, results)
+
+		if params.EarlyStopInterval > 0 && (i+1)%params.EarlyStopInterval == 0 &&
+			rootDecided(root, params.Iterations-i-1) {
+			break
+		}
 	}
 
-	// Return most visited child's move
-	bestChild := root.MostVisitedChild()
-	if bestChild == nil || bestChild.Move == nil {
-		// Fallback to first legal move if MCTS fails
-		moves := engine.GenerateLegalMoves(state, genome)
-		if len(moves) > 0 {
-			return &moves[0]
+	return root
+}
+
+// rootDecided reports whether root's most visited child leads every other
+// child by more visits than the remaining iterations could give one of them
+func rootDecided(root *MCTSNode, remaining int) bool {
+	if len(root.UntriedMoves) > 0 {
+		return false // An unexpanded move could still take the lead
+	}
+	top, second := 0, 0
+	for _, child := range root.Children {
+		if child.Visits > top {
+			top, second = child.Visits, top
+		} else if child.Visits > second {
+			second = child.Visits
 		}
-		return nil
 	}
+	return top-second > remaining
+}
 
-	// Create a copy of the move to return
-	moveCopy := *bestChild.Move
-	return &moveCopy
+// fallbackMove returns the first legal move, used when the search produced no children
+func fallbackMove(state *engine.GameState, genome *engine.Genome) *engine.LegalMove {
+	moves := engine.GenerateLegalMoves(state, genome)
+	if len(moves) > 0 {
+		return &moves[0]
+	}
+	return nil
+}
+
+// randIntn draws from rng, or from the global source when rng is nil
+func randIntn(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return rand.Intn(n)
+	}
+	return rng.Intn(n)
 }
 
-// expand adds a new child node for an untried move
-func expand(node *MCTSNode, genome *engine.Genome) *MCTSNode {
+// expand adds a new child node for an untried move, with priors from policy
+// for its own moves when policy is set
+func expand(node *MCTSNode, genome *engine.Genome, policy PolicyFunc, rng *rand.Rand) *MCTSNode {
 	// Pick a random untried move
-	moveIndex := rand.Intn(len(node.UntriedMoves))
+	moveIndex := randIntn(rng, len(node.UntriedMoves))
 	move := node.UntriedMoves[moveIndex]
+	prior := 0.0
+	if node.Guided {
+		prior = node.UntriedPriors[moveIndex]
+		node.UntriedPriors[moveIndex] = node.UntriedPriors[len(node.UntriedPriors)-1]
+		node.UntriedPriors = node.UntriedPriors[:len(node.UntriedPriors)-1]
+	}
 
 	// Remove from untried moves
 	node.UntriedMoves[moveIndex] = node.UntriedMoves[len(node.UntriedMoves)-1]
@@ -89,86 +233,213 @@ func expand(node *MCTSNode, genome *engine.Genome) *MCTSNode {
 	child.Move = &move
 	child.Parent = node
 	child.PlayerID = childState.CurrentPlayer
+	child.Prior = prior
 	child.UntriedMoves = engine.GenerateLegalMoves(childState, genome)
+	assignPriors(child, policy)
 
 	node.Children = append(node.Children, child)
 
 	return child
 }
 
-// simulate plays out the game randomly from the current state
-func simulate(state *engine.GameState, genome *engine.Genome) int8 {
+// rolloutResult is the outcome of one rollout. A rollout that reaches a
+// terminal state has a winner (-1 for draw); one cut off by the depth cap
+// instead carries the heuristic leader (-1 for tie) and normalized margin.
+type rolloutResult struct {
+	winner int8
+	cutoff bool
+	leader int
+	margin float32
+}
+
+// valueFor returns the rollout's value in [0,1] for the given player:
+// 1 = win, 0 = loss, 0.5 = draw. Cut-off rollouts score the leader at
+// 0.5 + margin/2 and everyone else at 0.5 - margin/2.
+func (r rolloutResult) valueFor(playerID uint8) float64 {
+	if r.cutoff {
+		margin := float64(r.margin)
+		if margin < 0 {
+			margin = 0
+		} else if margin > 1 {
+			margin = 1
+		}
+		if r.leader < 0 {
+			return 0.5
+		}
+		if r.leader == int(playerID) {
+			return 0.5 + margin/2
+		}
+		return 0.5 - margin/2
+	}
+	if r.winner < 0 {
+		return 0.5
+	}
+	if uint8(r.winner) == playerID {
+		return 1.0
+	}
+	return 0.0
+}
+
+// simulate plays out the game from the current state, each seat choosing
+// moves with its entry in policies (uniformly at random without one).
+// With rolloutDepth > 0 the rollout stops after that many moves and the
+// position is scored with detector instead of playing to a terminal state.
+// Legal moves are generated into *movesBuf, which keeps any growth for the
+// next rollout.
+func simulate(state *engine.GameState, genome *engine.Genome, rng *rand.Rand, policies []RolloutPolicy, rolloutDepth int, detector engine.LeaderDetector, movesBuf *[]engine.LegalMove) rolloutResult {
 	simState := state.Clone()
 	defer engine.PutState(simState)
 
 	maxSimulationTurns := int(genome.Header.MaxTurns) * 2 // Safety limit
 
 	for i := 0; i < maxSimulationTurns; i++ {
-		// Check win conditions
-		winner := engine.CheckWinConditions(simState, genome)
-		if winner >= 0 {
-			return winner
+		// Check win conditions (a drawn end returns winner -1)
+		if outcome := engine.CheckGameOutcome(simState, genome); outcome.Over() {
+			return rolloutResult{winner: outcome.Winner}
+		}
+
+		// Depth cap reached - evaluate heuristically
+		if rolloutDepth > 0 && i >= rolloutDepth && detector != nil {
+			return rolloutResult{
+				winner: -1,
+				cutoff: true,
+				leader: detector.GetLeader(simState),
+				margin: detector.GetMargin(simState),
+			}
+		}
+
+		// Forced moves skip generation and the random pick
+		if forced, ok := engine.SingleForcedMove(simState, genome); ok {
+			engine.ApplyMove(simState, forced, genome)
+			continue
 		}
 
 		// Generate legal moves
-		moves := engine.GenerateLegalMoves(simState, genome)
+		moves := engine.GenerateLegalMovesInto(simState, genome, (*movesBuf)[:0])
+		*movesBuf = moves
 		if len(moves) == 0 {
 			// No legal moves - game is stuck
-			return -1
+			return rolloutResult{winner: -1}
 		}
 
-		// Pick a random move
-		move := moves[rand.Intn(len(moves))]
+		// Pick a move with the seat's policy
+		move := moves[choosePolicy(policies, simState.CurrentPlayer)(simState, moves, rng)]
 		engine.ApplyMove(simState, &move, genome)
 	}
 
 	// Timeout - return draw
-	return -1
+	return rolloutResult{winner: -1}
 }
 
-// backpropagate updates node statistics up the tree
+// RolloutPolicy picks the index of the move to play in a rollout. rng is the
+// searching worker's generator (nil means the global math/rand source, see
+// randIntn). Parallel searches share policies across workers, so a policy
+// must not keep unsynchronized state. Policies are only asked when a player
+// has a choice; forced moves are played directly.
+type RolloutPolicy func(state *engine.GameState, moves []engine.LegalMove, rng *rand.Rand) int
+
+// RandomRollout picks uniformly at random, the default rollout policy
+func RandomRollout(state *engine.GameState, moves []engine.LegalMove, rng *rand.Rand) int {
+	return randIntn(rng, len(moves))
+}
+
+// choosePolicy returns the rollout policy for playerID
+func choosePolicy(policies []RolloutPolicy, playerID uint8) RolloutPolicy {
+	if int(playerID) < len(policies) && policies[playerID] != nil {
+		return policies[playerID]
+	}
+	return RandomRollout
+}
+
+// backpropagate updates node statistics up the tree with the mean value of
+// one iteration's rollouts, counting them as a single visit.
 // IMPORTANT: Wins are stored from the perspective of the player who MADE the move
 // leading to this node (i.e., the PARENT's player), not the current node's player.
 // This is because UCB1 is used to select which child to visit, and the parent
 // wants to pick moves that are good for them.
-func backpropagate(node *MCTSNode, winner int8) {
+func backpropagate(node *MCTSNode, results []rolloutResult) {
 	for node != nil {
 		node.Visits++
 
 		// Award wins from the perspective of who made the move to reach this node
-		// The move was made by the PARENT's player, so we check against parent's PlayerID
-		if winner >= 0 {
-			if node.Parent != nil {
-				// Credit the parent's player (who made the move to reach this node)
-				if uint8(winner) == node.Parent.PlayerID {
-					node.Wins += 1.0
-				}
-			} else {
-				// Root node - credit if winner matches root's player
-				if uint8(winner) == node.PlayerID {
-					node.Wins += 1.0
-				}
-			}
-		} else {
-			// Draw - give partial credit
-			node.Wins += 0.5
+		// The move was made by the PARENT's player; the root credits its own player
+		playerID := node.PlayerID
+		if node.Parent != nil {
+			playerID = node.Parent.PlayerID
 		}
+		value := 0.0
+		for _, result := range results {
+			value += result.valueFor(playerID)
+		}
+		node.Wins += value / float64(len(results))
 
 		node = node.Parent
 	}
 }
 
-// SearchWithVariant allows specifying different MCTS variants
+// SearchParams configures a search run
 type SearchParams struct {
 	Iterations       int
 	ExplorationParam float64
+	// ParallelWorkers > 1 enables root parallelization (see SearchParallel)
+	ParallelWorkers int
+	// Seed for the search's RNG (one per worker with ParallelWorkers > 1),
+	// so a seeded search repeats exactly; 0 uses the global source
+	Seed int64
+	// RolloutDepth > 0 caps rollouts at that many moves and scores the
+	// position with the genome's LeaderDetector instead (0 = play to terminal)
+	RolloutDepth int
+	// RolloutPolicies picks rollout moves per seat, indexed by player ID, so
+	// the opponents can play like a specific AI; nil entries play at random
+	RolloutPolicies []RolloutPolicy
+	// Policy supplies move priors, which switches selection from UCB1 to
+	// PUCT (see MCTSNode.PUCT); nil searches with UCB1
+	Policy PolicyFunc
+	// DirichletEpsilon > 0 mixes that share of Dirichlet(DirichletAlpha)
+	// noise into the root moves' priors for exploration (uniform priors
+	// without a Policy, see addDirichletNoise); a zero alpha uses
+	// DefaultDirichletAlpha
+	DirichletAlpha   float64
+	DirichletEpsilon float64
+	// Temperature > 0 samples the returned move by root visit counts (see
+	// SampleChildByVisits) instead of taking the most visited
+	Temperature float64
+	// RolloutsPerExpansion > 1 runs that many rollouts from each newly
+	// expanded node and backpropagates their mean, which steadies the value
+	// estimates of noisy games at the cost of more rollouts (0 = one)
+	RolloutsPerExpansion int
+	// EarlyStopInterval > 0 checks every that many iterations whether the
+	// most visited root move is out of reach of the runner-up for the rest
+	// of the budget, and stops the search early once it is (0 = never)
+	EarlyStopInterval int
+	// Determinize searches from a deal of the cards hidden from the
+	// searching player (see Determinize) instead of the true state, so the
+	// search cannot peek at opponents' hands or the deck order. Each tree
+	// gets one deal: with ParallelWorkers > 1 every worker searches its own
+	// and their visits are pooled. Search, SearchWithStats and
+	// SearchParallel set it unless the state has PerfectInformation.
+	Determinize bool
 	// Future extensions:
 	// UseRAVE         bool
 	// UseProgWiden    bool
-	// ParallelWorkers int
 }
 
 // SearchWithParams runs MCTS with custom parameters
 func SearchWithParams(state *engine.GameState, genome *engine.Genome, params SearchParams) *engine.LegalMove {
-	return Search(state, genome, params.Iterations, params.ExplorationParam)
+	if params.ParallelWorkers > 1 {
+		seed := params.Seed
+		if seed == 0 {
+			seed = rand.Int63()
+		}
+		return searchParallel(state, genome, params, params.ParallelWorkers, seed)
+	}
+	return searchSingle(state, genome, params, seededRand(params.Seed))
+}
+
+// seededRand returns an RNG seeded with seed, or nil (the global source) for 0
+func seededRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		return nil
+	}
+	return rand.New(rand.NewSource(seed))
 }
@@ -0,0 +1,119 @@
+package simulation
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+// BettingMatchResult is the outcome of a multi-hand betting match
+type BettingMatchResult struct {
+	Chips       []int64 // Final chips per player
+	HandsPlayed int
+	Eliminated  []int // Players in the order they went bust
+	Winner      int8  // Last player standing, or -1 if several remain
+	Carryover   int64 // Chips still waiting in the carryover pot at the end
+}
+
+// Standings returns player IDs from first to last place: survivors by
+// final chips, then busted players, the last to go bust first
+func (r BettingMatchResult) Standings() []int {
+	busted := make([]bool, len(r.Chips))
+	for _, p := range r.Eliminated {
+		busted[p] = true
+	}
+	standings := make([]int, 0, len(r.Chips))
+	for p := range r.Chips {
+		if !busted[p] {
+			standings = append(standings, p)
+		}
+	}
+	sort.SliceStable(standings, func(i, j int) bool { return r.Chips[standings[i]] > r.Chips[standings[j]] })
+	for i := len(r.Eliminated) - 1; i >= 0; i-- {
+		standings = append(standings, r.Eliminated[i])
+	}
+	return standings
+}
+
+// RunBettingMatch plays up to handCount hands of a betting genome with
+// random AI. Each hand is dealt, bet street by street, settled by fold or
+// showdown, and the betting start seat (the dealer button) moves on.
+// Players who run out of chips are eliminated and sit out later hands; the
+// match ends early when only one player has chips left. Every hand opens
+// with the antes and the pot carried over from passed hands (see
+// engine.PostAntes and engine.CarryOverPot).
+func RunBettingMatch(genome *engine.Genome, seed uint64, handCount int) BettingMatchResult {
+	state := engine.SetupGame(genome, seed)
+	defer engine.PutState(state)
+	setup := engine.ReadSetupParams(genome)
+	rng := rand.New(rand.NewSource(int64(seed)))
+	numPlayers := int(state.NumPlayers)
+
+	result := BettingMatchResult{Winner: -1}
+	busted := make([]bool, numPlayers)
+	bettingPhase := getBettingPhaseData(genome)
+
+	var metrics GameMetrics
+	for bettingPhase != nil && result.HandsPlayed < handCount {
+		if result.HandsPlayed > 0 {
+			handSeed := seed + uint64(result.HandsPlayed)*0x9E3779B97F4A7C15
+			redealHand(state, setup, handSeed)
+		}
+		for p := range busted {
+			state.Players[p].HasFolded = busted[p]
+		}
+		engine.PostAntes(state, bettingPhase)
+
+		// Bet each street until one player is left or the cards run out
+		for {
+			runBettingRound(state, genome, bettingPhase, RandomAI, &metrics, nil, nil, rng)
+			if _, ok := engine.OnlyOneActive(state); ok || !engine.HasMoreStreets(state, genome.DealPattern) {
+				break
+			}
+			engine.NextBettingRound(state)
+			engine.Deal(state, genome.DealPattern, state.DealRound)
+		}
+
+		engine.CarryOverPot(state, bettingPhase)
+		if winner, ok := engine.OnlyOneActive(state); ok {
+			engine.AwardPot(state, bettingPhase, []int{int(winner)})
+		} else if winners := engine.FindBestPokerWinner(state, numPlayers); len(winners) > 0 {
+			engine.AwardPot(state, bettingPhase, engine.PokerWinnerIDs(winners))
+		} else {
+			// Nobody can show a hand: return the bets and carry the
+			// antes over
+			for p := 0; p < numPlayers; p++ {
+				state.Players[p].Chips += state.Players[p].TotalBet
+				state.Pot -= state.Players[p].TotalBet
+			}
+			state.CarryoverPot += state.Pot
+			state.Pot = 0
+		}
+		result.HandsPlayed++
+
+		remaining := 0
+		for p := 0; p < numPlayers; p++ {
+			if !busted[p] && state.Players[p].Chips <= 0 {
+				busted[p] = true
+				result.Eliminated = append(result.Eliminated, p)
+			}
+			if !busted[p] {
+				remaining++
+				result.Winner = int8(p)
+			}
+		}
+		if remaining <= 1 {
+			break
+		}
+		result.Winner = -1
+		state.ResetHand()
+	}
+
+	result.Carryover = state.CarryoverPot
+	result.Chips = make([]int64, numPlayers)
+	for p := range result.Chips {
+		result.Chips[p] = state.Players[p].Chips
+	}
+	return result
+}
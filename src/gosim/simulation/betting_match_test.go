@@ -0,0 +1,131 @@
+package simulation
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+// shortStackPokerGenome returns the golden simple poker genome for
+// numPlayers with chips starting at chips each
+func shortStackPokerGenome(t *testing.T, numPlayers, chips uint32) *engine.Genome {
+	t.Helper()
+	bytecode, err := os.ReadFile(filepath.Join("..", "..", "..", "tests", "golden", "simple_poker_genome.bin"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	genome, err := engine.ParseGenome(bytecode)
+	if err != nil {
+		t.Fatalf("Failed to parse genome: %v", err)
+	}
+	genome.Header.PlayerCount = numPlayers
+	offset := genome.Header.SetupOffset
+	binary.BigEndian.PutUint32(genome.Bytecode[offset+8:offset+12], chips)
+	return genome
+}
+
+func TestRunBettingMatchEliminates(t *testing.T) {
+	// With stacks equal to the minimum bet any bet is all-in, so a called
+	// bet busts the loser within a few hands
+	genome := shortStackPokerGenome(t, 3, 10)
+
+	sawElimination := false
+	for seed := uint64(1); seed <= 20; seed++ {
+		result := RunBettingMatch(genome, seed, 3)
+
+		if result.HandsPlayed < 1 || result.HandsPlayed > 3 {
+			t.Fatalf("Seed %d: expected 1-3 hands, got %d", seed, result.HandsPlayed)
+		}
+		total := int64(0)
+		for _, chips := range result.Chips {
+			total += chips
+		}
+		if total != 30 {
+			t.Errorf("Seed %d: expected 30 chips in play, got %d (%v)", seed, total, result.Chips)
+		}
+		for _, p := range result.Eliminated {
+			if result.Chips[p] != 0 {
+				t.Errorf("Seed %d: eliminated player %d still has %d chips", seed, p, result.Chips[p])
+			}
+		}
+
+		if len(result.Eliminated) == 2 {
+			if result.Winner < 0 || result.Chips[result.Winner] != 30 {
+				t.Errorf("Seed %d: expected last player standing to hold all chips, got winner %d with %v",
+					seed, result.Winner, result.Chips)
+			}
+		} else if result.Winner != -1 {
+			t.Errorf("Seed %d: expected no winner with %d eliminated, got %d", seed, len(result.Eliminated), result.Winner)
+		}
+
+		if len(result.Eliminated) > 0 {
+			sawElimination = true
+			standings := result.Standings()
+			if last := standings[len(standings)-1]; last != result.Eliminated[0] {
+				t.Errorf("Seed %d: expected first player out %d in last place, got %v", seed, result.Eliminated[0], standings)
+			}
+		}
+	}
+	if !sawElimination {
+		t.Error("Expected at least one match with an elimination")
+	}
+}
+
+func TestRunBettingMatchDeterministic(t *testing.T) {
+	genome := shortStackPokerGenome(t, 2, 50)
+
+	a := RunBettingMatch(genome, 7, 3)
+	b := RunBettingMatch(genome, 7, 3)
+	if a.HandsPlayed != b.HandsPlayed || a.Winner != b.Winner {
+		t.Fatalf("Expected identical matches, got %+v and %+v", a, b)
+	}
+	for p := range a.Chips {
+		if a.Chips[p] != b.Chips[p] {
+			t.Errorf("Player %d: chips differ, %d vs %d", p, a.Chips[p], b.Chips[p])
+		}
+	}
+}
+
+func TestBettingMatchStandings(t *testing.T) {
+	result := BettingMatchResult{
+		Chips:      []int64{0, 40, 0, 60},
+		Eliminated: []int{2, 0},
+		Winner:     -1,
+	}
+	want := []int{3, 1, 0, 2}
+	got := result.Standings()
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected standings %v, got %v", want, got)
+		}
+	}
+}
+
+func TestRunBettingMatchCarriesPassedPots(t *testing.T) {
+	genome := shortStackPokerGenome(t, 2, 50)
+	genome.Betting = &engine.BettingPhaseData{MinBet: 10, MaxRaises: 3, Ante: 5, CarryoverPercent: 100}
+
+	sawCarryover := false
+	for seed := uint64(1); seed <= 30; seed++ {
+		result := RunBettingMatch(genome, seed, 1)
+		total := result.Carryover
+		for _, chips := range result.Chips {
+			total += chips
+		}
+		if total != 100 {
+			t.Errorf("Seed %d: expected 100 chips between stacks and carryover, got %d (%+v)", seed, total, result)
+		}
+		if result.Carryover > 0 {
+			sawCarryover = true
+			if result.Carryover%10 != 0 {
+				t.Errorf("Seed %d: expected only antes carried over, got %d", seed, result.Carryover)
+			}
+		}
+	}
+	if !sawCarryover {
+		t.Error("Expected at least one passed hand to carry its pot over")
+	}
+}
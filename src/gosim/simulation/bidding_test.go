@@ -1,6 +1,7 @@
 package simulation
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/signalnine/darwindeck/gosim/engine"
@@ -81,7 +82,7 @@ func TestRunSingleGameWithBidding(t *testing.T) {
 	
 	// Test runBiddingRound
 	aiTypes := []AIPlayerType{RandomAI, RandomAI, RandomAI, RandomAI}
-	runBiddingRound(state, genome, aiTypes)
+	runBiddingRound(state, genome, aiTypes, rand.New(rand.NewSource(1)))
 	
 	// Verify all players have bid
 	if !state.BiddingComplete {
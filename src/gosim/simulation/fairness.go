@@ -0,0 +1,57 @@
+package simulation
+
+import (
+	"math"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+// SeatAdvantage is how much more often the first player to move wins than
+// the player after them, measured by FirstPlayerAdvantage
+type SeatAdvantage struct {
+	Games       int     // Games finished without an error
+	FirstSeat   int     // Player who moves first
+	FirstWins   float64 // Share of games the first player won
+	SecondWins  float64 // Share of games the next player won
+	Delta       float64 // FirstWins - SecondWins; positive favors moving first
+	StdErr      float64 // Standard error of Delta
+	Significant bool    // Delta is more than 1.96 standard errors from 0
+}
+
+// FirstPlayerAdvantage plays n games of genome from seed with the greedy AI
+// at every seat, so the seats differ only in turn order, and compares the
+// win rates of the first player and the next. StdErr uses the Agresti-Caffo
+// adjustment (a win added to each seat), which keeps small or one-sided
+// samples from looking certain: a first player who wins all of 3 games is
+// not significant, all of 20 is.
+func FirstPlayerAdvantage(genome *engine.Genome, n int, seed uint64) SeatAdvantage {
+	return MeasureSeatAdvantage(genome, RunBatch(genome, n, GreedyAI, 0, seed))
+}
+
+// MeasureSeatAdvantage compares the first player's and the next player's
+// wins in a batch of genome already played, whatever AI played it. The first
+// player is the genome's starting player, not necessarily seat 0.
+func MeasureSeatAdvantage(genome *engine.Genome, stats AggregatedStats) SeatAdvantage {
+	setup := engine.ReadSetupParams(genome)
+	first := setup.StartingPlayer
+	second := (first + 1) % setup.NumPlayers
+
+	result := SeatAdvantage{
+		Games:     int(stats.TotalGames - stats.Errors),
+		FirstSeat: first,
+	}
+	if result.Games == 0 {
+		return result
+	}
+	games := float64(result.Games)
+	result.FirstWins = float64(stats.Wins[first]) / games
+	result.SecondWins = float64(stats.Wins[second]) / games
+	result.Delta = result.FirstWins - result.SecondWins
+
+	adjusted := games + 2
+	p1 := (float64(stats.Wins[first]) + 1) / adjusted
+	p2 := (float64(stats.Wins[second]) + 1) / adjusted
+	result.StdErr = math.Sqrt((p1 + p2 - (p1-p2)*(p1-p2)) / adjusted)
+	result.Significant = math.Abs(p1-p2) > 1.96*result.StdErr
+	return result
+}
@@ -0,0 +1,62 @@
+package simulation
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+// loadGoldenGenome returns the genome in the golden file name
+func loadGoldenGenome(t *testing.T, name string) *engine.Genome {
+	t.Helper()
+	bytecode, err := os.ReadFile(filepath.Join("..", "..", "..", "tests", "golden", name))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	genome, err := engine.ParseGenome(bytecode)
+	if err != nil {
+		t.Fatalf("Failed to parse genome: %v", err)
+	}
+	return genome
+}
+
+func TestFirstPlayerAdvantageSeatBiased(t *testing.T) {
+	// One card each and the first to empty their hand wins: whoever moves
+	// first always wins
+	genome := loadGoldenGenome(t, "war_genome.bin")
+	binary.BigEndian.PutUint32(genome.Bytecode[genome.Header.SetupOffset:], 1)
+	genome.WinConditions = []engine.WinCondition{{WinType: engine.WinTypeEmptyHand}}
+
+	advantage := FirstPlayerAdvantage(genome, 30, 1)
+	if advantage.Games != 30 || advantage.FirstWins != 1 || advantage.Delta != 1 {
+		t.Fatalf("Expected the first player to win all 30 games, got %+v", advantage)
+	}
+	if !advantage.Significant {
+		t.Errorf("Expected a significant advantage, got %+v", advantage)
+	}
+
+	// A few games aren't enough to be sure
+	if few := FirstPlayerAdvantage(genome, 3, 1); few.Delta != 1 || few.Significant {
+		t.Errorf("Expected 3 games to be inconclusive, got %+v", few)
+	}
+
+	// Moving the start moves the advantage
+	genome.StartingPlayer = 1
+	if moved := FirstPlayerAdvantage(genome, 30, 1); moved.FirstSeat != 1 || moved.Delta != 1 {
+		t.Errorf("Expected player 1 to win every game moving first, got %+v", moved)
+	}
+}
+
+func TestFirstPlayerAdvantagePoker(t *testing.T) {
+	// Heads-up poker is decided by the cards, not by who acts first
+	advantage := FirstPlayerAdvantage(shortStackPokerGenome(t, 2, 1000), 100, 7)
+	if advantage.Games != 100 || advantage.Significant {
+		t.Errorf("Expected no significant advantage in poker, got %+v", advantage)
+	}
+	if advantage.StdErr <= 0 || advantage.StdErr > 0.2 {
+		t.Errorf("Expected a standard error for 100 games, got %v", advantage.StdErr)
+	}
+}
@@ -103,6 +103,7 @@ func worker(wg *sync.WaitGroup, jobs <-chan GameJob, results chan<- GameResult,
 
 	for job := range jobs {
 		result := RunSingleGame(genome, aiType, mctsIterations, job.Seed)
+		result.Game, result.Seed = job.SimID, job.Seed
 		results <- result
 	}
 }
@@ -207,6 +208,7 @@ func workerAsymmetric(wg *sync.WaitGroup, jobs <-chan GameJob, results chan<- Ga
 
 	for job := range jobs {
 		result := RunSingleGameAsymmetric(genome, p0AIType, p1AIType, mctsIterations, job.Seed)
+		result.Game, result.Seed = job.SimID, job.Seed
 		results <- result
 	}
 }
@@ -0,0 +1,71 @@
+package simulation
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+// EvalResult is the outcome of evaluating one genome in a WorkerPool
+type EvalResult struct {
+	Stats AggregatedStats
+	Err   error // Set, with Stats empty, if the evaluation panicked
+}
+
+// WorkerPool evaluates genomes on goroutines for callers that embed the
+// engine, in place of a pool of worker processes. Each evaluation is a
+// batch of games (see RunBatch) and at most the pool's worker count run at
+// once. A genome that panics the engine only errors its own games (see
+// recoverGame); a panic outside the games fails only its own evaluation.
+type WorkerPool struct {
+	Games  int          // Games per evaluation
+	AIType AIPlayerType // AI at every seat
+	Seed   uint64       // Every genome is played from the same seed, so results compare like for like
+
+	slots chan struct{} // One token per running evaluation
+	// runBatch plays an evaluation's games; nil means RunBatch (tests stub it)
+	runBatch func(genome *engine.Genome, numGames int, aiType AIPlayerType, mctsIterations int, seed uint64) AggregatedStats
+}
+
+// NewWorkerPool returns a pool running up to workers evaluations at once
+// (runtime.NumCPU() if workers <= 0), each of games games with aiType
+func NewWorkerPool(workers, games int, aiType AIPlayerType, seed uint64) *WorkerPool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &WorkerPool{
+		Games:  games,
+		AIType: aiType,
+		Seed:   seed,
+		slots:  make(chan struct{}, workers),
+	}
+}
+
+// Submit queues genome for evaluation and returns a channel that receives
+// its result once and is then closed. Submit doesn't block; evaluations
+// beyond the worker count wait for a free worker.
+func (p *WorkerPool) Submit(genome *engine.Genome) <-chan EvalResult {
+	out := make(chan EvalResult, 1)
+	go func() {
+		defer close(out)
+		p.slots <- struct{}{}
+		defer func() { <-p.slots }()
+		out <- p.evaluate(genome)
+	}()
+	return out
+}
+
+// evaluate runs one evaluation, turning a panic into an error
+func (p *WorkerPool) evaluate(genome *engine.Genome) (result EvalResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = EvalResult{Err: fmt.Errorf("genome evaluation panicked: %v", r)}
+		}
+	}()
+	runBatch := p.runBatch
+	if runBatch == nil {
+		runBatch = RunBatch
+	}
+	return EvalResult{Stats: runBatch(genome, p.Games, p.AIType, 0, p.Seed)}
+}
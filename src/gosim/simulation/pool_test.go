@@ -0,0 +1,64 @@
+package simulation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+func TestWorkerPoolIsolatesPanics(t *testing.T) {
+	pool := NewWorkerPool(2, 10, RandomAI, 3)
+	war := loadGoldenGenome(t, "war_genome.bin")
+	hearts := loadGoldenGenome(t, "hearts_genome.bin")
+
+	// A genome without a header panics the engine
+	warResult := pool.Submit(war)
+	brokenResult := pool.Submit(&engine.Genome{})
+	heartsResult := pool.Submit(hearts)
+
+	if result := <-brokenResult; result.Err != nil || result.Stats.Errors != 10 {
+		t.Errorf("Expected the broken genome's games all counted as errors, got %+v", result)
+	}
+	if _, open := <-brokenResult; open {
+		t.Error("Expected the result channel closed after its result")
+	}
+	for genome, results := range map[*engine.Genome]<-chan EvalResult{war: warResult, hearts: heartsResult} {
+		result := <-results
+		if result.Err != nil {
+			t.Fatalf("Expected success next to a panicking genome, got %v", result.Err)
+		}
+		want := RunBatch(genome, 10, RandomAI, 0, 3)
+		if result.Stats.TotalGames != 10 || !reflect.DeepEqual(result.Stats.Wins, want.Wins) {
+			t.Errorf("Expected the wins of RunBatch %v, got %v", want.Wins, result.Stats.Wins)
+		}
+	}
+}
+
+func TestWorkerPoolRecoversEvaluationPanics(t *testing.T) {
+	pool := NewWorkerPool(2, 10, RandomAI, 3)
+	war := loadGoldenGenome(t, "war_genome.bin")
+	hearts := loadGoldenGenome(t, "hearts_genome.bin")
+
+	// Panic outside the game runners, which recover their own games
+	pool.runBatch = func(genome *engine.Genome, numGames int, aiType AIPlayerType, mctsIterations int, seed uint64) AggregatedStats {
+		if genome == hearts {
+			panic("aggregation failed")
+		}
+		return RunBatch(genome, numGames, aiType, mctsIterations, seed)
+	}
+	heartsResult := pool.Submit(hearts)
+	warResult := pool.Submit(war)
+
+	result := <-heartsResult
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "aggregation failed") {
+		t.Errorf("Expected the panic returned as an error, got %+v", result)
+	}
+	if result.Stats.TotalGames != 0 {
+		t.Errorf("Expected no stats from a panicked evaluation, got %+v", result.Stats)
+	}
+	if result := <-warResult; result.Err != nil || result.Stats.TotalGames != 10 {
+		t.Errorf("Expected the other evaluation to finish, got %+v", result)
+	}
+}
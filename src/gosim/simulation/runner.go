@@ -1,8 +1,10 @@
 package simulation
 
 import (
-	"encoding/binary"
+	"fmt"
 	"math/rand"
+	"runtime/debug"
+	"sort"
 	"time"
 
 	"github.com/signalnine/darwindeck/gosim/engine"
@@ -64,18 +66,36 @@ type GameResult struct {
 	TurnCount      uint32
 	DurationNs     uint64
 	Error          string
+	Looped         bool        // Drawn because a forced position recurred (see cycleDetector)
 	Metrics        GameMetrics // Phase 1 instrumentation
+	// Stack is where the engine panicked, for a game that ended in a panic
+	Stack string
+
+	// Set by the batch runners to identify the game
+	Game int    // Position in the batch
+	Seed uint64 // Seed the game was played with
+}
+
+// GameError describes a game in a batch that ended with an error
+type GameError struct {
+	Game    int    // Position in the batch
+	Seed    uint64 // Replays the game with RunSingleGame
+	Message string
+	Stack   string // Set if the game panicked
 }
 
-// AggregatedStats summarizes multiple game results
+// AggregatedStats summarizes multiple game results. Every game is counted
+// exactly once in Wins, Draws or Errors, so they sum to TotalGames.
 type AggregatedStats struct {
 	TotalGames    uint32
 	Wins          []uint32 // Wins per player (index = player ID)
-	Draws         uint32
-	AvgTurns      float32
-	MedianTurns   uint32
+	Draws         uint32   // Games that ended without a winner
+	LoopedGames   uint32   // Draws called because the game was stuck in a loop
+	AvgTurns      float32  // Mean game length of games without errors
+	MedianTurns   uint32   // Median game length of games without errors
 	AvgDurationNs uint64
 	Errors        uint32
+	ErrorDetails  []GameError // One per errored game, ordered by Game
 
 	// Phase 1 instrumentation: aggregated across all games
 	TotalDecisions    uint64
@@ -125,94 +145,29 @@ func RunBatch(genome *engine.Genome, numGames int, aiType AIPlayerType, mctsIter
 	for i := 0; i < numGames; i++ {
 		gameSeed := rng.Uint64()
 		results[i] = RunSingleGame(genome, aiType, mctsIterations, gameSeed)
+		results[i].Game, results[i].Seed = i, gameSeed
 	}
 
 	return aggregateResults(results)
 }
 
-// RunSingleGame plays one complete game to termination
-func RunSingleGame(genome *engine.Genome, aiType AIPlayerType, mctsIterations int, seed uint64) GameResult {
+// RunSingleGame plays one complete game to termination. A panic in the
+// engine ends the game with an error (see recoverGame).
+func RunSingleGame(genome *engine.Genome, aiType AIPlayerType, mctsIterations int, seed uint64) (result GameResult) {
 	start := time.Now()
+	defer recoverGame(&result, start)
 	var metrics GameMetrics
 
-	// Initialize game state
-	state := engine.GetState()
+	// Build the deck, deal, and seed chips/teams from the genome setup.
+	// setup is kept so match play can re-deal with the same parameters.
+	state := engine.SetupGame(genome, seed)
 	defer engine.PutState(state)
+	setup := engine.ReadSetupParams(genome)
+	handsPlayed := 0
 
-	// Setup deck and deal cards
-	setupDeck(state, seed)
-
-	// Read setup section from genome bytecode
-	// Format: cards_per_player:4 + initial_discard_count:4 + starting_chips:4
-	cardsPerPlayer := 26 // Default for War
-	initialDiscardCount := 0
-	startingChips := 0
-
-	if genome.Header.SetupOffset > 0 && genome.Header.SetupOffset+12 <= int32(len(genome.Bytecode)) {
-		setupOffset := genome.Header.SetupOffset
-		cardsPerPlayer = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset : setupOffset+4])))
-		initialDiscardCount = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset+4 : setupOffset+8])))
-		startingChips = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset+8 : setupOffset+12])))
-	}
-
-	// Determine number of players from genome header
-	numPlayers := int(genome.Header.PlayerCount)
-	if numPlayers == 0 || numPlayers > 4 {
-		numPlayers = 2 // Default to 2 players
-	}
-
-	// Initialize trick-taking state
-	state.NumPlayers = uint8(numPlayers)
-	state.CardsPerPlayer = cardsPerPlayer
-
-	// Set tableau mode from genome header
-	state.TableauMode = genome.Header.TableauMode
-	state.SequenceDirection = genome.Header.SequenceDirection
-
-	// Initialize teams if configured
-	if genome.Header.TeamMode && genome.Header.TeamCount > 0 && genome.Header.TeamDataOffset > 0 {
-		teamDataOffset := genome.Header.TeamDataOffset
-		if teamDataOffset < len(genome.Bytecode) {
-			teams := engine.ParseTeams(genome.Bytecode[teamDataOffset:])
-			state.InitializeTeams(teams)
-		}
-	}
-
-	// Deal cards to each player
-	for i := 0; i < cardsPerPlayer; i++ {
-		for p := 0; p < numPlayers; p++ {
-			state.DrawCard(uint8(p), engine.LocationDeck)
-		}
-	}
-
-	// Deal initial cards to discard/tableau
-	// For TableauMode games (Scopa), cards go to Tableau[0]
-	// For other games (Uno), cards go to Discard
-	if initialDiscardCount > 0 && len(state.Deck) >= initialDiscardCount {
-		// Initialize tableau pile if needed for TableauMode games
-		if state.TableauMode != 0 && len(state.Tableau) == 0 {
-			state.Tableau = make([][]engine.Card, 1)
-			state.Tableau[0] = make([]engine.Card, 0, initialDiscardCount)
-		}
-		for i := 0; i < initialDiscardCount; i++ {
-			if len(state.Deck) > 0 {
-				card := state.Deck[len(state.Deck)-1]
-				state.Deck = state.Deck[:len(state.Deck)-1]
-				if state.TableauMode != 0 {
-					// Scopa/MATCH_RANK/SEQUENCE: cards go to tableau[0]
-					state.Tableau[0] = append(state.Tableau[0], card)
-				} else {
-					// Uno-style: cards go to discard
-					state.Discard = append(state.Discard, card)
-				}
-			}
-		}
-	}
-
-	// Initialize chips if this genome uses betting
-	if startingChips > 0 {
-		state.InitializeChips(startingChips)
-	}
+	// Per-game random source for AI choices, so games are reproducible
+	// from seed and independent of other games running concurrently
+	rng := rand.New(rand.NewSource(int64(seed)))
 
 	// Initialize tension tracking
 	detector := engine.SelectLeaderDetector(genome)
@@ -220,10 +175,23 @@ func RunSingleGame(genome *engine.Genome, aiType AIPlayerType, mctsIterations in
 
 	// Game loop with turn limit protection
 	maxTurns := genome.Header.MaxTurns
+	movesBuf := make([]engine.LegalMove, 0, 16) // Reused by every turn
+	var cycles cycleDetector
 	for state.TurnNumber < maxTurns {
 		// Check win conditions
-		winner := engine.CheckWinConditions(state, genome)
-		if winner >= 0 {
+		// In match play a finished hand only ends the game once the
+		// cumulative target is reached; otherwise a new hand is dealt
+		// A condition can also end the game drawn (a chopped showdown)
+		var winner int8
+		over := false
+		if engine.HandComplete(state, genome) {
+			winner = resolveMatchHand(state, genome, setup, seed, &handsPlayed)
+			over = winner >= 0
+		} else {
+			outcome := engine.CheckGameOutcome(state, genome)
+			winner, over = outcome.Winner, outcome.Over()
+		}
+		if over {
 			tensionMetrics.Finalize(int(winner))
 			metrics.LeadChanges = uint32(tensionMetrics.LeadChanges)
 			metrics.DecisiveTurnPct = tensionMetrics.DecisiveTurnPct()
@@ -239,13 +207,13 @@ func RunSingleGame(genome *engine.Genome, aiType AIPlayerType, mctsIterations in
 		}
 
 		// Generate legal moves
-		moves := engine.GenerateLegalMoves(state, genome)
+		moves := legalMoves(state, genome, &movesBuf)
 
 		// Check if this is a betting phase
 		if hasBettingPhase(moves) {
 			bettingPhase := getBettingPhaseData(genome)
 			if bettingPhase != nil {
-				err := runBettingRound(state, genome, bettingPhase, aiType, &metrics, tensionMetrics, detector)
+				err := runBettingRound(state, genome, bettingPhase, aiType, &metrics, tensionMetrics, detector, rng)
 				if err != "" {
 					tensionMetrics.Finalize(-1)
 					metrics.LeadChanges = uint32(tensionMetrics.LeadChanges)
@@ -271,10 +239,9 @@ func RunSingleGame(genome *engine.Genome, aiType AIPlayerType, mctsIterations in
 				if engine.IsBlackjackGame(genome) {
 					// Blackjack: just continue to draw phase
 					// Only resolve showdown if someone folded
-					winners := engine.ResolveShowdown(state)
-					if len(winners) == 1 {
+					if winner, ok := engine.OnlyOneActive(state); ok {
 						// Single winner (opponent folded)
-						engine.AwardPot(state, winners)
+						engine.AwardPot(state, bettingPhase, []int{int(winner)})
 						metrics.FoldWins++
 						state.ResetHand()
 					}
@@ -282,17 +249,25 @@ func RunSingleGame(genome *engine.Genome, aiType AIPlayerType, mctsIterations in
 					continue
 				}
 
+				// Staged deals (flop/turn/river): deal the next street and
+				// bet a fresh round on it before any showdown
+				if _, ok := engine.OnlyOneActive(state); !ok && engine.HasMoreStreets(state, genome.DealPattern) {
+					engine.NextBettingRound(state)
+					engine.Deal(state, genome.DealPattern, state.DealRound)
+					continue
+				}
+
 				// Poker-style: resolve showdown after betting
-				winners := engine.ResolveShowdown(state)
-				if len(winners) == 1 {
-					// Single winner (others folded)
-					engine.AwardPot(state, winners)
+				if winner, ok := engine.OnlyOneActive(state); ok {
+					// Everyone else folded - award the pot without a showdown
+					engine.AwardPot(state, bettingPhase, []int{int(winner)})
 					metrics.FoldWins++ // Track fold win
-				} else if len(winners) > 1 {
+				} else {
 					// Multiple players - use poker hand comparison
-					winner := engine.FindBestPokerWinner(state, int(state.NumPlayers))
-					if winner >= 0 {
-						engine.AwardPot(state, []int{int(winner)})
+					showdownWinners := engine.FindBestPokerWinner(state, int(state.NumPlayers))
+					if len(showdownWinners) > 0 {
+						// Ties split the pot
+						engine.AwardPot(state, bettingPhase, engine.PokerWinnerIDs(showdownWinners))
 						metrics.ShowdownWins++ // Track showdown win
 					}
 				}
@@ -310,7 +285,7 @@ func RunSingleGame(genome *engine.Genome, aiType AIPlayerType, mctsIterations in
 			for i := range aiTypes {
 				aiTypes[i] = aiType
 			}
-			runBiddingRound(state, genome, aiTypes)
+			runBiddingRound(state, genome, aiTypes, rng)
 			continue // Skip normal move application, re-evaluate moves after bidding
 		}
 
@@ -351,6 +326,24 @@ func RunSingleGame(genome *engine.Genome, aiType AIPlayerType, mctsIterations in
 			}
 		}
 
+		// A position that recurs through forced moves alone loops forever,
+		// so call the game a draw now rather than at the turn limit
+		if cycles.repeated(state, len(moves)) {
+			tensionMetrics.Finalize(-1)
+			metrics.LeadChanges = uint32(tensionMetrics.LeadChanges)
+			metrics.DecisiveTurnPct = tensionMetrics.DecisiveTurnPct()
+			metrics.ClosestMargin = tensionMetrics.ClosestMargin
+			metrics.WinnerWasTrailing = tensionMetrics.WinnerWasTrailing
+			return GameResult{
+				WinnerID:    -1,
+				WinningTeam: -1,
+				TurnCount:   state.TurnNumber,
+				DurationNs:  uint64(time.Since(start).Nanoseconds()),
+				Looped:      true,
+				Metrics:     metrics,
+			}
+		}
+
 		// Phase 1 instrumentation: decision counting
 		metrics.TotalDecisions++
 		metrics.TotalValidMoves += uint64(len(moves))
@@ -394,7 +387,7 @@ func RunSingleGame(genome *engine.Genome, aiType AIPlayerType, mctsIterations in
 		} else {
 			switch aiType {
 			case RandomAI:
-				move = &moves[rand.Intn(len(moves))]
+				move = &moves[rng.Intn(len(moves))]
 			case GreedyAI:
 				move = selectGreedyMove(state, genome, moves)
 			case MCTS100AI:
@@ -494,98 +487,51 @@ func RunBatchAsymmetric(genome *engine.Genome, numGames int, p0AIType AIPlayerTy
 	for i := 0; i < numGames; i++ {
 		gameSeed := rng.Uint64()
 		results[i] = RunSingleGameAsymmetric(genome, p0AIType, p1AIType, mctsIterations, gameSeed)
+		results[i].Game, results[i].Seed = i, gameSeed
 	}
 
 	return aggregateResults(results)
 }
 
 // RunSingleGameAsymmetric plays one game with different AI for each player.
-func RunSingleGameAsymmetric(genome *engine.Genome, p0AIType AIPlayerType, p1AIType AIPlayerType, mctsIterations int, seed uint64) GameResult {
+// A panic in the engine ends the game with an error (see recoverGame).
+func RunSingleGameAsymmetric(genome *engine.Genome, p0AIType AIPlayerType, p1AIType AIPlayerType, mctsIterations int, seed uint64) (result GameResult) {
 	start := time.Now()
+	defer recoverGame(&result, start)
 	var metrics GameMetrics
 
-	state := engine.GetState()
+	// Build the deck, deal, and seed chips/teams from the genome setup.
+	// setup is kept so match play can re-deal with the same parameters.
+	state := engine.SetupGame(genome, seed)
 	defer engine.PutState(state)
+	setup := engine.ReadSetupParams(genome)
+	handsPlayed := 0
 
-	setupDeck(state, seed)
-
-	// Read setup section from genome bytecode
-	// Format: cards_per_player:4 + initial_discard_count:4 + starting_chips:4
-	cardsPerPlayer := 26
-	initialDiscardCount := 0
-	startingChips := 0
-
-	if genome.Header.SetupOffset > 0 && genome.Header.SetupOffset+12 <= int32(len(genome.Bytecode)) {
-		setupOffset := genome.Header.SetupOffset
-		cardsPerPlayer = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset : setupOffset+4])))
-		initialDiscardCount = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset+4 : setupOffset+8])))
-		startingChips = int(int32(binary.BigEndian.Uint32(genome.Bytecode[setupOffset+8 : setupOffset+12])))
-	}
-
-	numPlayers := int(genome.Header.PlayerCount)
-	if numPlayers == 0 || numPlayers > 4 {
-		numPlayers = 2
-	}
-
-	state.NumPlayers = uint8(numPlayers)
-	state.CardsPerPlayer = cardsPerPlayer
-
-	// Set tableau mode from genome header
-	state.TableauMode = genome.Header.TableauMode
-	state.SequenceDirection = genome.Header.SequenceDirection
-
-	// Initialize teams if configured
-	if genome.Header.TeamMode && genome.Header.TeamCount > 0 && genome.Header.TeamDataOffset > 0 {
-		teamDataOffset := genome.Header.TeamDataOffset
-		if teamDataOffset < len(genome.Bytecode) {
-			teams := engine.ParseTeams(genome.Bytecode[teamDataOffset:])
-			state.InitializeTeams(teams)
-		}
-	}
-
-	for i := 0; i < cardsPerPlayer; i++ {
-		for p := 0; p < numPlayers; p++ {
-			state.DrawCard(uint8(p), engine.LocationDeck)
-		}
-	}
-
-	// Deal initial cards to discard/tableau
-	// For TableauMode games (Scopa), cards go to Tableau[0]
-	// For other games (Uno), cards go to Discard
-	if initialDiscardCount > 0 && len(state.Deck) >= initialDiscardCount {
-		// Initialize tableau pile if needed for TableauMode games
-		if state.TableauMode != 0 && len(state.Tableau) == 0 {
-			state.Tableau = make([][]engine.Card, 1)
-			state.Tableau[0] = make([]engine.Card, 0, initialDiscardCount)
-		}
-		for i := 0; i < initialDiscardCount; i++ {
-			if len(state.Deck) > 0 {
-				card := state.Deck[len(state.Deck)-1]
-				state.Deck = state.Deck[:len(state.Deck)-1]
-				if state.TableauMode != 0 {
-					// Scopa/MATCH_RANK/SEQUENCE: cards go to tableau[0]
-					state.Tableau[0] = append(state.Tableau[0], card)
-				} else {
-					// Uno-style: cards go to discard
-					state.Discard = append(state.Discard, card)
-				}
-			}
-		}
-	}
-
-	// Initialize chips if this genome uses betting
-	if startingChips > 0 {
-		state.InitializeChips(startingChips)
-	}
+	// Per-game random source for AI choices, so games are reproducible
+	// from seed and independent of other games running concurrently
+	rng := rand.New(rand.NewSource(int64(seed)))
 
 	// Initialize tension tracking
 	detector := engine.SelectLeaderDetector(genome)
 	tensionMetrics := engine.NewTensionMetrics(int(state.NumPlayers))
 
 	maxTurns := genome.Header.MaxTurns
+	movesBuf := make([]engine.LegalMove, 0, 16) // Reused by every turn
+	var cycles cycleDetector
 	for state.TurnNumber < maxTurns {
-		winner := engine.CheckWinConditions(state, genome)
-		if winner >= 0 {
+		// In match play a finished hand only ends the game once the
+		// cumulative target is reached; otherwise a new hand is dealt
+		// A condition can also end the game drawn (a chopped showdown)
+		var winner int8
+		over := false
+		if engine.HandComplete(state, genome) {
+			winner = resolveMatchHand(state, genome, setup, seed, &handsPlayed)
+			over = winner >= 0
+		} else {
+			outcome := engine.CheckGameOutcome(state, genome)
+			winner, over = outcome.Winner, outcome.Over()
+		}
+		if over {
 			tensionMetrics.Finalize(int(winner))
 			metrics.LeadChanges = uint32(tensionMetrics.LeadChanges)
 			metrics.DecisiveTurnPct = tensionMetrics.DecisiveTurnPct()
@@ -600,13 +546,13 @@ func RunSingleGameAsymmetric(genome *engine.Genome, p0AIType AIPlayerType, p1AIT
 			}
 		}
 
-		moves := engine.GenerateLegalMoves(state, genome)
+		moves := legalMoves(state, genome, &movesBuf)
 
 		// Check if this is a betting phase
 		if hasBettingPhase(moves) {
 			bettingPhase := getBettingPhaseData(genome)
 			if bettingPhase != nil {
-				err := runBettingRoundAsymmetric(state, genome, bettingPhase, p0AIType, p1AIType, &metrics)
+				err := runBettingRoundAsymmetric(state, genome, bettingPhase, p0AIType, p1AIType, &metrics, rng)
 				if err != "" {
 					tensionMetrics.Finalize(-1)
 					metrics.LeadChanges = uint32(tensionMetrics.LeadChanges)
@@ -632,10 +578,9 @@ func RunSingleGameAsymmetric(genome *engine.Genome, p0AIType AIPlayerType, p1AIT
 				if engine.IsBlackjackGame(genome) {
 					// Blackjack: just continue to draw phase
 					// Only resolve showdown if someone folded
-					winners := engine.ResolveShowdown(state)
-					if len(winners) == 1 {
+					if winner, ok := engine.OnlyOneActive(state); ok {
 						// Single winner (opponent folded)
-						engine.AwardPot(state, winners)
+						engine.AwardPot(state, bettingPhase, []int{int(winner)})
 						metrics.FoldWins++
 						state.ResetHand()
 					}
@@ -643,17 +588,25 @@ func RunSingleGameAsymmetric(genome *engine.Genome, p0AIType AIPlayerType, p1AIT
 					continue
 				}
 
+				// Staged deals (flop/turn/river): deal the next street and
+				// bet a fresh round on it before any showdown
+				if _, ok := engine.OnlyOneActive(state); !ok && engine.HasMoreStreets(state, genome.DealPattern) {
+					engine.NextBettingRound(state)
+					engine.Deal(state, genome.DealPattern, state.DealRound)
+					continue
+				}
+
 				// Poker-style: resolve showdown after betting
-				winners := engine.ResolveShowdown(state)
-				if len(winners) == 1 {
-					// Single winner (others folded)
-					engine.AwardPot(state, winners)
+				if winner, ok := engine.OnlyOneActive(state); ok {
+					// Everyone else folded - award the pot without a showdown
+					engine.AwardPot(state, bettingPhase, []int{int(winner)})
 					metrics.FoldWins++ // Track fold win
-				} else if len(winners) > 1 {
+				} else {
 					// Multiple players - use poker hand comparison
-					winner := engine.FindBestPokerWinner(state, int(state.NumPlayers))
-					if winner >= 0 {
-						engine.AwardPot(state, []int{int(winner)})
+					showdownWinners := engine.FindBestPokerWinner(state, int(state.NumPlayers))
+					if len(showdownWinners) > 0 {
+						// Ties split the pot
+						engine.AwardPot(state, bettingPhase, engine.PokerWinnerIDs(showdownWinners))
 						metrics.ShowdownWins++ // Track showdown win
 					}
 				}
@@ -666,7 +619,7 @@ func RunSingleGameAsymmetric(genome *engine.Genome, p0AIType AIPlayerType, p1AIT
 
 		// Check if this is a bidding phase
 		if hasBiddingMoves(moves) {
-			runBiddingRoundAsymmetric(state, genome, p0AIType, p1AIType)
+			runBiddingRoundAsymmetric(state, genome, p0AIType, p1AIType, rng)
 			continue // Skip normal move application, re-evaluate moves after bidding
 		}
 
@@ -707,6 +660,24 @@ func RunSingleGameAsymmetric(genome *engine.Genome, p0AIType AIPlayerType, p1AIT
 			}
 		}
 
+		// A position that recurs through forced moves alone loops forever,
+		// so call the game a draw now rather than at the turn limit
+		if cycles.repeated(state, len(moves)) {
+			tensionMetrics.Finalize(-1)
+			metrics.LeadChanges = uint32(tensionMetrics.LeadChanges)
+			metrics.DecisiveTurnPct = tensionMetrics.DecisiveTurnPct()
+			metrics.ClosestMargin = tensionMetrics.ClosestMargin
+			metrics.WinnerWasTrailing = tensionMetrics.WinnerWasTrailing
+			return GameResult{
+				WinnerID:    -1,
+				WinningTeam: -1,
+				TurnCount:   state.TurnNumber,
+				DurationNs:  uint64(time.Since(start).Nanoseconds()),
+				Looped:      true,
+				Metrics:     metrics,
+			}
+		}
+
 		metrics.TotalDecisions++
 		metrics.TotalValidMoves += uint64(len(moves))
 		metrics.TotalHandSize += uint64(len(state.Players[state.CurrentPlayer].Hand))
@@ -741,7 +712,7 @@ func RunSingleGameAsymmetric(genome *engine.Genome, p0AIType AIPlayerType, p1AIT
 		} else {
 			switch aiType {
 			case RandomAI:
-				move = &moves[rand.Intn(len(moves))]
+				move = &moves[rng.Intn(len(moves))]
 			case GreedyAI:
 				move = selectGreedyMove(state, genome, moves)
 			case MCTS100AI:
@@ -1018,6 +989,18 @@ func isContentionEvent(state *engine.GameState, move *engine.LegalMove, genome *
 	return false
 }
 
+// legalMoves returns the current player's legal moves, reusing *buf and
+// skipping the full generator when the move is forced. The result is only
+// valid until the next call with the same buffer.
+func legalMoves(state *engine.GameState, genome *engine.Genome, buf *[]engine.LegalMove) []engine.LegalMove {
+	if forced, ok := engine.SingleForcedMove(state, genome); ok {
+		*buf = append((*buf)[:0], *forced)
+	} else {
+		*buf = engine.GenerateLegalMovesInto(state, genome, (*buf)[:0])
+	}
+	return *buf
+}
+
 // getLegalMovesForPlayer generates legal moves for a specific player
 // without mutating the game state's CurrentPlayer field.
 func getLegalMovesForPlayer(state *engine.GameState, genome *engine.Genome, playerIdx int) []engine.LegalMove {
@@ -1029,17 +1012,89 @@ func getLegalMovesForPlayer(state *engine.GameState, genome *engine.Genome, play
 	return moves
 }
 
-// setupDeck creates and shuffles a standard 52-card deck
-func setupDeck(state *engine.GameState, seed uint64) {
-	// Create standard 52-card deck
-	for suit := uint8(0); suit < 4; suit++ {
-		for rank := uint8(0); rank < 13; rank++ {
-			state.Deck = append(state.Deck, engine.Card{Rank: rank, Suit: suit})
+// cycleDetector spots games stuck in a loop. While every move is forced
+// the game plays itself, so a position that comes back during a run of
+// forced moves will keep coming back until the turn limit; a move with a
+// real choice ends the run.
+type cycleDetector struct {
+	seen map[uint64]struct{} // engine.StateHash of each position in the run
+}
+
+// repeated records the position in state, which has numMoves legal moves,
+// and reports whether it already came up in the current forced run
+func (c *cycleDetector) repeated(state *engine.GameState, numMoves int) bool {
+	if numMoves != 1 {
+		clear(c.seen)
+		return false
+	}
+	if c.seen == nil {
+		c.seen = make(map[uint64]struct{})
+	}
+	hash := engine.StateHash(state)
+	if _, ok := c.seen[hash]; ok {
+		return true
+	}
+	c.seen[hash] = struct{}{}
+	return false
+}
+
+// recoverGame, deferred by the game runners, turns a panic while playing a
+// genome (a malformed phase indexing out of range, say) into a game that
+// ended with an error and the stack where it happened, so one bad genome
+// is counted in a batch's Errors rather than aborting the batch
+func recoverGame(result *GameResult, start time.Time) {
+	if r := recover(); r != nil {
+		*result = GameResult{
+			WinnerID:    -1,
+			WinningTeam: -1,
+			DurationNs:  uint64(time.Since(start).Nanoseconds()),
+			Error:       fmt.Sprintf("panic: %v", r),
+			Stack:       string(debug.Stack()),
 		}
 	}
+}
 
-	// Shuffle with seed
-	state.ShuffleDeck(seed)
+// redealHand gathers all cards, reshuffles a fresh deck and deals a new hand
+// started by the next player. Scores, chips and team totals carry over
+// between hands.
+func redealHand(state *engine.GameState, setup engine.SetupParams, seed uint64) {
+	for i := range state.Players {
+		state.Players[i].Hand = state.Players[i].Hand[:0]
+		state.Players[i].FaceUp = state.Players[i].FaceUp[:0]
+	}
+	state.Deck = state.Deck[:0]
+	state.Discard = state.Discard[:0]
+	state.Tableau = state.Tableau[:0]
+	state.Community = state.Community[:0]
+	state.Kitty = state.Kitty[:0]
+	state.KittyFaceUp = false
+	state.CurrentTrick = state.CurrentTrick[:0]
+	state.Committed = state.Committed[:0]
+	state.CommitPot = state.CommitPot[:0]
+	for i := range state.HasStood {
+		state.HasStood[i] = false
+	}
+	state.ConsecutivePasses = 0
+	state.CurrentClaim = nil
+
+	engine.BuildDeck(state, seed)
+	engine.DealHand(state, setup)
+	engine.RotateStartingPlayer(state)
+}
+
+// resolveMatchHand scores a completed hand in match play.
+// Returns the match winner if the cumulative target was reached; otherwise
+// deals the next hand (with a per-hand seed) and returns -1.
+func resolveMatchHand(state *engine.GameState, genome *engine.Genome, setup engine.SetupParams, seed uint64, handsPlayed *int) int8 {
+	engine.ScoreHand(state, genome)
+	if winner := engine.MatchComplete(state, genome); winner >= 0 {
+		return winner
+	}
+
+	*handsPlayed++
+	handSeed := seed + uint64(*handsPlayed)*0x9E3779B97F4A7C15
+	redealHand(state, setup, handSeed)
+	return -1
 }
 
 // selectGreedyMove picks the move that maximizes immediate score
@@ -1118,6 +1173,12 @@ func aggregateResults(results []GameResult) AggregatedStats {
 	for _, result := range results {
 		if result.Error != "" {
 			stats.Errors++
+			stats.ErrorDetails = append(stats.ErrorDetails, GameError{
+				Game:    result.Game,
+				Seed:    result.Seed,
+				Message: result.Error,
+				Stack:   result.Stack,
+			})
 			continue
 		}
 
@@ -1126,6 +1187,9 @@ func aggregateResults(results []GameResult) AggregatedStats {
 			stats.Wins[result.WinnerID]++
 		} else {
 			stats.Draws++
+			if result.Looped {
+				stats.LoopedGames++
+			}
 		}
 
 		// Track team wins
@@ -1197,6 +1261,11 @@ func aggregateResults(results []GameResult) AggregatedStats {
 		stats.AvgDurationNs = totalDuration / uint64(stats.TotalGames)
 	}
 
+	// Parallel batches collect results in completion order
+	sort.Slice(stats.ErrorDetails, func(i, j int) bool {
+		return stats.ErrorDetails[i].Game < stats.ErrorDetails[j].Game
+	})
+
 	// Set team wins if this was a team game
 	stats.TeamWins = teamWins
 
@@ -1253,6 +1322,9 @@ func hasBiddingMoves(moves []engine.LegalMove) bool {
 func getBettingPhaseData(genome *engine.Genome) *engine.BettingPhaseData {
 	for _, phase := range genome.TurnPhases {
 		if phase.PhaseType == 5 { // BettingPhase
+			if genome.Betting != nil {
+				return genome.Betting
+			}
 			data, _ := engine.ParseBettingPhaseData(phase.Data)
 			return data
 		}
@@ -1272,7 +1344,7 @@ func anyNeedsToAct(needsToAct []bool) bool {
 
 // runBettingRound executes a complete betting round
 // Returns error string if round fails, empty string on success
-func runBettingRound(state *engine.GameState, genome *engine.Genome, bettingPhase *engine.BettingPhaseData, aiType AIPlayerType, metrics *GameMetrics, tensionMetrics *engine.TensionMetrics, detector engine.LeaderDetector) string {
+func runBettingRound(state *engine.GameState, genome *engine.Genome, bettingPhase *engine.BettingPhaseData, aiType AIPlayerType, metrics *GameMetrics, tensionMetrics *engine.TensionMetrics, detector engine.LeaderDetector, rng *rand.Rand) string {
 	// Track who needs to act
 	needsToAct := make([]bool, state.NumPlayers)
 	for i := 0; i < int(state.NumPlayers); i++ {
@@ -1286,7 +1358,7 @@ func runBettingRound(state *engine.GameState, genome *engine.Genome, bettingPhas
 
 	for actionCount := 0; actionCount < maxActions; actionCount++ {
 		// Check termination: only one player remains
-		if engine.CountActivePlayers(state) <= 1 {
+		if _, ok := engine.OnlyOneActive(state); ok {
 			break
 		}
 
@@ -1335,7 +1407,7 @@ func runBettingRound(state *engine.GameState, genome *engine.Genome, bettingPhas
 			handStrength := engine.EvaluateHandStrength(state.Players[currentPlayer].Hand)
 			action = engine.SelectGreedyBettingAction(state, moves, handStrength)
 		default: // RandomAI and MCTS use random for betting
-			action = engine.SelectRandomBettingAction(moves, rand.Intn)
+			action = engine.SelectRandomBettingAction(moves, rng.Intn)
 		}
 
 		// Track betting metrics before applying action
@@ -1383,7 +1455,7 @@ func runBettingRound(state *engine.GameState, genome *engine.Genome, bettingPhas
 
 // runBettingRoundAsymmetric executes a complete betting round with different AI per player
 // Returns error string if round fails, empty string on success
-func runBettingRoundAsymmetric(state *engine.GameState, genome *engine.Genome, bettingPhase *engine.BettingPhaseData, p0AIType AIPlayerType, p1AIType AIPlayerType, metrics *GameMetrics) string {
+func runBettingRoundAsymmetric(state *engine.GameState, genome *engine.Genome, bettingPhase *engine.BettingPhaseData, p0AIType AIPlayerType, p1AIType AIPlayerType, metrics *GameMetrics, rng *rand.Rand) string {
 	// Track who needs to act
 	needsToAct := make([]bool, state.NumPlayers)
 	for i := 0; i < int(state.NumPlayers); i++ {
@@ -1397,7 +1469,7 @@ func runBettingRoundAsymmetric(state *engine.GameState, genome *engine.Genome, b
 
 	for actionCount := 0; actionCount < maxActions; actionCount++ {
 		// Check termination: only one player remains
-		if engine.CountActivePlayers(state) <= 1 {
+		if _, ok := engine.OnlyOneActive(state); ok {
 			break
 		}
 
@@ -1454,7 +1526,7 @@ func runBettingRoundAsymmetric(state *engine.GameState, genome *engine.Genome, b
 			handStrength := engine.EvaluateHandStrength(state.Players[currentPlayer].Hand)
 			action = engine.SelectGreedyBettingAction(state, moves, handStrength)
 		default: // RandomAI and MCTS use random for betting
-			action = engine.SelectRandomBettingAction(moves, rand.Intn)
+			action = engine.SelectRandomBettingAction(moves, rng.Intn)
 		}
 
 		// Track betting metrics before applying action
@@ -1565,7 +1637,7 @@ func selectGreedyBid(state *engine.GameState, biddingPhase engine.BiddingPhase,
 }
 
 // runBiddingRound executes a complete bidding round for all players
-func runBiddingRound(state *engine.GameState, genome *engine.Genome, aiTypes []AIPlayerType) {
+func runBiddingRound(state *engine.GameState, genome *engine.Genome, aiTypes []AIPlayerType, rng *rand.Rand) {
 	biddingData := getBiddingPhaseData(genome)
 	if biddingData == nil {
 		return
@@ -1597,7 +1669,7 @@ func runBiddingRound(state *engine.GameState, genome *engine.Genome, aiTypes []A
 			handSize := len(state.Players[playerIdx].Hand)
 			bidMoves := engine.GenerateBidMoves(biddingPhase, handSize)
 			if len(bidMoves) > 0 {
-				bid = bidMoves[rand.Intn(len(bidMoves))]
+				bid = bidMoves[rng.Intn(len(bidMoves))]
 			} else {
 				bid = engine.BidMove{Value: 1, IsNil: false}
 			}
@@ -1609,7 +1681,7 @@ func runBiddingRound(state *engine.GameState, genome *engine.Genome, aiTypes []A
 }
 
 // runBiddingRoundAsymmetric executes a complete bidding round with different AI per player (for skill evaluation)
-func runBiddingRoundAsymmetric(state *engine.GameState, genome *engine.Genome, p0AIType AIPlayerType, p1AIType AIPlayerType) {
+func runBiddingRoundAsymmetric(state *engine.GameState, genome *engine.Genome, p0AIType AIPlayerType, p1AIType AIPlayerType, rng *rand.Rand) {
 	biddingData := getBiddingPhaseData(genome)
 	if biddingData == nil {
 		return
@@ -1648,7 +1720,7 @@ func runBiddingRoundAsymmetric(state *engine.GameState, genome *engine.Genome, p
 			handSize := len(state.Players[playerIdx].Hand)
 			bidMoves := engine.GenerateBidMoves(biddingPhase, handSize)
 			if len(bidMoves) > 0 {
-				bid = bidMoves[rand.Intn(len(bidMoves))]
+				bid = bidMoves[rng.Intn(len(bidMoves))]
 			} else {
 				bid = engine.BidMove{Value: 1, IsNil: false}
 			}
@@ -1,8 +1,11 @@
 package simulation
 
 import (
+	"encoding/binary"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/signalnine/darwindeck/gosim/engine"
@@ -88,6 +91,7 @@ func BenchmarkRunSingleGame(b *testing.B) {
 		b.Fatalf("Failed to parse genome: %v", err)
 	}
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		RunSingleGame(genome, RandomAI, 0, uint64(i))
@@ -519,3 +523,293 @@ func makeV2BytecodeWithTableauMode(tableauMode uint8, seqDir uint8) []byte {
 
 	return bytecode[:82]
 }
+
+// TestRunSingleGameMatchPlay verifies that a shedding game played as a match
+// re-deals after each hand and only ends once the cumulative target is reached.
+func TestRunSingleGameMatchPlay(t *testing.T) {
+	genome := &engine.Genome{
+		Header: &engine.BytecodeHeader{
+			PlayerCount: 2,
+			MaxTurns:    2000,
+		},
+		TurnPhases: []engine.PhaseDescriptor{
+			{
+				PhaseType: engine.PhaseTypePlay,
+				Data:      []byte{byte(engine.LocationDiscard), 1, 1, 1, 0, 0, 0, 0, 0},
+			},
+		},
+		WinConditions: []engine.WinCondition{
+			{WinType: engine.WinTypeEmptyHand, Threshold: 0},
+			{WinType: engine.WinTypeFirstToScore, Threshold: 3},
+		},
+		MatchPlay: true,
+	}
+
+	result := RunSingleGame(genome, RandomAI, 0, 42)
+	if result.Error != "" {
+		t.Fatalf("Game failed: %s", result.Error)
+	}
+
+	// 26 cards each: whoever leads a hand goes out first with the opponent
+	// holding one card, so each hand is worth 1 point and takes 51 turns.
+	// Turn order carries over, so the lead alternates and player 0 wins
+	// hands 1, 3 and 5.
+	if result.WinnerID != 0 {
+		t.Errorf("Expected player 0 to win the match, got %d", result.WinnerID)
+	}
+	if result.TurnCount != 5*51 {
+		t.Errorf("Expected 5 hands (%d turns), got %d", 5*51, result.TurnCount)
+	}
+}
+
+// sheddingBytecode compiles TestRunSingleGameMatchPlay's genome by hand: two
+// players with 26 cards each play one card a turn to the discard, under
+// empty_hand and first_to_score 3
+func sheddingBytecode() []byte {
+	const setupAt, turnsAt, winsAt = 39, 51, 65
+	bytecode := make([]byte, 39, 80)
+	bytecode[0] = 2                                   // Version 2
+	binary.BigEndian.PutUint32(bytecode[13:17], 2)    // players
+	binary.BigEndian.PutUint32(bytecode[17:21], 2000) // max turns
+	binary.BigEndian.PutUint32(bytecode[21:25], setupAt)
+	binary.BigEndian.PutUint32(bytecode[25:29], turnsAt)
+	binary.BigEndian.PutUint32(bytecode[29:33], winsAt)
+
+	// Setup: 26 cards each, no discard, no chips
+	bytecode = binary.BigEndian.AppendUint32(bytecode, 26)
+	bytecode = append(bytecode, make([]byte, 8)...)
+	// One play phase: target, min, max, mandatory, pass_if_unable, no condition
+	bytecode = binary.BigEndian.AppendUint32(bytecode, 1)
+	bytecode = append(bytecode, engine.PhaseTypePlay, byte(engine.LocationDiscard), 1, 1, 1, 0, 0, 0, 0, 0)
+	// Win conditions
+	bytecode = binary.BigEndian.AppendUint32(bytecode, 2)
+	bytecode = binary.BigEndian.AppendUint32(append(bytecode, engine.WinTypeEmptyHand), 0)
+	bytecode = binary.BigEndian.AppendUint32(append(bytecode, engine.WinTypeFirstToScore), 3)
+	// Scoring starts (empty) at the end
+	binary.BigEndian.PutUint32(bytecode[33:37], uint32(len(bytecode)))
+	return bytecode
+}
+
+func TestRunSingleGameMatchPlayFromBytecode(t *testing.T) {
+	single, err := engine.ParseGenome(sheddingBytecode())
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	withOption, err := engine.WithSetupOptions(sheddingBytecode(), &engine.Genome{MatchPlay: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	match, err := engine.ParseGenome(withOption)
+	if err != nil {
+		t.Fatalf("Failed to parse with setup options: %v", err)
+	}
+
+	// Plays out like the hand-built match genome
+	result := RunSingleGame(match, RandomAI, 0, 42)
+	if result.Error != "" || result.WinnerID != 0 || result.TurnCount != 5*51 {
+		t.Errorf("Expected player 0 to win the match in 5 hands, got %+v", result)
+	}
+
+	// Without the option, going out wins the first hand
+	result = RunSingleGame(single, RandomAI, 0, 42)
+	if result.Error != "" || result.WinnerID != 0 || result.TurnCount != 51 {
+		t.Errorf("Expected player 0 to win the only hand, got %+v", result)
+	}
+}
+
+func TestRunSingleGameChoppedShowdownIsDraw(t *testing.T) {
+	// Five cards each, and a turn that leaves hands alone, so best_hand
+	// compares the deal once both players have had two turns
+	genome := &engine.Genome{
+		Header:      &engine.BytecodeHeader{PlayerCount: 2, MaxTurns: 100},
+		DealPattern: &engine.DealPattern{Stages: []engine.DealStage{{FaceDown: 5}}},
+		TurnPhases: []engine.PhaseDescriptor{
+			{PhaseType: engine.PhaseTypeAction, Data: []byte{1, byte(engine.OpReverseOrder), 0}},
+		},
+		WinConditions: []engine.WinCondition{{WinType: engine.WinTypeBestHand}},
+	}
+
+	// First deal that chops, and one with a single best hand
+	chop, single := uint64(0), uint64(0)
+	for seed := uint64(1); seed < 20000 && (chop == 0 || single == 0); seed++ {
+		state := engine.SetupGame(genome, seed)
+		switch len(engine.FindBestPokerWinner(state, 2)) {
+		case 1:
+			if single == 0 {
+				single = seed
+			}
+		case 2:
+			if chop == 0 {
+				chop = seed
+			}
+		}
+		engine.PutState(state)
+	}
+	if chop == 0 {
+		t.Fatal("Expected a chopped deal among the seeds")
+	}
+
+	for _, ai := range []AIPlayerType{RandomAI, MCTS100AI} {
+		result := RunSingleGame(genome, ai, 0, chop)
+		if result.Error != "" || result.WinnerID != -1 || result.TurnCount != 4 {
+			t.Errorf("AI %v: expected the chop drawn at turn 4, got %+v", ai, result)
+		}
+	}
+	if result := RunSingleGame(genome, RandomAI, 0, single); result.WinnerID < 0 || result.TurnCount != 4 {
+		t.Errorf("Expected the best hand to win at turn 4, got %+v", result)
+	}
+}
+
+func TestRunSingleGameReproducibleWithSeed(t *testing.T) {
+	for _, name := range []string{"war_genome.bin", "simple_poker_genome.bin"} {
+		bytecode, err := os.ReadFile(filepath.Join("..", "..", "..", "tests", "golden", name))
+		if err != nil {
+			t.Fatalf("Failed to read golden file: %v", err)
+		}
+		genome, err := engine.ParseGenome(bytecode)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", name, err)
+		}
+
+		first := RunSingleGame(genome, RandomAI, 0, 777)
+		second := RunSingleGame(genome, RandomAI, 0, 777)
+		first.DurationNs, second.DurationNs = 0, 0
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("%s: results differ for the same seed\nfirst:  %+v\nsecond: %+v", name, first, second)
+		}
+	}
+}
+
+func TestAggregateResultsCountsAndErrors(t *testing.T) {
+	// Out of order, as parallel batches deliver them
+	results := []GameResult{
+		{Game: 3, Seed: 30, WinnerID: 1, TurnCount: 40},
+		{Game: 4, Seed: 40, Error: "AI returned nil move"},
+		{Game: 0, Seed: 10, WinnerID: 0, TurnCount: 10},
+		{Game: 1, Seed: 11, Error: "no legal moves"},
+		{Game: 2, Seed: 20, WinnerID: -1, TurnCount: 20},
+		{Game: 5, Seed: 50, WinnerID: 0, TurnCount: 30},
+	}
+
+	stats := aggregateResults(results)
+
+	if stats.Wins[0] != 2 || stats.Wins[1] != 1 || stats.Draws != 1 || stats.Errors != 2 {
+		t.Errorf("Unexpected counts: wins=%v draws=%d errors=%d", stats.Wins, stats.Draws, stats.Errors)
+	}
+	sum := stats.Draws + stats.Errors
+	for _, w := range stats.Wins {
+		sum += w
+	}
+	if sum != stats.TotalGames {
+		t.Errorf("Wins+draws+errors = %d, expected %d", sum, stats.TotalGames)
+	}
+	if stats.AvgTurns != 25 || stats.MedianTurns != 25 {
+		t.Errorf("Expected avg and median of 25 turns, got %.1f and %d", stats.AvgTurns, stats.MedianTurns)
+	}
+
+	want := []GameError{
+		{Game: 1, Seed: 11, Message: "no legal moves"},
+		{Game: 4, Seed: 40, Message: "AI returned nil move"},
+	}
+	if !reflect.DeepEqual(stats.ErrorDetails, want) {
+		t.Errorf("Expected error details %+v, got %+v", want, stats.ErrorDetails)
+	}
+}
+
+func TestRunBatchCapturesErrors(t *testing.T) {
+	// Without turn phases no player ever has a legal move
+	genome := &engine.Genome{
+		Header: &engine.BytecodeHeader{MaxTurns: 100, PlayerCount: 2},
+	}
+
+	stats := RunBatch(genome, 3, RandomAI, 0, 99)
+
+	if stats.Errors != 3 || len(stats.ErrorDetails) != 3 {
+		t.Fatalf("Expected 3 errors with details, got %d and %+v", stats.Errors, stats.ErrorDetails)
+	}
+	for i, e := range stats.ErrorDetails {
+		if e.Game != i || e.Message == "" {
+			t.Errorf("Unexpected error detail %d: %+v", i, e)
+		}
+		if replay := RunSingleGame(genome, RandomAI, 0, e.Seed); replay.Error != e.Message {
+			t.Errorf("Replaying seed %d gave %q, expected %q", e.Seed, replay.Error, e.Message)
+		}
+	}
+}
+
+func TestCycleDetectorCallsLoopingWarADraw(t *testing.T) {
+	bytecode, err := os.ReadFile(filepath.Join("..", "..", "..", "tests", "golden", "war_genome.bin"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	genome, err := engine.ParseGenome(bytecode)
+	if err != nil {
+		t.Fatalf("Failed to parse genome: %v", err)
+	}
+
+	// Piles play from the top (last card): K beats 2 one way, then the
+	// other, and the won pairs go back under in the order they started
+	state := engine.NewGameState(2)
+	defer engine.PutState(state)
+	state.TableauMode = 1
+	state.WarToBottom = true
+	state.Tableau = append(state.Tableau, nil)
+	state.Players[0].Hand = []engine.Card{{Rank: 11, Suit: 0}, {Rank: 0, Suit: 1}} // KH 2D
+	state.Players[1].Hand = []engine.Card{{Rank: 0, Suit: 2}, {Rank: 11, Suit: 3}} // 2C KS
+
+	var cycles cycleDetector
+	for state.TurnNumber < 1000 {
+		if winner := engine.CheckWinConditions(state, genome); winner >= 0 {
+			t.Fatalf("Expected the position to cycle, but player %d won at turn %d", winner, state.TurnNumber)
+		}
+		moves := engine.GenerateLegalMoves(state, genome)
+		if cycles.repeated(state, len(moves)) {
+			return
+		}
+		engine.ApplyMove(state, &moves[0], genome)
+	}
+	t.Fatal("Expected the loop to be detected before turn 1000")
+}
+
+func TestRunSingleGameDrawsLoopedGame(t *testing.T) {
+	bytecode, err := os.ReadFile(filepath.Join("..", "..", "..", "tests", "golden", "war_genome.bin"))
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	genome, err := engine.ParseGenome(bytecode)
+	if err != nil {
+		t.Fatalf("Failed to parse genome: %v", err)
+	}
+	genome.WarToBottom = true
+
+	// This deal falls into a loop around turn 300
+	result := RunSingleGame(genome, RandomAI, 0, 166)
+	if !result.Looped || result.WinnerID != -1 || result.TurnCount >= genome.Header.MaxTurns {
+		t.Fatalf("Expected an early draw by repetition, got %+v", result)
+	}
+
+	stats := aggregateResults([]GameResult{result, {WinnerID: -1, TurnCount: genome.Header.MaxTurns}})
+	if stats.Draws != 2 || stats.LoopedGames != 1 {
+		t.Errorf("Expected 2 draws, 1 of them looped, got %d and %d", stats.Draws, stats.LoopedGames)
+	}
+}
+
+func TestRunBatchCountsPanicsAsErrors(t *testing.T) {
+	// Malformed phase data is caught by the move generator, but a genome
+	// missing its header panics the engine
+	genome := &engine.Genome{TurnPhases: []engine.PhaseDescriptor{{PhaseType: engine.PhaseTypePlay}}}
+
+	stats := RunBatch(genome, 5, RandomAI, 0, 1)
+	if stats.Errors != 5 || len(stats.ErrorDetails) != 5 {
+		t.Fatalf("Expected all 5 games counted as errors, got %d", stats.Errors)
+	}
+	first := stats.ErrorDetails[0]
+	if !strings.HasPrefix(first.Message, "panic: ") || !strings.Contains(first.Stack, "engine.") {
+		t.Errorf("Expected a panic message and engine stack, got %q\n%s", first.Message, first.Stack)
+	}
+
+	// The replayed game fails the same way on its own
+	if result := RunSingleGame(genome, RandomAI, 0, first.Seed); result.Error != first.Message || result.WinnerID != -1 {
+		t.Errorf("Expected seed %d to panic again, got %+v", first.Seed, result)
+	}
+}
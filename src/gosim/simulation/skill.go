@@ -0,0 +1,54 @@
+package simulation
+
+import "github.com/signalnine/darwindeck/gosim/engine"
+
+// SkillResult is how much a strong AI beats a weak one at a genome,
+// measured by SkillSensitivity
+type SkillResult struct {
+	Games         int     // Games finished without an error
+	StrongWinRate float64 // Share of games with a winner that the MCTS player won
+	DrawRate      float64 // Share of games without a winner
+	// LuckBaseline is the strong player's win rate if skill made no
+	// difference: one game in NumPlayers
+	LuckBaseline float64
+	// Skill scales StrongWinRate from 0 at the baseline to 1 at always
+	// winning (negative if the weak AI does better, 0 if no game had a winner)
+	Skill float64
+}
+
+// SkillSensitivity plays n games of genome from seed between MCTS (100
+// iterations) and the random AI and reports how often MCTS wins the games
+// that have a winner. Near the luck baseline (50% heads-up) the game is all
+// luck; near 100% it is all skill. MCTS sits at seat 0 against random
+// everywhere else; heads-up, the seats swap for the second half of the
+// games so an advantage for moving first doesn't count as skill.
+func SkillSensitivity(genome *engine.Genome, n int, seed uint64) SkillResult {
+	numPlayers := engine.ReadSetupParams(genome).NumPlayers
+	result := SkillResult{LuckBaseline: 1 / float64(numPlayers)}
+
+	strongFirst := n
+	if numPlayers == 2 {
+		strongFirst = (n + 1) / 2
+	}
+	stats := RunBatchAsymmetric(genome, strongFirst, MCTS100AI, RandomAI, 0, seed)
+	strongWins := stats.Wins[0]
+	finished := stats.TotalGames - stats.Errors
+	draws := stats.Draws
+	if strongFirst < n {
+		swapped := RunBatchAsymmetric(genome, n-strongFirst, RandomAI, MCTS100AI, 0, seed+1)
+		strongWins += swapped.Wins[1]
+		finished += swapped.TotalGames - swapped.Errors
+		draws += swapped.Draws
+	}
+
+	result.Games = int(finished)
+	if finished == 0 {
+		return result
+	}
+	result.DrawRate = float64(draws) / float64(finished)
+	if decided := finished - draws; decided > 0 {
+		result.StrongWinRate = float64(strongWins) / float64(decided)
+		result.Skill = (result.StrongWinRate - result.LuckBaseline) / (1 - result.LuckBaseline)
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package simulation
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/signalnine/darwindeck/gosim/engine"
+)
+
+// luckyWarGenome returns War played from piles of 4 cards until a pile
+// runs out: every move is forced, so the deal decides the game
+func luckyWarGenome(t *testing.T) *engine.Genome {
+	t.Helper()
+	genome := loadGoldenGenome(t, "war_genome.bin")
+	genome.WarToBottom = true
+	binary.BigEndian.PutUint32(genome.Bytecode[genome.Header.SetupOffset:], 4)
+	genome.WinConditions = []engine.WinCondition{{WinType: engine.WinTypeEmptyHand}}
+	return genome
+}
+
+func TestSkillSensitivityWar(t *testing.T) {
+	result := SkillSensitivity(luckyWarGenome(t), 100, 5)
+	if result.Games != 100 || result.LuckBaseline != 0.5 {
+		t.Fatalf("Expected 100 heads-up games, got %+v", result)
+	}
+	if result.StrongWinRate < 0.35 || result.StrongWinRate > 0.65 {
+		t.Errorf("Expected War near the 50%% luck baseline, got %+v", result)
+	}
+}
+
+func TestSkillSensitivityHearts(t *testing.T) {
+	result := SkillSensitivity(loadGoldenGenome(t, "hearts_genome.bin"), 20, 7)
+	if result.Games != 20 || result.LuckBaseline != 0.25 {
+		t.Fatalf("Expected 20 four-player games, got %+v", result)
+	}
+	luck := SkillSensitivity(luckyWarGenome(t), 100, 5)
+	if result.Skill < luck.Skill+0.2 {
+		t.Errorf("Expected hearts to reward skill well beyond War, got %v against %v", result.Skill, luck.Skill)
+	}
+}
@@ -27,6 +27,7 @@ func RunBatchTyped(g *genome.GameGenome, numGames int, aiType AIPlayerType, mcts
 	for i := 0; i < numGames; i++ {
 		gameSeed := rng.Uint64()
 		results[i] = RunSingleGameTyped(g, aiType, mctsIterations, gameSeed)
+		results[i].Game, results[i].Seed = i, gameSeed
 	}
 
 	return aggregateResults(results)
@@ -90,6 +91,7 @@ func typedWorker(wg *sync.WaitGroup, jobs <-chan TypedGameJob, results chan<- Ga
 
 	for job := range jobs {
 		result := RunSingleGameTyped(g, aiType, mctsIterations, job.Seed)
+		result.Game, result.Seed = job.SimID, job.Seed
 		results <- result
 	}
 }
@@ -98,8 +100,10 @@ func typedWorker(wg *sync.WaitGroup, jobs <-chan TypedGameJob, results chan<- Ga
 const GameTimeout = 100 * time.Millisecond
 
 // RunSingleGameTyped plays one complete game using a typed genome.
-func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIterations int, seed uint64) GameResult {
+// A panic in the engine ends the game with an error (see recoverGame).
+func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIterations int, seed uint64) (result GameResult) {
 	start := time.Now()
+	defer recoverGame(&result, start)
 	var metrics GameMetrics
 
 	// Initialize game state
@@ -107,7 +111,10 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 	defer engine.PutState(state)
 
 	// Setup deck and shuffle
-	setupDeck(state, seed)
+	engine.BuildDeck(state, seed)
+
+	// Per-game random source for AI choices (see RunSingleGame)
+	rng := rand.New(rand.NewSource(int64(seed)))
 
 	// Read setup from typed genome
 	cardsPerPlayer := g.Setup.CardsPerPlayer
@@ -127,6 +134,10 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 	// Set tableau mode from typed genome
 	state.TableauMode = uint8(g.TurnStructure.TableauMode)
 	state.SequenceDirection = uint8(g.TurnStructure.SequenceDirection)
+	state.AceLow = g.Setup.AceLow
+	state.WarToBottom = g.Setup.WarToBottom
+	state.PerfectInformation = g.Setup.PerfectInformation
+	state.SuitOrder = g.Setup.SuitOrder
 
 	// Initialize teams if configured
 	if g.Teams != nil && g.Teams.Enabled && len(g.Teams.Teams) > 0 {
@@ -145,6 +156,8 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 		}
 	}
 
+	engine.DealKitty(state, g.Setup.KittySize, g.Setup.KittyFaceUp)
+
 	// Deal initial cards to discard/tableau
 	if initialDiscardCount > 0 && len(state.Deck) >= initialDiscardCount {
 		// Initialize tableau pile if needed for TableauMode games
@@ -167,7 +180,14 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 
 	// Initialize chips if this genome uses betting
 	if startingChips > 0 {
-		state.InitializeChips(startingChips)
+		state.InitializeChips(int64(startingChips))
+	}
+
+	if g.Setup.StartingPlayer > 0 && g.Setup.StartingPlayer < numPlayers {
+		state.StartingPlayer = uint8(g.Setup.StartingPlayer)
+		state.CurrentPlayer = state.StartingPlayer
+		state.TrickLeader = state.StartingPlayer
+		state.BettingStartPlayer = g.Setup.StartingPlayer
 	}
 
 	// Create bytecode genome for compatibility with existing win condition checks
@@ -183,6 +203,7 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 	if maxTurns == 0 {
 		maxTurns = 1000 // Default
 	}
+	var cycles cycleDetector
 
 	for state.TurnNumber < maxTurns {
 		// Check timeout to prevent infinite loops from bad genomes
@@ -222,7 +243,7 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 		if hasBettingMoves(moves) {
 			bettingPhase := findBettingPhase(g)
 			if bettingPhase != nil {
-				err := runBettingRoundTyped(state, g, bettingPhase, aiType, &metrics, tensionMetrics, detector)
+				err := runBettingRoundTyped(state, g, bettingPhase, aiType, &metrics, tensionMetrics, detector, rng)
 				if err != "" {
 					tensionMetrics.Finalize(-1)
 					metrics.LeadChanges = uint32(tensionMetrics.LeadChanges)
@@ -242,14 +263,15 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 				state.BettingComplete = true
 
 				// Resolve showdown after betting
-				winners := engine.ResolveShowdown(state)
-				if len(winners) == 1 {
-					engine.AwardPot(state, winners)
+				if winner, ok := engine.OnlyOneActive(state); ok {
+					// Everyone else folded - award the pot without a showdown
+					engine.AwardPot(state, engineBettingData(bettingPhase), []int{int(winner)})
 					metrics.FoldWins++
-				} else if len(winners) > 1 {
-					winner := engine.FindBestPokerWinner(state, int(state.NumPlayers))
-					if winner >= 0 {
-						engine.AwardPot(state, []int{int(winner)})
+				} else {
+					showdownWinners := engine.FindBestPokerWinner(state, int(state.NumPlayers))
+					if len(showdownWinners) > 0 {
+						// Ties split the pot
+						engine.AwardPot(state, engineBettingData(bettingPhase), engine.PokerWinnerIDs(showdownWinners))
 						metrics.ShowdownWins++
 					}
 				}
@@ -265,7 +287,7 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 			for i := range aiTypes {
 				aiTypes[i] = aiType
 			}
-			runBiddingRoundTyped(state, g, aiTypes)
+			runBiddingRoundTyped(state, g, aiTypes, rng)
 			continue
 		}
 
@@ -285,6 +307,24 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 			}
 		}
 
+		// A position that recurs through forced moves alone loops forever,
+		// so call the game a draw now rather than at the turn limit
+		if cycles.repeated(state, len(moves)) {
+			tensionMetrics.Finalize(-1)
+			metrics.LeadChanges = uint32(tensionMetrics.LeadChanges)
+			metrics.DecisiveTurnPct = tensionMetrics.DecisiveTurnPct()
+			metrics.ClosestMargin = tensionMetrics.ClosestMargin
+			metrics.WinnerWasTrailing = tensionMetrics.WinnerWasTrailing
+			return GameResult{
+				WinnerID:    -1,
+				WinningTeam: -1,
+				TurnCount:   state.TurnNumber,
+				DurationNs:  uint64(time.Since(start).Nanoseconds()),
+				Looped:      true,
+				Metrics:     metrics,
+			}
+		}
+
 		// Phase 1 instrumentation
 		metrics.TotalDecisions++
 		metrics.TotalValidMoves += uint64(len(moves))
@@ -301,7 +341,7 @@ func RunSingleGameTyped(g *genome.GameGenome, aiType AIPlayerType, mctsIteration
 		} else {
 			switch aiType {
 			case RandomAI:
-				move = &moves[rand.Intn(len(moves))]
+				move = &moves[rng.Intn(len(moves))]
 			case GreedyAI:
 				move = selectGreedyMoveTyped(state, g, moves)
 			case MCTS100AI, MCTS500AI, MCTS1000AI, MCTS2000AI:
@@ -463,10 +503,10 @@ func checkWinConditionsTyped(state *engine.GameState, g *genome.GameGenome) int8
 			continue
 
 		case genome.WinTypeFirstToScore:
-			// Same as high score
-			for i := 0; i < int(state.NumPlayers); i++ {
-				if state.Players[i].Score >= wc.Threshold {
-					return int8(i)
+			// Evaluated at hand boundaries; the sign of Threshold picks the variant
+			if typedHandBoundary(state, g) {
+				if winner := engine.FirstToScoreWinner(state, wc.Threshold); winner >= 0 {
+					return winner
 				}
 			}
 		}
@@ -475,6 +515,18 @@ func checkWinConditionsTyped(state *engine.GameState, g *genome.GameGenome) int8
 	return -1 // No winner yet
 }
 
+// typedHandBoundary is engine.HandBoundary for a typed genome, which has no
+// match play: trick games are between hands once every trick is played, and
+// any other game at every turn
+func typedHandBoundary(state *engine.GameState, g *genome.GameGenome) bool {
+	for _, phase := range g.TurnStructure.Phases {
+		if _, ok := phase.(*genome.TrickPhase); ok {
+			return engine.AllHandsEmpty(state)
+		}
+	}
+	return !g.TurnStructure.IsTrickBased || engine.AllHandsEmpty(state)
+}
+
 // findBettingPhase returns the first BettingPhase in the genome, or nil.
 func findBettingPhase(g *genome.GameGenome) *genome.BettingPhase {
 	for _, phase := range g.TurnStructure.Phases {
@@ -505,13 +557,20 @@ func hasBettingMoves(moves []engine.LegalMove) bool {
 	return false
 }
 
+// engineBettingData converts a typed betting phase to the engine's form
+func engineBettingData(p *genome.BettingPhase) *engine.BettingPhaseData {
+	return &engine.BettingPhaseData{
+		MinBet:      p.MinBet,
+		MaxRaises:   p.MaxRaises,
+		RakePercent: p.RakePercent,
+		RakeCap:     p.RakeCap,
+	}
+}
+
 // runBettingRoundTyped executes a betting round using typed genome.
-func runBettingRoundTyped(state *engine.GameState, g *genome.GameGenome, bettingPhase *genome.BettingPhase, aiType AIPlayerType, metrics *GameMetrics, tensionMetrics *engine.TensionMetrics, detector engine.LeaderDetector) string {
+func runBettingRoundTyped(state *engine.GameState, g *genome.GameGenome, bettingPhase *genome.BettingPhase, aiType AIPlayerType, metrics *GameMetrics, tensionMetrics *engine.TensionMetrics, detector engine.LeaderDetector, rng *rand.Rand) string {
 	// Convert to engine type for compatibility
-	engineBettingPhase := &engine.BettingPhaseData{
-		MinBet:    bettingPhase.MinBet,
-		MaxRaises: bettingPhase.MaxRaises,
-	}
+	engineBettingPhase := engineBettingData(bettingPhase)
 
 	// Track who needs to act
 	needsToAct := make([]bool, state.NumPlayers)
@@ -524,7 +583,7 @@ func runBettingRoundTyped(state *engine.GameState, g *genome.GameGenome, betting
 	maxActions := int(state.NumPlayers) * (bettingPhase.MaxRaises + 2) * 2
 
 	for actionCount := 0; actionCount < maxActions; actionCount++ {
-		if engine.CountActivePlayers(state) <= 1 {
+		if _, ok := engine.OnlyOneActive(state); ok {
 			break
 		}
 		if engine.CountActingPlayers(state) == 0 {
@@ -564,7 +623,7 @@ func runBettingRoundTyped(state *engine.GameState, g *genome.GameGenome, betting
 			handStrength := engine.EvaluateHandStrength(state.Players[currentPlayer].Hand)
 			action = engine.SelectGreedyBettingAction(state, moves, handStrength)
 		default:
-			action = engine.SelectRandomBettingAction(moves, rand.Intn)
+			action = engine.SelectRandomBettingAction(moves, rng.Intn)
 		}
 
 		handStrength := engine.EvaluateHandStrength(state.Players[currentPlayer].Hand)
@@ -605,7 +664,7 @@ func runBettingRoundTyped(state *engine.GameState, g *genome.GameGenome, betting
 }
 
 // runBiddingRoundTyped executes a bidding round using typed genome.
-func runBiddingRoundTyped(state *engine.GameState, g *genome.GameGenome, aiTypes []AIPlayerType) {
+func runBiddingRoundTyped(state *engine.GameState, g *genome.GameGenome, aiTypes []AIPlayerType, rng *rand.Rand) {
 	biddingPhase := findBiddingPhase(g)
 	if biddingPhase == nil {
 		return
@@ -638,7 +697,7 @@ func runBiddingRoundTyped(state *engine.GameState, g *genome.GameGenome, aiTypes
 			handSize := len(state.Players[playerIdx].Hand)
 			bidMoves := engine.GenerateBidMoves(engineBiddingPhase, handSize)
 			if len(bidMoves) > 0 {
-				bid = bidMoves[rand.Intn(len(bidMoves))]
+				bid = bidMoves[rng.Intn(len(bidMoves))]
 			} else {
 				bid = engine.BidMove{Value: 1, IsNil: false}
 			}
@@ -711,9 +770,19 @@ func createCompatGenome(g *genome.GameGenome) *engine.Genome {
 			SequenceDirection: uint8(g.TurnStructure.SequenceDirection),
 			PlayerCount:       2, // Default
 		},
-		TurnPhases:    make([]engine.PhaseDescriptor, len(g.TurnStructure.Phases)),
-		WinConditions: make([]engine.WinCondition, len(g.WinConditions)),
-		Effects:       make(map[uint8]engine.SpecialEffect),
+		TurnPhases:         make([]engine.PhaseDescriptor, len(g.TurnStructure.Phases)),
+		WinConditions:      make([]engine.WinCondition, len(g.WinConditions)),
+		Effects:            make(map[uint8]engine.SpecialEffect),
+		RankValues:         g.RankValues,
+		StartingPlayer:     g.Setup.StartingPlayer,
+		MaxHandSize:        g.Setup.MaxHandSize,
+		MatchPlay:          g.Setup.MatchPlay,
+		AceLow:             g.Setup.AceLow,
+		SuitOrder:          g.Setup.SuitOrder,
+		KittySize:          g.Setup.KittySize,
+		KittyFaceUp:        g.Setup.KittyFaceUp,
+		PerfectInformation: g.Setup.PerfectInformation,
+		WarToBottom:        g.Setup.WarToBottom,
 	}
 
 	// Convert phases to descriptors
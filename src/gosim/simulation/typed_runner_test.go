@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/signalnine/darwindeck/gosim/engine"
 	"github.com/signalnine/darwindeck/gosim/genome"
 )
 
@@ -282,3 +283,39 @@ func TestRunBatchTypedParallelSpeedup(t *testing.T) {
 		t.Logf("Warning: Parallel speedup is low (%.2fx), expected at least 1.5x on multi-core", speedup)
 	}
 }
+
+// TestCompatGenomeCarriesSetup checks that setup settings serialized with a
+// typed genome reach the engine genome the typed runner plays with
+func TestCompatGenomeCarriesSetup(t *testing.T) {
+	tests := []struct {
+		name  string
+		set   func(s *genome.SetupRules)
+		check func(g *engine.Genome) bool
+	}{
+		{"match play", func(s *genome.SetupRules) { s.MatchPlay = true }, func(g *engine.Genome) bool { return g.MatchPlay }},
+		{"ace low", func(s *genome.SetupRules) { s.AceLow = true }, func(g *engine.Genome) bool { return g.AceLow }},
+		{"suit order", func(s *genome.SetupRules) { s.SuitOrder = [4]uint8{1, 2, 3, 4} }, func(g *engine.Genome) bool { return g.SuitOrder == [4]uint8{1, 2, 3, 4} }},
+		{"max hand size", func(s *genome.SetupRules) { s.MaxHandSize = 7 }, func(g *engine.Genome) bool { return g.MaxHandSize == 7 }},
+		{"widow", func(s *genome.SetupRules) { s.KittySize, s.KittyFaceUp = 2, true }, func(g *engine.Genome) bool { return g.KittySize == 2 && g.KittyFaceUp }},
+		{"war to bottom", func(s *genome.SetupRules) { s.WarToBottom = true }, func(g *engine.Genome) bool { return g.WarToBottom }},
+		{"perfect information", func(s *genome.SetupRules) { s.PerfectInformation = true }, func(g *engine.Genome) bool { return g.PerfectInformation }},
+	}
+	for _, tt := range tests {
+		original := genome.CreateWarGenome()
+		tt.set(&original.Setup)
+		data, err := original.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		loaded := &genome.GameGenome{}
+		if err := loaded.UnmarshalJSON(data); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !tt.check(createCompatGenome(loaded)) {
+			t.Errorf("%s: expected the setting in the engine genome", tt.name)
+		}
+		if tt.check(createCompatGenome(genome.CreateWarGenome())) {
+			t.Errorf("%s: expected the setting off by default", tt.name)
+		}
+	}
+}
@@ -1436,6 +1436,48 @@ class TestWinConditions:
         winner = check_win_conditions(state, genome)
         assert winner is None  # Not triggered
 
+    def _score_race_genome(self, threshold, phases):
+        """Helper to create a genome with a single first_to_score condition."""
+        from darwindeck.genome.schema import GameGenome, SetupRules, TurnStructure, WinCondition
+
+        return GameGenome(
+            schema_version="1.0",
+            genome_id="test",
+            generation=0,
+            setup=SetupRules(cards_per_player=0),
+            turn_structure=TurnStructure(phases=phases),
+            special_effects=[],
+            win_conditions=[WinCondition(type="first_to_score", threshold=threshold)],
+            scoring_rules=[],
+            player_count=2,
+        )
+
+    def test_first_to_score_fires_with_cards_in_hand(self):
+        """Without tricks every turn is a hand boundary, as in the Go engine."""
+        from darwindeck.simulation.movegen import check_win_conditions
+        from darwindeck.genome.schema import DrawPhase, Location
+
+        genome = self._score_race_genome(100, [DrawPhase(source=Location.DECK, count=1)])
+        assert check_win_conditions(self._make_state([["2H"], ["3S"]], scores=[40, 99]), genome) is None
+        assert check_win_conditions(self._make_state([["2H"], ["3S"]], scores=[40, 100]), genome) == 1
+
+    def test_first_to_score_waits_for_trick_hand_end(self):
+        """Trick games settle first_to_score once every trick is played."""
+        from darwindeck.simulation.movegen import check_win_conditions
+        from darwindeck.genome.schema import TrickPhase
+
+        genome = self._score_race_genome(100, [TrickPhase()])
+        assert check_win_conditions(self._make_state([["2H"], ["3S"]], scores=[120, 0]), genome) is None
+        assert check_win_conditions(self._make_state([[], []], scores=[120, 0]), genome) == 0
+
+    def test_first_to_score_negative_floor(self):
+        """A negative threshold ends the game when a score drops below it; the highest wins."""
+        from darwindeck.simulation.movegen import check_win_conditions
+
+        genome = self._score_race_genome(-100, [])
+        assert check_win_conditions(self._make_state([[], []], scores=[60, -90]), genome) is None
+        assert check_win_conditions(self._make_state([[], []], scores=[60, -140]), genome) == 0
+
 
 class TestPokerHandEvaluation:
     """Test poker hand evaluation for best_hand win condition."""